@@ -2,20 +2,38 @@ package main
 
 import (
 	"context"
+	"errors"
+	"fullcycle-auction_go/configuration/auction_rules"
 	"fullcycle-auction_go/configuration/database/mongodb"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/infra/api/graphql"
 	"fullcycle-auction_go/internal/infra/api/web/controller/auction_controller"
 	"fullcycle-auction_go/internal/infra/api/web/controller/bid_controller"
+	"fullcycle-auction_go/internal/infra/api/web/controller/health_controller"
 	"fullcycle-auction_go/internal/infra/api/web/controller/user_controller"
+	"fullcycle-auction_go/internal/infra/api/web/middleware"
+	"fullcycle-auction_go/internal/infra/api/web/ratelimit"
+	"fullcycle-auction_go/internal/infra/broadcast"
 	"fullcycle-auction_go/internal/infra/database/auction"
 	"fullcycle-auction_go/internal/infra/database/bid"
 	"fullcycle-auction_go/internal/infra/database/user"
+	"fullcycle-auction_go/internal/infra/database/webhook"
+	"fullcycle-auction_go/internal/infra/messaging"
 	"fullcycle-auction_go/internal/usecase/auction_usecase"
 	"fullcycle-auction_go/internal/usecase/bid_usecase"
 	"fullcycle-auction_go/internal/usecase/user_usecase"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
+	"github.com/prometheus/client_golang/prometheus"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
@@ -33,35 +51,186 @@ func main() {
 		return
 	}
 
+	rules, err := auction_rules.Load()
+	if err != nil {
+		log.Fatal(err.Error())
+		return
+	}
+
 	router := gin.Default()
+	if err := router.SetTrustedProxies(trustedProxies()); err != nil {
+		log.Fatal(err.Error())
+		return
+	}
+	router.Use(middleware.CorrelationId())
+
+	userController, bidController, auctionsController, healthController, graphqlResolver, shutdown :=
+		initDependencies(ctx, databaseConnection, rules)
 
-	userController, bidController, auctionsController := initDependencies(ctx, databaseConnection)
+	registerRoutes(router, adminToken(),
+		userController, bidController, auctionsController, healthController, graphqlResolver)
 
-	router.GET("/auction", auctionsController.FindAuctions)
-	router.GET("/auction/:auctionId", auctionsController.FindAuctionById)
-	router.POST("/auction", auctionsController.CreateAuction)
-	router.GET("/auction/winner/:auctionId", auctionsController.FindWinningBidByAuctionId)
-	router.POST("/bid", bidController.CreateBid)
-	router.GET("/bid/:auctionId", bidController.FindBidByAuctionId)
-	router.GET("/user/:userId", userController.FindUserById)
+	server := &http.Server{Addr: ":8080", Handler: router}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal(err.Error())
+		}
+	}()
+
+	// No SIGINT ou SIGTERM o servidor para de aceitar requisições, espera as
+	// em andamento e só então as rotinas em segundo plano são encerradas
+	signalCtx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	<-signalCtx.Done()
+
+	logger.Info("Shutting down")
+	shutdownCtx, cancel := context.WithTimeout(ctx, shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		logger.Error("Error trying to shut down the HTTP server", err)
+	}
 
-	router.Run(":8080")
+	shutdown()
+
+	disconnectCtx, cancelDisconnect := context.WithTimeout(ctx, shutdownTimeout)
+	defer cancelDisconnect()
+	if err := databaseConnection.Client().Disconnect(disconnectCtx); err != nil {
+		logger.Error("Error trying to disconnect from MongoDB", err)
+	}
 }
 
-func initDependencies(ctx context.Context, database *mongo.Database) (
+// shutdownTimeout limita a espera pelas requisições em andamento no
+// encerramento
+const shutdownTimeout = 10 * time.Second
+
+func initDependencies(ctx context.Context, database *mongo.Database, rules auction_entity.Rules) (
 	userController *user_controller.UserController,
 	bidController *bid_controller.BidController,
-	auctionController *auction_controller.AuctionController) {
+	auctionController *auction_controller.AuctionController,
+	healthController *health_controller.HealthController,
+	graphqlResolver *graphql.Resolver,
+	shutdown func()) {
+
+	// O notifier e o webhook existem antes do repositório de leilões para que
+	// os leilões fechados pela reconciliação do construtor também sejam
+	// notificados; eles esperam na fila até o Start.
+	eventPublisher := initEventPublisher()
+	auctionClosedNotifier := messaging.NewAuctionClosedNotifier(eventPublisher)
+	webhookOutbox := webhook.NewWebhookOutboxRepository(ctx, database)
+	auctionClosedWebhook := messaging.NewAuctionClosedWebhook(webhookOutbox, nil)
 
-	auctionRepository := auction.NewAuctionRepository(ctx, database)
-	bidRepository := bid.NewBidRepository(database, auctionRepository)
 	userRepository := user.NewUserRepository(database)
+	auctionRepository := auction.NewAuctionRepositoryWithOptions(ctx, database,
+		auction.WithRegisterer(prometheus.DefaultRegisterer),
+		auction.WithUserRepository(userRepository),
+		auction.WithRules(rules),
+		auction.WithOnClosed(func(ctx context.Context, auctionID string) {
+			auctionClosedNotifier.OnAuctionClosed(ctx, auctionID)
+			auctionClosedWebhook.OnAuctionClosed(ctx, auctionID)
+		}))
+	bidRepository := bid.NewBidRepository(database, auctionRepository)
+	auctionClosedNotifier.Start(bidRepository)
+	auctionClosedWebhook.Start(auctionRepository, bidRepository, userRepository)
+	webhookOutboxWorker := messaging.NewWebhookOutboxWorker(webhookOutbox, nil)
+	go reloadAuctionIntervalOnSIGHUP(auctionRepository)
 
 	userController = user_controller.NewUserController(
 		user_usecase.NewUserUseCase(userRepository))
-	auctionController = auction_controller.NewAuctionController(
-		auction_usecase.NewAuctionUseCase(auctionRepository, bidRepository))
-	bidController = bid_controller.NewBidController(bid_usecase.NewBidUseCase(bidRepository))
+	auctionUseCase := auction_usecase.NewAuctionUseCase(auctionRepository, bidRepository, rules)
+	auctionController = auction_controller.NewAuctionController(auctionUseCase)
+	bidHub := broadcast.NewBidHub()
+	bidUseCase := bid_usecase.NewBidUseCase(bidRepository, auctionRepository, bidHub)
+	bidController = bid_controller.NewBidController(bidUseCase, bidHub, ratelimit.NewBidLimiter())
+	graphqlResolver = graphql.NewResolver(auctionUseCase, bidUseCase)
+	healthController = health_controller.NewHealthController(auctionRepository)
+
+	// A ordem segue as dependências: os lances pendentes são gravados antes
+	// de o closer parar, e o notifier e o webhook só fecham depois dele, já
+	// sem novos leilões fechados para receber
+	shutdown = func() {
+		bidUseCase.Close()
+		bidRepository.Close()
+		auctionRepository.Close()
+		auctionClosedNotifier.Close()
+		auctionClosedWebhook.Close()
+		webhookOutboxWorker.Close()
+
+		if closer, ok := eventPublisher.(interface{ Close() error }); ok {
+			if err := closer.Close(); err != nil {
+				logger.Error("Error trying to close the event publisher", err)
+			}
+		}
+	}
 
 	return
 }
+
+// trustedProxies lê TRUSTED_PROXIES, a lista de IPs ou CIDRs separados por
+// vírgula cujo X-Forwarded-For é aceito. Sem a variável nenhum proxy é
+// confiável e o IP do cliente, usado no limite de lances, é o da conexão.
+func trustedProxies() []string {
+	value := os.Getenv("TRUSTED_PROXIES")
+	if value == "" {
+		return nil
+	}
+
+	var proxies []string
+	for _, proxy := range strings.Split(value, ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+
+	return proxies
+}
+
+// adminToken protege as rotas administrativas com o ADMIN_TOKEN. Sem a
+// variável elas ficam fechadas.
+func adminToken() gin.HandlerFunc {
+	token := os.Getenv("ADMIN_TOKEN")
+	if token == "" {
+		logger.Warn("ADMIN_TOKEN is not set, admin routes will reject every request")
+	}
+
+	return middleware.AdminToken(token)
+}
+
+// reloadAuctionIntervalOnSIGHUP relê o .env a cada SIGHUP e aplica o novo
+// AUCTION_INTERVAL sem reiniciar o serviço.
+func reloadAuctionIntervalOnSIGHUP(auctionRepository *auction.AuctionRepository) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+
+	for range signals {
+		if err := godotenv.Overload("cmd/auction/.env"); err != nil {
+			logger.Error("Error trying to reload env variables", err)
+			continue
+		}
+
+		if err := auctionRepository.ReloadAuctionInterval(); err != nil {
+			logger.Error("Error trying to reload auction interval", err)
+		}
+	}
+}
+
+// initEventPublisher usa o RabbitMQ quando RABBITMQ_URL está definida. Sem o
+// broker a aplicação continua funcionando, apenas sem publicar eventos.
+func initEventPublisher() messaging.EventPublisher {
+	rabbitMQURL := os.Getenv("RABBITMQ_URL")
+	if rabbitMQURL == "" {
+		return messaging.NoopPublisher{}
+	}
+
+	exchange := os.Getenv("RABBITMQ_EXCHANGE")
+	if exchange == "" {
+		exchange = "auction.events"
+	}
+
+	publisher, err := messaging.NewRabbitMQPublisher(rabbitMQURL, exchange)
+	if err != nil {
+		logger.Error("Error trying to connect to RabbitMQ, auction events will not be published", err)
+		return messaging.NoopPublisher{}
+	}
+
+	return publisher
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrustedProxies(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testCases := []struct {
+		name       string
+		proxies    string
+		expectedIP string
+	}{
+		// Sem proxies confiáveis o X-Forwarded-For não troca o IP do cliente
+		{name: "no trusted proxies", expectedIP: "192.0.2.10"},
+		{name: "trusted proxy", proxies: "10.0.0.0/8, 192.0.2.10", expectedIP: "203.0.113.7"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("TRUSTED_PROXIES", tc.proxies)
+
+			router := gin.New()
+			require.NoError(t, router.SetTrustedProxies(trustedProxies()))
+			router.GET("/ip", func(c *gin.Context) {
+				c.String(http.StatusOK, c.ClientIP())
+			})
+
+			request := httptest.NewRequest(http.MethodGet, "/ip", nil)
+			request.RemoteAddr = "192.0.2.10:4321"
+			request.Header.Set("X-Forwarded-For", "203.0.113.7")
+			recorder := httptest.NewRecorder()
+
+			router.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedIP, recorder.Body.String())
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fullcycle-auction_go/internal/infra/api/graphql"
+	"fullcycle-auction_go/internal/infra/api/web/controller/auction_controller"
+	"fullcycle-auction_go/internal/infra/api/web/controller/bid_controller"
+	"fullcycle-auction_go/internal/infra/api/web/controller/health_controller"
+	"fullcycle-auction_go/internal/infra/api/web/controller/user_controller"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// registerRoutes liga as rotas HTTP aos controllers. As rotas que removem ou
+// cancelam leilões e as de administração passam antes pelo admin.
+func registerRoutes(
+	router *gin.Engine,
+	admin gin.HandlerFunc,
+	userController *user_controller.UserController,
+	bidController *bid_controller.BidController,
+	auctionsController *auction_controller.AuctionController,
+	healthController *health_controller.HealthController,
+	graphqlResolver *graphql.Resolver) {
+	router.GET("/auction", auctionsController.FindAuctions)
+	router.GET("/auction/:auctionId", auctionsController.FindAuctionById)
+	router.POST("/auction", auctionsController.CreateAuction)
+	router.DELETE("/auction/:auctionId", admin, auctionsController.DeleteAuction)
+	router.POST("/auction/:auctionId/cancel", admin, auctionsController.CancelAuction)
+	router.GET("/auction/:auctionId/remaining", auctionsController.FindAuctionTimeRemaining)
+	router.GET("/auction/winner/:auctionId", auctionsController.FindWinningBidByAuctionId)
+	router.POST("/bid", bidController.CreateBid)
+	router.GET("/bid/:auctionId", bidController.FindBidByAuctionId)
+	router.GET("/bid/:auctionId/stream", bidController.StreamBids)
+	router.GET("/bid/:auctionId/leader", bidController.IsLeadingBidder)
+	router.GET("/user/:userId", userController.FindUserById)
+	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+	router.GET("/health/closer", healthController.CheckAuctionCloser)
+	router.GET("/admin/auction/stale", admin, auctionsController.FindStaleActiveAuctions)
+	router.POST("/graphql", graphqlResolver.Handle)
+}
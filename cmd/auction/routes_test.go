@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fullcycle-auction_go/internal/infra/api/web/middleware"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProtectedRoutesRequireAdminToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// Sem token válido o middleware responde antes de chegar aos controllers,
+	// por isso eles podem ficar nil aqui
+	router := gin.New()
+	registerRoutes(router, middleware.AdminToken("secret"), nil, nil, nil, nil, nil)
+
+	testCases := []struct {
+		name          string
+		method        string
+		path          string
+		authorization string
+	}{
+		{name: "cancel without token", method: http.MethodPost, path: "/auction/a1b2c3/cancel"},
+		{name: "cancel with a wrong token", method: http.MethodPost, path: "/auction/a1b2c3/cancel", authorization: "Bearer other"},
+		{name: "delete without token", method: http.MethodDelete, path: "/auction/a1b2c3"},
+		{name: "stale auctions without token", method: http.MethodGet, path: "/admin/auction/stale"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			request := httptest.NewRequest(tc.method, tc.path, strings.NewReader(`{"reason":"sold elsewhere"}`))
+			request.Header.Set("Content-Type", "application/json")
+			if tc.authorization != "" {
+				request.Header.Set("Authorization", tc.authorization)
+			}
+			recorder := httptest.NewRecorder()
+
+			router.ServeHTTP(recorder, request)
+
+			require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			require.Contains(t, recorder.Body.String(), `"error_code":"UNAUTHORIZED"`)
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package auction_rules
+
+import (
+	"fmt"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	AUCTION_MAX_DESCRIPTION_LEN  = "AUCTION_MAX_DESCRIPTION_LEN"
+	AUCTION_TRUNCATE_DESCRIPTION = "AUCTION_TRUNCATE_DESCRIPTION"
+	AUCTION_MAX_IMAGES           = "AUCTION_MAX_IMAGES"
+	BLOCKLIST_WORDS              = "BLOCKLIST_WORDS"
+	BLOCKLIST_FILE               = "BLOCKLIST_FILE"
+)
+
+// Load lê as regras dos leilões das variáveis de ambiente. É chamado uma vez
+// na inicialização; um BLOCKLIST_FILE que não pode ser lido é um erro, para o
+// serviço não subir sem a blocklist configurada.
+func Load() (auction_entity.Rules, error) {
+	blocklist, err := loadBlocklist()
+	if err != nil {
+		return auction_entity.Rules{}, err
+	}
+
+	rules := auction_entity.DefaultRules()
+	rules.Blocklist = blocklist
+
+	// Valores inválidos ficam no padrão
+	if value, err := strconv.Atoi(os.Getenv(AUCTION_MAX_DESCRIPTION_LEN)); err == nil {
+		rules.MaxDescriptionLen = value
+	}
+	if value, err := strconv.ParseBool(os.Getenv(AUCTION_TRUNCATE_DESCRIPTION)); err == nil {
+		rules.TruncateDescription = value
+	}
+	if value, err := strconv.Atoi(os.Getenv(AUCTION_MAX_IMAGES)); err == nil && value >= 0 {
+		rules.MaxImages = value
+	}
+
+	return rules, nil
+}
+
+// loadBlocklist junta as palavras de BLOCKLIST_WORDS, separadas por vírgula,
+// e as do arquivo em BLOCKLIST_FILE, uma por linha; linhas vazias ou
+// começando com "#" são ignoradas.
+func loadBlocklist() (auction_entity.Blocklist, error) {
+	entries := strings.Split(os.Getenv(BLOCKLIST_WORDS), ",")
+
+	if path := os.Getenv(BLOCKLIST_FILE); path != "" {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("error trying to read %s: %w", BLOCKLIST_FILE, err)
+		}
+
+		for _, line := range strings.Split(string(content), "\n") {
+			if !strings.HasPrefix(strings.TrimSpace(line), "#") {
+				entries = append(entries, line)
+			}
+		}
+	}
+
+	return auction_entity.NewBlocklist(entries), nil
+}
@@ -0,0 +1,55 @@
+package auction_rules
+
+import (
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadBlocklist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "blocklist.txt")
+	require.NoError(t, os.WriteFile(path, []byte("# termos proibidos\nréplica\n\nnão original\n"), 0o600))
+	t.Setenv(BLOCKLIST_WORDS, "golpe, ,Pirata")
+	t.Setenv(BLOCKLIST_FILE, path)
+
+	rules, err := Load()
+	require.NoError(t, err)
+	require.Equal(t, [][]string{{"golpe"}, {"pirata"}, {"réplica"}, {"não", "original"}}, [][]string(rules.Blocklist))
+
+	// Palavras soltas de uma expressão não bloqueiam
+	require.Nil(t, rules.Blocklist.Check("Relógio", "Produto original, não usado"))
+	require.NotNil(t, rules.Blocklist.Check("Relógio", "Produto Não Original, sem caixa"))
+}
+
+func TestLoadBlocklistUnreadableFile(t *testing.T) {
+	t.Setenv(BLOCKLIST_FILE, filepath.Join(t.TempDir(), "missing.txt"))
+
+	_, err := Load()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), BLOCKLIST_FILE)
+}
+
+func TestLoadLimits(t *testing.T) {
+	t.Setenv(AUCTION_MAX_DESCRIPTION_LEN, "500")
+	t.Setenv(AUCTION_TRUNCATE_DESCRIPTION, "true")
+	t.Setenv(AUCTION_MAX_IMAGES, "3")
+
+	rules, err := Load()
+	require.NoError(t, err)
+	require.Equal(t, 500, rules.MaxDescriptionLen)
+	require.True(t, rules.TruncateDescription)
+	require.Equal(t, 3, rules.MaxImages)
+
+	t.Setenv(AUCTION_MAX_DESCRIPTION_LEN, "abc")
+	t.Setenv(AUCTION_TRUNCATE_DESCRIPTION, "")
+	t.Setenv(AUCTION_MAX_IMAGES, "-1")
+
+	rules, err = Load()
+	require.NoError(t, err)
+	require.Equal(t, auction_entity.DefaultMaxDescriptionLen, rules.MaxDescriptionLen)
+	require.False(t, rules.TruncateDescription)
+	require.Equal(t, auction_entity.DefaultMaxImages, rules.MaxImages)
+}
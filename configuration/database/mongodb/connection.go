@@ -3,31 +3,192 @@ package mongodb
 import (
 	"context"
 	"fullcycle-auction_go/configuration/logger"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
-	"os"
+	"go.uber.org/zap"
+)
+
+const (
+	MONGODB_URL         = "MONGODB_URL"
+	MONGODB_DB          = "MONGODB_DB"
+	MONGO_MAX_POOL_SIZE = "MONGO_MAX_POOL_SIZE"
+	MONGO_MIN_POOL_SIZE = "MONGO_MIN_POOL_SIZE"
+	MONGO_COMPRESSORS   = "MONGO_COMPRESSORS"
+
+	MONGO_CONNECT_TIMEOUT = "MONGO_CONNECT_TIMEOUT"
 )
 
 const (
-	MONGODB_URL = "MONGODB_URL"
-	MONGODB_DB  = "MONGODB_DB"
+	defaultConnectTimeout = time.Minute
+	connectBaseDelay      = 500 * time.Millisecond
+	connectMaxDelay       = 10 * time.Second
 )
 
+// Compressores de rede suportados pelo driver
+var supportedCompressors = map[string]bool{
+	"zstd":   true,
+	"snappy": true,
+	"zlib":   true,
+}
+
+// NewMongoDBConnection tenta conectar até MONGO_CONNECT_TIMEOUT, com espera
+// exponencial entre as tentativas, para o serviço não cair quando sobe antes
+// do Mongo. Depois de conectado, quedas são tratadas pelo próprio driver: o
+// pool reconecta sozinho e as operações voltam a funcionar quando o servidor
+// responde, sem recriar o client.
 func NewMongoDBConnection(ctx context.Context) (*mongo.Database, error) {
-	mongoURL := os.Getenv(MONGODB_URL)
 	mongoDatabase := os.Getenv(MONGODB_DB)
 
-	client, err := mongo.Connect(
-		ctx, options.Client().ApplyURI(mongoURL))
+	client, err := mongo.Connect(ctx, newClientOptions())
 	if err != nil {
 		logger.Error("Error trying to connect to mongodb database", err)
 		return nil, err
 	}
 
-	if err := client.Ping(ctx, nil); err != nil {
+	if err := pingWithBackoff(ctx, client, getConnectTimeout()); err != nil {
 		logger.Error("Error trying to ping mongodb database", err)
+		client.Disconnect(context.Background())
 		return nil, err
 	}
 
 	return client.Database(mongoDatabase), nil
 }
+
+func pingWithBackoff(ctx context.Context, client *mongo.Client, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+
+	for attempt := 1; ; attempt++ {
+		// Sem o limite cada tentativa esperaria o serverSelectionTimeout do
+		// driver (30s por padrão)
+		pingCtx, cancel := context.WithTimeout(ctx, connectMaxDelay)
+		err := client.Ping(pingCtx, nil)
+		cancel()
+		if err == nil {
+			return nil
+		}
+
+		delay := connectDelay(attempt)
+		if ctx.Err() != nil || time.Now().Add(delay).After(deadline) {
+			return err
+		}
+
+		logger.Warn("MongoDB is not available yet, retrying",
+			zap.Int("attempt", attempt),
+			zap.Duration("delay", delay),
+			zap.Error(err))
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+	}
+}
+
+// connectDelay dobra a espera a cada tentativa, até connectMaxDelay
+func connectDelay(attempt int) time.Duration {
+	delay := connectBaseDelay
+	for i := 1; i < attempt && delay < connectMaxDelay; i++ {
+		delay *= 2
+	}
+
+	if delay > connectMaxDelay {
+		return connectMaxDelay
+	}
+
+	return delay
+}
+
+// getConnectTimeout aceita 0 para uma única tentativa
+func getConnectTimeout() time.Duration {
+	value := os.Getenv(MONGO_CONNECT_TIMEOUT)
+	if value == "" {
+		return defaultConnectTimeout
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration < 0 {
+		logger.Warn("MONGO_CONNECT_TIMEOUT is invalid, using default value",
+			zap.String("value", value),
+			zap.Duration("default", defaultConnectTimeout))
+		return defaultConnectTimeout
+	}
+
+	return duration
+}
+
+// newClientOptions parte da MONGODB_URL e aplica o ajuste de pool e a
+// compressão definidos no ambiente. Variáveis ausentes ou inválidas mantêm o
+// padrão do driver (ou o que veio na URL).
+func newClientOptions() *options.ClientOptions {
+	clientOptions := options.Client().ApplyURI(os.Getenv(MONGODB_URL))
+
+	maxPoolSize, hasMaxPoolSize := getPoolSize(MONGO_MAX_POOL_SIZE)
+	if hasMaxPoolSize {
+		clientOptions.SetMaxPoolSize(maxPoolSize)
+	}
+
+	if minPoolSize, ok := getPoolSize(MONGO_MIN_POOL_SIZE); ok {
+		if hasMaxPoolSize && maxPoolSize > 0 && minPoolSize > maxPoolSize {
+			logger.Warn("MONGO_MIN_POOL_SIZE is greater than MONGO_MAX_POOL_SIZE, ignoring it",
+				zap.Uint64("min", minPoolSize),
+				zap.Uint64("max", maxPoolSize))
+		} else {
+			clientOptions.SetMinPoolSize(minPoolSize)
+		}
+	}
+
+	if compressors := getCompressors(); len(compressors) > 0 {
+		clientOptions.SetCompressors(compressors)
+	}
+
+	return clientOptions
+}
+
+func getPoolSize(name string) (uint64, bool) {
+	value := os.Getenv(name)
+	if value == "" {
+		return 0, false
+	}
+
+	poolSize, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		logger.Warn(name+" is invalid, using the driver default",
+			zap.String("value", value))
+		return 0, false
+	}
+
+	return poolSize, true
+}
+
+// getCompressors lê MONGO_COMPRESSORS em ordem de preferência, por exemplo
+// "zstd,snappy". O servidor usa o primeiro que também suportar.
+func getCompressors() []string {
+	value := os.Getenv(MONGO_COMPRESSORS)
+	if value == "" {
+		return nil
+	}
+
+	var compressors []string
+	for _, compressor := range strings.Split(value, ",") {
+		compressor = strings.ToLower(strings.TrimSpace(compressor))
+		if compressor == "" {
+			continue
+		}
+
+		if !supportedCompressors[compressor] {
+			logger.Warn("MONGO_COMPRESSORS has an unsupported compressor, ignoring it",
+				zap.String("compressor", compressor))
+			continue
+		}
+
+		compressors = append(compressors, compressor)
+	}
+
+	return compressors
+}
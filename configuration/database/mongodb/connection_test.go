@@ -0,0 +1,89 @@
+package mongodb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewClientOptions(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		t.Setenv(MONGODB_URL, "mongodb://localhost:27017")
+		t.Setenv(MONGO_MAX_POOL_SIZE, "")
+		t.Setenv(MONGO_MIN_POOL_SIZE, "")
+		t.Setenv(MONGO_COMPRESSORS, "")
+
+		clientOptions := newClientOptions()
+		require.NoError(t, clientOptions.Validate())
+		require.Nil(t, clientOptions.MaxPoolSize)
+		require.Nil(t, clientOptions.MinPoolSize)
+		require.Empty(t, clientOptions.Compressors)
+	})
+
+	t.Run("configured", func(t *testing.T) {
+		t.Setenv(MONGODB_URL, "mongodb://localhost:27017")
+		t.Setenv(MONGO_MAX_POOL_SIZE, "200")
+		t.Setenv(MONGO_MIN_POOL_SIZE, "10")
+		t.Setenv(MONGO_COMPRESSORS, "zstd, Snappy")
+
+		clientOptions := newClientOptions()
+		require.NoError(t, clientOptions.Validate())
+		require.Equal(t, uint64(200), *clientOptions.MaxPoolSize)
+		require.Equal(t, uint64(10), *clientOptions.MinPoolSize)
+		require.Equal(t, []string{"zstd", "snappy"}, clientOptions.Compressors)
+	})
+
+	t.Run("env overrides the url", func(t *testing.T) {
+		t.Setenv(MONGODB_URL, "mongodb://localhost:27017/?maxPoolSize=50&compressors=zlib")
+		t.Setenv(MONGO_MAX_POOL_SIZE, "20")
+		t.Setenv(MONGO_MIN_POOL_SIZE, "")
+		t.Setenv(MONGO_COMPRESSORS, "snappy")
+
+		clientOptions := newClientOptions()
+		require.Equal(t, uint64(20), *clientOptions.MaxPoolSize)
+		require.Equal(t, []string{"snappy"}, clientOptions.Compressors)
+	})
+
+	t.Run("invalid values are ignored", func(t *testing.T) {
+		t.Setenv(MONGODB_URL, "mongodb://localhost:27017")
+		t.Setenv(MONGO_MAX_POOL_SIZE, "5")
+		t.Setenv(MONGO_MIN_POOL_SIZE, "10")
+		t.Setenv(MONGO_COMPRESSORS, "gzip,zstd")
+
+		clientOptions := newClientOptions()
+		require.NoError(t, clientOptions.Validate())
+		require.Equal(t, uint64(5), *clientOptions.MaxPoolSize)
+		require.Nil(t, clientOptions.MinPoolSize)
+		require.Equal(t, []string{"zstd"}, clientOptions.Compressors)
+
+		t.Setenv(MONGO_MAX_POOL_SIZE, "-1")
+		require.Nil(t, newClientOptions().MaxPoolSize)
+	})
+}
+
+func TestConnectDelay(t *testing.T) {
+	require.Equal(t, 500*time.Millisecond, connectDelay(1))
+	require.Equal(t, time.Second, connectDelay(2))
+	require.Equal(t, 2*time.Second, connectDelay(3))
+	require.Equal(t, 8*time.Second, connectDelay(5))
+	require.Equal(t, connectMaxDelay, connectDelay(6))
+	require.Equal(t, connectMaxDelay, connectDelay(100))
+}
+
+func TestNewMongoDBConnectionGivesUp(t *testing.T) {
+	// Porta sem servidor: cada tentativa falha rápido
+	t.Setenv(MONGODB_URL, "mongodb://127.0.0.1:1/?serverSelectionTimeoutMS=100")
+	t.Setenv(MONGO_CONNECT_TIMEOUT, "2s")
+
+	start := time.Now()
+	database, err := NewMongoDBConnection(context.Background())
+	require.Error(t, err)
+	require.Nil(t, database)
+
+	// Tentou de novo com espera, mas respeitou o prazo
+	elapsed := time.Since(start)
+	require.GreaterOrEqual(t, elapsed, connectDelay(1))
+	require.Less(t, elapsed, 5*time.Second)
+}
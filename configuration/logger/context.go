@@ -0,0 +1,46 @@
+package logger
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+type correlationIdKey struct{}
+
+// ContextWithCorrelationId guarda no contexto o id usado para agrupar os logs
+// de uma mesma requisição ou rodada do closer.
+func ContextWithCorrelationId(ctx context.Context, correlationId string) context.Context {
+	return context.WithValue(ctx, correlationIdKey{}, correlationId)
+}
+
+func CorrelationIdFromContext(ctx context.Context) string {
+	correlationId, _ := ctx.Value(correlationIdKey{}).(string)
+	return correlationId
+}
+
+// ContextLogger acrescenta os campos do contexto a cada mensagem
+type ContextLogger struct {
+	fields []zap.Field
+}
+
+func WithContext(ctx context.Context) ContextLogger {
+	var fields []zap.Field
+	if correlationId := CorrelationIdFromContext(ctx); correlationId != "" {
+		fields = append(fields, zap.String("correlation_id", correlationId))
+	}
+
+	return ContextLogger{fields: fields}
+}
+
+func (l ContextLogger) Info(message string, tags ...zap.Field) {
+	Info(message, append(tags, l.fields...)...)
+}
+
+func (l ContextLogger) Warn(message string, tags ...zap.Field) {
+	Warn(message, append(tags, l.fields...)...)
+}
+
+func (l ContextLogger) Error(message string, err error, tags ...zap.Field) {
+	Error(message, err, append(tags, l.fields...)...)
+}
@@ -0,0 +1,31 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestWithContext(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	restore := Replace(zap.New(core))
+	defer restore()
+
+	ctx := ContextWithCorrelationId(context.Background(), "request-123")
+	WithContext(ctx).Info("with id", zap.String("auction_id", "auction-1"))
+	WithContext(ctx).Error("with id and error", errors.New("boom"))
+	WithContext(context.Background()).Info("without id")
+
+	entries := logs.All()
+	require.Len(t, entries, 3)
+
+	require.Equal(t, "request-123", entries[0].ContextMap()["correlation_id"])
+	require.Equal(t, "auction-1", entries[0].ContextMap()["auction_id"])
+	require.Equal(t, "request-123", entries[1].ContextMap()["correlation_id"])
+	require.Equal(t, "boom", entries[1].ContextMap()["error"])
+	require.NotContains(t, entries[2].ContextMap(), "correlation_id")
+}
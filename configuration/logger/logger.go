@@ -31,8 +31,23 @@ func Info(message string, tags ...zap.Field) {
 	log.Sync()
 }
 
+func Warn(message string, tags ...zap.Field) {
+	log.Warn(message, tags...)
+	log.Sync()
+}
+
 func Error(message string, err error, tags ...zap.Field) {
 	tags = append(tags, zap.NamedError("error", err))
 	log.Error(message, tags...)
 	log.Sync()
 }
+
+// Replace troca o logger usado pelo pacote e devolve uma função que restaura
+// o anterior. Útil para inspecionar logs em testes.
+func Replace(logger *zap.Logger) func() {
+	previous := log
+	log = logger
+	return func() {
+		log = previous
+	}
+}
@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"errors"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type AuctionMetrics struct {
+	ClosedAuctions     *prometheus.CounterVec
+	ActiveAuctions     prometheus.Gauge
+	CloserTickerResets prometheus.Counter
+	CloserPanics       prometheus.Counter
+}
+
+// NewAuctionMetrics cria as métricas da rotina de fechamento. Com registerer
+// nil as métricas funcionam normalmente, apenas não são expostas.
+func NewAuctionMetrics(registerer prometheus.Registerer) *AuctionMetrics {
+	auctionMetrics := &AuctionMetrics{
+		ClosedAuctions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "auctions_closed_total",
+			Help: "Total number of closed auctions, by reason",
+		}, []string{"reason"}),
+		ActiveAuctions: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "auctions_active",
+			Help: "Number of currently active auctions",
+		}),
+		CloserTickerResets: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "auction_closer_ticker_resets_total",
+			Help: "Total number of times the auction closer ticker was reconfigured",
+		}),
+		CloserPanics: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "auction_closer_panics_total",
+			Help: "Total number of auction closer ticks that panicked",
+		}),
+	}
+
+	if registerer == nil {
+		return auctionMetrics
+	}
+
+	auctionMetrics.ClosedAuctions = register(registerer, auctionMetrics.ClosedAuctions)
+	auctionMetrics.ActiveAuctions = register(registerer, auctionMetrics.ActiveAuctions)
+	auctionMetrics.CloserTickerResets = register(registerer, auctionMetrics.CloserTickerResets)
+	auctionMetrics.CloserPanics = register(registerer, auctionMetrics.CloserPanics)
+
+	return auctionMetrics
+}
+
+func register[T prometheus.Collector](registerer prometheus.Registerer, collector T) T {
+	if err := registerer.Register(collector); err != nil {
+		var alreadyRegistered prometheus.AlreadyRegisteredError
+		if errors.As(err, &alreadyRegistered) {
+			if existing, ok := alreadyRegistered.ExistingCollector.(T); ok {
+				return existing
+			}
+		}
+	}
+
+	return collector
+}
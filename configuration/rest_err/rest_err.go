@@ -6,10 +6,11 @@ import (
 )
 
 type RestErr struct {
-	Message string   `json:"message"`
-	Err     string   `json:"err"`
-	Code    int      `json:"code"`
-	Causes  []Causes `json:"causes"`
+	Message   string   `json:"message"`
+	Err       string   `json:"err"`
+	ErrorCode string   `json:"error_code"`
+	Code      int      `json:"code"`
+	Causes    []Causes `json:"causes"`
 }
 
 type Causes struct {
@@ -22,39 +23,95 @@ func (r *RestErr) Error() string {
 }
 
 func ConvertError(internalError *internal_error.InternalError) *RestErr {
+	var restErr *RestErr
 	switch internalError.Err {
 	case "bad_request":
-		return NewBadRequestError(internalError.Error())
+		restErr = NewBadRequestError(internalError.Error())
 	case "not_found":
-		return NewNotFoundError(internalError.Error())
+		restErr = NewNotFoundError(internalError.Error())
+	case "timeout":
+		restErr = NewGatewayTimeoutError(internalError.Error())
+	case "too_many_requests":
+		restErr = NewTooManyRequestsError(internalError.Error())
+	case "conflict":
+		restErr = NewConflictError(internalError.Error())
 	default:
-		return NewInternalServerError(internalError.Error())
+		restErr = NewInternalServerError(internalError.Error())
 	}
+
+	if internalError.Code != "" {
+		restErr.ErrorCode = internalError.Code
+	}
+
+	return restErr
 }
 
 func NewBadRequestError(message string, causes ...Causes) *RestErr {
 	return &RestErr{
-		Message: message,
-		Err:     "bad_request",
-		Code:    http.StatusBadRequest,
-		Causes:  causes,
+		Message:   message,
+		Err:       "bad_request",
+		ErrorCode: internal_error.CodeBadRequest,
+		Code:      http.StatusBadRequest,
+		Causes:    causes,
 	}
 }
 
 func NewInternalServerError(message string) *RestErr {
 	return &RestErr{
-		Message: message,
-		Err:     "internal_server",
-		Code:    http.StatusInternalServerError,
-		Causes:  nil,
+		Message:   message,
+		Err:       "internal_server",
+		ErrorCode: internal_error.CodeInternalServerError,
+		Code:      http.StatusInternalServerError,
+		Causes:    nil,
 	}
 }
 
 func NewNotFoundError(message string) *RestErr {
 	return &RestErr{
-		Message: message,
-		Err:     "not_found",
-		Code:    http.StatusNotFound,
-		Causes:  nil,
+		Message:   message,
+		Err:       "not_found",
+		ErrorCode: internal_error.CodeNotFound,
+		Code:      http.StatusNotFound,
+		Causes:    nil,
+	}
+}
+
+func NewGatewayTimeoutError(message string) *RestErr {
+	return &RestErr{
+		Message:   message,
+		Err:       "gateway_timeout",
+		ErrorCode: internal_error.CodeTimeout,
+		Code:      http.StatusGatewayTimeout,
+		Causes:    nil,
+	}
+}
+
+func NewConflictError(message string) *RestErr {
+	return &RestErr{
+		Message:   message,
+		Err:       "conflict",
+		ErrorCode: internal_error.CodeConflict,
+		Code:      http.StatusConflict,
+		Causes:    nil,
+	}
+}
+
+func NewTooManyRequestsError(message string) *RestErr {
+	return &RestErr{
+		Message:   message,
+		Err:       "too_many_requests",
+		ErrorCode: internal_error.CodeRateLimited,
+		Code:      http.StatusTooManyRequests,
+		Causes:    nil,
+	}
+}
+
+func NewUnauthorizedError(message string) *RestErr {
+	return &RestErr{
+		Message:   message,
+		Err:       "unauthorized",
+		ErrorCode: internal_error.CodeUnauthorized,
+		Code:      http.StatusUnauthorized,
+		Causes:    nil,
 	}
 }
@@ -0,0 +1,75 @@
+package rest_err
+
+import (
+	"encoding/json"
+	"fullcycle-auction_go/internal/internal_error"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertErrorKeepsCode(t *testing.T) {
+	testCases := []struct {
+		name           string
+		err            *internal_error.InternalError
+		expectedStatus int
+		expectedCode   string
+	}{
+		{
+			name:           "default bad request code",
+			err:            internal_error.NewBadRequestError("invalid"),
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   internal_error.CodeBadRequest,
+		},
+		{
+			name:           "specific not found code",
+			err:            internal_error.NewNotFoundError("missing").WithCode(internal_error.CodeAuctionNotFound),
+			expectedStatus: http.StatusNotFound,
+			expectedCode:   internal_error.CodeAuctionNotFound,
+		},
+		{
+			name:           "timeout code",
+			err:            internal_error.NewTimeoutError("slow"),
+			expectedStatus: http.StatusGatewayTimeout,
+			expectedCode:   internal_error.CodeTimeout,
+		},
+		{
+			name:           "conflict code",
+			err:            internal_error.NewConflictError("stale"),
+			expectedStatus: http.StatusConflict,
+			expectedCode:   internal_error.CodeConflict,
+		},
+		{
+			name:           "internal server error code",
+			err:            internal_error.NewInternalServerError("boom"),
+			expectedStatus: http.StatusInternalServerError,
+			expectedCode:   internal_error.CodeInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			restErr := ConvertError(tc.err)
+
+			require.Equal(t, tc.expectedStatus, restErr.Code)
+			require.Equal(t, tc.expectedCode, restErr.ErrorCode)
+			require.Equal(t, tc.err.Message, restErr.Message)
+		})
+	}
+}
+
+func TestRestErrJSON(t *testing.T) {
+	restErr := ConvertError(
+		internal_error.NewBadRequestError("auction is not active").WithCode(internal_error.CodeAuctionNotActive))
+
+	body, err := json.Marshal(restErr)
+	require.NoError(t, err)
+
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal(body, &decoded))
+	require.Equal(t, "auction is not active", decoded["message"])
+	require.Equal(t, internal_error.CodeAuctionNotActive, decoded["error_code"])
+	require.Equal(t, float64(http.StatusBadRequest), decoded["code"])
+	require.NotContains(t, decoded, "status")
+}
@@ -2,22 +2,69 @@ package auction_entity
 
 import (
 	"context"
+	"fmt"
+	"fullcycle-auction_go/internal/entity/bid_entity"
 	"fullcycle-auction_go/internal/internal_error"
 	"github.com/google/uuid"
+	"net"
+	"net/url"
+	"strings"
 	"time"
+	"unicode/utf8"
 )
 
-func CreateAuction(
-	productName, category, description string,
-	condition ProductCondition) (*Auction, *internal_error.InternalError) {
+const minDescriptionLength = 10
+
+// AuctionInput reúne os dados informados na criação de um leilão. StartsAt
+// zero abre o leilão na hora e Currency vazia vale DefaultCurrency.
+type AuctionInput struct {
+	UserId       string
+	ProductName  string
+	Category     string
+	Description  string
+	Condition    ProductCondition
+	Type         AuctionType
+	ReservePrice float64
+	BuyNowPrice  float64
+	Currency     string
+	StartsAt     time.Time
+	WebhookURL   string
+	Images       []string
+}
+
+func CreateAuction(input AuctionInput) (*Auction, *internal_error.InternalError) {
+	now := time.Now()
+
+	currency, err := bid_entity.ParseCurrency(input.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	// Sem StartsAt, ou com um horário que já passou, o leilão abre na hora
+	startsAt := input.StartsAt
+	status := Active
+	if startsAt.After(now) {
+		status = Scheduled
+	} else {
+		startsAt = now
+	}
+
 	auction := &Auction{
-		Id:          uuid.New().String(),
-		ProductName: productName,
-		Category:    category,
-		Description: description,
-		Condition:   condition,
-		Status:      Active,
-		Timestamp:   time.Now(),
+		Id:           uuid.New().String(),
+		UserId:       input.UserId,
+		ProductName:  input.ProductName,
+		Category:     input.Category,
+		Description:  input.Description,
+		Condition:    input.Condition,
+		Type:         input.Type,
+		ReservePrice: input.ReservePrice,
+		BuyNowPrice:  input.BuyNowPrice,
+		Currency:     currency,
+		Status:       status,
+		Timestamp:    now,
+		StartsAt:     startsAt,
+		WebhookURL:   input.WebhookURL,
+		Images:       input.Images,
 	}
 
 	if err := auction.Validate(); err != nil {
@@ -28,33 +75,249 @@ func CreateAuction(
 }
 
 func (au *Auction) Validate() *internal_error.InternalError {
-	if len(au.ProductName) <= 1 ||
-		len(au.Category) <= 2 ||
-		len(au.Description) <= 10 && (au.Condition != New &&
-			au.Condition != Refurbished &&
-			au.Condition != Used) {
-		return internal_error.NewBadRequestError("invalid auction object")
+	// Os tamanhos contam caracteres, não bytes: "Lâmpada" tem 7 caracteres e 8
+	// bytes. Textos com UTF-8 inválido não seriam gravados corretamente no Mongo.
+	if strings.TrimSpace(au.ProductName) == "" {
+		return internal_error.NewBadRequestError("ProductName is required")
+	} else if !utf8.ValidString(au.ProductName) ||
+		!utf8.ValidString(au.Category) ||
+		!utf8.ValidString(au.Description) {
+		return internal_error.NewBadRequestError("Text fields must be valid UTF-8")
+	} else if utf8.RuneCountInString(au.Category) <= 2 {
+		return internal_error.NewBadRequestError("Category must have more than 2 characters")
+	} else if utf8.RuneCountInString(au.Description) < minDescriptionLength {
+		return internal_error.NewBadRequestError(
+			fmt.Sprintf("Description must have at least %d characters", minDescriptionLength))
+	} else if au.Condition != New &&
+		au.Condition != Used &&
+		au.Condition != Refurbished {
+		return internal_error.NewBadRequestError("Condition is not a valid value")
+	} else if au.Type != Open && au.Type != Sealed {
+		return internal_error.NewBadRequestError("Type is not a valid value")
+	} else if au.UserId != "" && uuid.Validate(au.UserId) != nil {
+		// Leilões gravados antes do campo existir não têm dono
+		return internal_error.NewBadRequestError("UserId is not a valid id")
+	} else if au.ReservePrice < 0 {
+		return internal_error.NewBadRequestError("ReservePrice must not be negative")
+	} else if au.BuyNowPrice < 0 {
+		return internal_error.NewBadRequestError("BuyNowPrice must not be negative")
+	} else if au.BuyNowPrice > 0 && au.BuyNowPrice < au.ReservePrice {
+		return internal_error.NewBadRequestError("BuyNowPrice must not be lower than ReservePrice")
+	} else if _, err := bid_entity.ParseCurrency(au.Currency); err != nil {
+		return err
+	} else if au.WebhookURL != "" && !validHTTPURL(au.WebhookURL) {
+		return internal_error.NewBadRequestError("WebhookURL must be an absolute http or https URL")
+	} else if au.WebhookURL != "" && internalHost(au.WebhookURL) {
+		return internal_error.NewBadRequestError("WebhookURL must not point to an internal address")
+	}
+
+	for _, image := range au.Images {
+		if !validHTTPURL(image) {
+			return internal_error.NewBadRequestError("Images must be absolute http or https URLs")
+		}
+	}
+
+	return nil
+}
+
+func validHTTPURL(rawURL string) bool {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+
+	return (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
+
+// internalHost indica se a URL aponta para localhost ou para um IP privado,
+// de loopback ou link-local. Nomes que resolvem para esses IPs só são
+// descobertos na conexão, onde o webhook faz a mesma verificação.
+func internalHost(rawURL string) bool {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+
+	host := strings.ToLower(parsed.Hostname())
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return true
+	}
+
+	ip := net.ParseIP(host)
+	return ip != nil && (ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast())
+}
+
+// TimeRemaining é quanto falta para o leilão fechar. Leilões que não estão
+// ativos, ou que já passaram do prazo e aguardam o closer, retornam zero.
+func (au *Auction) TimeRemaining(now time.Time) time.Duration {
+	if au.Status != Active || !au.ExpiresAt.After(now) {
+		return 0
+	}
+
+	return au.ExpiresAt.Sub(now)
+}
+
+// CheckAcceptsBid recusa lances em leilões que ainda não começaram, que não
+// estão ativos ou cujo prazo, somado o closeGrace, já tinha passado em
+// placedAt, o horário do lance.
+func (au *Auction) CheckAcceptsBid(placedAt time.Time, closeGrace time.Duration) *internal_error.InternalError {
+	if au.Status == Scheduled {
+		return internal_error.NewBadRequestError("auction has not started yet").
+			WithCode(internal_error.CodeAuctionNotActive)
+	}
+
+	if au.Status != Active || placedAt.After(au.ExpiresAt.Add(closeGrace)) {
+		return internal_error.NewBadRequestError("auction is not active").
+			WithCode(internal_error.CodeAuctionNotActive)
 	}
 
 	return nil
 }
 
+// BidsHidden indica se os lances do leilão ainda não podem ser mostrados:
+// leilões Sealed só revelam os lances depois de Completed.
+func (au *Auction) BidsHidden() bool {
+	return au.Type == Sealed && au.Status != Completed
+}
+
 type Auction struct {
 	Id          string
+	UserId      string
 	ProductName string
 	Category    string
 	Description string
 	Condition   ProductCondition
 	Status      AuctionStatus
 	Timestamp   time.Time
+	ExpiresAt   time.Time
+
+	// Type define se os lances ficam visíveis durante o leilão. Leilões
+	// gravados antes do campo existir são Open.
+	Type AuctionType
+
+	// StartsAt é quando o leilão passa a aceitar lances. Leilões Scheduled
+	// viram Active quando StartsAt chega.
+	StartsAt time.Time
+
+	// ReservePrice é o menor lance vencedor aceito; 0 significa sem reserva.
+	// ReserveMet fica nil até o leilão com reserva ser fechado.
+	ReservePrice float64
+	ReserveMet   *bool
+
+	// BuyNowPrice fecha o leilão no primeiro lance que o alcançar; 0 desliga
+	// o compre já. WinningBidId guarda o lance que fechou o leilão assim.
+	BuyNowPrice  float64
+	WinningBidId string
+
+	// Currency é o código ISO 4217 dos preços e dos lances do leilão
+	Currency string
+
+	// CancelReason é o motivo informado pelo vendedor ao cancelar o leilão
+	CancelReason string
+
+	// IdempotencyKey identifica a requisição de criação; repetir a criação com
+	// a mesma chave retorna o leilão original
+	IdempotencyKey string
+
+	// Version é incrementada a cada atualização gravada; as atualizações só
+	// são aplicadas se o leilão ainda estiver na versão lida
+	Version int
+
+	// WinnerResolutionPending indica um leilão fechado cuja reserva ainda não
+	// foi verificada porque os lances estavam indisponíveis no fechamento
+	WinnerResolutionPending bool
+
+	// WebhookURL recebe um POST com o resultado quando o leilão fecha; vazio
+	// desliga a notificação
+	WebhookURL string
+
+	// Images são as URLs das fotos do produto, na ordem de exibição
+	Images []string
+
+	// WinnerUserId e WinningAmount são gravados no fechamento com o maior
+	// lance; ficam vazios quando ninguém deu lance, caso em que NoBids é true,
+	// ou quando a reserva não foi atingida
+	WinnerUserId  string
+	WinningAmount bid_entity.Amount
+	NoBids        bool
+}
+
+// AuctionWithBids é um leilão junto com os lances que recebeu, em ordem de
+// chegada.
+type AuctionWithBids struct {
+	Auction Auction
+	Bids    []bid_entity.Bid
+}
+
+// AuctionTransition é uma mudança de status registrada na auditoria. From é
+// nil na criação do leilão.
+type AuctionTransition struct {
+	AuctionId string
+	From      *AuctionStatus
+	To        AuctionStatus
+	Actor     string
+	Reason    string
+	Timestamp time.Time
+}
+
+// AuctionPatch reúne os campos editáveis de um leilão; campos nil não são
+// alterados. Status e Timestamp ficam de fora de propósito.
+type AuctionPatch struct {
+	ProductName *string
+	Category    *string
+	Description *string
+	Condition   *ProductCondition
+}
+
+// AuctionFilter restringe as buscas paginadas; campos vazios não filtram.
+type AuctionFilter struct {
+	Status      *AuctionStatus
+	Category    string
+	ProductName string
+}
+
+// AuctionCursor aponta para o último leilão de uma página. A próxima página
+// começa logo depois dele na ordem (timestamp, id).
+type AuctionCursor struct {
+	Timestamp int64
+	Id        string
+}
+
+// AuctionPage é uma página numerada de leilões. Total fica nil quando a
+// contagem foi dispensada; HasNext é calculado mesmo assim.
+type AuctionPage struct {
+	Items   []Auction
+	Total   *int64
+	Page    int64
+	Limit   int64
+	HasNext bool
+}
+
+// SellerStats resume os leilões concluídos de um vendedor. Sold conta os que
+// tiveram vencedor; Unsold, os sem lances ou com a reserva não atingida.
+// TotalValue soma os lances vencedores por moeda, já que leilões em moedas
+// diferentes não podem ser somados.
+type SellerStats struct {
+	Sold       int64
+	Unsold     int64
+	TotalValue map[string]bid_entity.Amount
 }
 
 type ProductCondition int
 type AuctionStatus int
+type AuctionType int
 
 const (
 	Active AuctionStatus = iota
 	Completed
+	Cancelled
+	Scheduled
+)
+
+const (
+	Open AuctionType = iota
+	Sealed
 )
 
 const (
@@ -66,13 +329,23 @@ const (
 type AuctionRepositoryInterface interface {
 	CreateAuction(
 		ctx context.Context,
-		auctionEntity *Auction) *internal_error.InternalError
+		auctionEntity *Auction) (*Auction, *internal_error.InternalError)
 
 	FindAuctions(
 		ctx context.Context,
-		status AuctionStatus,
-		category, productName string) ([]Auction, *internal_error.InternalError)
+		status *AuctionStatus,
+		category, productName string,
+		page, limit int64) ([]Auction, *internal_error.InternalError)
 
 	FindAuctionById(
 		ctx context.Context, id string) (*Auction, *internal_error.InternalError)
+
+	DeleteAuction(
+		ctx context.Context, id string) *internal_error.InternalError
+
+	FindStaleActiveAuctions(
+		ctx context.Context) ([]Auction, *internal_error.InternalError)
+
+	CancelAuction(
+		ctx context.Context, id string, reason string) *internal_error.InternalError
 }
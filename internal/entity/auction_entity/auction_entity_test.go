@@ -0,0 +1,486 @@
+package auction_entity
+
+import (
+	"fullcycle-auction_go/internal/internal_error"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateAuction(t *testing.T) {
+	validDescription := "Produto em perfeito estado"
+
+	testCases := []struct {
+		name            string
+		userId          string
+		productName     string
+		category        string
+		description     string
+		condition       ProductCondition
+		reservePrice    float64
+		buyNowPrice     float64
+		currency        string
+		webhookURL      string
+		expectedMessage string
+	}{
+		{
+			name:            "empty product name",
+			productName:     "",
+			category:        "Eletrônicos",
+			description:     validDescription,
+			condition:       New,
+			expectedMessage: "ProductName is required",
+		},
+		{
+			name:            "blank product name",
+			productName:     "   ",
+			category:        "Eletrônicos",
+			description:     validDescription,
+			condition:       New,
+			expectedMessage: "ProductName is required",
+		},
+		{
+			name:            "short category",
+			productName:     "iPhone",
+			category:        "El",
+			description:     validDescription,
+			condition:       New,
+			expectedMessage: "Category must have more than 2 characters",
+		},
+		{
+			name:            "short description",
+			productName:     "iPhone",
+			category:        "Eletrônicos",
+			description:     "Curta",
+			condition:       New,
+			expectedMessage: "Description must have at least 10 characters",
+		},
+		{
+			name:            "zero condition",
+			productName:     "iPhone",
+			category:        "Eletrônicos",
+			description:     validDescription,
+			condition:       0,
+			expectedMessage: "Condition is not a valid value",
+		},
+		{
+			name:            "unknown condition",
+			productName:     "iPhone",
+			category:        "Eletrônicos",
+			description:     validDescription,
+			condition:       Refurbished + 1,
+			expectedMessage: "Condition is not a valid value",
+		},
+		{
+			name:            "invalid user id",
+			userId:          "not-a-uuid",
+			productName:     "iPhone",
+			category:        "Eletrônicos",
+			description:     validDescription,
+			condition:       New,
+			expectedMessage: "UserId is not a valid id",
+		},
+		{
+			name:            "negative reserve price",
+			productName:     "iPhone",
+			category:        "Eletrônicos",
+			description:     validDescription,
+			condition:       New,
+			reservePrice:    -1,
+			expectedMessage: "ReservePrice must not be negative",
+		},
+		{
+			name:            "negative buy now price",
+			productName:     "iPhone",
+			category:        "Eletrônicos",
+			description:     validDescription,
+			condition:       New,
+			buyNowPrice:     -1,
+			expectedMessage: "BuyNowPrice must not be negative",
+		},
+		{
+			name:            "buy now price below the reserve",
+			productName:     "iPhone",
+			category:        "Eletrônicos",
+			description:     validDescription,
+			condition:       New,
+			reservePrice:    500,
+			buyNowPrice:     400,
+			expectedMessage: "BuyNowPrice must not be lower than ReservePrice",
+		},
+		{
+			// 2 caracteres, 4 bytes
+			name:            "short multibyte category",
+			productName:     "iPhone",
+			category:        "Áé",
+			description:     validDescription,
+			condition:       New,
+			expectedMessage: "Category must have more than 2 characters",
+		},
+		{
+			// 9 caracteres, 10 bytes
+			name:            "short multibyte description",
+			productName:     "iPhone",
+			category:        "Eletrônicos",
+			description:     "Relógio é",
+			condition:       New,
+			expectedMessage: "Description must have at least 10 characters",
+		},
+		{
+			name:            "invalid utf-8",
+			productName:     "iPhone\xff",
+			category:        "Eletrônicos",
+			description:     validDescription,
+			condition:       New,
+			expectedMessage: "Text fields must be valid UTF-8",
+		},
+		{
+			name:            "invalid currency",
+			productName:     "iPhone",
+			category:        "Eletrônicos",
+			description:     validDescription,
+			condition:       New,
+			currency:        "DOLLAR",
+			expectedMessage: "Currency must be an ISO 4217 code",
+		},
+		{
+			name:            "relative webhook url",
+			productName:     "iPhone",
+			category:        "Eletrônicos",
+			description:     validDescription,
+			condition:       New,
+			webhookURL:      "/hooks/auction",
+			expectedMessage: "WebhookURL must be an absolute http or https URL",
+		},
+		{
+			name:            "non http webhook url",
+			productName:     "iPhone",
+			category:        "Eletrônicos",
+			description:     validDescription,
+			condition:       New,
+			webhookURL:      "ftp://example.com/hooks",
+			expectedMessage: "WebhookURL must be an absolute http or https URL",
+		},
+		{
+			name:            "loopback webhook url",
+			productName:     "iPhone",
+			category:        "Eletrônicos",
+			description:     validDescription,
+			condition:       New,
+			webhookURL:      "http://127.0.0.1:8080/admin",
+			expectedMessage: "WebhookURL must not point to an internal address",
+		},
+		{
+			name:            "link-local webhook url",
+			productName:     "iPhone",
+			category:        "Eletrônicos",
+			description:     validDescription,
+			condition:       New,
+			webhookURL:      "http://169.254.169.254/latest/meta-data",
+			expectedMessage: "WebhookURL must not point to an internal address",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			auction, err := CreateAuction(AuctionInput{
+				UserId:       tc.userId,
+				ProductName:  tc.productName,
+				Category:     tc.category,
+				Description:  tc.description,
+				Condition:    tc.condition,
+				ReservePrice: tc.reservePrice,
+				BuyNowPrice:  tc.buyNowPrice,
+				Currency:     tc.currency,
+				WebhookURL:   tc.webhookURL,
+			})
+			require.Nil(t, auction)
+			require.NotNil(t, err)
+			require.Equal(t, "bad_request", err.Err)
+			require.Equal(t, tc.expectedMessage, err.Message)
+		})
+	}
+
+	t.Run("valid auction", func(t *testing.T) {
+		userId := uuid.New().String()
+		auction, err := CreateAuction(AuctionInput{
+			UserId:      userId,
+			ProductName: "iPhone",
+			Category:    "Eletrônicos",
+			Description: strings.Repeat("a", 10),
+			Condition:   Used,
+			Currency:    "usd",
+			WebhookURL:  "https://seller.example.com/hooks/auction",
+		})
+		require.Nil(t, err)
+		require.NotEmpty(t, auction.Id)
+		require.Equal(t, "https://seller.example.com/hooks/auction", auction.WebhookURL)
+		require.Equal(t, "USD", auction.Currency)
+		require.Equal(t, userId, auction.UserId)
+		require.Equal(t, Active, auction.Status)
+		require.False(t, auction.Timestamp.IsZero())
+		require.Equal(t, auction.Timestamp, auction.StartsAt)
+	})
+
+	t.Run("scheduled auction", func(t *testing.T) {
+		startsAt := time.Now().Add(time.Hour)
+		auction, err := CreateAuction(AuctionInput{
+			ProductName: "iPhone",
+			Category:    "Eletrônicos",
+			Description: strings.Repeat("a", 10),
+			Condition:   Used,
+			StartsAt:    startsAt,
+		})
+		require.Nil(t, err)
+		require.Equal(t, Scheduled, auction.Status)
+		require.Equal(t, startsAt, auction.StartsAt)
+	})
+
+	t.Run("start in the past opens immediately", func(t *testing.T) {
+		auction, err := CreateAuction(AuctionInput{
+			ProductName: "iPhone",
+			Category:    "Eletrônicos",
+			Description: strings.Repeat("a", 10),
+			Condition:   Used,
+			StartsAt:    time.Now().Add(-time.Hour),
+		})
+		require.Nil(t, err)
+		require.Equal(t, Active, auction.Status)
+		require.Equal(t, auction.Timestamp, auction.StartsAt)
+	})
+}
+
+func FuzzCreateAuctionValidation(f *testing.F) {
+	f.Add("iPhone", "Eletrônicos", "Produto em perfeito estado")
+	f.Add("", "", "")
+	f.Add("   ", "El", "Curta")
+	f.Add("Relógio", "Áé", "Relógio é")
+	f.Add("📱📱📱", "📱📱📱", "📱📱📱📱📱📱📱📱📱📱")
+	f.Add("nome\x00com\ncontrole", "cat\tegoria", "descrição\r\ncom controle")
+	f.Add("\xff\xfe", "\xc3", "descrição truncada \xe2\x82")
+	f.Add(strings.Repeat("ç", 10_000), strings.Repeat("ã", 10_000), strings.Repeat("€", 10_000))
+
+	f.Fuzz(func(t *testing.T, productName, category, description string) {
+		auction, err := CreateAuction(AuctionInput{
+			ProductName: productName,
+			Category:    category,
+			Description: description,
+			Condition:   New,
+		})
+
+		if err != nil {
+			require.Nil(t, auction)
+			require.Equal(t, "bad_request", err.Err)
+			require.NotEmpty(t, err.Message)
+			return
+		}
+
+		require.NotNil(t, auction)
+		require.Nil(t, auction.Validate())
+		require.Equal(t, productName, auction.ProductName)
+		require.Equal(t, category, auction.Category)
+		require.Equal(t, description, auction.Description)
+		require.True(t, utf8.ValidString(productName+category+description))
+		require.Greater(t, utf8.RuneCountInString(category), 2)
+		require.GreaterOrEqual(t, utf8.RuneCountInString(description), minDescriptionLength)
+	})
+}
+
+func TestTimeRemaining(t *testing.T) {
+	now := time.Now()
+
+	testCases := []struct {
+		name     string
+		status   AuctionStatus
+		expires  time.Time
+		expected time.Duration
+	}{
+		{name: "active", status: Active, expires: now.Add(90 * time.Second), expected: 90 * time.Second},
+		{name: "active past expiry", status: Active, expires: now.Add(-time.Second), expected: 0},
+		{name: "completed", status: Completed, expires: now.Add(time.Hour), expected: 0},
+		{name: "cancelled", status: Cancelled, expires: now.Add(time.Hour), expected: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			auction := &Auction{Status: tc.status, ExpiresAt: tc.expires}
+			require.Equal(t, tc.expected, auction.TimeRemaining(now))
+		})
+	}
+}
+
+func TestRulesDescriptionMaxLen(t *testing.T) {
+	rules := Rules{MaxDescriptionLen: 20, MaxImages: DefaultMaxImages}
+
+	createWithDescription := func(rules Rules, description string) (*Auction, *internal_error.InternalError) {
+		auction, err := CreateAuction(AuctionInput{
+			ProductName: "iPhone",
+			Category:    "Eletrônicos",
+			Description: rules.TruncatedDescription(description),
+			Condition:   New,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return auction, rules.Check(auction)
+	}
+
+	t.Run("at the limit", func(t *testing.T) {
+		description := strings.Repeat("ç", 20)
+		auction, err := createWithDescription(rules, description)
+		require.Nil(t, err)
+		require.Equal(t, description, auction.Description)
+	})
+
+	t.Run("rejects longer descriptions", func(t *testing.T) {
+		// 21 caracteres e 42 bytes: o limite conta caracteres
+		_, err := createWithDescription(rules, strings.Repeat("ç", 21))
+		require.NotNil(t, err)
+		require.Equal(t, "bad_request", err.Err)
+		require.Equal(t, "Description must have at most 20 characters", err.Message)
+	})
+
+	t.Run("truncates with an ellipsis", func(t *testing.T) {
+		truncating := rules
+		truncating.TruncateDescription = true
+
+		auction, err := createWithDescription(truncating, strings.Repeat("📱", 50))
+		require.Nil(t, err)
+		require.Equal(t, strings.Repeat("📱", 19)+"…", auction.Description)
+		require.Equal(t, 20, utf8.RuneCountInString(auction.Description))
+	})
+
+	t.Run("invalid limit uses the default", func(t *testing.T) {
+		invalid := Rules{MaxDescriptionLen: 3}
+
+		_, err := createWithDescription(invalid, strings.Repeat("a", DefaultMaxDescriptionLen))
+		require.Nil(t, err)
+
+		_, err = createWithDescription(invalid, strings.Repeat("a", DefaultMaxDescriptionLen+1))
+		require.NotNil(t, err)
+	})
+
+	t.Run("validate ignores the configured limit", func(t *testing.T) {
+		// Um leilão gravado antes de o limite baixar continua válido
+		auction := &Auction{
+			ProductName: "iPhone",
+			Category:    "Eletrônicos",
+			Description: strings.Repeat("a", 100),
+			Condition:   New,
+			Currency:    "BRL",
+		}
+		require.Nil(t, auction.Validate())
+
+		category := "Celulares"
+		_, err := rules.PreparePatch(AuctionPatch{Category: &category})
+		require.Nil(t, err)
+	})
+
+	t.Run("patch is truncated and checked", func(t *testing.T) {
+		truncating := rules
+		truncating.TruncateDescription = true
+
+		description := strings.Repeat("a", 30)
+		patch, err := truncating.PreparePatch(AuctionPatch{Description: &description})
+		require.Nil(t, err)
+		require.Equal(t, strings.Repeat("a", 19)+"…", *patch.Description)
+
+		_, err = rules.PreparePatch(AuctionPatch{Description: &description})
+		require.NotNil(t, err)
+		require.Equal(t, "Description must have at most 20 characters", err.Message)
+	})
+}
+
+func TestCreateAuctionImages(t *testing.T) {
+	rules := Rules{MaxImages: 3}
+
+	createWithImages := func(images []string) (*Auction, *internal_error.InternalError) {
+		auction, err := CreateAuction(AuctionInput{
+			ProductName: "iPhone",
+			Category:    "Eletrônicos",
+			Description: "Produto em perfeito estado",
+			Condition:   New,
+			Images:      images,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := rules.Check(auction); err != nil {
+			return nil, err
+		}
+		return auction, nil
+	}
+
+	t.Run("valid urls at the limit", func(t *testing.T) {
+		images := []string{
+			"https://cdn.example.com/iphone/front.jpg",
+			"https://cdn.example.com/iphone/back.jpg",
+			"http://cdn.example.com/iphone/box.png?size=large",
+		}
+		auction, err := createWithImages(images)
+		require.Nil(t, err)
+		require.Equal(t, images, auction.Images)
+	})
+
+	t.Run("more images than the limit", func(t *testing.T) {
+		images := []string{
+			"https://cdn.example.com/1.jpg",
+			"https://cdn.example.com/2.jpg",
+			"https://cdn.example.com/3.jpg",
+			"https://cdn.example.com/4.jpg",
+		}
+		auction, err := createWithImages(images)
+		require.Nil(t, auction)
+		require.NotNil(t, err)
+		require.Equal(t, "Images must have at most 3 items", err.Message)
+	})
+
+	for _, image := range []string{"", "cdn.example.com/1.jpg", "/images/1.jpg", "ftp://cdn.example.com/1.jpg", "https://", "http://[::1"} {
+		t.Run("rejects "+image, func(t *testing.T) {
+			auction, err := createWithImages([]string{"https://cdn.example.com/1.jpg", image})
+			require.Nil(t, auction)
+			require.NotNil(t, err)
+			require.Equal(t, "bad_request", err.Err)
+			require.Equal(t, "Images must be absolute http or https URLs", err.Message)
+		})
+	}
+
+	t.Run("without images", func(t *testing.T) {
+		auction, err := createWithImages(nil)
+		require.Nil(t, err)
+		require.Empty(t, auction.Images)
+	})
+}
+
+func TestSealedAuctionBidsHidden(t *testing.T) {
+	auction, err := CreateAuction(AuctionInput{
+		ProductName: "iPhone",
+		Category:    "Eletrônicos",
+		Description: "Produto em perfeito estado",
+		Condition:   New,
+		Type:        Sealed,
+	})
+	require.Nil(t, err)
+	require.Equal(t, Sealed, auction.Type)
+	require.True(t, auction.BidsHidden())
+
+	auction.Status = Completed
+	require.False(t, auction.BidsHidden())
+
+	open := Auction{Type: Open, Status: Active}
+	require.False(t, open.BidsHidden())
+
+	_, err = CreateAuction(AuctionInput{
+		ProductName: "iPhone",
+		Category:    "Eletrônicos",
+		Description: "Produto em perfeito estado",
+		Condition:   New,
+		Type:        AuctionType(5),
+	})
+	require.NotNil(t, err)
+	require.Equal(t, "Type is not a valid value", err.Message)
+}
@@ -0,0 +1,85 @@
+package auction_entity
+
+import (
+	"fmt"
+	"fullcycle-auction_go/internal/internal_error"
+	"strings"
+)
+
+var auctionStatusNames = map[AuctionStatus]string{
+	Active:    "Active",
+	Completed: "Completed",
+	Cancelled: "Cancelled",
+	Scheduled: "Scheduled",
+}
+
+var productConditionNames = map[ProductCondition]string{
+	New:         "New",
+	Used:        "Used",
+	Refurbished: "Refurbished",
+}
+
+var auctionTypeNames = map[AuctionType]string{
+	Open:   "Open",
+	Sealed: "Sealed",
+}
+
+func (as AuctionStatus) String() string {
+	if name, ok := auctionStatusNames[as]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("AuctionStatus(%d)", int(as))
+}
+
+func (pc ProductCondition) String() string {
+	if name, ok := productConditionNames[pc]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("ProductCondition(%d)", int(pc))
+}
+
+func (at AuctionType) String() string {
+	if name, ok := auctionTypeNames[at]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("AuctionType(%d)", int(at))
+}
+
+// ParseAuctionStatus converte o nome de um status, sem diferenciar
+// maiúsculas, no valor do enum.
+func ParseAuctionStatus(value string) (AuctionStatus, *internal_error.InternalError) {
+	for status, name := range auctionStatusNames {
+		if strings.EqualFold(name, value) {
+			return status, nil
+		}
+	}
+
+	return 0, internal_error.NewBadRequestError(fmt.Sprintf("invalid auction status %q", value))
+}
+
+// ParseProductCondition converte o nome de uma condição, sem diferenciar
+// maiúsculas, no valor do enum.
+func ParseProductCondition(value string) (ProductCondition, *internal_error.InternalError) {
+	for condition, name := range productConditionNames {
+		if strings.EqualFold(name, value) {
+			return condition, nil
+		}
+	}
+
+	return 0, internal_error.NewBadRequestError(fmt.Sprintf("invalid product condition %q", value))
+}
+
+// ParseAuctionType converte o nome de um tipo de leilão, sem diferenciar
+// maiúsculas, no valor do enum.
+func ParseAuctionType(value string) (AuctionType, *internal_error.InternalError) {
+	for auctionType, name := range auctionTypeNames {
+		if strings.EqualFold(name, value) {
+			return auctionType, nil
+		}
+	}
+
+	return 0, internal_error.NewBadRequestError(fmt.Sprintf("invalid auction type %q", value))
+}
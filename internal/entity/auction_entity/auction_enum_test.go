@@ -0,0 +1,102 @@
+package auction_entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuctionStatusString(t *testing.T) {
+	testCases := []struct {
+		status   AuctionStatus
+		expected string
+	}{
+		{status: Active, expected: "Active"},
+		{status: Completed, expected: "Completed"},
+		{status: Cancelled, expected: "Cancelled"},
+		{status: Scheduled, expected: "Scheduled"},
+		{status: AuctionStatus(42), expected: "AuctionStatus(42)"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expected, func(t *testing.T) {
+			require.Equal(t, tc.expected, tc.status.String())
+		})
+	}
+}
+
+func TestProductConditionString(t *testing.T) {
+	testCases := []struct {
+		condition ProductCondition
+		expected  string
+	}{
+		{condition: New, expected: "New"},
+		{condition: Used, expected: "Used"},
+		{condition: Refurbished, expected: "Refurbished"},
+		{condition: ProductCondition(0), expected: "ProductCondition(0)"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expected, func(t *testing.T) {
+			require.Equal(t, tc.expected, tc.condition.String())
+		})
+	}
+}
+
+func TestParseAuctionStatus(t *testing.T) {
+	for _, status := range []AuctionStatus{Active, Completed, Cancelled, Scheduled} {
+		t.Run(status.String(), func(t *testing.T) {
+			parsed, err := ParseAuctionStatus(status.String())
+			require.Nil(t, err)
+			require.Equal(t, status, parsed)
+		})
+	}
+
+	parsed, err := ParseAuctionStatus("completed")
+	require.Nil(t, err)
+	require.Equal(t, Completed, parsed)
+
+	_, err = ParseAuctionStatus("Closed")
+	require.NotNil(t, err)
+	require.Equal(t, "bad_request", err.Err)
+	require.Equal(t, `invalid auction status "Closed"`, err.Message)
+}
+
+func TestParseProductCondition(t *testing.T) {
+	for _, condition := range []ProductCondition{New, Used, Refurbished} {
+		t.Run(condition.String(), func(t *testing.T) {
+			parsed, err := ParseProductCondition(condition.String())
+			require.Nil(t, err)
+			require.Equal(t, condition, parsed)
+		})
+	}
+
+	parsed, err := ParseProductCondition("used")
+	require.Nil(t, err)
+	require.Equal(t, Used, parsed)
+
+	_, err = ParseProductCondition("")
+	require.NotNil(t, err)
+	require.Equal(t, "bad_request", err.Err)
+	require.Equal(t, `invalid product condition ""`, err.Message)
+}
+
+func TestParseAuctionType(t *testing.T) {
+	for _, auctionType := range []AuctionType{Open, Sealed} {
+		t.Run(auctionType.String(), func(t *testing.T) {
+			parsed, err := ParseAuctionType(auctionType.String())
+			require.Nil(t, err)
+			require.Equal(t, auctionType, parsed)
+		})
+	}
+
+	parsed, err := ParseAuctionType("sealed")
+	require.Nil(t, err)
+	require.Equal(t, Sealed, parsed)
+	require.Equal(t, "AuctionType(7)", AuctionType(7).String())
+
+	_, err = ParseAuctionType("Dutch")
+	require.NotNil(t, err)
+	require.Equal(t, "bad_request", err.Err)
+	require.Equal(t, `invalid auction type "Dutch"`, err.Message)
+}
@@ -0,0 +1,76 @@
+package auction_entity
+
+import (
+	"fullcycle-auction_go/internal/internal_error"
+	"strings"
+	"unicode"
+)
+
+// Blocklist guarda as entradas bloqueadas já separadas em palavras
+// minúsculas. A lista vazia não bloqueia nada.
+type Blocklist [][]string
+
+// NewBlocklist monta a blocklist a partir das entradas, uma palavra ou
+// expressão cada; entradas sem palavras são ignoradas.
+func NewBlocklist(entries []string) Blocklist {
+	var blocked Blocklist
+	for _, entry := range entries {
+		if words := splitWords(entry); len(words) > 0 {
+			blocked = append(blocked, words)
+		}
+	}
+
+	return blocked
+}
+
+// Check recusa nome ou descrição que contém uma palavra da blocklist. A
+// comparação é por palavra inteira e sem diferenciar maiúsculas: "ass"
+// bloqueia "ASS", mas não "class".
+func (b Blocklist) Check(productName, description string) *internal_error.InternalError {
+	if len(b) == 0 {
+		return nil
+	}
+
+	if containsBlockedWord(productName, b) {
+		return internal_error.NewBadRequestError("ProductName contains a blocked word")
+	}
+	if containsBlockedWord(description, b) {
+		return internal_error.NewBadRequestError("Description contains a blocked word")
+	}
+
+	return nil
+}
+
+// containsBlockedWord procura cada entrada da blocklist como uma sequência de
+// palavras do texto, para que expressões com mais de uma palavra também sejam
+// encontradas.
+func containsBlockedWord(text string, blocked Blocklist) bool {
+	words := splitWords(text)
+	for _, entry := range blocked {
+		for i := 0; i+len(entry) <= len(words); i++ {
+			if equalWords(words[i:i+len(entry)], entry) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func equalWords(words, entry []string) bool {
+	for i := range entry {
+		if words[i] != entry[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// splitWords separa o texto em palavras minúsculas; letras acentuadas contam
+// como parte da palavra.
+func splitWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
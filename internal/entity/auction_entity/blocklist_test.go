@@ -0,0 +1,79 @@
+package auction_entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRulesBlocklist(t *testing.T) {
+	rules := Rules{Blocklist: NewBlocklist([]string{"ass", " golpe ", "Pirata"})}
+
+	testCases := []struct {
+		name            string
+		productName     string
+		description     string
+		expectedMessage string
+	}{
+		{name: "blocked product name", productName: "Pirata edition", description: "Produto em perfeito estado", expectedMessage: "ProductName contains a blocked word"},
+		{name: "blocked description", productName: "iPhone", description: "Isso não é um golpe, confie", expectedMessage: "Description contains a blocked word"},
+		{name: "upper case", productName: "iPhone", description: "Produto GOLPE garantido", expectedMessage: "Description contains a blocked word"},
+		{name: "mixed case", productName: "PiRaTa", description: "Produto em perfeito estado", expectedMessage: "ProductName contains a blocked word"},
+		{name: "next to punctuation", productName: "iPhone", description: "Produto novo (ass.)", expectedMessage: "Description contains a blocked word"},
+		{name: "benign substring", productName: "Classic bass guitar", description: "Passou por revisão, sem golpes"},
+		{name: "accented word", productName: "iPhone", description: "Produto assíduo em bom estado"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			auction, err := CreateAuction(AuctionInput{
+				ProductName: tc.productName,
+				Category:    "Eletrônicos",
+				Description: tc.description,
+				Condition:   New,
+			})
+			require.Nil(t, err)
+
+			err = rules.Check(auction)
+			if tc.expectedMessage == "" {
+				require.Nil(t, err)
+				return
+			}
+
+			require.NotNil(t, err)
+			require.Equal(t, "bad_request", err.Err)
+			require.Equal(t, tc.expectedMessage, err.Message)
+		})
+	}
+}
+
+func TestRulesBlocklistExpression(t *testing.T) {
+	rules := Rules{Blocklist: NewBlocklist([]string{"réplica", "", "não original"})}
+
+	require.NotNil(t, rules.Blocklist.Check("Relógio RÉPLICA", "Produto em perfeito estado"))
+	require.NotNil(t, rules.Blocklist.Check("Relógio", "Produto Não Original, sem caixa"))
+
+	// Palavras soltas de uma expressão não bloqueiam
+	require.Nil(t, rules.Blocklist.Check("Relógio", "Produto original, não usado"))
+
+	require.Nil(t, Rules{}.Blocklist.Check("Relógio RÉPLICA", "Produto em perfeito estado"))
+}
+
+func TestRulesPreparePatchBlocklist(t *testing.T) {
+	rules := Rules{Blocklist: NewBlocklist([]string{"golpe"})}
+
+	description := "Não é golpe"
+	_, err := rules.PreparePatch(AuctionPatch{Description: &description})
+	require.NotNil(t, err)
+	require.Equal(t, "Description contains a blocked word", err.Message)
+
+	productName := "Golpe"
+	_, err = rules.PreparePatch(AuctionPatch{ProductName: &productName})
+	require.NotNil(t, err)
+	require.Equal(t, "ProductName contains a blocked word", err.Message)
+
+	category := "Eletrônicos"
+	patch, err := rules.PreparePatch(AuctionPatch{Category: &category})
+	require.Nil(t, err)
+	require.Equal(t, &category, patch.Category)
+}
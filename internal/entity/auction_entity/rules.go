@@ -0,0 +1,108 @@
+package auction_entity
+
+import (
+	"fmt"
+	"fullcycle-auction_go/internal/internal_error"
+	"unicode/utf8"
+)
+
+const (
+	DefaultMaxDescriptionLen = 2000
+	DefaultMaxImages         = 8
+	descriptionEllipsis      = "…"
+)
+
+// Rules reúne as regras configuráveis de um leilão. São lidas uma vez na
+// inicialização e valem tanto para a criação quanto para a edição; o Validate
+// fica só com as regras fixas, para que uma mudança de configuração não
+// invalide os leilões já gravados.
+type Rules struct {
+	// MaxDescriptionLen conta caracteres; valores menores que o mínimo de
+	// caracteres da descrição usam DefaultMaxDescriptionLen
+	MaxDescriptionLen int
+
+	// TruncateDescription corta descrições longas com reticências em vez de
+	// recusá-las
+	TruncateDescription bool
+
+	MaxImages int
+	Blocklist Blocklist
+}
+
+func DefaultRules() Rules {
+	return Rules{
+		MaxDescriptionLen: DefaultMaxDescriptionLen,
+		MaxImages:         DefaultMaxImages,
+	}
+}
+
+// Check aplica as regras a um leilão novo
+func (r Rules) Check(auction *Auction) *internal_error.InternalError {
+	if err := r.checkDescription(auction.Description); err != nil {
+		return err
+	}
+
+	if len(auction.Images) > r.MaxImages {
+		return internal_error.NewBadRequestError(
+			fmt.Sprintf("Images must have at most %d items", r.MaxImages))
+	}
+
+	return r.Blocklist.Check(auction.ProductName, auction.Description)
+}
+
+// PreparePatch aplica as regras aos campos alterados pela edição, cortando a
+// descrição como na criação; os campos que não mudam não são conferidos de
+// novo.
+func (r Rules) PreparePatch(patch AuctionPatch) (AuctionPatch, *internal_error.InternalError) {
+	var productName, description string
+	if patch.ProductName != nil {
+		productName = *patch.ProductName
+	}
+	if patch.Description != nil {
+		description = r.TruncatedDescription(*patch.Description)
+		if err := r.checkDescription(description); err != nil {
+			return AuctionPatch{}, err
+		}
+		patch.Description = &description
+	}
+
+	if err := r.Blocklist.Check(productName, description); err != nil {
+		return AuctionPatch{}, err
+	}
+
+	return patch, nil
+}
+
+// TruncatedDescription corta a descrição no máximo de caracteres,
+// terminando com reticências, quando TruncateDescription está ligado.
+// Desligado, a descrição longa é recusada pelo Check.
+func (r Rules) TruncatedDescription(description string) string {
+	if !r.TruncateDescription || !utf8.ValidString(description) {
+		return description
+	}
+
+	maxLen := r.maxDescriptionLen()
+	if utf8.RuneCountInString(description) <= maxLen {
+		return description
+	}
+
+	runes := []rune(description)
+	return string(runes[:maxLen-utf8.RuneCountInString(descriptionEllipsis)]) + descriptionEllipsis
+}
+
+func (r Rules) checkDescription(description string) *internal_error.InternalError {
+	if maxLen := r.maxDescriptionLen(); utf8.RuneCountInString(description) > maxLen {
+		return internal_error.NewBadRequestError(
+			fmt.Sprintf("Description must have at most %d characters", maxLen))
+	}
+
+	return nil
+}
+
+func (r Rules) maxDescriptionLen() int {
+	if r.MaxDescriptionLen < minDescriptionLength {
+		return DefaultMaxDescriptionLen
+	}
+
+	return r.MaxDescriptionLen
+}
@@ -0,0 +1,131 @@
+package bid_entity
+
+import (
+	"fmt"
+	"fullcycle-auction_go/internal/internal_error"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// AmountScale é o número de casas decimais aceitas nos valores dos lances
+const AmountScale = 2
+
+const amountUnit = 100
+
+// Amount é um valor monetário exato, guardado em centavos. Somas e
+// comparações não acumulam erro de arredondamento como em float64:
+// 0.1 + 0.2 é exatamente 0.3.
+type Amount struct {
+	cents int64
+}
+
+// AmountFromCents cria um valor a partir da quantidade de centavos
+func AmountFromCents(cents int64) Amount {
+	return Amount{cents: cents}
+}
+
+// ParseAmount converte um valor decimal como "150.5". Valores com mais de
+// AmountScale casas decimais são recusados em vez de arredondados.
+func ParseAmount(value string) (Amount, *internal_error.InternalError) {
+	invalid := internal_error.NewBadRequestError("Amount is not a valid value")
+
+	digits := strings.TrimPrefix(value, "-")
+	negative := len(digits) < len(value)
+
+	integerPart, fractionPart, _ := strings.Cut(digits, ".")
+	if integerPart == "" || !isDigits(integerPart) || !isDigits(fractionPart) {
+		return Amount{}, invalid
+	}
+
+	if len(fractionPart) > AmountScale {
+		return Amount{}, internal_error.NewBadRequestError(
+			fmt.Sprintf("Amount must have at most %d decimal places", AmountScale))
+	}
+	fractionPart += strings.Repeat("0", AmountScale-len(fractionPart))
+
+	cents, err := strconv.ParseInt(integerPart+fractionPart, 10, 64)
+	if err != nil {
+		return Amount{}, invalid
+	}
+
+	if negative {
+		cents = -cents
+	}
+
+	return Amount{cents: cents}, nil
+}
+
+// NewAmount converte um float64 pela sua menor representação decimal, então
+// 0.1 vira exatamente 0.10 e não 0.1000000000000000055511151231257827.
+func NewAmount(value float64) (Amount, *internal_error.InternalError) {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return Amount{}, internal_error.NewBadRequestError("Amount is not a valid value")
+	}
+
+	return ParseAmount(strconv.FormatFloat(value, 'f', -1, 64))
+}
+
+func (a Amount) Cents() int64 {
+	return a.cents
+}
+
+func (a Amount) Add(other Amount) Amount {
+	return Amount{cents: a.cents + other.cents}
+}
+
+// Cmp retorna -1, 0 ou 1 quando a é menor, igual ou maior que other
+func (a Amount) Cmp(other Amount) int {
+	switch {
+	case a.cents < other.cents:
+		return -1
+	case a.cents > other.cents:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Reaches diz se o valor alcança um preço do leilão, como a reserva ou o
+// compre já. Preços com mais casas do que um lance aceita são comparados como
+// float64.
+func (a Amount) Reaches(price float64) bool {
+	priceAmount, err := NewAmount(price)
+	if err != nil {
+		return a.Float64() >= price
+	}
+
+	return a.Cmp(priceAmount) >= 0
+}
+
+func (a Amount) IsPositive() bool {
+	return a.cents > 0
+}
+
+// Float64 é usado só na saída para JSON; comparações devem usar Cmp
+func (a Amount) Float64() float64 {
+	value, _ := strconv.ParseFloat(a.String(), 64)
+	return value
+}
+
+// String formata o valor sempre com AmountScale casas, como "0.30"
+func (a Amount) String() string {
+	cents := a.cents
+	sign := ""
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+
+	return fmt.Sprintf("%s%d.%0*d", sign, cents/amountUnit, AmountScale, cents%amountUnit)
+}
+
+func isDigits(value string) bool {
+	for _, char := range value {
+		if char < '0' || char > '9' {
+			return false
+		}
+	}
+
+	return true
+}
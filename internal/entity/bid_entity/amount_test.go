@@ -0,0 +1,79 @@
+package bid_entity
+
+import (
+	"math"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAmount(t *testing.T) {
+	testCases := []struct {
+		name            string
+		value           float64
+		expectedCents   int64
+		expectedMessage string
+	}{
+		{name: "integer", value: 100, expectedCents: 100_00},
+		{name: "one decimal place", value: 150.5, expectedCents: 150_50},
+		{name: "binary fraction", value: 0.1, expectedCents: 10},
+		{name: "negative", value: -5, expectedCents: -5_00},
+		{name: "too many decimal places", value: 10.005, expectedMessage: "Amount must have at most 2 decimal places"},
+		{name: "NaN", value: math.NaN(), expectedMessage: "Amount is not a valid value"},
+		{name: "infinite", value: math.Inf(1), expectedMessage: "Amount is not a valid value"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			amount, err := NewAmount(tc.value)
+			if tc.expectedMessage != "" {
+				require.NotNil(t, err)
+				require.Equal(t, "bad_request", err.Err)
+				require.Equal(t, tc.expectedMessage, err.Message)
+				return
+			}
+
+			require.Nil(t, err)
+			require.Equal(t, tc.expectedCents, amount.Cents())
+		})
+	}
+}
+
+func TestParseAmount(t *testing.T) {
+	for _, value := range []string{"", "abc", "1.2.3", ".5", "1e3", "--1", "99999999999999999999"} {
+		_, err := ParseAmount(value)
+		require.NotNil(t, err, value)
+	}
+
+	amount, err := ParseAmount("0.30")
+	require.Nil(t, err)
+	require.Equal(t, "0.30", amount.String())
+}
+
+func TestAmountIsExact(t *testing.T) {
+	// Em float64, 0.1 + 0.2 é 0.30000000000000004
+	tenCents, twentyCents := 0.1, 0.2
+	require.NotEqual(t, 0.3, tenCents+twentyCents)
+
+	first, err := NewAmount(0.1)
+	require.Nil(t, err)
+	second, err := NewAmount(0.2)
+	require.Nil(t, err)
+	expected, err := NewAmount(0.3)
+	require.Nil(t, err)
+
+	sum := first.Add(second)
+	require.Equal(t, 0, sum.Cmp(expected))
+	require.Equal(t, expected, sum)
+	require.Equal(t, "0.30", sum.String())
+	require.Equal(t, 0.3, sum.Float64())
+
+	amounts := []Amount{AmountFromCents(30), sum, AmountFromCents(29), AmountFromCents(31)}
+	sort.SliceStable(amounts, func(i, j int) bool {
+		return amounts[i].Cmp(amounts[j]) < 0
+	})
+	require.Equal(t, []Amount{
+		AmountFromCents(29), AmountFromCents(30), AmountFromCents(30), AmountFromCents(31),
+	}, amounts)
+}
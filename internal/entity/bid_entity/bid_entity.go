@@ -11,17 +11,35 @@ type Bid struct {
 	Id        string
 	UserId    string
 	AuctionId string
-	Amount    float64
+	Amount    Amount
 	Timestamp time.Time
+
+	// Currency é o código ISO 4217 do Amount; precisa ser a moeda do leilão
+	Currency string
+}
+
+// AuctionStatsResult resume os lances de um leilão. Um leilão sem lances tem
+// todos os campos zerados.
+type AuctionStatsResult struct {
+	BidCount        int64
+	MaxAmount       Amount
+	AverageAmount   float64
+	DistinctBidders int64
 }
 
-func CreateBid(userId, auctionId string, amount float64) (*Bid, *internal_error.InternalError) {
+func CreateBid(userId, auctionId string, amount Amount, currency string) (*Bid, *internal_error.InternalError) {
+	currency, err := ParseCurrency(currency)
+	if err != nil {
+		return nil, err
+	}
+
 	bid := &Bid{
 		Id:        uuid.New().String(),
 		UserId:    userId,
 		AuctionId: auctionId,
 		Amount:    amount,
 		Timestamp: time.Now(),
+		Currency:  currency,
 	}
 
 	if err := bid.Validate(); err != nil {
@@ -36,8 +54,10 @@ func (b *Bid) Validate() *internal_error.InternalError {
 		return internal_error.NewBadRequestError("UserId is not a valid id")
 	} else if err := uuid.Validate(b.AuctionId); err != nil {
 		return internal_error.NewBadRequestError("AuctionId is not a valid id")
-	} else if b.Amount <= 0 {
+	} else if !b.Amount.IsPositive() {
 		return internal_error.NewBadRequestError("Amount is not a valid value")
+	} else if _, err := ParseCurrency(b.Currency); err != nil {
+		return err
 	}
 
 	return nil
@@ -49,8 +69,14 @@ type BidEntityRepository interface {
 		bidEntities []Bid) *internal_error.InternalError
 
 	FindBidByAuctionId(
-		ctx context.Context, auctionId string) ([]Bid, *internal_error.InternalError)
+		ctx context.Context, auctionId string, sort string, limit int64) ([]Bid, *internal_error.InternalError)
 
 	FindWinningBidByAuctionId(
 		ctx context.Context, auctionId string) (*Bid, *internal_error.InternalError)
+
+	DeleteBidsByAuctionId(
+		ctx context.Context, auctionId string) (int64, *internal_error.InternalError)
+
+	IsLeadingBidder(
+		ctx context.Context, auctionId, userId string) (bool, *internal_error.InternalError)
 }
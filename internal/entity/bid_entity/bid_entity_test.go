@@ -0,0 +1,60 @@
+package bid_entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateBid(t *testing.T) {
+	validUserId := uuid.New().String()
+	validAuctionId := uuid.New().String()
+
+	testCases := []struct {
+		name            string
+		userId          string
+		auctionId       string
+		amount          Amount
+		currency        string
+		expectedMessage string
+	}{
+		{name: "empty user id", userId: "", auctionId: validAuctionId, amount: AmountFromCents(10_00), expectedMessage: "UserId is not a valid id"},
+		{name: "invalid user id", userId: "user-1", auctionId: validAuctionId, amount: AmountFromCents(10_00), expectedMessage: "UserId is not a valid id"},
+		{name: "empty auction id", userId: validUserId, auctionId: "", amount: AmountFromCents(10_00), expectedMessage: "AuctionId is not a valid id"},
+		{name: "invalid auction id", userId: validUserId, auctionId: "auction-1", amount: AmountFromCents(10_00), expectedMessage: "AuctionId is not a valid id"},
+		{name: "zero amount", userId: validUserId, auctionId: validAuctionId, amount: Amount{}, expectedMessage: "Amount is not a valid value"},
+		{name: "negative amount", userId: validUserId, auctionId: validAuctionId, amount: AmountFromCents(-5_00), expectedMessage: "Amount is not a valid value"},
+		{name: "invalid currency", userId: validUserId, auctionId: validAuctionId, amount: AmountFromCents(10_00), currency: "US$", expectedMessage: "Currency must be an ISO 4217 code"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			bid, err := CreateBid(tc.userId, tc.auctionId, tc.amount, tc.currency)
+			require.Nil(t, bid)
+			require.NotNil(t, err)
+			require.Equal(t, "bad_request", err.Err)
+			require.Equal(t, tc.expectedMessage, err.Message)
+		})
+	}
+
+	t.Run("valid bid", func(t *testing.T) {
+		before := time.Now()
+
+		bid, err := CreateBid(validUserId, validAuctionId, AmountFromCents(150_50), "")
+		require.Nil(t, err)
+		require.NoError(t, uuid.Validate(bid.Id))
+		require.Equal(t, validUserId, bid.UserId)
+		require.Equal(t, validAuctionId, bid.AuctionId)
+		require.Equal(t, AmountFromCents(150_50), bid.Amount)
+		require.False(t, bid.Timestamp.Before(before))
+		require.Equal(t, DefaultCurrency, bid.Currency)
+	})
+
+	t.Run("currency is normalized", func(t *testing.T) {
+		bid, err := CreateBid(validUserId, validAuctionId, AmountFromCents(150_50), " usd")
+		require.Nil(t, err)
+		require.Equal(t, "USD", bid.Currency)
+	})
+}
@@ -0,0 +1,41 @@
+package bid_entity
+
+import (
+	"fullcycle-auction_go/internal/internal_error"
+	"strings"
+)
+
+// DefaultCurrency é a moeda dos leilões e lances criados sem moeda, inclusive
+// os gravados antes do campo existir
+const DefaultCurrency = "BRL"
+
+// ParseCurrency normaliza um código ISO 4217 ("usd" vira "USD"). Vazio
+// significa DefaultCurrency. Só o formato é verificado, três letras, para não
+// manter aqui a lista de moedas da norma.
+func ParseCurrency(code string) (string, *internal_error.InternalError) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" {
+		return DefaultCurrency, nil
+	}
+
+	if len(code) != 3 {
+		return "", internal_error.NewBadRequestError("Currency must be an ISO 4217 code")
+	}
+	for _, letter := range code {
+		if letter < 'A' || letter > 'Z' {
+			return "", internal_error.NewBadRequestError("Currency must be an ISO 4217 code")
+		}
+	}
+
+	return code, nil
+}
+
+// CurrencyOrDefault trata a moeda vazia, de registros gravados antes do campo
+// existir, como DefaultCurrency
+func CurrencyOrDefault(code string) string {
+	if code == "" {
+		return DefaultCurrency
+	}
+
+	return code
+}
@@ -8,9 +8,16 @@ import (
 type User struct {
 	Id   string
 	Name string
+
+	// WebhookSecret assina os webhooks dos leilões do usuário; nunca sai na API
+	WebhookSecret string
 }
 
 type UserRepositoryInterface interface {
 	FindUserById(
 		ctx context.Context, userId string) (*User, *internal_error.InternalError)
+
+	// FindExistingUserIds retorna, dentre userIds, os que existem
+	FindExistingUserIds(
+		ctx context.Context, userIds []string) ([]string, *internal_error.InternalError)
 }
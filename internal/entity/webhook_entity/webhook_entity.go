@@ -0,0 +1,80 @@
+package webhook_entity
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/internal_error"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type WebhookStatus int
+
+const (
+	Pending WebhookStatus = iota
+	Delivered
+	DeadLetter
+)
+
+// Webhook é uma notificação de leilão fechado cuja entrega falhou e que
+// aguarda uma nova tentativa. Payload é o corpo JSON já montado e Signature a
+// assinatura dele, reenviados sem alterações.
+type Webhook struct {
+	Id            string
+	AuctionId     string
+	URL           string
+	Payload       []byte
+	Signature     string
+	Status        WebhookStatus
+	Attempts      int
+	LastError     string
+	NextAttemptAt time.Time
+	CreatedAt     time.Time
+	DeliveredAt   time.Time
+}
+
+// CreateWebhook registra uma notificação que já falhou attempts vezes, com a
+// próxima tentativa em nextAttemptAt.
+func CreateWebhook(
+	auctionId, url string,
+	payload []byte,
+	signature string,
+	attempts int,
+	lastError string,
+	nextAttemptAt time.Time) *Webhook {
+	return &Webhook{
+		Id:            uuid.New().String(),
+		AuctionId:     auctionId,
+		URL:           url,
+		Payload:       payload,
+		Signature:     signature,
+		Status:        Pending,
+		Attempts:      attempts,
+		LastError:     lastError,
+		NextAttemptAt: nextAttemptAt,
+		CreatedAt:     time.Now(),
+	}
+}
+
+type WebhookOutboxRepositoryInterface interface {
+	SaveWebhook(ctx context.Context, webhook *Webhook) *internal_error.InternalError
+
+	// ClaimDueWebhook reserva uma notificação pendente com a tentativa
+	// vencida, adiando a próxima tentativa por lease para que outra instância
+	// não a entregue ao mesmo tempo. Retorna nil quando não há nenhuma.
+	ClaimDueWebhook(
+		ctx context.Context, lease time.Duration) (*Webhook, *internal_error.InternalError)
+
+	MarkWebhookDelivered(ctx context.Context, id string) *internal_error.InternalError
+
+	// RetryWebhookLater conta mais uma tentativa e agenda a próxima
+	RetryWebhookLater(
+		ctx context.Context,
+		id, lastError string,
+		nextAttemptAt time.Time) *internal_error.InternalError
+
+	// DeadLetterWebhook conta mais uma tentativa e desiste da notificação
+	DeadLetterWebhook(ctx context.Context, id, lastError string) *internal_error.InternalError
+
+	FindPendingWebhooks(ctx context.Context) ([]Webhook, *internal_error.InternalError)
+}
@@ -0,0 +1,27 @@
+package graphql
+
+import (
+	"fullcycle-auction_go/configuration/rest_err"
+	"fullcycle-auction_go/internal/internal_error"
+)
+
+// Error segue o formato de erro da especificação do GraphQL. Em extensions
+// vão o mesmo code estável das respostas REST e o status HTTP equivalente.
+type Error struct {
+	Message    string                 `json:"message"`
+	Path       []string               `json:"path,omitempty"`
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
+}
+
+func newError(err *internal_error.InternalError, path ...string) *Error {
+	restErr := rest_err.ConvertError(err)
+
+	return &Error{
+		Message: err.Message,
+		Path:    path,
+		Extensions: map[string]interface{}{
+			"code":   restErr.ErrorCode,
+			"status": restErr.Code,
+		},
+	}
+}
@@ -0,0 +1,299 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"fullcycle-auction_go/internal/internal_error"
+)
+
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// Response omite data quando a consulta é recusada antes da execução, como
+// pede a especificação
+type Response struct {
+	Data   interface{} `json:"data,omitempty"`
+	Errors []*Error    `json:"errors,omitempty"`
+}
+
+// rootField liga um campo de Query ou Mutation ao resolver que o atende
+type rootField struct {
+	arguments []string
+	returns   *objectType
+	resolve   func(r *Resolver, ctx context.Context, args map[string]interface{}) (interface{}, *internal_error.InternalError)
+}
+
+// Execute valida a consulta contra o schema e resolve os campos raiz na ordem
+// em que aparecem. Erros de um campo não impedem os demais de serem
+// resolvidos.
+func (r *Resolver) Execute(ctx context.Context, request Request) *Response {
+	operations, err := parseDocument(request.Query)
+	if err != nil {
+		return requestError(err.Error())
+	}
+
+	op, selectErr := selectOperation(operations, request.OperationName)
+	if selectErr != nil {
+		return requestError(selectErr.Error())
+	}
+
+	roots, rootName := queryFields, "Query"
+	if op.kind == "mutation" {
+		roots, rootName = mutationFields, "Mutation"
+	}
+
+	for _, selection := range op.selections {
+		if err := validateRootField(selection, roots, rootName); err != nil {
+			return requestError(err.Error())
+		}
+	}
+
+	variables := map[string]interface{}{}
+	for name, defaultValue := range op.variables {
+		variables[name], _ = resolveValue(defaultValue, nil)
+	}
+	for name, variable := range request.Variables {
+		variables[name] = variable
+	}
+
+	response := &Response{}
+	data := orderedFields{}
+
+	for _, selection := range op.selections {
+		name := selection.responseName()
+
+		if selection.name == "__typename" {
+			data = append(data, fieldValue{name: name, value: rootName})
+			continue
+		}
+
+		root := roots[selection.name]
+		args, err := resolveArguments(selection.arguments, variables)
+		if err != nil {
+			response.Errors = append(response.Errors,
+				newError(internal_error.NewBadRequestError(err.Error()), name))
+			data = append(data, fieldValue{name: name})
+			continue
+		}
+
+		result, resolveErr := root.resolve(r, ctx, args)
+		if resolveErr != nil {
+			response.Errors = append(response.Errors, newError(resolveErr, name))
+			data = append(data, fieldValue{name: name})
+			continue
+		}
+
+		data = append(data, fieldValue{name: name, value: project(result, selection.selections, root.returns)})
+	}
+
+	response.Data = data
+	return response
+}
+
+func requestError(message string) *Response {
+	return &Response{
+		Errors: []*Error{newError(internal_error.NewBadRequestError(message))},
+	}
+}
+
+func selectOperation(operations []*operation, operationName string) (*operation, error) {
+	if operationName == "" {
+		if len(operations) > 1 {
+			return nil, fmt.Errorf("operationName is required when the document has more than one operation")
+		}
+		return operations[0], nil
+	}
+
+	for _, op := range operations {
+		if op.name == operationName {
+			return op, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown operation %q", operationName)
+}
+
+func validateRootField(selection *field, roots map[string]rootField, rootName string) error {
+	if selection.name == "__typename" {
+		return validateSelections(selection, nil)
+	}
+
+	root, ok := roots[selection.name]
+	if !ok {
+		return fmt.Errorf("cannot query field %q on type %q", selection.name, rootName)
+	}
+
+	for argument := range selection.arguments {
+		if !contains(root.arguments, argument) {
+			return fmt.Errorf("unknown argument %q on field %q", argument, selection.name)
+		}
+	}
+
+	return validateSelections(selection, root.returns)
+}
+
+func validateSelections(selection *field, fieldType *objectType) error {
+	if fieldType == nil {
+		if len(selection.selections) > 0 {
+			return fmt.Errorf("field %q must not have a selection since it is a scalar", selection.name)
+		}
+		return nil
+	}
+
+	if len(selection.selections) == 0 {
+		return fmt.Errorf("field %q of type %q must have a selection of subfields", selection.name, fieldType.name)
+	}
+
+	for _, child := range selection.selections {
+		if len(child.arguments) > 0 {
+			return fmt.Errorf("unknown argument on field %q", child.name)
+		}
+
+		if child.name == "__typename" {
+			if err := validateSelections(child, nil); err != nil {
+				return err
+			}
+			continue
+		}
+
+		childType, ok := fieldType.fields[child.name]
+		if !ok {
+			return fmt.Errorf("cannot query field %q on type %q", child.name, fieldType.name)
+		}
+
+		if err := validateSelections(child, childType); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, current := range values {
+		if current == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func resolveArguments(arguments map[string]value, variables map[string]interface{}) (map[string]interface{}, error) {
+	resolved := make(map[string]interface{}, len(arguments))
+	for name, argument := range arguments {
+		resolvedValue, err := resolveValue(argument, variables)
+		if err != nil {
+			return nil, err
+		}
+		resolved[name] = resolvedValue
+	}
+
+	return resolved, nil
+}
+
+// resolveValue troca as variáveis pelos valores recebidos e os enums pelo
+// nome, deixando os argumentos no mesmo formato de um JSON decodificado
+func resolveValue(literal value, variables map[string]interface{}) (interface{}, error) {
+	switch typed := literal.(type) {
+	case variableRef:
+		resolved, ok := variables[string(typed)]
+		if !ok {
+			return nil, fmt.Errorf("variable $%s is not defined", typed)
+		}
+		return resolved, nil
+	case enumValue:
+		return string(typed), nil
+	case []value:
+		list := make([]interface{}, 0, len(typed))
+		for _, item := range typed {
+			resolved, err := resolveValue(item, variables)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, resolved)
+		}
+		return list, nil
+	case map[string]value:
+		object := make(map[string]interface{}, len(typed))
+		for name, item := range typed {
+			resolved, err := resolveValue(item, variables)
+			if err != nil {
+				return nil, err
+			}
+			object[name] = resolved
+		}
+		return object, nil
+	default:
+		return typed, nil
+	}
+}
+
+// project monta a resposta apenas com os campos pedidos, na ordem da
+// consulta. A seleção já foi validada contra o schema.
+func project(result interface{}, selections []*field, resultType *objectType) interface{} {
+	switch typed := result.(type) {
+	case map[string]interface{}:
+		object := make(orderedFields, 0, len(selections))
+		for _, selection := range selections {
+			if selection.name == "__typename" {
+				object = append(object, fieldValue{name: selection.responseName(), value: resultType.name})
+				continue
+			}
+
+			object = append(object, fieldValue{
+				name:  selection.responseName(),
+				value: project(typed[selection.name], selection.selections, resultType.fields[selection.name]),
+			})
+		}
+		return object
+	case []map[string]interface{}:
+		list := make([]interface{}, 0, len(typed))
+		for _, item := range typed {
+			list = append(list, project(item, selections, resultType))
+		}
+		return list
+	default:
+		return typed
+	}
+}
+
+type fieldValue struct {
+	name  string
+	value interface{}
+}
+
+// orderedFields serializa um objeto mantendo a ordem dos campos da consulta
+type orderedFields []fieldValue
+
+func (fields orderedFields) MarshalJSON() ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteByte('{')
+
+	for i, current := range fields {
+		if i > 0 {
+			buffer.WriteByte(',')
+		}
+
+		name, err := json.Marshal(current.name)
+		if err != nil {
+			return nil, err
+		}
+		encoded, err := json.Marshal(current.value)
+		if err != nil {
+			return nil, err
+		}
+
+		buffer.Write(name)
+		buffer.WriteByte(':')
+		buffer.Write(encoded)
+	}
+
+	buffer.WriteByte('}')
+	return buffer.Bytes(), nil
+}
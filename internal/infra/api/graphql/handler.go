@@ -0,0 +1,40 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxRequestBodyBytes limita o corpo lido por Handle
+const maxRequestBodyBytes = 1 << 20
+
+// Handle atende POST /graphql. Erros de resolução voltam com status 200 no
+// campo errors, como é o padrão em GraphQL; só um corpo inválido ou maior que
+// maxRequestBodyBytes gera 400.
+func (r *Resolver) Handle(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxRequestBodyBytes)
+
+	var request Request
+	if err := c.ShouldBindJSON(&request); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			c.JSON(http.StatusBadRequest, requestError(
+				fmt.Sprintf("request body must be at most %d bytes", maxRequestBodyBytes)))
+			return
+		}
+
+		c.JSON(http.StatusBadRequest, requestError("request body must be a JSON object with a query"))
+		return
+	}
+
+	if request.Query == "" {
+		c.JSON(http.StatusBadRequest, requestError("query is required"))
+		return
+	}
+
+	c.JSON(http.StatusOK, r.Execute(context.WithoutCancel(c.Request.Context()), request))
+}
@@ -0,0 +1,543 @@
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// O parser cobre o subconjunto de GraphQL usado pelo schema: operações
+// query e mutation com variáveis, aliases, argumentos e seleções aninhadas.
+// Fragments, diretivas e introspecção não são suportados.
+
+type operation struct {
+	kind       string
+	name       string
+	variables  map[string]value
+	selections []*field
+}
+
+type field struct {
+	alias      string
+	name       string
+	arguments  map[string]value
+	selections []*field
+}
+
+func (f *field) responseName() string {
+	if f.alias != "" {
+		return f.alias
+	}
+
+	return f.name
+}
+
+// value é um valor literal da consulta ainda sem as variáveis aplicadas
+type value interface{}
+
+type variableRef string
+
+type enumValue string
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenPunct
+	tokenName
+	tokenInt
+	tokenFloat
+	tokenString
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+func tokenize(source string) ([]token, error) {
+	var tokens []token
+
+	for pos := 0; pos < len(source); {
+		char := source[pos]
+
+		switch {
+		case char == ' ' || char == '\t' || char == '\n' || char == '\r' || char == ',':
+			pos++
+		case char == '#':
+			for pos < len(source) && source[pos] != '\n' {
+				pos++
+			}
+		case strings.HasPrefix(source[pos:], "..."):
+			return nil, fmt.Errorf("fragments are not supported (position %d)", pos)
+		case strings.ContainsRune("{}():!$=[]@", rune(char)):
+			tokens = append(tokens, token{kind: tokenPunct, value: string(char), pos: pos})
+			pos++
+		case isNameStart(char):
+			start := pos
+			for pos < len(source) && (isNameStart(source[pos]) || isDigit(source[pos])) {
+				pos++
+			}
+			tokens = append(tokens, token{kind: tokenName, value: source[start:pos], pos: start})
+		case char == '-' || isDigit(char):
+			number, kind, err := readNumber(source, pos)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: kind, value: number, pos: pos})
+			pos += len(number)
+		case char == '"':
+			text, length, err := readString(source, pos)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenString, value: text, pos: pos})
+			pos += length
+		default:
+			r, _ := utf8.DecodeRuneInString(source[pos:])
+			return nil, fmt.Errorf("unexpected character %q (position %d)", r, pos)
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, pos: len(source)}), nil
+}
+
+func isNameStart(char byte) bool {
+	return char == '_' || (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z')
+}
+
+func isDigit(char byte) bool {
+	return char >= '0' && char <= '9'
+}
+
+func readNumber(source string, start int) (string, tokenKind, error) {
+	pos := start
+	kind := tokenInt
+
+	if source[pos] == '-' {
+		pos++
+	}
+	digits := pos
+	for pos < len(source) && isDigit(source[pos]) {
+		pos++
+	}
+	if pos == digits {
+		return "", 0, fmt.Errorf("invalid number (position %d)", start)
+	}
+
+	if pos < len(source) && source[pos] == '.' {
+		kind = tokenFloat
+		pos++
+		for pos < len(source) && isDigit(source[pos]) {
+			pos++
+		}
+	}
+	if pos < len(source) && (source[pos] == 'e' || source[pos] == 'E') {
+		kind = tokenFloat
+		pos++
+		if pos < len(source) && (source[pos] == '+' || source[pos] == '-') {
+			pos++
+		}
+		for pos < len(source) && isDigit(source[pos]) {
+			pos++
+		}
+	}
+
+	return source[start:pos], kind, nil
+}
+
+// readString lê uma string entre aspas e retorna o texto sem escapes e
+// quantos bytes da consulta ela ocupa
+func readString(source string, start int) (string, int, error) {
+	var builder strings.Builder
+
+	for pos := start + 1; pos < len(source); pos++ {
+		char := source[pos]
+
+		switch {
+		case char == '"':
+			return builder.String(), pos - start + 1, nil
+		case char == '\n':
+			return "", 0, fmt.Errorf("unterminated string (position %d)", start)
+		case char == '\\' && pos+1 < len(source):
+			pos++
+			switch source[pos] {
+			case '"', '\\', '/':
+				builder.WriteByte(source[pos])
+			case 'b':
+				builder.WriteByte('\b')
+			case 'f':
+				builder.WriteByte('\f')
+			case 'n':
+				builder.WriteByte('\n')
+			case 'r':
+				builder.WriteByte('\r')
+			case 't':
+				builder.WriteByte('\t')
+			case 'u':
+				if pos+4 >= len(source) {
+					return "", 0, fmt.Errorf("invalid unicode escape (position %d)", pos)
+				}
+				code, err := strconv.ParseUint(source[pos+1:pos+5], 16, 32)
+				if err != nil {
+					return "", 0, fmt.Errorf("invalid unicode escape (position %d)", pos)
+				}
+				builder.WriteRune(rune(code))
+				pos += 4
+			default:
+				return "", 0, fmt.Errorf("invalid escape sequence (position %d)", pos)
+			}
+		default:
+			builder.WriteByte(char)
+		}
+	}
+
+	return "", 0, fmt.Errorf("unterminated string (position %d)", start)
+}
+
+// maxNestingDepth limita o aninhamento de seleções e valores. O parser é
+// recursivo, então sem o limite um documento pequeno e muito aninhado
+// esgotaria a pilha.
+const maxNestingDepth = 32
+
+type parser struct {
+	tokens []token
+	pos    int
+	depth  int
+}
+
+func parseDocument(source string) ([]*operation, error) {
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+
+	var operations []*operation
+	for p.peek().kind != tokenEOF {
+		operation, err := p.parseOperation()
+		if err != nil {
+			return nil, err
+		}
+		operations = append(operations, operation)
+	}
+
+	if len(operations) == 0 {
+		return nil, fmt.Errorf("document does not contain any operation")
+	}
+
+	return operations, nil
+}
+
+// enter conta mais um nível de aninhamento; cada enter sem erro tem o seu
+// leave
+func (p *parser) enter() error {
+	if p.depth >= maxNestingDepth {
+		return fmt.Errorf("document exceeds the maximum nesting depth of %d", maxNestingDepth)
+	}
+	p.depth++
+
+	return nil
+}
+
+func (p *parser) leave() {
+	p.depth--
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	current := p.tokens[p.pos]
+	if current.kind != tokenEOF {
+		p.pos++
+	}
+
+	return current
+}
+
+func (p *parser) peekPunct(punct string) bool {
+	current := p.peek()
+	return current.kind == tokenPunct && current.value == punct
+}
+
+func (p *parser) expectPunct(punct string) error {
+	current := p.next()
+	if current.kind != tokenPunct || current.value != punct {
+		return unexpected(current, fmt.Sprintf("%q", punct))
+	}
+
+	return nil
+}
+
+func (p *parser) expectName() (string, error) {
+	current := p.next()
+	if current.kind != tokenName {
+		return "", unexpected(current, "a name")
+	}
+
+	return current.value, nil
+}
+
+func unexpected(current token, expected string) error {
+	if current.kind == tokenEOF {
+		return fmt.Errorf("unexpected end of document, expected %s", expected)
+	}
+
+	return fmt.Errorf("unexpected %q (position %d), expected %s", current.value, current.pos, expected)
+}
+
+func (p *parser) parseOperation() (*operation, error) {
+	// A forma abreviada "{ ... }" é uma query sem nome
+	if p.peekPunct("{") {
+		selections, err := p.parseSelectionSet()
+		if err != nil {
+			return nil, err
+		}
+		return &operation{kind: "query", selections: selections}, nil
+	}
+
+	kind, err := p.expectName()
+	if err != nil {
+		return nil, err
+	}
+	if kind != "query" && kind != "mutation" {
+		return nil, fmt.Errorf("unsupported operation type %q", kind)
+	}
+
+	op := &operation{kind: kind, variables: map[string]value{}}
+	if p.peek().kind == tokenName {
+		op.name = p.next().value
+	}
+
+	if p.peekPunct("(") {
+		if err := p.parseVariableDefinitions(op); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.peekPunct("@") {
+		return nil, fmt.Errorf("directives are not supported")
+	}
+
+	op.selections, err = p.parseSelectionSet()
+	if err != nil {
+		return nil, err
+	}
+
+	return op, nil
+}
+
+// parseVariableDefinitions guarda apenas os valores padrão; os tipos são
+// conferidos pelos resolvers ao converter os argumentos
+func (p *parser) parseVariableDefinitions(op *operation) error {
+	p.next()
+
+	for !p.peekPunct(")") {
+		if err := p.expectPunct("$"); err != nil {
+			return err
+		}
+		name, err := p.expectName()
+		if err != nil {
+			return err
+		}
+		if err := p.expectPunct(":"); err != nil {
+			return err
+		}
+		if err := p.skipType(); err != nil {
+			return err
+		}
+
+		op.variables[name] = nil
+		if p.peekPunct("=") {
+			p.next()
+			defaultValue, err := p.parseValue()
+			if err != nil {
+				return err
+			}
+			op.variables[name] = defaultValue
+		}
+	}
+
+	return p.expectPunct(")")
+}
+
+func (p *parser) skipType() error {
+	if p.peekPunct("[") {
+		p.next()
+		if err := p.skipType(); err != nil {
+			return err
+		}
+		if err := p.expectPunct("]"); err != nil {
+			return err
+		}
+	} else if _, err := p.expectName(); err != nil {
+		return err
+	}
+
+	if p.peekPunct("!") {
+		p.next()
+	}
+
+	return nil
+}
+
+func (p *parser) parseSelectionSet() ([]*field, error) {
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+
+	if err := p.expectPunct("{"); err != nil {
+		return nil, err
+	}
+
+	var selections []*field
+	for !p.peekPunct("}") {
+		selection, err := p.parseField()
+		if err != nil {
+			return nil, err
+		}
+		selections = append(selections, selection)
+	}
+	p.next()
+
+	if len(selections) == 0 {
+		return nil, fmt.Errorf("selection set cannot be empty")
+	}
+
+	return selections, nil
+}
+
+func (p *parser) parseField() (*field, error) {
+	name, err := p.expectName()
+	if err != nil {
+		return nil, err
+	}
+
+	selection := &field{name: name}
+	if p.peekPunct(":") {
+		p.next()
+		selection.alias = name
+		if selection.name, err = p.expectName(); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.peekPunct("(") {
+		if selection.arguments, err = p.parseArguments(); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.peekPunct("@") {
+		return nil, fmt.Errorf("directives are not supported")
+	}
+
+	if p.peekPunct("{") {
+		if selection.selections, err = p.parseSelectionSet(); err != nil {
+			return nil, err
+		}
+	}
+
+	return selection, nil
+}
+
+func (p *parser) parseArguments() (map[string]value, error) {
+	p.next()
+
+	arguments := map[string]value{}
+	for !p.peekPunct(")") {
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectPunct(":"); err != nil {
+			return nil, err
+		}
+		if arguments[name], err = p.parseValue(); err != nil {
+			return nil, err
+		}
+	}
+	p.next()
+
+	return arguments, nil
+}
+
+func (p *parser) parseValue() (value, error) {
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+
+	current := p.next()
+
+	switch current.kind {
+	case tokenInt:
+		number, err := strconv.ParseInt(current.value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Int %s", current.value)
+		}
+		return number, nil
+	case tokenFloat:
+		number, err := strconv.ParseFloat(current.value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Float %s", current.value)
+		}
+		return number, nil
+	case tokenString:
+		return current.value, nil
+	case tokenName:
+		switch current.value {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		case "null":
+			return nil, nil
+		}
+		return enumValue(current.value), nil
+	case tokenPunct:
+		switch current.value {
+		case "$":
+			name, err := p.expectName()
+			if err != nil {
+				return nil, err
+			}
+			return variableRef(name), nil
+		case "[":
+			list := []value{}
+			for !p.peekPunct("]") {
+				item, err := p.parseValue()
+				if err != nil {
+					return nil, err
+				}
+				list = append(list, item)
+			}
+			p.next()
+			return list, nil
+		case "{":
+			object := map[string]value{}
+			for !p.peekPunct("}") {
+				name, err := p.expectName()
+				if err != nil {
+					return nil, err
+				}
+				if err := p.expectPunct(":"); err != nil {
+					return nil, err
+				}
+				if object[name], err = p.parseValue(); err != nil {
+					return nil, err
+				}
+			}
+			p.next()
+			return object, nil
+		}
+	}
+
+	return nil, unexpected(current, "a value")
+}
@@ -0,0 +1,427 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"fullcycle-auction_go/internal/usecase/auction_usecase"
+	"fullcycle-auction_go/internal/usecase/bid_usecase"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Resolver atende o schema GraphQL com os mesmos casos de uso da API REST
+type Resolver struct {
+	auctionUseCase auction_usecase.AuctionUseCaseInterface
+	bidUseCase     bid_usecase.BidUseCaseInterface
+}
+
+func NewResolver(
+	auctionUseCase auction_usecase.AuctionUseCaseInterface,
+	bidUseCase bid_usecase.BidUseCaseInterface) *Resolver {
+	return &Resolver{
+		auctionUseCase: auctionUseCase,
+		bidUseCase:     bidUseCase,
+	}
+}
+
+var queryFields = map[string]rootField{
+	"auction": {
+		arguments: []string{"id"},
+		returns:   auctionType,
+		resolve:   (*Resolver).auction,
+	},
+	"auctions": {
+		arguments: []string{"filter", "page"},
+		returns:   auctionType,
+		resolve:   (*Resolver).auctions,
+	},
+	"bids": {
+		arguments: []string{"auctionId", "sort", "limit"},
+		returns:   bidType,
+		resolve:   (*Resolver).bids,
+	},
+}
+
+var mutationFields = map[string]rootField{
+	"createAuction": {
+		arguments: []string{"input"},
+		returns:   auctionType,
+		resolve:   (*Resolver).createAuction,
+	},
+}
+
+func (r *Resolver) auction(
+	ctx context.Context, args map[string]interface{}) (interface{}, *internal_error.InternalError) {
+	id, err := idArgument(args, "id")
+	if err != nil {
+		return nil, err
+	}
+
+	auction, err := r.auctionUseCase.FindAuctionById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return auctionObject(*auction), nil
+}
+
+func (r *Resolver) auctions(
+	ctx context.Context, args map[string]interface{}) (interface{}, *internal_error.InternalError) {
+	filter, err := objectArgument(args, "filter", true)
+	if err != nil {
+		return nil, err
+	}
+
+	statusName, err := stringArgument(filter, "status", true)
+	if err != nil {
+		return nil, err
+	}
+	status, err := auction_usecase.ParseAuctionStatus(statusName)
+	if err != nil {
+		return nil, err
+	}
+
+	category, err := stringArgument(filter, "category", false)
+	if err != nil {
+		return nil, err
+	}
+	productName, err := stringArgument(filter, "productName", false)
+	if err != nil {
+		return nil, err
+	}
+
+	page, err := objectArgument(args, "page", false)
+	if err != nil {
+		return nil, err
+	}
+	pageNumber, err := intArgument(page, "page", 1)
+	if err != nil {
+		return nil, err
+	}
+	limit, err := intArgument(page, "limit", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	auctions, err := r.auctionUseCase.FindAuctions(ctx, &status, category, productName, pageNumber, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make([]map[string]interface{}, 0, len(auctions))
+	for _, auction := range auctions {
+		objects = append(objects, auctionObject(auction))
+	}
+
+	return objects, nil
+}
+
+func (r *Resolver) bids(
+	ctx context.Context, args map[string]interface{}) (interface{}, *internal_error.InternalError) {
+	auctionId, err := idArgument(args, "auctionId")
+	if err != nil {
+		return nil, err
+	}
+
+	sort, err := stringArgument(args, "sort", false)
+	if err != nil {
+		return nil, err
+	}
+	limit, err := intArgument(args, "limit", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	bids, err := r.bidUseCase.FindBidByAuctionId(ctx, auctionId, sort, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make([]map[string]interface{}, 0, len(bids))
+	for _, bid := range bids {
+		objects = append(objects, bidObject(bid))
+	}
+
+	return objects, nil
+}
+
+func (r *Resolver) createAuction(
+	ctx context.Context, args map[string]interface{}) (interface{}, *internal_error.InternalError) {
+	input, err := objectArgument(args, "input", true)
+	if err != nil {
+		return nil, err
+	}
+
+	userId, err := idArgument(input, "userId")
+	if err != nil {
+		return nil, err
+	}
+	productName, err := stringArgument(input, "productName", true)
+	if err != nil {
+		return nil, err
+	}
+	category, err := stringArgument(input, "category", true)
+	if err != nil {
+		return nil, err
+	}
+	description, err := stringArgument(input, "description", true)
+	if err != nil {
+		return nil, err
+	}
+
+	conditionName, err := stringArgument(input, "condition", true)
+	if err != nil {
+		return nil, err
+	}
+	condition, err := auction_entity.ParseProductCondition(conditionName)
+	if err != nil {
+		return nil, err
+	}
+
+	reservePrice, err := floatArgument(input, "reservePrice")
+	if err != nil {
+		return nil, err
+	}
+
+	buyNowPrice, err := floatArgument(input, "buyNowPrice")
+	if err != nil {
+		return nil, err
+	}
+
+	currency, err := stringArgument(input, "currency", false)
+	if err != nil {
+		return nil, err
+	}
+
+	webhookURL, err := stringArgument(input, "webhookUrl", false)
+	if err != nil {
+		return nil, err
+	}
+
+	images, err := stringListArgument(input, "images")
+	if err != nil {
+		return nil, err
+	}
+
+	var auctionType auction_entity.AuctionType
+	typeName, err := stringArgument(input, "type", false)
+	if err != nil {
+		return nil, err
+	}
+	if typeName != "" {
+		if auctionType, err = auction_entity.ParseAuctionType(typeName); err != nil {
+			return nil, err
+		}
+	}
+
+	auctionInput := auction_usecase.AuctionInputDTO{
+		UserId:       userId,
+		ProductName:  productName,
+		Category:     category,
+		Description:  description,
+		Condition:    auction_usecase.ProductCondition(condition),
+		ReservePrice: reservePrice,
+		BuyNowPrice:  buyNowPrice,
+		Currency:     currency,
+		WebhookURL:   webhookURL,
+		Images:       images,
+		Type:         auction_usecase.AuctionType(auctionType),
+	}
+
+	startsAt, err := stringArgument(input, "startsAt", false)
+	if err != nil {
+		return nil, err
+	}
+	if startsAt != "" {
+		parsed, parseErr := time.Parse(time.RFC3339, startsAt)
+		if parseErr != nil {
+			return nil, internal_error.NewBadRequestError("startsAt must be an RFC 3339 timestamp")
+		}
+		auctionInput.StartsAt = &parsed
+	}
+
+	auction, err := r.auctionUseCase.CreateAuction(ctx, auctionInput)
+	if err != nil {
+		return nil, err
+	}
+
+	return auctionObject(*auction), nil
+}
+
+func auctionObject(auction auction_usecase.AuctionOutputDTO) map[string]interface{} {
+	images := make([]interface{}, 0, len(auction.Images))
+	for _, image := range auction.Images {
+		images = append(images, image)
+	}
+
+	object := map[string]interface{}{
+		"id":           auction.Id,
+		"userId":       nil,
+		"productName":  auction.ProductName,
+		"category":     auction.Category,
+		"description":  auction.Description,
+		"condition":    auction_entity.ProductCondition(auction.Condition).String(),
+		"type":         auction_entity.AuctionType(auction.Type).String(),
+		"status":       auction_entity.AuctionStatus(auction.Status).String(),
+		"timestamp":    formatTime(auction.Timestamp),
+		"expiresAt":    formatTime(auction.ExpiresAt),
+		"startsAt":     formatTime(auction.StartsAt),
+		"reservePrice": nil,
+		"reserveMet":   nil,
+		"buyNowPrice":  nil,
+		"currency":     auction.Currency,
+		"images":       images,
+		"cancelReason": nil,
+		"version":      auction.Version,
+	}
+
+	// Campos opcionais ausentes vão como null, como na API REST
+	if auction.UserId != "" {
+		object["userId"] = auction.UserId
+	}
+	if auction.ReservePrice > 0 {
+		object["reservePrice"] = auction.ReservePrice
+	}
+	if auction.ReserveMet != nil {
+		object["reserveMet"] = *auction.ReserveMet
+	}
+	if auction.BuyNowPrice > 0 {
+		object["buyNowPrice"] = auction.BuyNowPrice
+	}
+	if auction.CancelReason != "" {
+		object["cancelReason"] = auction.CancelReason
+	}
+
+	return object
+}
+
+func bidObject(bid bid_usecase.BidOutputDTO) map[string]interface{} {
+	return map[string]interface{}{
+		"id":        bid.Id,
+		"userId":    bid.UserId,
+		"auctionId": bid.AuctionId,
+		"amount":    bid.Amount,
+		"currency":  bid.Currency,
+		"timestamp": formatTime(bid.Timestamp),
+	}
+}
+
+func formatTime(value time.Time) string {
+	return value.UTC().Format(time.RFC3339)
+}
+
+// Os argumentos chegam como literais da consulta ou como variáveis JSON, por
+// isso números podem vir como int64 ou float64
+
+func idArgument(args map[string]interface{}, name string) (string, *internal_error.InternalError) {
+	id, err := stringArgument(args, name, true)
+	if err != nil {
+		return "", err
+	}
+
+	if uuid.Validate(id) != nil {
+		return "", internal_error.NewBadRequestError(fmt.Sprintf("%s must be a valid UUID", name))
+	}
+
+	return id, nil
+}
+
+func stringArgument(
+	args map[string]interface{}, name string, required bool) (string, *internal_error.InternalError) {
+	raw, ok := args[name]
+	if !ok || raw == nil {
+		if required {
+			return "", internal_error.NewBadRequestError(fmt.Sprintf("%s is required", name))
+		}
+		return "", nil
+	}
+
+	value, ok := raw.(string)
+	if !ok {
+		return "", internal_error.NewBadRequestError(fmt.Sprintf("%s must be a string", name))
+	}
+
+	return value, nil
+}
+
+// stringListArgument lê uma lista opcional de strings; ausente ou null vira nil
+func stringListArgument(
+	args map[string]interface{}, name string) ([]string, *internal_error.InternalError) {
+	raw, ok := args[name]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+
+	items, ok := raw.([]interface{})
+	if !ok {
+		return nil, internal_error.NewBadRequestError(fmt.Sprintf("%s must be a list of strings", name))
+	}
+
+	values := make([]string, 0, len(items))
+	for _, item := range items {
+		value, ok := item.(string)
+		if !ok {
+			return nil, internal_error.NewBadRequestError(fmt.Sprintf("%s must be a list of strings", name))
+		}
+		values = append(values, value)
+	}
+
+	return values, nil
+}
+
+func intArgument(
+	args map[string]interface{}, name string, defaultValue int64) (int64, *internal_error.InternalError) {
+	raw, ok := args[name]
+	if !ok || raw == nil {
+		return defaultValue, nil
+	}
+
+	switch value := raw.(type) {
+	case int64:
+		return value, nil
+	case float64:
+		if value == math.Trunc(value) {
+			return int64(value), nil
+		}
+	}
+
+	return 0, internal_error.NewBadRequestError(fmt.Sprintf("%s must be an integer", name))
+}
+
+func floatArgument(args map[string]interface{}, name string) (float64, *internal_error.InternalError) {
+	raw, ok := args[name]
+	if !ok || raw == nil {
+		return 0, nil
+	}
+
+	switch value := raw.(type) {
+	case int64:
+		return float64(value), nil
+	case float64:
+		return value, nil
+	}
+
+	return 0, internal_error.NewBadRequestError(fmt.Sprintf("%s must be a number", name))
+}
+
+func objectArgument(
+	args map[string]interface{}, name string, required bool) (map[string]interface{}, *internal_error.InternalError) {
+	raw, ok := args[name]
+	if !ok || raw == nil {
+		if required {
+			return nil, internal_error.NewBadRequestError(fmt.Sprintf("%s is required", name))
+		}
+		return map[string]interface{}{}, nil
+	}
+
+	value, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, internal_error.NewBadRequestError(fmt.Sprintf("%s must be an object", name))
+	}
+
+	return value, nil
+}
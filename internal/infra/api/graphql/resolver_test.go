@@ -0,0 +1,170 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"fullcycle-auction_go/internal/internal_error"
+	"fullcycle-auction_go/internal/usecase/auction_usecase"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAuctionUseCase implementa só a busca por id; os demais métodos não são
+// usados pela query auction
+type fakeAuctionUseCase struct {
+	auction_usecase.AuctionUseCaseInterface
+	auctions map[string]auction_usecase.AuctionOutputDTO
+}
+
+func (f *fakeAuctionUseCase) FindAuctionById(
+	ctx context.Context, id string) (*auction_usecase.AuctionOutputDTO, *internal_error.InternalError) {
+	auction, ok := f.auctions[id]
+	if !ok {
+		return nil, internal_error.NewNotFoundError(
+			fmt.Sprintf("Auction not found with this id = %s", id)).
+			WithCode(internal_error.CodeAuctionNotFound)
+	}
+
+	return &auction, nil
+}
+
+func TestAuctionQuery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	auctionId := uuid.New().String()
+	timestamp := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	resolver := NewResolver(&fakeAuctionUseCase{
+		auctions: map[string]auction_usecase.AuctionOutputDTO{
+			auctionId: {
+				Id:          auctionId,
+				ProductName: "Notebook",
+				Category:    "Electronics",
+				Description: "Notebook used for the GraphQL test",
+				Condition:   auction_usecase.ProductCondition(1),
+				Status:      auction_usecase.AuctionStatus(0),
+				Timestamp:   timestamp,
+				ExpiresAt:   timestamp.Add(time.Hour),
+				StartsAt:    timestamp,
+				Version:     2,
+			},
+		},
+	}, nil)
+
+	router := gin.New()
+	router.POST("/graphql", resolver.Handle)
+
+	postQuery := func(body string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+		recorder := httptest.NewRecorder()
+
+		router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	missingId := uuid.New().String()
+	testCases := []struct {
+		name           string
+		body           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "returns only the selected fields",
+			body: fmt.Sprintf(`{"query": "{ auction(id: \"%s\") { productName status expiresAt reserveMet __typename } }"}`,
+				auctionId),
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"data": {"auction": {
+				"productName": "Notebook",
+				"status": "Active",
+				"expiresAt": "2026-10-01T13:00:00Z",
+				"reserveMet": null,
+				"__typename": "Auction"
+			}}}`,
+		},
+		{
+			name: "accepts variables and aliases",
+			body: fmt.Sprintf(`{
+				"query": "query Find($id: ID!) { found: auction(id: $id) { id version } }",
+				"variables": {"id": %q}
+			}`, auctionId),
+			expectedStatus: http.StatusOK,
+			expectedBody:   fmt.Sprintf(`{"data": {"found": {"id": %q, "version": 2}}}`, auctionId),
+		},
+		{
+			name:           "maps internal errors to extensions",
+			body:           fmt.Sprintf(`{"query": "{ auction(id: \"%s\") { id } }"}`, missingId),
+			expectedStatus: http.StatusOK,
+			expectedBody: fmt.Sprintf(`{"data": {"auction": null}, "errors": [{
+				"message": "Auction not found with this id = %s",
+				"path": ["auction"],
+				"extensions": {"code": "AUCTION_NOT_FOUND", "status": 404}
+			}]}`, missingId),
+		},
+		{
+			name:           "rejects an invalid id",
+			body:           `{"query": "{ auction(id: \"123\") { id } }"}`,
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"data": {"auction": null}, "errors": [{
+				"message": "id must be a valid UUID",
+				"path": ["auction"],
+				"extensions": {"code": "BAD_REQUEST", "status": 400}
+			}]}`,
+		},
+		{
+			name: "rejects unknown fields before resolving",
+			body: fmt.Sprintf(`{"query": "{ auction(id: \"%s\") { id password } }"}`,
+				auctionId),
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"errors": [{
+				"message": "cannot query field \"password\" on type \"Auction\"",
+				"extensions": {"code": "BAD_REQUEST", "status": 400}
+			}]}`,
+		},
+		{
+			name: "rejects deeply nested documents",
+			body: fmt.Sprintf(`{"query": "{ auction(id: %s1) { id } }"}`,
+				strings.Repeat("[", maxNestingDepth+1)),
+			expectedStatus: http.StatusOK,
+			expectedBody: fmt.Sprintf(`{"errors": [{
+				"message": "document exceeds the maximum nesting depth of %d",
+				"extensions": {"code": "BAD_REQUEST", "status": 400}
+			}]}`, maxNestingDepth),
+		},
+		{
+			name: "rejects bodies over the limit",
+			body: fmt.Sprintf(`{"query": "{ auction(id: \"%s\") { id } }", "variables": {"padding": %q}}`,
+				auctionId, strings.Repeat("a", maxRequestBodyBytes)),
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: fmt.Sprintf(`{"errors": [{
+				"message": "request body must be at most %d bytes",
+				"extensions": {"code": "BAD_REQUEST", "status": 400}
+			}]}`, maxRequestBodyBytes),
+		},
+		{
+			name:           "requires a query",
+			body:           `{}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{"errors": [{
+				"message": "query is required",
+				"extensions": {"code": "BAD_REQUEST", "status": 400}
+			}]}`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := postQuery(tc.body)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+			require.JSONEq(t, tc.expectedBody, recorder.Body.String())
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package graphql
+
+// Schema descreve a API GraphQL. O executor confere as consultas contra os
+// tipos abaixo, que precisam acompanhar esta definição.
+const Schema = `
+enum AuctionStatus {
+  Active
+  Completed
+  Cancelled
+  Scheduled
+}
+
+enum AuctionType {
+  Open
+  Sealed
+}
+
+enum ProductCondition {
+  New
+  Used
+  Refurbished
+}
+
+type Auction {
+  id: ID!
+  userId: ID
+  productName: String!
+  category: String!
+  description: String!
+  condition: ProductCondition!
+  type: AuctionType!
+  status: AuctionStatus!
+  timestamp: String!
+  expiresAt: String!
+  startsAt: String!
+  reservePrice: Float
+  reserveMet: Boolean
+  buyNowPrice: Float
+  currency: String!
+  images: [String!]!
+  cancelReason: String
+  version: Int!
+}
+
+type Bid {
+  id: ID!
+  userId: ID!
+  auctionId: ID!
+  amount: Float!
+  currency: String!
+  timestamp: String!
+}
+
+input AuctionFilter {
+  status: AuctionStatus!
+  category: String
+  productName: String
+}
+
+input Page {
+  page: Int
+  limit: Int
+}
+
+input CreateAuctionInput {
+  userId: ID!
+  productName: String!
+  category: String!
+  description: String!
+  condition: ProductCondition!
+  reservePrice: Float
+  buyNowPrice: Float
+  currency: String
+  startsAt: String
+  webhookUrl: String
+  images: [String!]
+  type: AuctionType
+}
+
+type Query {
+  auction(id: ID!): Auction
+  auctions(filter: AuctionFilter!, page: Page): [Auction!]!
+  bids(auctionId: ID!, sort: String, limit: Int): [Bid!]!
+}
+
+type Mutation {
+  createAuction(input: CreateAuctionInput!): Auction
+}
+`
+
+// objectType lista os campos de um tipo do schema. Campos escalares apontam
+// para nil; campos de objeto apontam para o tipo aninhado.
+type objectType struct {
+	name   string
+	fields map[string]*objectType
+}
+
+var auctionType = &objectType{
+	name: "Auction",
+	fields: map[string]*objectType{
+		"id":           nil,
+		"userId":       nil,
+		"productName":  nil,
+		"category":     nil,
+		"description":  nil,
+		"condition":    nil,
+		"type":         nil,
+		"status":       nil,
+		"timestamp":    nil,
+		"expiresAt":    nil,
+		"startsAt":     nil,
+		"reservePrice": nil,
+		"reserveMet":   nil,
+		"buyNowPrice":  nil,
+		"currency":     nil,
+		"images":       nil,
+		"cancelReason": nil,
+		"version":      nil,
+	},
+}
+
+var bidType = &objectType{
+	name: "Bid",
+	fields: map[string]*objectType{
+		"id":        nil,
+		"userId":    nil,
+		"auctionId": nil,
+		"amount":    nil,
+		"currency":  nil,
+		"timestamp": nil,
+	},
+}
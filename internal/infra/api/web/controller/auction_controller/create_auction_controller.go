@@ -9,6 +9,8 @@ import (
 	"net/http"
 )
 
+const maxIdempotencyKeyLength = 255
+
 type AuctionController struct {
 	auctionUseCase auction_usecase.AuctionUseCaseInterface
 }
@@ -29,7 +31,19 @@ func (u *AuctionController) CreateAuction(c *gin.Context) {
 		return
 	}
 
-	err := u.auctionUseCase.CreateAuction(context.Background(), auctionInputDTO)
+	// Uma nova tentativa com a mesma chave devolve o leilão já criado
+	auctionInputDTO.IdempotencyKey = c.GetHeader("Idempotency-Key")
+	if len(auctionInputDTO.IdempotencyKey) > maxIdempotencyKeyLength {
+		restErr := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "Idempotency-Key",
+			Message: "Idempotency-Key must have at most 255 characters",
+		})
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
+	auctionOutputDTO, err := u.auctionUseCase.CreateAuction(context.WithoutCancel(c.Request.Context()), auctionInputDTO)
 	if err != nil {
 		restErr := rest_err.ConvertError(err)
 
@@ -37,5 +51,5 @@ func (u *AuctionController) CreateAuction(c *gin.Context) {
 		return
 	}
 
-	c.Status(http.StatusCreated)
+	c.JSON(http.StatusCreated, auctionOutputDTO)
 }
@@ -23,7 +23,7 @@ func (u *AuctionController) FindAuctionById(c *gin.Context) {
 		return
 	}
 
-	auctionData, err := u.auctionUseCase.FindAuctionById(context.Background(), auctionId)
+	auctionData, err := u.auctionUseCase.FindAuctionById(context.WithoutCancel(c.Request.Context()), auctionId)
 	if err != nil {
 		errRest := rest_err.ConvertError(err)
 		c.JSON(errRest.Code, errRest)
@@ -38,15 +38,47 @@ func (u *AuctionController) FindAuctions(c *gin.Context) {
 	category := c.Query("category")
 	productName := c.Query("productName")
 
-	statusNumber, errConv := strconv.Atoi(status)
+	// Sem status na query todos os leilões são listados
+	var auctionStatus *auction_usecase.AuctionStatus
+	if status != "" {
+		parsedStatus, err := auction_usecase.ParseAuctionStatus(status)
+		if err != nil {
+			errRest := rest_err.ConvertError(err)
+			c.JSON(errRest.Code, errRest)
+			return
+		}
+		auctionStatus = &parsedStatus
+	}
+
+	page, errConv := parseInt64Query(c, "page", 1)
+	if errConv != nil {
+		errRest := rest_err.NewBadRequestError("Error trying to validate page param")
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	limit, errConv := parseInt64Query(c, "limit", 0)
 	if errConv != nil {
-		errRest := rest_err.NewBadRequestError("Error trying to validate auction status param")
+		errRest := rest_err.NewBadRequestError("Error trying to validate limit param")
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	auctions, err := u.auctionUseCase.FindAuctions(context.WithoutCancel(c.Request.Context()),
+		auctionStatus, category, productName, page, limit)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
 		c.JSON(errRest.Code, errRest)
 		return
 	}
 
-	auctions, err := u.auctionUseCase.FindAuctions(context.Background(),
-		auction_usecase.AuctionStatus(statusNumber), category, productName)
+	c.JSON(http.StatusOK, auctions)
+}
+
+// FindStaleActiveAuctions é um endpoint administrativo para diagnosticar a
+// rotina de fechamento
+func (u *AuctionController) FindStaleActiveAuctions(c *gin.Context) {
+	auctions, err := u.auctionUseCase.FindStaleActiveAuctions(context.WithoutCancel(c.Request.Context()))
 	if err != nil {
 		errRest := rest_err.ConvertError(err)
 		c.JSON(errRest.Code, errRest)
@@ -56,6 +88,15 @@ func (u *AuctionController) FindAuctions(c *gin.Context) {
 	c.JSON(http.StatusOK, auctions)
 }
 
+func parseInt64Query(c *gin.Context, key string, defaultValue int64) (int64, error) {
+	value := c.Query(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	return strconv.ParseInt(value, 10, 64)
+}
+
 func (u *AuctionController) FindWinningBidByAuctionId(c *gin.Context) {
 	auctionId := c.Param("auctionId")
 
@@ -69,7 +110,7 @@ func (u *AuctionController) FindWinningBidByAuctionId(c *gin.Context) {
 		return
 	}
 
-	auctionData, err := u.auctionUseCase.FindWinningBidByAuctionId(context.Background(), auctionId)
+	auctionData, err := u.auctionUseCase.FindWinningBidByAuctionId(context.WithoutCancel(c.Request.Context()), auctionId)
 	if err != nil {
 		errRest := rest_err.ConvertError(err)
 		c.JSON(errRest.Code, errRest)
@@ -78,3 +119,27 @@ func (u *AuctionController) FindWinningBidByAuctionId(c *gin.Context) {
 
 	c.JSON(http.StatusOK, auctionData)
 }
+
+func (u *AuctionController) FindAuctionTimeRemaining(c *gin.Context) {
+	auctionId := c.Param("auctionId")
+
+	if err := uuid.Validate(auctionId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "auctionId",
+			Message: "Invalid UUID value",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	timeRemaining, err := u.auctionUseCase.FindAuctionTimeRemaining(
+		context.WithoutCancel(c.Request.Context()), auctionId)
+	if err != nil {
+		errRest := rest_err.ConvertError(err)
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	c.JSON(http.StatusOK, timeRemaining)
+}
@@ -3,23 +3,44 @@ package bid_controller
 import (
 	"context"
 	"fullcycle-auction_go/configuration/rest_err"
+	"fullcycle-auction_go/internal/infra/api/web/ratelimit"
 	"fullcycle-auction_go/internal/infra/api/web/validation"
+	"fullcycle-auction_go/internal/infra/broadcast"
+	"fullcycle-auction_go/internal/internal_error"
 	"fullcycle-auction_go/internal/usecase/bid_usecase"
 	"github.com/gin-gonic/gin"
 	"net/http"
 )
 
 type BidController struct {
-	bidUseCase bid_usecase.BidUseCaseInterface
+	bidUseCase  bid_usecase.BidUseCaseInterface
+	bidHub      *broadcast.BidHub
+	rateLimiter *ratelimit.Limiter
 }
 
-func NewBidController(bidUseCase bid_usecase.BidUseCaseInterface) *BidController {
+// NewBidController recebe o limitador de lances; nil desliga o limite.
+func NewBidController(
+	bidUseCase bid_usecase.BidUseCaseInterface,
+	bidHub *broadcast.BidHub,
+	rateLimiter *ratelimit.Limiter) *BidController {
 	return &BidController{
-		bidUseCase: bidUseCase,
+		bidUseCase:  bidUseCase,
+		bidHub:      bidHub,
+		rateLimiter: rateLimiter,
 	}
 }
 
 func (u *BidController) CreateBid(c *gin.Context) {
+	// O limite é por IP: o user_id vem do corpo e trocar de id a cada lance
+	// escaparia do limite
+	if !u.rateLimiter.Allow("ip:" + c.ClientIP()) {
+		restErr := rest_err.ConvertError(
+			internal_error.NewTooManyRequestsError("too many bids, try again later"))
+
+		c.JSON(restErr.Code, restErr)
+		return
+	}
+
 	var bidInputDTO bid_usecase.BidInputDTO
 
 	if err := c.ShouldBindJSON(&bidInputDTO); err != nil {
@@ -29,7 +50,7 @@ func (u *BidController) CreateBid(c *gin.Context) {
 		return
 	}
 
-	err := u.bidUseCase.CreateBid(context.Background(), bidInputDTO)
+	err := u.bidUseCase.CreateBid(context.WithoutCancel(c.Request.Context()), bidInputDTO)
 	if err != nil {
 		restErr := rest_err.ConvertError(err)
 
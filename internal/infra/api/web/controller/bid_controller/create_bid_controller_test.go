@@ -0,0 +1,60 @@
+package bid_controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"fullcycle-auction_go/configuration/rest_err"
+	"fullcycle-auction_go/internal/infra/api/web/ratelimit"
+	"fullcycle-auction_go/internal/infra/broadcast"
+	"fullcycle-auction_go/internal/internal_error"
+	"fullcycle-auction_go/internal/usecase/bid_usecase"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateBidRateLimited(t *testing.T) {
+	t.Setenv("BATCH_INSERT_INTERVAL", "1h")
+
+	gin.SetMode(gin.TestMode)
+
+	bidHub := broadcast.NewBidHub()
+	bidUseCase := bid_usecase.NewBidUseCase(&fakeBidRepository{}, nil, bidHub)
+	t.Cleanup(bidUseCase.Close)
+	bidController := NewBidController(bidUseCase, bidHub, ratelimit.NewLimiter(0.001, 2))
+
+	router := gin.New()
+	router.POST("/bid", bidController.CreateBid)
+
+	postBid := func(remoteAddr string) *httptest.ResponseRecorder {
+		body := fmt.Sprintf(`{"user_id": %q, "auction_id": %q, "amount": 100}`,
+			uuid.New().String(), uuid.New().String())
+		request := httptest.NewRequest(http.MethodPost, "/bid", strings.NewReader(body))
+		request.Header.Set("Content-Type", "application/json")
+		request.RemoteAddr = remoteAddr
+		recorder := httptest.NewRecorder()
+
+		router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	// Cada lance usa outro user_id, e o limite continua valendo para o IP
+	require.Equal(t, http.StatusCreated, postBid("203.0.113.10:1234").Code)
+	require.Equal(t, http.StatusCreated, postBid("203.0.113.10:1234").Code)
+
+	recorder := postBid("203.0.113.10:5678")
+	require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+
+	var restErr rest_err.RestErr
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &restErr))
+	require.Equal(t, internal_error.CodeRateLimited, restErr.ErrorCode)
+	require.Equal(t, http.StatusTooManyRequests, restErr.Code)
+
+	// O limite de um IP não afeta os demais
+	require.Equal(t, http.StatusCreated, postBid("203.0.113.20:1234").Code)
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"net/http"
+	"strconv"
 )
 
 func (u *BidController) FindBidByAuctionId(c *gin.Context) {
@@ -21,7 +22,19 @@ func (u *BidController) FindBidByAuctionId(c *gin.Context) {
 		return
 	}
 
-	bidOutputList, err := u.bidUseCase.FindBidByAuctionId(context.Background(), auctionId)
+	limit, parseErr := strconv.ParseInt(c.DefaultQuery("limit", "0"), 10, 64)
+	if parseErr != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "limit",
+			Message: "Invalid integer value",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	bidOutputList, err := u.bidUseCase.FindBidByAuctionId(
+		context.WithoutCancel(c.Request.Context()), auctionId, c.Query("sort"), limit)
 	if err != nil {
 		errRest := rest_err.ConvertError(err)
 		c.JSON(errRest.Code, errRest)
@@ -0,0 +1,81 @@
+package bid_controller
+
+import (
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/configuration/rest_err"
+	"fullcycle-auction_go/internal/usecase/bid_usecase"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+	"net/http"
+	"time"
+)
+
+const streamWriteTimeout = 10 * time.Second
+
+var upgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+func (u *BidController) StreamBids(c *gin.Context) {
+	auctionId := c.Param("auctionId")
+
+	if err := uuid.Validate(auctionId); err != nil {
+		errRest := rest_err.NewBadRequestError("Invalid fields", rest_err.Causes{
+			Field:   "auctionId",
+			Message: "Invalid UUID value",
+		})
+
+		c.JSON(errRest.Code, errRest)
+		return
+	}
+
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		logger.Error("Error trying to upgrade bid stream connection", err,
+			zap.String("auction_id", auctionId))
+		return
+	}
+	defer conn.Close()
+
+	subscription := u.bidHub.Subscribe(auctionId)
+	defer u.bidHub.Unsubscribe(subscription)
+
+	// O cliente não envia mensagens; a leitura serve apenas para detectar a desconexão
+	disconnected := make(chan struct{})
+	go func() {
+		defer close(disconnected)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-disconnected:
+			return
+		case bid, ok := <-subscription.Bids:
+			if !ok {
+				conn.WriteControl(websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "client too slow"),
+					time.Now().Add(streamWriteTimeout))
+				return
+			}
+
+			conn.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+			if err := conn.WriteJSON(bid_usecase.BidOutputDTO{
+				Id:        bid.Id,
+				UserId:    bid.UserId,
+				AuctionId: bid.AuctionId,
+				Amount:    bid.Amount.Float64(),
+				Currency:  bid.Currency,
+				Timestamp: bid.Timestamp,
+			}); err != nil {
+				return
+			}
+		}
+	}
+}
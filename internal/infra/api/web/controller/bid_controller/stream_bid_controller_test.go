@@ -0,0 +1,97 @@
+package bid_controller
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/entity/bid_entity"
+	"fullcycle-auction_go/internal/infra/broadcast"
+	"fullcycle-auction_go/internal/internal_error"
+	"fullcycle-auction_go/internal/usecase/bid_usecase"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeBidRepository struct{}
+
+func (f *fakeBidRepository) CreateBid(
+	ctx context.Context, bidEntities []bid_entity.Bid) *internal_error.InternalError {
+	return nil
+}
+
+func (f *fakeBidRepository) FindBidByAuctionId(
+	ctx context.Context, auctionId string, sort string, limit int64) ([]bid_entity.Bid, *internal_error.InternalError) {
+	return nil, nil
+}
+
+func (f *fakeBidRepository) FindWinningBidByAuctionId(
+	ctx context.Context, auctionId string) (*bid_entity.Bid, *internal_error.InternalError) {
+	return nil, internal_error.NewNotFoundError("no bids found for this auction")
+}
+
+func (f *fakeBidRepository) DeleteBidsByAuctionId(
+	ctx context.Context, auctionId string) (int64, *internal_error.InternalError) {
+	return 0, nil
+}
+
+func (f *fakeBidRepository) IsLeadingBidder(
+	ctx context.Context, auctionId, userId string) (bool, *internal_error.InternalError) {
+	return false, nil
+}
+
+func TestStreamBids(t *testing.T) {
+	// Lote de tamanho 1 para que cada lance seja gravado e publicado imediatamente
+	t.Setenv("MAX_BATCH_SIZE", "1")
+	t.Setenv("BATCH_INSERT_INTERVAL", "1h")
+
+	gin.SetMode(gin.TestMode)
+
+	bidHub := broadcast.NewBidHub()
+	bidUseCase := bid_usecase.NewBidUseCase(&fakeBidRepository{}, nil, bidHub)
+	t.Cleanup(bidUseCase.Close)
+	bidController := NewBidController(bidUseCase, bidHub, nil)
+
+	router := gin.New()
+	router.GET("/bid/:auctionId/stream", bidController.StreamBids)
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	auctionId := uuid.New().String()
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/bid/" + auctionId + "/stream"
+
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// Aguarda a inscrição ser registrada antes de enviar o lance
+	require.Eventually(t, func() bool {
+		return bidHub.SubscriberCount(auctionId) == 1
+	}, 2*time.Second, 10*time.Millisecond)
+
+	userId := uuid.New().String()
+	require.Nil(t, bidUseCase.CreateBid(context.Background(), bid_usecase.BidInputDTO{
+		UserId:    userId,
+		AuctionId: auctionId,
+		Amount:    150,
+	}))
+
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+
+	var received bid_usecase.BidOutputDTO
+	require.NoError(t, conn.ReadJSON(&received))
+	require.Equal(t, auctionId, received.AuctionId)
+	require.Equal(t, userId, received.UserId)
+	require.Equal(t, 150.0, received.Amount)
+
+	// Ao desconectar, a inscrição deve ser removida do hub
+	require.NoError(t, conn.Close())
+	require.Eventually(t, func() bool {
+		return bidHub.SubscriberCount(auctionId) == 0
+	}, 2*time.Second, 10*time.Millisecond)
+}
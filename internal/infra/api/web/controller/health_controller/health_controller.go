@@ -0,0 +1,41 @@
+package health_controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"time"
+)
+
+// Quantos intervalos de verificação podem passar sem tick antes de o closer
+// ser considerado parado
+const staleTickMultiplier = 3
+
+type AuctionCloser interface {
+	LastTickAt() time.Time
+	CheckInterval() time.Duration
+}
+
+type HealthController struct {
+	auctionCloser AuctionCloser
+}
+
+func NewHealthController(auctionCloser AuctionCloser) *HealthController {
+	return &HealthController{
+		auctionCloser: auctionCloser,
+	}
+}
+
+func (h *HealthController) CheckAuctionCloser(c *gin.Context) {
+	lastTickAt := h.auctionCloser.LastTickAt()
+	maxTickAge := staleTickMultiplier * h.auctionCloser.CheckInterval()
+
+	status := http.StatusOK
+	if time.Since(lastTickAt) > maxTickAge {
+		status = http.StatusServiceUnavailable
+	}
+
+	c.JSON(status, gin.H{
+		"healthy":      status == http.StatusOK,
+		"last_tick_at": lastTickAt.UTC(),
+	})
+}
@@ -0,0 +1,50 @@
+package health_controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeAuctionCloser struct {
+	lastTickAt time.Time
+}
+
+func (f *fakeAuctionCloser) LastTickAt() time.Time {
+	return f.lastTickAt
+}
+
+func (f *fakeAuctionCloser) CheckInterval() time.Duration {
+	return time.Second
+}
+
+func TestCheckAuctionCloser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testCases := []struct {
+		name           string
+		lastTickAt     time.Time
+		expectedStatus int
+	}{
+		{name: "recent tick", lastTickAt: time.Now(), expectedStatus: http.StatusOK},
+		{name: "tick within limit", lastTickAt: time.Now().Add(-2 * time.Second), expectedStatus: http.StatusOK},
+		{name: "stale tick", lastTickAt: time.Now().Add(-4 * time.Second), expectedStatus: http.StatusServiceUnavailable},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/health/closer",
+				NewHealthController(&fakeAuctionCloser{lastTickAt: tc.lastTickAt}).CheckAuctionCloser)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/health/closer", nil))
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"fullcycle-auction_go/configuration/rest_err"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminToken libera as rotas administrativas só para requisições com
+// "Authorization: Bearer <token>". Sem token configurado todas as requisições
+// são recusadas, para que as rotas não fiquem abertas por esquecimento.
+func AdminToken(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		bearer, found := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if token == "" || !found ||
+			subtle.ConstantTimeCompare([]byte(bearer), []byte(token)) != 1 {
+			restErr := rest_err.NewUnauthorizedError("admin token is missing or invalid")
+			c.AbortWithStatusJSON(restErr.Code, restErr)
+			return
+		}
+
+		c.Next()
+	}
+}
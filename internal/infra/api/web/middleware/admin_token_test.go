@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRouter := func(token string) *gin.Engine {
+		router := gin.New()
+		router.GET("/admin", AdminToken(token), func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+		return router
+	}
+
+	testCases := []struct {
+		name           string
+		token          string
+		authorization  string
+		expectedStatus int
+	}{
+		{name: "accepts the configured token", token: "secret", authorization: "Bearer secret", expectedStatus: http.StatusOK},
+		{name: "rejects a wrong token", token: "secret", authorization: "Bearer other", expectedStatus: http.StatusUnauthorized},
+		{name: "rejects a missing header", token: "secret", expectedStatus: http.StatusUnauthorized},
+		{name: "rejects a token without the bearer scheme", token: "secret", authorization: "secret", expectedStatus: http.StatusUnauthorized},
+		{name: "rejects everything without a configured token", authorization: "Bearer ", expectedStatus: http.StatusUnauthorized},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, "/admin", nil)
+			if tc.authorization != "" {
+				request.Header.Set("Authorization", tc.authorization)
+			}
+			recorder := httptest.NewRecorder()
+
+			newRouter(tc.token).ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedStatus, recorder.Code)
+			if tc.expectedStatus == http.StatusUnauthorized {
+				require.Contains(t, recorder.Body.String(), `"error_code":"UNAUTHORIZED"`)
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"fullcycle-auction_go/configuration/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const CorrelationIdHeader = "X-Request-Id"
+
+// CorrelationId reaproveita o X-Request-Id enviado pelo cliente ou gera um
+// novo, devolve o id na resposta e o coloca no contexto da requisição para
+// que os logs das camadas seguintes possam ser agrupados.
+func CorrelationId() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		correlationId := c.GetHeader(CorrelationIdHeader)
+		if correlationId == "" {
+			correlationId = uuid.New().String()
+		}
+
+		c.Header(CorrelationIdHeader, correlationId)
+		c.Request = c.Request.WithContext(
+			logger.ContextWithCorrelationId(c.Request.Context(), correlationId))
+
+		c.Next()
+	}
+}
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"fullcycle-auction_go/configuration/logger"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCorrelationId(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var correlationIdInContext string
+	router := gin.New()
+	router.Use(CorrelationId())
+	router.GET("/", func(c *gin.Context) {
+		correlationIdInContext = logger.CorrelationIdFromContext(c.Request.Context())
+		c.Status(http.StatusOK)
+	})
+
+	t.Run("keeps the id sent by the client", func(t *testing.T) {
+		request := httptest.NewRequest(http.MethodGet, "/", nil)
+		request.Header.Set(CorrelationIdHeader, "request-123")
+		recorder := httptest.NewRecorder()
+
+		router.ServeHTTP(recorder, request)
+
+		require.Equal(t, "request-123", correlationIdInContext)
+		require.Equal(t, "request-123", recorder.Header().Get(CorrelationIdHeader))
+	})
+
+	t.Run("generates an id when missing", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		require.NotEmpty(t, correlationIdInContext)
+		require.Equal(t, correlationIdInContext, recorder.Header().Get(CorrelationIdHeader))
+	})
+}
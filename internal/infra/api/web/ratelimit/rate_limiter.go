@@ -0,0 +1,147 @@
+package ratelimit
+
+import (
+	"fullcycle-auction_go/configuration/logger"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+)
+
+// Limiter aplica um token bucket por chave, como o IP do cliente. Baldes sem uso há
+// tempo suficiente para encher de novo são descartados, já que equivalem a um
+// balde novo; assim a memória não cresce com chaves que sumiram.
+type Limiter struct {
+	limit       rate.Limit
+	burst       int
+	idleTimeout time.Duration
+	now         func() time.Time
+
+	mutex     *sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+type bucket struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// Intervalo mínimo entre varreduras dos baldes ociosos
+const minSweepInterval = time.Minute
+
+func NewLimiter(perSecond float64, burst int) *Limiter {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &Limiter{
+		limit:       rate.Limit(perSecond),
+		burst:       burst,
+		idleTimeout: time.Duration(float64(burst) / perSecond * float64(time.Second)),
+		now:         time.Now,
+		mutex:       &sync.Mutex{},
+		buckets:     make(map[string]*bucket),
+		lastSweep:   time.Now(),
+	}
+}
+
+// NewBidLimiter lê BID_RATE_LIMIT (lances por segundo) e BID_RATE_BURST.
+// Sem BID_RATE_LIMIT o limite fica desligado e nil é retornado.
+func NewBidLimiter() *Limiter {
+	perSecond := getBidRateLimit()
+	if perSecond <= 0 {
+		return nil
+	}
+
+	return NewLimiter(perSecond, getBidRateBurst(perSecond))
+}
+
+// Allow consome um token da chave. Um Limiter nil permite tudo.
+func (l *Limiter) Allow(key string) bool {
+	if l == nil {
+		return true
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	keyBucket, ok := l.buckets[key]
+	if !ok {
+		keyBucket = &bucket{limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.buckets[key] = keyBucket
+	}
+	keyBucket.lastSeen = now
+
+	return keyBucket.limiter.AllowN(now, 1)
+}
+
+// Len retorna quantas chaves estão sendo acompanhadas
+func (l *Limiter) Len() int {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return len(l.buckets)
+}
+
+func (l *Limiter) sweep(now time.Time) {
+	sweepInterval := l.idleTimeout
+	if sweepInterval < minSweepInterval {
+		sweepInterval = minSweepInterval
+	}
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+
+	for key, keyBucket := range l.buckets {
+		if now.Sub(keyBucket.lastSeen) >= l.idleTimeout {
+			delete(l.buckets, key)
+		}
+	}
+	l.lastSweep = now
+}
+
+func getBidRateLimit() float64 {
+	bidRateLimit := os.Getenv("BID_RATE_LIMIT")
+	if bidRateLimit == "" {
+		return 0
+	}
+
+	value, err := strconv.ParseFloat(bidRateLimit, 64)
+	if err != nil || value < 0 {
+		logger.Warn("BID_RATE_LIMIT is invalid, bid rate limiting is disabled",
+			zap.String("value", bidRateLimit))
+		return 0
+	}
+
+	return value
+}
+
+// getBidRateBurst usa por padrão o equivalente a um segundo de lances
+func getBidRateBurst(perSecond float64) int {
+	defaultBurst := int(perSecond)
+	if defaultBurst < 1 {
+		defaultBurst = 1
+	}
+
+	bidRateBurst := os.Getenv("BID_RATE_BURST")
+	if bidRateBurst == "" {
+		return defaultBurst
+	}
+
+	value, err := strconv.Atoi(bidRateBurst)
+	if err != nil || value < 1 {
+		logger.Warn("BID_RATE_BURST is invalid, using default value",
+			zap.String("value", bidRateBurst),
+			zap.Int("default", defaultBurst))
+		return defaultBurst
+	}
+
+	return value
+}
@@ -0,0 +1,88 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLimiter(perSecond float64, burst int) (*Limiter, *time.Time) {
+	now := time.Now()
+	limiter := NewLimiter(perSecond, burst)
+	limiter.now = func() time.Time { return now }
+	limiter.lastSweep = now
+
+	return limiter, &now
+}
+
+func TestLimiterBurstPastLimit(t *testing.T) {
+	limiter, now := newTestLimiter(2, 3)
+
+	for i := 0; i < 3; i++ {
+		require.True(t, limiter.Allow("user:1"), "request %d should be allowed", i)
+	}
+	require.False(t, limiter.Allow("user:1"))
+
+	// Outras chaves têm seu próprio balde
+	require.True(t, limiter.Allow("user:2"))
+
+	// Meio segundo repõe um token a 2 por segundo
+	*now = now.Add(500 * time.Millisecond)
+	require.True(t, limiter.Allow("user:1"))
+	require.False(t, limiter.Allow("user:1"))
+}
+
+func TestLimiterRemovesIdleKeys(t *testing.T) {
+	limiter, now := newTestLimiter(1, 5)
+
+	require.True(t, limiter.Allow("user:1"))
+	require.True(t, limiter.Allow("user:2"))
+	require.Equal(t, 2, limiter.Len())
+
+	*now = now.Add(30 * time.Second)
+	require.True(t, limiter.Allow("user:2"))
+	require.Equal(t, 2, limiter.Len(), "keys are only swept once per interval")
+
+	*now = now.Add(minSweepInterval - 32*time.Second)
+	require.True(t, limiter.Allow("user:2"))
+
+	// Na varredura user:1 está ocioso há mais do que o tempo de encher o
+	// balde; user:2 foi usado há 2 segundos
+	*now = now.Add(2 * time.Second)
+	require.True(t, limiter.Allow("user:3"))
+	require.Equal(t, 2, limiter.Len())
+
+	limiter.mutex.Lock()
+	_, tracked := limiter.buckets["user:1"]
+	limiter.mutex.Unlock()
+	require.False(t, tracked)
+}
+
+func TestNilLimiterAllowsEverything(t *testing.T) {
+	var limiter *Limiter
+	for i := 0; i < 100; i++ {
+		require.True(t, limiter.Allow("user:1"))
+	}
+}
+
+func TestNewBidLimiter(t *testing.T) {
+	t.Setenv("BID_RATE_LIMIT", "")
+	require.Nil(t, NewBidLimiter())
+
+	t.Setenv("BID_RATE_LIMIT", "invalid")
+	require.Nil(t, NewBidLimiter())
+
+	t.Setenv("BID_RATE_LIMIT", "0.5")
+	t.Setenv("BID_RATE_BURST", "")
+	limiter := NewBidLimiter()
+	require.NotNil(t, limiter)
+	require.Equal(t, 1, limiter.burst)
+
+	t.Setenv("BID_RATE_LIMIT", "10")
+	t.Setenv("BID_RATE_BURST", "20")
+	limiter = NewBidLimiter()
+	require.NotNil(t, limiter)
+	require.Equal(t, 20, limiter.burst)
+	require.Equal(t, 2*time.Second, limiter.idleTimeout)
+}
@@ -0,0 +1,95 @@
+package broadcast
+
+import (
+	"fullcycle-auction_go/internal/entity/bid_entity"
+	"sync"
+)
+
+const defaultSubscriberBufferSize = 16
+
+type BidSubscription struct {
+	AuctionId string
+	Bids      <-chan bid_entity.Bid
+
+	bids chan bid_entity.Bid
+}
+
+type BidHub struct {
+	mutex       *sync.Mutex
+	bufferSize  int
+	subscribers map[string]map[*BidSubscription]struct{}
+}
+
+func NewBidHub() *BidHub {
+	return &BidHub{
+		mutex:       &sync.Mutex{},
+		bufferSize:  defaultSubscriberBufferSize,
+		subscribers: make(map[string]map[*BidSubscription]struct{}),
+	}
+}
+
+func (h *BidHub) Subscribe(auctionId string) *BidSubscription {
+	bids := make(chan bid_entity.Bid, h.bufferSize)
+	subscription := &BidSubscription{
+		AuctionId: auctionId,
+		Bids:      bids,
+		bids:      bids,
+	}
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if h.subscribers[auctionId] == nil {
+		h.subscribers[auctionId] = make(map[*BidSubscription]struct{})
+	}
+	h.subscribers[auctionId][subscription] = struct{}{}
+
+	return subscription
+}
+
+func (h *BidHub) Unsubscribe(subscription *BidSubscription) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.remove(subscription)
+}
+
+func (h *BidHub) SubscriberCount(auctionId string) int {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	return len(h.subscribers[auctionId])
+}
+
+// Publish nunca bloqueia: clientes lentos, com o buffer cheio, são
+// desconectados para não atrasar o processamento dos lances.
+func (h *BidHub) Publish(bid bid_entity.Bid) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	for subscription := range h.subscribers[bid.AuctionId] {
+		select {
+		case subscription.bids <- bid:
+		default:
+			h.remove(subscription)
+		}
+	}
+}
+
+func (h *BidHub) remove(subscription *BidSubscription) {
+	subscriptions, ok := h.subscribers[subscription.AuctionId]
+	if !ok {
+		return
+	}
+
+	if _, ok := subscriptions[subscription]; !ok {
+		return
+	}
+
+	delete(subscriptions, subscription)
+	close(subscription.bids)
+
+	if len(subscriptions) == 0 {
+		delete(h.subscribers, subscription.AuctionId)
+	}
+}
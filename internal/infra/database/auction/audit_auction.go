@@ -0,0 +1,137 @@
+package auction
+
+import (
+	"context"
+	"fmt"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/infra/database/database_error"
+	"fullcycle-auction_go/internal/internal_error"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+// Coleção com o histórico de mudanças de status dos leilões. Só recebe
+// inserções: as entradas nunca são alteradas nem apagadas, nem pela limpeza
+// de leilões antigos.
+const auditCollectionName = "auction_audit"
+
+// Autores das transições. As feitas pela API levam o id do dono do leilão
+// quando ele existe.
+const (
+	auditActorSystem = "system"
+	auditActorUser   = "user"
+)
+
+type AuctionTransitionMongo struct {
+	// O ObjectID cresce com a inserção e desempata transições no mesmo segundo
+	Id        primitive.ObjectID            `bson:"_id"`
+	AuctionId string                        `bson:"auction_id"`
+	From      *auction_entity.AuctionStatus `bson:"from,omitempty"`
+	To        auction_entity.AuctionStatus  `bson:"to"`
+	Actor     string                        `bson:"actor"`
+	Reason    string                        `bson:"reason,omitempty"`
+	Timestamp int64                         `bson:"timestamp"`
+}
+
+func (ar *AuctionRepository) auditCollection() *mongo.Collection {
+	return ar.Collection.Database().Collection(auditCollectionName)
+}
+
+// recordTransition grava uma mudança de status já aplicada ao leilão. from é
+// nil na criação. Uma falha aqui não desfaz a mudança; fica registrada no log.
+func (ar *AuctionRepository) recordTransition(
+	ctx context.Context,
+	auctionId string,
+	from *auction_entity.AuctionStatus,
+	to auction_entity.AuctionStatus,
+	actor, reason string) {
+	transition := AuctionTransitionMongo{
+		Id:        primitive.NewObjectID(),
+		AuctionId: auctionId,
+		From:      from,
+		To:        to,
+		Actor:     actor,
+		Reason:    reason,
+		Timestamp: ar.now().Unix(),
+	}
+
+	// O _id é gerado aqui, então repetir a inserção após um erro transitório
+	// nunca duplica a entrada
+	err := retryTransient(ctx, closerRetryAttempts, closerRetryBaseDelay, func() error {
+		_, err := ar.auditCollection().InsertOne(ctx, transition)
+		if mongo.IsDuplicateKeyError(err) {
+			return nil
+		}
+		return err
+	})
+	if err != nil {
+		logger.WithContext(ctx).Error("Error trying to record auction transition", err,
+			zap.String("auction_id", auctionId),
+			zap.Stringer("to", to),
+			zap.String("actor", actor))
+	}
+}
+
+// FindAuctionHistory retorna as mudanças de status do leilão na ordem em que
+// aconteceram.
+func (ar *AuctionRepository) FindAuctionHistory(
+	ctx context.Context, id string) ([]auction_entity.AuctionTransition, *internal_error.InternalError) {
+	ctx, span := ar.startSpan(ctx, "FindAuctionHistory", attribute.String("auction.id", id))
+	history, err := ar.findAuctionHistory(ctx, id)
+	endSpan(span, err)
+
+	return history, err
+}
+
+func (ar *AuctionRepository) findAuctionHistory(
+	ctx context.Context, id string) ([]auction_entity.AuctionTransition, *internal_error.InternalError) {
+	ctx, cancel := ar.withOpTimeout(ctx)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := ar.auditCollection().Find(ctx, bson.M{"auction_id": id}, opts)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Error trying to find history of auction = %s", id), err)
+		return nil, database_error.NewInternalError(err, "Error trying to find auction history")
+	}
+	defer closeCursor(ctx, cursor)
+
+	var transitionsMongo []AuctionTransitionMongo
+	if err := cursor.All(ctx, &transitionsMongo); err != nil {
+		logger.Error(fmt.Sprintf("Error trying to decode history of auction = %s", id), err)
+		return nil, database_error.NewInternalError(err, "Error trying to find auction history")
+	}
+
+	history := make([]auction_entity.AuctionTransition, 0, len(transitionsMongo))
+	for _, transitionMongo := range transitionsMongo {
+		history = append(history, auction_entity.AuctionTransition{
+			AuctionId: transitionMongo.AuctionId,
+			From:      transitionMongo.From,
+			To:        transitionMongo.To,
+			Actor:     transitionMongo.Actor,
+			Reason:    transitionMongo.Reason,
+			Timestamp: time.Unix(transitionMongo.Timestamp, 0),
+		})
+	}
+
+	return history, nil
+}
+
+func auditActor(userId string) string {
+	if userId == "" {
+		return auditActorUser
+	}
+
+	return userId
+}
+
+func statusRef(status auction_entity.AuctionStatus) *auction_entity.AuctionStatus {
+	return &status
+}
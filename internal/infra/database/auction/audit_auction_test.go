@@ -0,0 +1,88 @@
+package auction
+
+import (
+	"context"
+	"fmt"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestAuctionHistoryRecordsCreateAndAutoClose(t *testing.T) {
+	t.Setenv("AUCTION_INTERVAL", "2s")
+	t.Setenv("AUCTION_CHECK_INTERVAL", "1h")
+
+	ctx := context.Background()
+
+	client, db, container := getTestDatabase(ctx, t)
+	defer func() {
+		if err := container.Terminate(ctx); err != nil {
+			log.Printf("failed to terminate container: %s", err)
+		}
+	}()
+	defer func() {
+		if err := client.Disconnect(ctx); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	collectionName := fmt.Sprintf("auctions_test_%d", time.Now().UnixNano())
+	collection := db.Collection(collectionName)
+	collection.Drop(ctx)
+	defer collection.Drop(ctx)
+
+	repo := NewAuctionRepositoryWithCollection(ctx, db, collectionName)
+	defer repo.Close()
+
+	clock := newFakeClock()
+	repo.SetClock(clock)
+
+	userId := uuid.New().String()
+	auctionEntity := &auction_entity.Auction{
+		Id:          uuid.New().String(),
+		UserId:      userId,
+		ProductName: "Audited Product",
+		Category:    "Electronics",
+		Description: "This auction is tracked by the audit log",
+		Condition:   auction_entity.New,
+		Status:      auction_entity.Active,
+		Timestamp:   clock.Now(),
+	}
+	defer repo.auditCollection().DeleteMany(ctx, bson.M{"auction_id": auctionEntity.Id})
+
+	if _, err := repo.CreateAuction(ctx, auctionEntity); err != nil {
+		t.Fatalf("Failed to create auction: %v", err)
+	}
+	createdAt := clock.Now()
+
+	clock.Advance(4 * time.Second)
+	require.NoError(t, repo.closeExpiredAuctions(ctx))
+
+	// Um tick sem nada a fechar não gera novas entradas
+	require.NoError(t, repo.closeExpiredAuctions(ctx))
+
+	history, err := repo.FindAuctionHistory(ctx, auctionEntity.Id)
+	require.Nil(t, err)
+	require.Len(t, history, 2)
+
+	require.Nil(t, history[0].From)
+	require.Equal(t, auction_entity.Active, history[0].To)
+	require.Equal(t, userId, history[0].Actor)
+	require.Equal(t, "created", history[0].Reason)
+	require.Equal(t, createdAt.Unix(), history[0].Timestamp.Unix())
+
+	require.Equal(t, statusRef(auction_entity.Active), history[1].From)
+	require.Equal(t, auction_entity.Completed, history[1].To)
+	require.Equal(t, auditActorSystem, history[1].Actor)
+	require.Equal(t, "expired", history[1].Reason)
+	require.Equal(t, clock.Now().Unix(), history[1].Timestamp.Unix())
+
+	history, err = repo.FindAuctionHistory(ctx, uuid.New().String())
+	require.Nil(t, err)
+	require.Empty(t, history)
+}
@@ -0,0 +1,68 @@
+package auction
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/entity/bid_entity"
+	"fullcycle-auction_go/internal/internal_error"
+)
+
+// BidStore é o acesso do repositório de leilões aos lances. É implementado
+// pelo repositório de lances, que depende deste e por isso se registra com
+// SetBidStore ao ser criado; o repositório de leilões nunca lê a coleção de
+// lances diretamente.
+type BidStore interface {
+	// CountBidsByAuctionId conta os lances do leilão, em qualquer moeda.
+	CountBidsByAuctionId(ctx context.Context, auctionId string) (int64, *internal_error.InternalError)
+
+	// FindHighestBidInCurrency retorna o maior lance do leilão na moeda
+	// informada, o mais antigo no empate, e se o leilão recebeu algum lance.
+	FindHighestBidInCurrency(
+		ctx context.Context, auctionId, currency string) (*bid_entity.Bid, bool, *internal_error.InternalError)
+
+	// ListBidsByAuctionId retorna todos os lances do leilão ordenados por
+	// (timestamp, _id).
+	ListBidsByAuctionId(ctx context.Context, auctionId string) ([]bid_entity.Bid, *internal_error.InternalError)
+
+	// InsertWinningBid grava o lance que fechou o leilão pelo compre já, sem
+	// exigir que o leilão ainda esteja ativo.
+	InsertWinningBid(ctx context.Context, bid bid_entity.Bid) *internal_error.InternalError
+
+	// DeleteBidsByAuctionIds apaga os lances dos leilões informados.
+	DeleteBidsByAuctionIds(ctx context.Context, auctionIds []string) (int64, *internal_error.InternalError)
+
+	// CollectionName é o nome da coleção de lances, usado só no $lookup de
+	// FindAuctionWithBids para trazer o leilão e os lances numa ida ao banco.
+	CollectionName() string
+}
+
+// SetBidStore define de onde o repositório lê os lances. Até ele ser chamado,
+// as operações que dependem dos lances falham e os vencedores dos leilões
+// fechados ficam pendentes.
+func (ar *AuctionRepository) SetBidStore(bidStore BidStore) {
+	ar.mutex.Lock()
+	defer ar.mutex.Unlock()
+
+	ar.bids = bidStore
+}
+
+func (ar *AuctionRepository) bidStore() (BidStore, *internal_error.InternalError) {
+	ar.mutex.Lock()
+	defer ar.mutex.Unlock()
+
+	if ar.bids == nil {
+		return nil, internal_error.NewInternalServerError("bid store is not configured")
+	}
+
+	return ar.bids, nil
+}
+
+// countBids conta os lances do leilão pelo BidStore.
+func (ar *AuctionRepository) countBids(
+	ctx context.Context, auctionId string) (int64, *internal_error.InternalError) {
+	bidStore, err := ar.bidStore()
+	if err != nil {
+		return 0, err
+	}
+
+	return bidStore.CountBidsByAuctionId(ctx, auctionId)
+}
@@ -0,0 +1,173 @@
+package auction
+
+import (
+	"context"
+	"fmt"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/entity/bid_entity"
+	"fullcycle-auction_go/internal/infra/database/database_error"
+	"fullcycle-auction_go/internal/infra/database/decimal"
+	"fullcycle-auction_go/internal/internal_error"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Coleção onde os testes gravam lances, a mesma do repositório de lances
+const testBidsCollectionName = "bids"
+
+// collectionBidStore é um BidStore sobre a coleção de lances para os testes
+// deste pacote, que não podem importar o repositório de lances.
+type collectionBidStore struct {
+	collection *mongo.Collection
+}
+
+func newTestBidStore(db *mongo.Database) *collectionBidStore {
+	return &collectionBidStore{collection: db.Collection(testBidsCollectionName)}
+}
+
+func (s *collectionBidStore) CountBidsByAuctionId(
+	ctx context.Context, auctionId string) (int64, *internal_error.InternalError) {
+	count, err := s.collection.CountDocuments(ctx, bson.M{"auction_id": auctionId})
+	if err != nil {
+		return 0, database_error.NewInternalError(err, "Error trying to count bids")
+	}
+
+	return count, nil
+}
+
+func (s *collectionBidStore) FindHighestBidInCurrency(
+	ctx context.Context, auctionId, currency string) (*bid_entity.Bid, bool, *internal_error.InternalError) {
+	currencyFilter := interface{}(currency)
+	if currency == bid_entity.DefaultCurrency {
+		currencyFilter = bson.M{"$in": bson.A{currency, nil}}
+	}
+
+	opts := options.FindOne().
+		SetSort(bson.D{{Key: "amount", Value: -1}, {Key: "timestamp", Value: 1}, {Key: "_id", Value: 1}})
+
+	var bid auctionBidMongo
+	err := s.collection.FindOne(ctx,
+		bson.M{"auction_id": auctionId, "currency": currencyFilter}, opts).Decode(&bid)
+	if err == nil {
+		winner := toTestBid(bid)
+		return &winner, true, nil
+	}
+	if !database_error.IsNotFound(err) {
+		return nil, false, database_error.NewInternalError(err, "Error trying to find the highest bid")
+	}
+
+	count, countErr := s.CountBidsByAuctionId(ctx, auctionId)
+	if countErr != nil {
+		return nil, false, countErr
+	}
+
+	return nil, count > 0, nil
+}
+
+func (s *collectionBidStore) ListBidsByAuctionId(
+	ctx context.Context, auctionId string) ([]bid_entity.Bid, *internal_error.InternalError) {
+	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: 1}, {Key: "_id", Value: 1}})
+
+	cursor, err := s.collection.Find(ctx, bson.M{"auction_id": auctionId}, opts)
+	if err != nil {
+		return nil, database_error.NewInternalError(err, "Error trying to find bids")
+	}
+	defer cursor.Close(ctx)
+
+	var bidsMongo []auctionBidMongo
+	if err := cursor.All(ctx, &bidsMongo); err != nil {
+		return nil, database_error.NewInternalError(err, "Error trying to find bids")
+	}
+
+	bids := make([]bid_entity.Bid, 0, len(bidsMongo))
+	for _, bid := range bidsMongo {
+		bids = append(bids, toTestBid(bid))
+	}
+
+	return bids, nil
+}
+
+func (s *collectionBidStore) InsertWinningBid(
+	ctx context.Context, bid bid_entity.Bid) *internal_error.InternalError {
+	_, err := s.collection.InsertOne(ctx, auctionBidMongo{
+		Id:        bid.Id,
+		UserId:    bid.UserId,
+		AuctionId: bid.AuctionId,
+		Amount:    decimal.Amount(bid.Amount),
+		Timestamp: bid.Timestamp.Unix(),
+		Currency:  bid.Currency,
+	})
+	if err != nil {
+		return database_error.NewInternalError(err, "Error trying to insert bid")
+	}
+
+	return nil
+}
+
+func (s *collectionBidStore) DeleteBidsByAuctionIds(
+	ctx context.Context, auctionIds []string) (int64, *internal_error.InternalError) {
+	result, err := s.collection.DeleteMany(ctx, bson.M{"auction_id": bson.M{"$in": auctionIds}})
+	if err != nil {
+		return 0, database_error.NewInternalError(err, "Error trying to delete bids")
+	}
+
+	return result.DeletedCount, nil
+}
+
+func (s *collectionBidStore) CollectionName() string {
+	return s.collection.Name()
+}
+
+func toTestBid(bid auctionBidMongo) bid_entity.Bid {
+	return bid_entity.Bid{
+		Id:        bid.Id,
+		UserId:    bid.UserId,
+		AuctionId: bid.AuctionId,
+		Amount:    bid_entity.Amount(bid.Amount),
+		Timestamp: timeFromUnix(bid.Timestamp),
+		Currency:  bid_entity.CurrencyOrDefault(bid.Currency),
+	}
+}
+
+func TestOperationsWithoutBidStore(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	db := getSharedTestDatabase(ctx, t)
+
+	collectionName := fmt.Sprintf("auctions_test_%d", time.Now().UnixNano())
+	collection := db.Collection(collectionName)
+	collection.Drop(ctx)
+	defer collection.Drop(ctx)
+
+	repo := NewAuctionRepositoryWithCollection(ctx, db, collectionName)
+	defer repo.Close()
+
+	auctionEntity := &auction_entity.Auction{
+		Id:          "test-auction-no-bid-store",
+		ProductName: "Product",
+		Category:    "Electronics",
+		Description: "Auction of a repository without bid store",
+		Condition:   auction_entity.New,
+		Status:      auction_entity.Active,
+		Timestamp:   time.Now(),
+	}
+	_, err := repo.CreateAuction(ctx, auctionEntity)
+	require.Nil(t, err)
+
+	err = repo.CancelAuction(ctx, auctionEntity.Id, "sold elsewhere")
+	require.NotNil(t, err)
+	require.Equal(t, "internal_server_error", err.Err)
+
+	stored, err := repo.FindAuctionById(ctx, auctionEntity.Id)
+	require.Nil(t, err)
+	require.Equal(t, auction_entity.Active, stored.Status)
+
+	repo.SetBidStore(newTestBidStore(db))
+	require.Nil(t, repo.CancelAuction(ctx, auctionEntity.Id, "sold elsewhere"))
+}
@@ -0,0 +1,142 @@
+package auction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/entity/bid_entity"
+	"fullcycle-auction_go/internal/infra/database/database_error"
+	"fullcycle-auction_go/internal/infra/database/decimal"
+	"fullcycle-auction_go/internal/internal_error"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+// CompleteWithBuyNow fecha o leilão com o lance que alcançou o preço de
+// compre já e grava esse lance como vencedor. A troca Active -> Completed é
+// atômica no documento: quando dois lances de compre já chegam juntos só o
+// primeiro fecha o leilão e o outro recebe AUCTION_NOT_ACTIVE.
+func (ar *AuctionRepository) CompleteWithBuyNow(
+	ctx context.Context, bid bid_entity.Bid) *internal_error.InternalError {
+	ctx, span := ar.startSpan(ctx, "CompleteWithBuyNow",
+		attribute.String("auction.id", bid.AuctionId),
+		attribute.String("bid.id", bid.Id))
+	err := ar.completeWithBuyNow(ctx, bid)
+	endSpan(span, err)
+
+	return err
+}
+
+func (ar *AuctionRepository) completeWithBuyNow(
+	ctx context.Context, bid bid_entity.Bid) *internal_error.InternalError {
+	ctx, cancel := ar.withOpTimeout(ctx)
+	defer cancel()
+
+	log := logger.WithContext(ctx)
+
+	bidStore, storeErr := ar.bidStore()
+	if storeErr != nil {
+		log.Error("Error trying to close auction with buy now", storeErr,
+			zap.String("auction_id", bid.AuctionId))
+		return storeErr
+	}
+
+	// O leilão expirado que aguarda o closer não pode mais ser arrematado;
+	// como nos lances, o AUCTION_CLOSE_GRACE ainda conta como prazo
+	deadline := ar.now().Add(-ar.closeGrace)
+	filter := bson.M{
+		"_id":           bid.AuctionId,
+		"status":        auction_entity.Active,
+		"deleted_at":    nil,
+		"closed_at":     nil,
+		"buy_now_price": bson.M{"$gt": 0},
+		"$or": bson.A{
+			bson.M{"expires_at": bson.M{"$gte": deadline.Unix()}},
+			bson.M{
+				"expires_at": bson.M{"$exists": false},
+				"timestamp":  bson.M{"$gte": deadline.Add(-ar.interval()).Unix()},
+			},
+		},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"status":         auction_entity.Completed,
+			"closed_at":      ar.now().Unix(),
+			"winning_bid_id": bid.Id,
+			"winner_user_id": bid.UserId,
+			"winning_amount": decimal.Amount(bid.Amount),
+		},
+		"$inc": bson.M{"version": 1},
+	}
+
+	var auctionEntityMongo AuctionEntityMongo
+	err := ar.Collection.FindOneAndUpdate(ctx, filter, update).Decode(&auctionEntityMongo)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return internal_error.NewBadRequestError("auction is not active").
+			WithCode(internal_error.CodeAuctionNotActive)
+	}
+	if err != nil {
+		log.Error(fmt.Sprintf("Error trying to close auction = %s with buy now", bid.AuctionId), err)
+		return database_error.NewInternalError(err, "Error trying to close auction with buy now")
+	}
+
+	// O lance é gravado aqui, depois do fechamento, para que nenhum outro
+	// lance entre no leilão já vendido
+	if insertErr := bidStore.InsertWinningBid(ctx, bid); insertErr != nil {
+		log.Error("Error trying to insert buy now bid, reopening the auction", insertErr,
+			zap.String("auction_id", bid.AuctionId))
+		ar.undoBuyNow(ctx, bid)
+		return insertErr
+	}
+
+	// O compre já nunca fica abaixo da reserva
+	if auctionEntityMongo.ReservePrice > 0 {
+		_, err := ar.Collection.UpdateOne(ctx,
+			bson.M{"_id": bid.AuctionId},
+			bson.M{
+				"$set": bson.M{"reserve_met": true},
+				"$inc": bson.M{"version": 1},
+			})
+		if err != nil {
+			log.Error("Error trying to record the reserve price result", err,
+				zap.String("auction_id", bid.AuctionId))
+		}
+	}
+
+	ar.recordTransition(ctx, bid.AuctionId, statusRef(auction_entity.Active), auction_entity.Completed,
+		auditActor(bid.UserId), "buy now")
+	ar.metrics.ClosedAuctions.WithLabelValues("buy_now").Inc()
+
+	log.Info("Auction closed with buy now",
+		zap.String("auction_id", bid.AuctionId),
+		zap.String("bid_id", bid.Id),
+		zap.Stringer("amount", bid.Amount))
+
+	if onAuctionClosed := ar.auctionClosedCallback(); onAuctionClosed != nil {
+		onAuctionClosed(ctx, bid.AuctionId)
+	}
+
+	return nil
+}
+
+// undoBuyNow devolve o leilão para Active quando o lance vencedor não pôde ser
+// gravado. O filtro pelo winning_bid_id garante que só o fechamento deste
+// lance é desfeito.
+func (ar *AuctionRepository) undoBuyNow(ctx context.Context, bid bid_entity.Bid) {
+	_, err := ar.Collection.UpdateOne(ctx,
+		bson.M{"_id": bid.AuctionId, "winning_bid_id": bid.Id},
+		bson.M{
+			"$set":   bson.M{"status": auction_entity.Active},
+			"$unset": bson.M{"closed_at": "", "winning_bid_id": "", "winner_user_id": "", "winning_amount": ""},
+			"$inc":   bson.M{"version": 1},
+		})
+	if err != nil {
+		logger.WithContext(ctx).Error("Error trying to reopen auction after a failed buy now", err,
+			zap.String("auction_id", bid.AuctionId))
+	}
+}
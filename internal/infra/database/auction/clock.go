@@ -0,0 +1,15 @@
+package auction
+
+import "time"
+
+// Clock abstrai a hora atual usada pela rotina de fechamento, permitindo que
+// os testes avancem o tempo sem depender de sleep.
+type Clock interface {
+	Now() time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
@@ -0,0 +1,277 @@
+package auction
+
+import (
+	"context"
+	"fmt"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/infra/database/memory"
+	"fullcycle-auction_go/internal/internal_error"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// closeSemanticsRepository junta o que os testes de fechamento precisam de
+// cada implementação do repositório de leilões
+type closeSemanticsRepository struct {
+	repo          auction_entity.AuctionRepositoryInterface
+	clock         *fakeClock
+	closeAuctions func(ctx context.Context, ids []string) (int64, *internal_error.InternalError)
+	closeExpired  func(ctx context.Context)
+	recordBid     func(ctx context.Context, auctionId string)
+	closedIds     func() []string
+}
+
+type closedIdsRecorder struct {
+	mutex *sync.Mutex
+	ids   []string
+}
+
+func (r *closedIdsRecorder) onAuctionClosed(ctx context.Context, auctionId string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.ids = append(r.ids, auctionId)
+}
+
+func (r *closedIdsRecorder) closed() []string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	ids := append([]string(nil), r.ids...)
+	sort.Strings(ids)
+	return ids
+}
+
+func newMongoCloseSemanticsRepository(ctx context.Context, t *testing.T) closeSemanticsRepository {
+	db := getSharedTestDatabase(ctx, t)
+
+	collectionName := fmt.Sprintf("auctions_test_%d", time.Now().UnixNano())
+	collection := db.Collection(collectionName)
+	collection.Drop(ctx)
+	t.Cleanup(func() { collection.Drop(ctx) })
+
+	repo := NewAuctionRepositoryWithCollection(ctx, db, collectionName)
+	t.Cleanup(repo.Close)
+	repo.SetBidStore(newTestBidStore(db))
+
+	clock := newFakeClock()
+	repo.SetClock(clock)
+
+	recorder := &closedIdsRecorder{mutex: &sync.Mutex{}}
+	repo.SetOnAuctionClosed(recorder.onAuctionClosed)
+
+	bidsCollection := db.Collection(testBidsCollectionName)
+
+	return closeSemanticsRepository{
+		repo:          repo,
+		clock:         clock,
+		closeAuctions: repo.CloseAuctions,
+		closeExpired: func(ctx context.Context) {
+			require.NoError(t, repo.closeExpiredAuctions(ctx))
+		},
+		recordBid: func(ctx context.Context, auctionId string) {
+			_, err := bidsCollection.InsertOne(ctx, bson.M{
+				"_id":        fmt.Sprintf("bid-%d", time.Now().UnixNano()),
+				"auction_id": auctionId,
+			})
+			require.NoError(t, err)
+			t.Cleanup(func() { bidsCollection.DeleteMany(ctx, bson.M{"auction_id": auctionId}) })
+		},
+		closedIds: recorder.closed,
+	}
+}
+
+func newMemoryCloseSemanticsRepository(ctx context.Context, t *testing.T) closeSemanticsRepository {
+	repo := memory.NewAuctionRepository(2 * time.Second)
+
+	clock := newFakeClock()
+	repo.SetClock(clock)
+
+	recorder := &closedIdsRecorder{mutex: &sync.Mutex{}}
+	repo.SetOnAuctionClosed(recorder.onAuctionClosed)
+
+	return closeSemanticsRepository{
+		repo:          repo,
+		clock:         clock,
+		closeAuctions: repo.CloseAuctions,
+		closeExpired: func(ctx context.Context) {
+			repo.CloseExpiredAuctions(ctx)
+		},
+		recordBid: func(ctx context.Context, auctionId string) {
+			repo.RecordBid(auctionId)
+		},
+		closedIds: recorder.closed,
+	}
+}
+
+// TestCloseSemantics roda os mesmos cenários no repositório Mongo e no em
+// memória, garantindo que os testes de use case feitos com o segundo valem
+// para o primeiro.
+func TestCloseSemantics(t *testing.T) {
+	implementations := []struct {
+		name    string
+		newRepo func(ctx context.Context, t *testing.T) closeSemanticsRepository
+	}{
+		{name: "mongo", newRepo: newMongoCloseSemanticsRepository},
+		{name: "memory", newRepo: newMemoryCloseSemanticsRepository},
+	}
+
+	for _, implementation := range implementations {
+		t.Run(implementation.name, func(t *testing.T) {
+			t.Setenv("AUCTION_INTERVAL", "2s")
+			t.Setenv("AUCTION_CHECK_INTERVAL", "1h")
+
+			testCloseSemantics(t, implementation.newRepo)
+		})
+	}
+}
+
+func testCloseSemantics(
+	t *testing.T, newRepo func(ctx context.Context, t *testing.T) closeSemanticsRepository) {
+	ctx := context.Background()
+
+	createAuction := func(t *testing.T, s closeSemanticsRepository, id string, status auction_entity.AuctionStatus, timestamp time.Time) {
+		_, err := s.repo.CreateAuction(ctx, &auction_entity.Auction{
+			Id:          id,
+			ProductName: "Close Semantics Product",
+			Category:    "Electronics",
+			Description: "Auction used by the close semantics test",
+			Condition:   auction_entity.New,
+			Status:      status,
+			Timestamp:   timestamp,
+		})
+		require.Nil(t, err)
+	}
+
+	requireStatus := func(t *testing.T, s closeSemanticsRepository, id string, status auction_entity.AuctionStatus) {
+		auctionEntity, err := s.repo.FindAuctionById(ctx, id)
+		require.Nil(t, err)
+		require.Equal(t, status, auctionEntity.Status)
+	}
+
+	t.Run("closes only expired active auctions", func(t *testing.T) {
+		s := newRepo(ctx, t)
+		now := s.clock.Now()
+
+		createAuction(t, s, "expired", auction_entity.Active, now.Add(-3*time.Second))
+		createAuction(t, s, "open", auction_entity.Active, now)
+		createAuction(t, s, "cancelled", auction_entity.Cancelled, now.Add(-3*time.Second))
+
+		stale, err := s.repo.FindStaleActiveAuctions(ctx)
+		require.Nil(t, err)
+		require.Len(t, stale, 1)
+		require.Equal(t, "expired", stale[0].Id)
+
+		s.closeExpired(ctx)
+
+		requireStatus(t, s, "expired", auction_entity.Completed)
+		requireStatus(t, s, "open", auction_entity.Active)
+		requireStatus(t, s, "cancelled", auction_entity.Cancelled)
+		require.Equal(t, []string{"expired"}, s.closedIds())
+
+		stale, err = s.repo.FindStaleActiveAuctions(ctx)
+		require.Nil(t, err)
+		require.Empty(t, stale)
+
+		// Um segundo tick não fecha de novo nem repete o callback
+		s.closeExpired(ctx)
+		require.Equal(t, []string{"expired"}, s.closedIds())
+
+		s.clock.Advance(3 * time.Second)
+		s.closeExpired(ctx)
+		requireStatus(t, s, "open", auction_entity.Completed)
+		require.Equal(t, []string{"expired", "open"}, s.closedIds())
+	})
+
+	t.Run("opens scheduled auctions before closing", func(t *testing.T) {
+		s := newRepo(ctx, t)
+		now := s.clock.Now()
+
+		_, err := s.repo.CreateAuction(ctx, &auction_entity.Auction{
+			Id:          "scheduled",
+			ProductName: "Scheduled Product",
+			Category:    "Electronics",
+			Description: "Auction used by the close semantics test",
+			Condition:   auction_entity.New,
+			Status:      auction_entity.Scheduled,
+			Timestamp:   now,
+			StartsAt:    now.Add(time.Second),
+		})
+		require.Nil(t, err)
+
+		s.closeExpired(ctx)
+		requireStatus(t, s, "scheduled", auction_entity.Scheduled)
+
+		s.clock.Advance(time.Second)
+		s.closeExpired(ctx)
+		requireStatus(t, s, "scheduled", auction_entity.Active)
+
+		// Ficou fora do ar além do fim do leilão: abre e fecha no mesmo tick
+		s.clock.Advance(3 * time.Second)
+		s.closeExpired(ctx)
+		requireStatus(t, s, "scheduled", auction_entity.Completed)
+		require.Equal(t, []string{"scheduled"}, s.closedIds())
+	})
+
+	t.Run("force close ignores repeated, unknown and inactive auctions", func(t *testing.T) {
+		s := newRepo(ctx, t)
+		now := s.clock.Now()
+
+		createAuction(t, s, "first", auction_entity.Active, now)
+		createAuction(t, s, "second", auction_entity.Active, now)
+		createAuction(t, s, "cancelled", auction_entity.Cancelled, now)
+
+		closed, err := s.closeAuctions(ctx, []string{"first", "first", "missing", "cancelled", "second", ""})
+		require.Nil(t, err)
+		require.Equal(t, int64(2), closed)
+
+		requireStatus(t, s, "first", auction_entity.Completed)
+		requireStatus(t, s, "second", auction_entity.Completed)
+		requireStatus(t, s, "cancelled", auction_entity.Cancelled)
+		require.Equal(t, []string{"first", "second"}, s.closedIds())
+
+		closed, err = s.closeAuctions(ctx, []string{"first", "second"})
+		require.Nil(t, err)
+		require.Zero(t, closed)
+	})
+
+	t.Run("cancel only applies to active auctions without bids", func(t *testing.T) {
+		s := newRepo(ctx, t)
+		now := s.clock.Now()
+
+		createAuction(t, s, "cancellable", auction_entity.Active, now)
+		createAuction(t, s, "closed", auction_entity.Active, now)
+
+		err := s.repo.CancelAuction(ctx, "cancellable", " ")
+		require.NotNil(t, err)
+		require.Equal(t, "cancel reason is required", err.Message)
+
+		require.Nil(t, s.repo.CancelAuction(ctx, "cancellable", "listed by mistake"))
+		requireStatus(t, s, "cancellable", auction_entity.Cancelled)
+
+		// O closer não fecha leilões cancelados
+		s.clock.Advance(3 * time.Second)
+		s.closeExpired(ctx)
+		requireStatus(t, s, "cancellable", auction_entity.Cancelled)
+		require.NotContains(t, s.closedIds(), "cancellable")
+
+		createAuction(t, s, "with-bids", auction_entity.Active, s.clock.Now())
+		s.recordBid(ctx, "with-bids")
+		err = s.repo.CancelAuction(ctx, "with-bids", "changed my mind")
+		require.NotNil(t, err)
+		require.Equal(t, "auction already has bids", err.Message)
+
+		err = s.repo.CancelAuction(ctx, "closed", "too late")
+		require.NotNil(t, err)
+		require.Equal(t, internal_error.CodeAuctionNotActive, err.Code)
+
+		err = s.repo.CancelAuction(ctx, "missing", "does not exist")
+		require.NotNil(t, err)
+		require.Equal(t, "not_found", err.Err)
+	})
+}
@@ -2,132 +2,1367 @@ package auction
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/configuration/metrics"
 	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/entity/bid_entity"
+	"fullcycle-auction_go/internal/entity/user_entity"
+	"fullcycle-auction_go/internal/infra/database/database_error"
+	"fullcycle-auction_go/internal/infra/database/decimal"
 	"fullcycle-auction_go/internal/internal_error"
 	"os"
+	"runtime/debug"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
 )
 
 type AuctionEntityMongo struct {
-	Id          string                          `bson:"_id"`
-	ProductName string                          `bson:"product_name"`
-	Category    string                          `bson:"category"`
-	Description string                          `bson:"description"`
-	Condition   auction_entity.ProductCondition `bson:"condition"`
-	Status      auction_entity.AuctionStatus    `bson:"status"`
-	Timestamp   int64                           `bson:"timestamp"`
+	Id             string                          `bson:"_id"`
+	UserId         string                          `bson:"user_id,omitempty"`
+	ProductName    string                          `bson:"product_name"`
+	Category       string                          `bson:"category"`
+	Description    string                          `bson:"description"`
+	Condition      auction_entity.ProductCondition `bson:"condition"`
+	Type           auction_entity.AuctionType      `bson:"type,omitempty"`
+	Status         auction_entity.AuctionStatus    `bson:"status"`
+	Timestamp      int64                           `bson:"timestamp"`
+	ExpiresAt      int64                           `bson:"expires_at,omitempty"`
+	StartsAt       int64                           `bson:"starts_at,omitempty"`
+	DeletedAt      *int64                          `bson:"deleted_at,omitempty"`
+	ClosedAt       *int64                          `bson:"closed_at,omitempty"`
+	ExtendedBy     int64                           `bson:"extended_by,omitempty"`
+	ReservePrice   float64                         `bson:"reserve_price,omitempty"`
+	ReserveMet     *bool                           `bson:"reserve_met,omitempty"`
+	BuyNowPrice    float64                         `bson:"buy_now_price,omitempty"`
+	WinningBidId   string                          `bson:"winning_bid_id,omitempty"`
+	Currency       string                          `bson:"currency,omitempty"`
+	CancelReason   string                          `bson:"cancel_reason,omitempty"`
+	CancelledAt    *int64                          `bson:"cancelled_at,omitempty"`
+	IdempotencyKey string                          `bson:"idempotency_key,omitempty"`
+	WebhookURL     string                          `bson:"webhook_url,omitempty"`
+	Images         []string                        `bson:"images,omitempty"`
+	WinnerUserId   string                          `bson:"winner_user_id,omitempty"`
+	WinningAmount  *decimal.Amount                 `bson:"winning_amount,omitempty"`
+	NoBids         bool                            `bson:"no_bids,omitempty"`
+	// WinnerResolutionPending marca leilões fechados cuja reserva não pôde ser
+	// verificada; o closer tenta de novo nos próximos ticks
+	WinnerResolutionPending bool `bson:"winner_resolution_pending,omitempty"`
+	Version                 int  `bson:"version"`
 }
 type AuctionRepository struct {
-	Collection      *mongo.Collection
-	auctionInterval time.Duration
+	Collection            *mongo.Collection
+	auctionInterval       time.Duration
+	categoryIntervals     map[string]time.Duration
+	checkInterval         time.Duration
+	checkIntervalFixed    bool
+	closeBatchSize        int64
+	opTimeout             time.Duration
+	closerOpTimeout       time.Duration
+	closeGrace            time.Duration
+	maxActiveAuctionsUser int64
+	maxAuctionExtension   time.Duration
+	retention             time.Duration
+	dryRun                bool
+	done                  chan struct{}
+	intervalChanged       chan struct{}
+	closeOnce             *sync.Once
+	closerWg              *sync.WaitGroup
+	metrics               *metrics.AuctionMetrics
+	lastTickAt            *atomic.Int64
+	tracer                *atomic.Value
+
+	// As escritas no Mongo (criação, atualizações, fechamento) não passam por
+	// mutex: a consistência vem das operações atômicas por documento
+	// (FindOneAndUpdate no closer, filtro pela versão lida nas atualizações),
+	// que também valem entre instâncias do serviço, onde um mutex local não
+	// ajudaria. tickMutex só evita que dois ticks do mesmo repositório
+	// disputem os mesmos leilões, e mutex protege clock, onAuctionClosed, bids e
+	// os intervalos, que podem ser trocados com o closer rodando.
+	tickMutex       *sync.Mutex
 	mutex           *sync.Mutex
+	clock           Clock
+	onAuctionClosed func(ctx context.Context, auctionID string)
+	bids            BidStore
+
+	// userRepository valida o novo dono em TransferAuction. Vem do
+	// WithUserRepository; sem ele as transferências são recusadas
+	userRepository user_entity.UserRepositoryInterface
+
+	// rules são as regras de texto aplicadas em UpdateAuction
+	rules auction_entity.Rules
+
+	// collectionOptions guarda o write concern e a read preference das opções
+	collectionOptions *options.CollectionOptions
 }
 
-func NewAuctionRepository(ctx context.Context, database *mongo.Database) *AuctionRepository {
-	return NewAuctionRepositoryWithCollection(ctx, database, "auctions")
+var _ auction_entity.AuctionRepositoryInterface = (*AuctionRepository)(nil)
+
+func NewAuctionRepository(
+	ctx context.Context,
+	database *mongo.Database,
+	registerer ...prometheus.Registerer) *AuctionRepository {
+	return NewAuctionRepositoryWithCollection(ctx, database, "auctions", registerer...)
 }
 
-func NewAuctionRepositoryWithCollection(ctx context.Context, database *mongo.Database, collectionName string) *AuctionRepository {
+func NewAuctionRepositoryWithCollection(
+	ctx context.Context,
+	database *mongo.Database,
+	collectionName string,
+	registerer ...prometheus.Registerer) *AuctionRepository {
+	opts := []Option{WithCollectionName(collectionName)}
+	if len(registerer) > 0 {
+		opts = append(opts, WithRegisterer(registerer[0]))
+	}
+
+	return NewAuctionRepositoryWithOptions(ctx, database, opts...)
+}
+
+func NewAuctionRepositoryWithOptions(
+	ctx context.Context,
+	database *mongo.Database,
+	opts ...Option) *AuctionRepository {
+	repositoryOptions := &repositoryOptions{
+		collectionName: "auctions",
+		clock:          realClock{},
+		rules:          auction_entity.DefaultRules(),
+	}
+	for _, opt := range opts {
+		opt(repositoryOptions)
+	}
+
+	auctionInterval := getAuctionInterval()
+
+	checkInterval := repositoryOptions.checkInterval
+	if checkInterval <= 0 {
+		checkInterval = getAuctionCheckInterval(auctionInterval)
+	}
+
+	closeBatchSize := repositoryOptions.closeBatchSize
+	if closeBatchSize <= 0 {
+		closeBatchSize = getAuctionCloseBatchSize()
+	}
+
+	var dryRun bool
+	if repositoryOptions.dryRun != nil {
+		dryRun = *repositoryOptions.dryRun
+	} else {
+		dryRun = getAuctionCloserDryRun()
+	}
+
+	collectionOptions := repositoryOptions.collectionOptions()
+
 	repo := &AuctionRepository{
-		Collection:      database.Collection(collectionName),
-		auctionInterval: getAuctionInterval(),
-		mutex:           &sync.Mutex{},
+		Collection:            database.Collection(repositoryOptions.collectionName, collectionOptions),
+		collectionOptions:     collectionOptions,
+		auctionInterval:       auctionInterval,
+		categoryIntervals:     getCategoryIntervals(),
+		checkInterval:         checkInterval,
+		checkIntervalFixed:    repositoryOptions.checkInterval > 0,
+		closeBatchSize:        closeBatchSize,
+		opTimeout:             getMongoOpTimeout(),
+		closerOpTimeout:       getAuctionCloserOpTimeout(),
+		closeGrace:            getAuctionCloseGrace(),
+		maxActiveAuctionsUser: getMaxActiveAuctionsPerUser(),
+		maxAuctionExtension:   getMaxAuctionExtension(),
+		retention:             getAuctionRetention(),
+		dryRun:                dryRun,
+		tickMutex:             &sync.Mutex{},
+		mutex:                 &sync.Mutex{},
+		done:                  make(chan struct{}),
+		intervalChanged:       make(chan struct{}, 1),
+		closeOnce:             &sync.Once{},
+		closerWg:              &sync.WaitGroup{},
+		onAuctionClosed:       repositoryOptions.onAuctionClosed,
+		metrics:               metrics.NewAuctionMetrics(repositoryOptions.registerer),
+		lastTickAt:            &atomic.Int64{},
+		clock:                 repositoryOptions.clock,
+		tracer:                &atomic.Value{},
 	}
+	repo.tracer.Store(defaultTracer())
+
+	repo.userRepository = repositoryOptions.userRepository
+	repo.rules = repositoryOptions.rules
+
+	// Conta a criação como primeiro tick para não reportar o closer como
+	// parado antes da primeira verificação
+	repo.lastTickAt.Store(time.Now().UnixNano())
 
-	go repo.startAuctionCloser(ctx)
+	repo.ensureIndexes(ctx)
+
+	if err := repo.ReconcileOnStartup(ctx); err != nil {
+		logger.Error("Error trying to reconcile expired auctions on startup", err)
+	}
+
+	repo.closerWg.Add(1)
+	go func() {
+		defer repo.closerWg.Done()
+		repo.startAuctionCloser(ctx)
+	}()
+
+	if repo.retention > 0 {
+		repo.closerWg.Add(1)
+		go func() {
+			defer repo.closerWg.Done()
+			repo.startAuctionPurger(ctx)
+		}()
+	}
 
 	return repo
 }
 
+// Close sinaliza a goroutine de fechamento para parar e aguarda a iteração
+// em andamento terminar. Pode ser chamado mais de uma vez.
+func (ar *AuctionRepository) Close() {
+	ar.closeOnce.Do(func() {
+		close(ar.done)
+	})
+	ar.closerWg.Wait()
+}
+
+var indexedCollections sync.Map
+
+// ensureIndexes cria os índices usados pela rotina de fechamento. Roda uma
+// única vez por coleção e nunca impede a inicialização do repositório.
+func (ar *AuctionRepository) ensureIndexes(ctx context.Context) {
+	key := ar.Collection.Database().Name() + "." + ar.Collection.Name()
+	if _, loaded := indexedCollections.LoadOrStore(key, struct{}{}); loaded {
+		return
+	}
+
+	indexModels := []mongo.IndexModel{
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "timestamp", Value: 1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "expires_at", Value: 1}}},
+		{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "status", Value: 1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "starts_at", Value: 1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "closed_at", Value: 1}}},
+		// Chaves de idempotência são únicas por usuário; documentos sem chave
+		// ficam fora do índice
+		{
+			Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "idempotency_key", Value: 1}},
+			Options: options.Index().
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"idempotency_key": bson.M{"$exists": true}}),
+		},
+	}
+
+	for _, indexModel := range indexModels {
+		if _, err := ar.Collection.Indexes().CreateOne(ctx, indexModel); err != nil {
+			logger.Error("Error trying to create auction index", err)
+		}
+	}
+
+	auditIndex := mongo.IndexModel{
+		Keys: bson.D{{Key: "auction_id", Value: 1}, {Key: "timestamp", Value: 1}},
+	}
+	if _, err := ar.auditCollection().Indexes().CreateOne(ctx, auditIndex); err != nil {
+		logger.Error("Error trying to create auction audit index", err)
+	}
+}
+
 func (ar *AuctionRepository) CreateAuction(
 	ctx context.Context,
-	auctionEntity *auction_entity.Auction) *internal_error.InternalError {
-	auctionEntityMongo := &AuctionEntityMongo{
-		Id:          auctionEntity.Id,
-		ProductName: auctionEntity.ProductName,
-		Category:    auctionEntity.Category,
-		Description: auctionEntity.Description,
-		Condition:   auctionEntity.Condition,
-		Status:      auctionEntity.Status,
-		Timestamp:   auctionEntity.Timestamp.Unix(),
+	auctionEntity *auction_entity.Auction) (*auction_entity.Auction, *internal_error.InternalError) {
+	ctx, span := ar.startSpan(ctx, "CreateAuction", attribute.String("auction.id", auctionEntity.Id))
+	createdAuction, err := ar.createAuction(ctx, auctionEntity)
+	endSpan(span, err)
+
+	return createdAuction, err
+}
+
+func (ar *AuctionRepository) createAuction(
+	ctx context.Context,
+	auctionEntity *auction_entity.Auction) (*auction_entity.Auction, *internal_error.InternalError) {
+	auctionEntityMongo := ar.toAuctionEntityMongo(auctionEntity)
+
+	ctx, cancel := ar.withOpTimeout(ctx)
+	defer cancel()
+
+	// Uma repetição já gravada não passa pelo limite de leilões ativos
+	if originalAuction, err := ar.findByIdempotencyKey(ctx, auctionEntity); originalAuction != nil || err != nil {
+		return originalAuction, err
+	}
+
+	if err := ar.checkActiveAuctionsLimit(ctx, auctionEntity.UserId, 1); err != nil {
+		return nil, err
 	}
+
 	_, err := ar.Collection.InsertOne(ctx, auctionEntityMongo)
 	if err != nil {
-		logger.Error("Error trying to insert auction", err)
-		return internal_error.NewInternalServerError("Error trying to insert auction")
+		if mongo.IsDuplicateKeyError(err) {
+			// Duas tentativas simultâneas com a mesma chave: o índice único
+			// deixa só uma gravar e a outra recebe o leilão gravado
+			if originalAuction, findErr := ar.findByIdempotencyKey(ctx, auctionEntity); originalAuction != nil || findErr != nil {
+				return originalAuction, findErr
+			}
+		}
+
+		logger.WithContext(ctx).Error("Error trying to insert auction", err,
+			zap.String("auction_id", auctionEntity.Id))
+
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, internal_error.NewConflictError("auction already exists")
+		}
+
+		if database_error.IsTimeout(err) {
+			return nil, internal_error.NewTimeoutError("Timeout trying to insert auction")
+		}
+
+		return nil, internal_error.NewInternalServerError("Error trying to insert auction")
+	}
+
+	undoneIds, undoErr := ar.undoOverLimitCreations(ctx,
+		map[string][]string{auctionEntity.UserId: {auctionEntity.Id}})
+	if len(undoneIds) > 0 {
+		if undoErr != nil {
+			return nil, undoErr
+		}
+		return nil, ar.activeAuctionsLimitError()
+	}
+
+	ar.recordTransition(ctx, auctionEntity.Id, nil, auctionEntityMongo.Status,
+		auditActor(auctionEntity.UserId), "created")
+
+	logger.WithContext(ctx).Info("Auction created",
+		zap.String("auction_id", auctionEntity.Id),
+		zap.Stringer("status", auctionEntity.Status),
+		zap.Stringer("condition", auctionEntity.Condition),
+		zap.Int64("expires_at", auctionEntityMongo.ExpiresAt))
+
+	return ar.toAuctionEntity(*auctionEntityMongo), nil
+}
+
+// findByIdempotencyKey busca o leilão já criado pelo mesmo usuário com a
+// mesma chave. Retorna nil quando a criação não tem chave ou é a primeira.
+func (ar *AuctionRepository) findByIdempotencyKey(
+	ctx context.Context,
+	auctionEntity *auction_entity.Auction) (*auction_entity.Auction, *internal_error.InternalError) {
+	if auctionEntity.IdempotencyKey == "" {
+		return nil, nil
+	}
+
+	var auctionEntityMongo AuctionEntityMongo
+	err := ar.Collection.FindOne(ctx, bson.M{
+		"user_id":         auctionEntity.UserId,
+		"idempotency_key": auctionEntity.IdempotencyKey,
+	}).Decode(&auctionEntityMongo)
+	if err != nil {
+		if database_error.IsNotFound(err) {
+			return nil, nil
+		}
+
+		logger.WithContext(ctx).Error("Error trying to find auction by idempotency key", err)
+		return nil, database_error.NewInternalError(err, "Error trying to insert auction")
+	}
+
+	logger.WithContext(ctx).Info("Auction creation replayed with the same idempotency key",
+		zap.String("auction_id", auctionEntityMongo.Id))
+
+	return ar.toAuctionEntity(auctionEntityMongo), nil
+}
+
+// CreateAuctions grava vários leilões com um único InsertMany não ordenado:
+// uma falha (por exemplo, id duplicado) não impede a gravação dos demais, e o
+// erro retornado lista em FailedIds os leilões que não foram gravados. Um
+// leilão nil ou inválido recusa o lote inteiro antes de qualquer escrita.
+func (ar *AuctionRepository) CreateAuctions(
+	ctx context.Context, auctionEntities []*auction_entity.Auction) *internal_error.InternalError {
+	ctx, span := ar.startSpan(ctx, "CreateAuctions", attribute.Int("auctions.count", len(auctionEntities)))
+	err := ar.createAuctions(ctx, auctionEntities)
+	endSpan(span, err)
+
+	return err
+}
+
+func (ar *AuctionRepository) createAuctions(
+	ctx context.Context, auctionEntities []*auction_entity.Auction) *internal_error.InternalError {
+	if len(auctionEntities) == 0 {
+		return nil
+	}
+
+	for i, auctionEntity := range auctionEntities {
+		if auctionEntity == nil {
+			return internal_error.NewBadRequestError(fmt.Sprintf("auctions[%d] is required", i))
+		}
+		if err := auctionEntity.Validate(); err != nil {
+			return internal_error.NewBadRequestError(fmt.Sprintf("auctions[%d]: %s", i, err.Message)).
+				WithFailedIds([]string{auctionEntity.Id})
+		}
+	}
+
+	ctx, cancel := ar.withOpTimeout(ctx)
+	defer cancel()
+
+	documents := make([]interface{}, 0, len(auctionEntities))
+	auctionsPerUser := make(map[string]int64)
+	for _, auctionEntity := range auctionEntities {
+		documents = append(documents, ar.toAuctionEntityMongo(auctionEntity))
+		auctionsPerUser[auctionEntity.UserId]++
+	}
+
+	for userId, newAuctions := range auctionsPerUser {
+		if err := ar.checkActiveAuctionsLimit(ctx, userId, newAuctions); err != nil {
+			return err
+		}
+	}
+
+	log := logger.WithContext(ctx)
+	_, err := ar.Collection.InsertMany(ctx, documents, options.InsertMany().SetOrdered(false))
+
+	// Lotes em que só ids repetidos falharam respondem conflito; com leilões
+	// recusados pelo limite a resposta é bad request
+	var failedIds []string
+	failed := make(map[string]bool)
+	allDuplicates := true
+	allBadRequest := true
+	if err != nil {
+		var bulkWriteErr mongo.BulkWriteException
+		if !errors.As(err, &bulkWriteErr) || len(bulkWriteErr.WriteErrors) == 0 {
+			log.Error("Error trying to insert auctions", err)
+			return database_error.NewInternalError(err, "Error trying to insert auctions")
+		}
+
+		for _, writeErr := range bulkWriteErr.WriteErrors {
+			failedIds = append(failedIds, auctionEntities[writeErr.Index].Id)
+			failed[auctionEntities[writeErr.Index].Id] = true
+			if writeErr.Code != duplicateKeyErrorCode {
+				allDuplicates = false
+				allBadRequest = false
+			}
+		}
+	}
+
+	insertedPerUser := make(map[string][]string)
+	for _, auctionEntity := range auctionEntities {
+		if !failed[auctionEntity.Id] {
+			insertedPerUser[auctionEntity.UserId] = append(insertedPerUser[auctionEntity.UserId], auctionEntity.Id)
+		}
+	}
+	undoneIds, undoErr := ar.undoOverLimitCreations(ctx, insertedPerUser)
+	failedIds = append(failedIds, undoneIds...)
+	if len(undoneIds) > 0 {
+		allDuplicates = false
+	}
+	if undoErr != nil {
+		allBadRequest = false
+	}
+
+	ar.recordCreations(ctx, auctionEntities, failedIds)
+
+	if len(failedIds) == 0 {
+		log.Info("Auctions created", zap.Int("count", len(auctionEntities)))
+		return nil
+	}
+
+	log.Error("Error trying to insert some auctions", err,
+		zap.Int("created", len(auctionEntities)-len(failedIds)),
+		zap.Strings("failed_auction_ids", failedIds))
+
+	message := fmt.Sprintf("%d of %d auctions could not be created", len(failedIds), len(auctionEntities))
+	if allDuplicates {
+		return internal_error.NewConflictError(message).
+			WithCode(internal_error.CodePartialFailure).
+			WithFailedIds(failedIds)
+	}
+	if allBadRequest {
+		return internal_error.NewBadRequestError(message).
+			WithCode(internal_error.CodePartialFailure).
+			WithFailedIds(failedIds)
+	}
+
+	return internal_error.NewInternalServerError(message).
+		WithCode(internal_error.CodePartialFailure).
+		WithFailedIds(failedIds)
+}
+
+// recordCreations registra na auditoria a criação dos leilões do lote que
+// foram gravados
+func (ar *AuctionRepository) recordCreations(
+	ctx context.Context, auctionEntities []*auction_entity.Auction, failedIds []string) {
+	failed := make(map[string]bool, len(failedIds))
+	for _, id := range failedIds {
+		failed[id] = true
+	}
+
+	for _, auctionEntity := range auctionEntities {
+		if failed[auctionEntity.Id] {
+			continue
+		}
+
+		ar.recordTransition(ctx, auctionEntity.Id, nil, auctionEntity.Status,
+			auditActor(auctionEntity.UserId), "created")
+	}
+}
+
+// Código do Mongo para violação de índice único
+const duplicateKeyErrorCode = 11000
+
+func (ar *AuctionRepository) toAuctionEntityMongo(auctionEntity *auction_entity.Auction) *AuctionEntityMongo {
+	return &AuctionEntityMongo{
+		Id:             auctionEntity.Id,
+		UserId:         auctionEntity.UserId,
+		ProductName:    auctionEntity.ProductName,
+		Category:       auctionEntity.Category,
+		Description:    auctionEntity.Description,
+		Condition:      auctionEntity.Condition,
+		Type:           auctionEntity.Type,
+		Status:         auctionEntity.Status,
+		Timestamp:      auctionEntity.Timestamp.Unix(),
+		ExpiresAt:      ar.expiresAt(auctionEntity).Unix(),
+		StartsAt:       startsAtUnix(auctionEntity),
+		ReservePrice:   auctionEntity.ReservePrice,
+		BuyNowPrice:    auctionEntity.BuyNowPrice,
+		Currency:       auctionEntity.Currency,
+		IdempotencyKey: auctionEntity.IdempotencyKey,
+		WebhookURL:     auctionEntity.WebhookURL,
+		Images:         auctionEntity.Images,
+	}
+}
+
+func startsAtUnix(auctionEntity *auction_entity.Auction) int64 {
+	if auctionEntity.StartsAt.IsZero() {
+		return 0
+	}
+
+	return auctionEntity.StartsAt.Unix()
+}
+
+// checkActiveAuctionsLimit impede que um usuário passe de
+// MAX_ACTIVE_AUCTIONS_PER_USER leilões ativos ao criar newAuctions leilões.
+// Leilões agendados contam, já que vão ficar ativos. A contagem e a escrita
+// não são atômicas; exceedsActiveAuctionsLimit confere de novo depois dela.
+func (ar *AuctionRepository) checkActiveAuctionsLimit(
+	ctx context.Context, userId string, newAuctions int64) *internal_error.InternalError {
+	if ar.maxActiveAuctionsUser <= 0 || userId == "" {
+		return nil
+	}
+
+	activeAuctions, err := ar.countActiveAuctions(ctx, userId)
+	if err != nil {
+		return err
+	}
+
+	if activeAuctions+newAuctions > ar.maxActiveAuctionsUser {
+		return ar.activeAuctionsLimitError()
 	}
 
 	return nil
 }
 
-func (ar *AuctionRepository) startAuctionCloser(ctx context.Context) {
-	// Verifica com mais frequência do que o intervalo de expiração
-	checkInterval := ar.auctionInterval / 2
-	if checkInterval < time.Second {
-		checkInterval = time.Second
+// exceedsActiveAuctionsLimit reconta os leilões ativos do usuário depois da
+// escrita, que já está visível na contagem. Escritas simultâneas do mesmo
+// usuário passam juntas por checkActiveAuctionsLimit; cada uma que vê o
+// usuário acima do limite desfaz a sua. Pode acontecer de todas desfazerem,
+// mas o limite nunca fica ultrapassado.
+func (ar *AuctionRepository) exceedsActiveAuctionsLimit(
+	ctx context.Context, userId string) (bool, *internal_error.InternalError) {
+	if ar.maxActiveAuctionsUser <= 0 || userId == "" {
+		return false, nil
+	}
+
+	activeAuctions, err := ar.countActiveAuctions(ctx, userId)
+	if err != nil {
+		return false, err
+	}
+
+	return activeAuctions > ar.maxActiveAuctionsUser, nil
+}
+
+func (ar *AuctionRepository) countActiveAuctions(
+	ctx context.Context, userId string) (int64, *internal_error.InternalError) {
+	activeAuctions, err := ar.Collection.CountDocuments(ctx, bson.M{
+		"user_id":    userId,
+		"status":     bson.M{"$in": bson.A{auction_entity.Active, auction_entity.Scheduled}},
+		"deleted_at": nil,
+	})
+	if err != nil {
+		logger.WithContext(ctx).Error(
+			fmt.Sprintf("Error trying to count active auctions of user = %s", userId), err)
+		return 0, database_error.NewInternalError(err, "Error trying to insert auction")
+	}
+
+	return activeAuctions, nil
+}
+
+func (ar *AuctionRepository) activeAuctionsLimitError() *internal_error.InternalError {
+	return internal_error.NewBadRequestError(
+		fmt.Sprintf("user already has the maximum of %d active auctions", ar.maxActiveAuctionsUser)).
+		WithCode(internal_error.CodeActiveAuctionsLimit)
+}
+
+// undoOverLimitCreations apaga os leilões recém gravados, ids, dos usuários
+// que passaram do limite e retorna os ids apagados. Uma falha na recontagem
+// também desfaz, para não deixar o limite sem verificação, e é devolvida no
+// lugar do erro de limite.
+func (ar *AuctionRepository) undoOverLimitCreations(
+	ctx context.Context, idsPerUser map[string][]string) ([]string, *internal_error.InternalError) {
+	var undoneIds []string
+	var countErr *internal_error.InternalError
+	for userId, ids := range idsPerUser {
+		exceeds, err := ar.exceedsActiveAuctionsLimit(ctx, userId)
+		if err == nil && !exceeds {
+			continue
+		}
+		if err != nil {
+			countErr = err
+		}
+
+		if _, deleteErr := ar.Collection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}}); deleteErr != nil {
+			logger.WithContext(ctx).Error("Error trying to undo auctions over the active auctions limit", deleteErr,
+				zap.String("user_id", userId),
+				zap.Strings("auction_ids", ids))
+			continue
+		}
+
+		undoneIds = append(undoneIds, ids...)
 	}
 
+	return undoneIds, countErr
+}
+
+// withOpTimeout limita a duração de uma operação no Mongo mesmo quando o
+// contexto recebido não possui deadline.
+func (ar *AuctionRepository) withOpTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, ar.opTimeout)
+}
+
+// intervalChangeQuietPeriod é quanto tempo o closer espera sem novas
+// chamadas a SetAuctionInterval antes de reconfigurar o ticker
+const intervalChangeQuietPeriod = 500 * time.Millisecond
+
+func (ar *AuctionRepository) startAuctionCloser(ctx context.Context) {
+	checkInterval := ar.CheckInterval()
 	ticker := time.NewTicker(checkInterval)
 	defer ticker.Stop()
 
+	// Trocas seguidas de intervalo, como um arquivo de configuração oscilando,
+	// reiniciam a espera; o ticker só é refeito uma vez, com o último valor
+	quietTimer := time.NewTimer(intervalChangeQuietPeriod)
+	quietTimer.Stop()
+	defer quietTimer.Stop()
+
 	for {
 		select {
 		case <-ctx.Done():
 			return
+		case <-ar.done:
+			return
 		case <-ticker.C:
-			ar.closeExpiredAuctions(ctx)
+			ar.runCloserTick(ctx)
+		case <-ar.intervalChanged:
+			quietTimer.Reset(intervalChangeQuietPeriod)
+		case <-quietTimer.C:
+			newCheckInterval := ar.CheckInterval()
+			if newCheckInterval == checkInterval {
+				continue
+			}
+
+			checkInterval = newCheckInterval
+			ticker.Reset(checkInterval)
+			ar.metrics.CloserTickerResets.Inc()
+			logger.Info("Auction closer ticker reconfigured",
+				zap.Duration("check_interval", checkInterval))
 		}
 	}
 }
 
-func (ar *AuctionRepository) closeExpiredAuctions(ctx context.Context) {
+// runCloserTick roda uma rodada do closer. Um panic na rodada é registrado
+// com o stack trace e na métrica auction_closer_panics_total, e o closer
+// segue para o próximo tick; sem o recover a goroutine morreria e os leilões
+// deixariam de fechar sem nenhum aviso.
+func (ar *AuctionRepository) runCloserTick(ctx context.Context) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			ar.metrics.CloserPanics.Inc()
+			logger.Error("Auction closer tick panicked", fmt.Errorf("%v", recovered),
+				zap.ByteString("stack", debug.Stack()))
+		}
+	}()
+
+	ar.closeExpiredAuctions(ctx)
+}
+
+// SetAuctionInterval troca o AUCTION_INTERVAL com o serviço rodando. Vale
+// para os leilões criados depois da troca e para os antigos gravados sem
+// expires_at. Sem AUCTION_CHECK_INTERVAL ou WithCheckInterval, o intervalo
+// de verificação é derivado de novo e o closer ajusta o ticker depois de
+// intervalChangeQuietPeriod sem novas trocas.
+func (ar *AuctionRepository) SetAuctionInterval(auctionInterval time.Duration) *internal_error.InternalError {
+	if auctionInterval <= 0 {
+		return internal_error.NewBadRequestError("auction interval must be positive")
+	}
+
+	ar.mutex.Lock()
+	defer ar.mutex.Unlock()
+
+	ar.auctionInterval = auctionInterval
+	if !ar.checkIntervalFixed {
+		ar.checkInterval = getAuctionCheckInterval(auctionInterval)
+	}
+
+	logger.Info("Auction interval updated",
+		zap.Duration("auction_interval", ar.auctionInterval),
+		zap.Duration("check_interval", ar.checkInterval))
+
+	// Já existe um aviso pendente quando o canal está cheio
+	select {
+	case ar.intervalChanged <- struct{}{}:
+	default:
+	}
+
+	return nil
+}
+
+// ReloadAuctionInterval relê AUCTION_INTERVAL do ambiente. Um valor ausente
+// ou inválido mantém o intervalo atual.
+func (ar *AuctionRepository) ReloadAuctionInterval() *internal_error.InternalError {
+	auctionInterval := os.Getenv("AUCTION_INTERVAL")
+
+	duration, err := time.ParseDuration(auctionInterval)
+	if err != nil {
+		return internal_error.NewBadRequestError(
+			fmt.Sprintf("AUCTION_INTERVAL is invalid: %q", auctionInterval))
+	}
+
+	return ar.SetAuctionInterval(duration)
+}
+
+// SetOnAuctionClosed registra um callback chamado para cada leilão fechado
+// automaticamente pela rotina de expiração. Passar nil remove o callback.
+func (ar *AuctionRepository) SetOnAuctionClosed(onAuctionClosed func(ctx context.Context, auctionID string)) {
+	ar.mutex.Lock()
+	defer ar.mutex.Unlock()
+
+	ar.onAuctionClosed = onAuctionClosed
+}
+
+// SetClock troca a fonte de hora usada para decidir quais leilões expiraram.
+func (ar *AuctionRepository) SetClock(clock Clock) {
+	ar.mutex.Lock()
+	defer ar.mutex.Unlock()
+
+	ar.clock = clock
+}
+
+func (ar *AuctionRepository) now() time.Time {
+	ar.mutex.Lock()
+	defer ar.mutex.Unlock()
+
+	return ar.clock.Now()
+}
+
+func (ar *AuctionRepository) auctionClosedCallback() func(ctx context.Context, auctionID string) {
+	ar.mutex.Lock()
+	defer ar.mutex.Unlock()
+
+	return ar.onAuctionClosed
+}
+
+func (ar *AuctionRepository) interval() time.Duration {
+	ar.mutex.Lock()
+	defer ar.mutex.Unlock()
+
+	return ar.auctionInterval
+}
+
+// LastTickAt retorna quando a rotina de fechamento rodou pela última vez.
+func (ar *AuctionRepository) LastTickAt() time.Time {
+	return time.Unix(0, ar.lastTickAt.Load())
+}
+
+func (ar *AuctionRepository) CheckInterval() time.Duration {
 	ar.mutex.Lock()
 	defer ar.mutex.Unlock()
 
-	expirationThreshold := time.Now().Add(-ar.auctionInterval).Unix()
+	return ar.checkInterval
+}
+
+// CloseGrace retorna o AUCTION_CLOSE_GRACE: depois de expirar, o leilão
+// continua aceitando lances por esse tempo, até o closer fechá-lo.
+func (ar *AuctionRepository) CloseGrace() time.Duration {
+	return ar.closeGrace
+}
+
+// ReconcileOnStartup fecha imediatamente os leilões que expiraram enquanto o
+// serviço estava fora, sem esperar o primeiro tick. Roda na criação do
+// repositório, antes de SetOnAuctionClosed; para receber esses fechamentos o
+// callback deve ser passado com WithOnClosed.
+func (ar *AuctionRepository) ReconcileOnStartup(ctx context.Context) error {
+	return ar.closeExpiredAuctions(ctx)
+}
+
+func (ar *AuctionRepository) closeExpiredAuctions(ctx context.Context) error {
+	ar.lastTickAt.Store(time.Now().UnixNano())
+
+	// Cada tick tem o seu prazo: uma operação travada no Mongo é abandonada e
+	// o que faltou fechar fica para o próximo tick, em vez de prender a
+	// goroutine. O cancelamento do contexto do repositório continua valendo.
+	ctx, cancel := context.WithTimeout(ctx, ar.closerOpTimeout)
+	defer cancel()
+
+	// Cada rodada do closer tem seu próprio id para agrupar os logs
+	ctx = logger.ContextWithCorrelationId(ctx, uuid.New().String())
+
+	ctx, span := ar.startSpan(ctx, "closeExpiredAuctions")
+	closedIds, err := ar.completeExpiredAuctions(ctx)
+	span.SetAttributes(attribute.Int("auctions.closed", len(closedIds)))
+	if err != nil {
+		endSpan(span, database_error.NewInternalError(err, "Error trying to close expired auctions"))
+	} else {
+		endSpan(span, nil)
+	}
+
+	onAuctionClosed := ar.auctionClosedCallback()
+	if onAuctionClosed != nil {
+		for _, auctionId := range closedIds {
+			onAuctionClosed(ctx, auctionId)
+		}
+	}
+
+	return err
+}
+
+// completeExpiredAuctions pode rodar ao mesmo tempo que criações e
+// atualizações; um leilão criado durante o tick é avaliado no próximo.
+func (ar *AuctionRepository) completeExpiredAuctions(ctx context.Context) ([]string, error) {
+	ar.tickMutex.Lock()
+	defer ar.tickMutex.Unlock()
+
+	log := logger.WithContext(ctx)
+	now := ar.now()
+	filter := ar.expiredAuctionsFilter(now)
+
+	log.Info("Checking for expired auctions",
+		zap.Int64("threshold", now.Add(-ar.interval()-ar.closeGrace).Unix()),
+		zap.Int64("now", now.Unix()))
+
+	if ar.dryRun {
+		// Nada é gravado no dry run, nem a abertura dos agendados
+		if err := ar.logDryRunCandidates(ctx, ar.scheduledAuctionsFilter(now),
+			"Auction closer dry run, scheduled auctions were not started"); err != nil {
+			return nil, err
+		}
+		return nil, ar.logDryRunCandidates(ctx, filter,
+			"Auction closer dry run, expired auctions were not closed")
+	}
+
+	// Abre os leilões agendados antes de fechar os expirados: um agendado que
+	// abriu e expirou com o serviço fora do ar fecha no mesmo tick
+	if err := ar.activateScheduledAuctions(ctx, now); err != nil {
+		log.Error("Error trying to activate scheduled auctions", err)
+	}
+
+	// Fecha em lotes para manter cada rodada limitada e permitir parar
+	// entre um lote e outro
+	var closedIds []string
+	var closeErr error
+closeLoop:
+	for {
+		select {
+		case <-ctx.Done():
+			closeErr = ctx.Err()
+			break closeLoop
+		case <-ar.done:
+			break closeLoop
+		default:
+		}
+
+		claimedIds, err := ar.claimAuctions(ctx, filter, now.Unix(), ar.closeBatchSize, "expired")
+		closedIds = append(closedIds, claimedIds...)
+		for _, auctionId := range claimedIds {
+			log.Info("Auction closed", zap.String("auction_id", auctionId))
+		}
+		if err != nil {
+			log.Error("Error trying to close expired auctions", err)
+			closeErr = err
+			break
+		}
+
+		if int64(len(claimedIds)) < ar.closeBatchSize {
+			break
+		}
+	}
+
+	ar.resolvePendingWinners(ctx)
+
+	totalClosed := int64(len(closedIds))
+	if totalClosed > 0 {
+		log.Info("Successfully closed expired auctions",
+			zap.Int64("count", totalClosed))
+	} else {
+		log.Info("No expired auctions found")
+	}
+
+	ar.metrics.ClosedAuctions.WithLabelValues("expired").Add(float64(totalClosed))
+
+	activeAuctions, err := ar.Collection.CountDocuments(ctx, bson.M{
+		"status":     auction_entity.Active,
+		"deleted_at": nil,
+	})
+	if err != nil {
+		log.Error("Error trying to count active auctions", err)
+	} else {
+		ar.metrics.ActiveAuctions.Set(float64(activeAuctions))
+	}
+
+	return closedIds, closeErr
+}
+
+// activateScheduledAuctions passa para Active os leilões agendados cujo
+// starts_at já chegou. Cada leilão é ativado com FindOneAndUpdate filtrado
+// por status, o que torna a operação segura com várias instâncias rodando o
+// closer e deixa só a instância que ativou registrar a transição.
+func (ar *AuctionRepository) activateScheduledAuctions(ctx context.Context, now time.Time) error {
+	filter := ar.scheduledAuctionsFilter(now)
+	update := bson.M{
+		"$set": bson.M{"status": auction_entity.Active},
+		"$inc": bson.M{"version": 1},
+	}
+
+	var activated int64
+	for {
+		var auctionEntityMongo AuctionEntityMongo
+		err := retryTransient(ctx, closerRetryAttempts, closerRetryBaseDelay, func() error {
+			return ar.Collection.FindOneAndUpdate(ctx, filter, update).Decode(&auctionEntityMongo)
+		})
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		activated++
+		ar.recordTransition(ctx, auctionEntityMongo.Id,
+			statusRef(auction_entity.Scheduled), auction_entity.Active, auditActorSystem, "started")
+	}
+
+	if activated > 0 {
+		logger.WithContext(ctx).Info("Scheduled auctions started",
+			zap.Int64("count", activated))
+	}
+
+	return nil
+}
 
-	filter := bson.M{
-		"status": auction_entity.Active,
-		"timestamp": bson.M{
-			"$lt": expirationThreshold,
+// scheduledAuctionsFilter seleciona os leilões agendados cujo starts_at já
+// chegou
+func (ar *AuctionRepository) scheduledAuctionsFilter(now time.Time) bson.M {
+	return bson.M{
+		"status":     auction_entity.Scheduled,
+		"starts_at":  bson.M{"$lte": now.Unix()},
+		"deleted_at": nil,
+	}
+}
+
+// expiredAuctionsFilter seleciona os leilões ativos cuja expiração passou há
+// mais de AUCTION_CLOSE_GRACE. Leilões sem expires_at (criados antes do campo
+// existir) continuam expirando em timestamp + AUCTION_INTERVAL. Documentos com
+// closed_at já foram fechados pelo closer e nunca são fechados de novo.
+func (ar *AuctionRepository) expiredAuctionsFilter(now time.Time) bson.M {
+	now = now.Add(-ar.closeGrace)
+
+	return bson.M{
+		"status":     auction_entity.Active,
+		"deleted_at": nil,
+		"closed_at":  nil,
+		"$or": bson.A{
+			bson.M{"expires_at": bson.M{"$lt": now.Unix()}},
+			bson.M{
+				"expires_at": bson.M{"$exists": false},
+				"timestamp":  bson.M{"$lt": now.Add(-ar.interval()).Unix()},
+			},
 		},
 	}
+}
+
+// logDryRunCandidates registra, com message, quais leilões do filtro seriam
+// alterados, sem alterar nenhum documento. Os ids listados são limitados a AUCTION_CLOSE_BATCH_SIZE.
+func (ar *AuctionRepository) logDryRunCandidates(ctx context.Context, filter bson.M, message string) error {
+	log := logger.WithContext(ctx)
 
+	candidates, err := ar.Collection.CountDocuments(ctx, filter)
+	if err != nil {
+		log.Error("Error trying to count auctions in dry run", err)
+		return err
+	}
+
+	opts := options.Find().
+		SetProjection(bson.M{"_id": 1}).
+		SetLimit(ar.closeBatchSize)
+	cursor, err := ar.Collection.Find(ctx, filter, opts)
+	if err != nil {
+		log.Error("Error trying to find auctions in dry run", err)
+		return err
+	}
+	defer closeCursor(ctx, cursor)
+
+	var candidatesMongo []AuctionEntityMongo
+	if err := cursor.All(ctx, &candidatesMongo); err != nil {
+		log.Error("Error trying to decode auctions in dry run", err)
+		return err
+	}
+
+	candidateIds := make([]string, 0, len(candidatesMongo))
+	for _, candidate := range candidatesMongo {
+		candidateIds = append(candidateIds, candidate.Id)
+	}
+
+	log.Info(message,
+		zap.Int64("candidates", candidates),
+		zap.Strings("auction_ids", candidateIds))
+
+	return nil
+}
+
+// claimAuctions fecha até limit leilões do filtro, um por vez, com
+// FindOneAndUpdate. A troca Active -> Completed é atômica no documento, então
+// quando várias instâncias do serviço rodam o closer sobre a mesma coleção
+// cada leilão é reivindicado por exatamente uma delas, e apenas essa instância
+// dispara callbacks e conta o fechamento nas métricas. O closed_at registra
+// quando o fechamento aconteceu e reason vai para o histórico do leilão.
+func (ar *AuctionRepository) claimAuctions(
+	ctx context.Context, filter bson.M, closedAt int64, limit int64, reason string) ([]string, error) {
 	update := bson.M{
 		"$set": bson.M{
-			"status": auction_entity.Completed,
+			"status":    auction_entity.Completed,
+			"closed_at": closedAt,
 		},
+		"$inc": bson.M{"version": 1},
 	}
 
-	logger.Info("Checking for expired auctions",
-		zap.Int64("threshold", expirationThreshold),
-		zap.Int64("now", time.Now().Unix()))
+	var claimedIds []string
+	for int64(len(claimedIds)) < limit {
+		var auctionEntityMongo AuctionEntityMongo
+		err := retryTransient(ctx, closerRetryAttempts, closerRetryBaseDelay, func() error {
+			return ar.Collection.FindOneAndUpdate(ctx, filter, update).Decode(&auctionEntityMongo)
+		})
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			break
+		}
+		if err != nil {
+			return claimedIds, err
+		}
+
+		claimedIds = append(claimedIds, auctionEntityMongo.Id)
+		ar.recordTransition(ctx, auctionEntityMongo.Id,
+			statusRef(auction_entity.Active), auction_entity.Completed, auditActorSystem, reason)
 
-	result, err := ar.Collection.UpdateMany(ctx, filter, update)
-	if err != nil {
-		logger.Error("Error trying to close expired auctions", err)
-		return
+		ar.resolveWinner(ctx, auctionEntityMongo)
 	}
 
-	if result.ModifiedCount > 0 {
-		logger.Info("Successfully closed expired auctions",
-			zap.Int64("count", result.ModifiedCount))
-	} else {
-		logger.Info("No expired auctions found")
+	return claimedIds, nil
+}
+
+// expiresAt conta a duração do leilão a partir da abertura, que para leilões
+// agendados é o StartsAt.
+func (ar *AuctionRepository) expiresAt(auctionEntity *auction_entity.Auction) time.Time {
+	if auctionEntity.ExpiresAt.IsZero() {
+		interval := ar.intervalFor(auctionEntity.Category)
+		if auctionEntity.StartsAt.After(auctionEntity.Timestamp) {
+			return auctionEntity.StartsAt.Add(interval)
+		}
+		return auctionEntity.Timestamp.Add(interval)
+	}
+
+	return auctionEntity.ExpiresAt
+}
+
+// intervalFor retorna a duração definida em CATEGORY_INTERVALS para a
+// categoria, sem diferenciar maiúsculas, ou o AUCTION_INTERVAL.
+func (ar *AuctionRepository) intervalFor(category string) time.Duration {
+	if interval, ok := ar.categoryIntervals[strings.ToLower(strings.TrimSpace(category))]; ok {
+		return interval
+	}
+
+	return ar.interval()
+}
+
+// toAuctionEntity converte o documento gravado na entidade de domínio, com os
+// horários normalizados em UTC e na precisão de segundos do Mongo.
+func (ar *AuctionRepository) toAuctionEntity(auctionEntityMongo AuctionEntityMongo) *auction_entity.Auction {
+	return &auction_entity.Auction{
+		Id:             auctionEntityMongo.Id,
+		UserId:         auctionEntityMongo.UserId,
+		ProductName:    auctionEntityMongo.ProductName,
+		Category:       auctionEntityMongo.Category,
+		Description:    auctionEntityMongo.Description,
+		Condition:      auctionEntityMongo.Condition,
+		Type:           auctionEntityMongo.Type,
+		Status:         auctionEntityMongo.Status,
+		Timestamp:      timeFromUnix(auctionEntityMongo.Timestamp),
+		ExpiresAt:      ar.storedExpiresAt(auctionEntityMongo),
+		StartsAt:       storedStartsAt(auctionEntityMongo),
+		ReservePrice:   auctionEntityMongo.ReservePrice,
+		ReserveMet:     auctionEntityMongo.ReserveMet,
+		BuyNowPrice:    auctionEntityMongo.BuyNowPrice,
+		WinningBidId:   auctionEntityMongo.WinningBidId,
+		Currency:       bid_entity.CurrencyOrDefault(auctionEntityMongo.Currency),
+		CancelReason:   auctionEntityMongo.CancelReason,
+		IdempotencyKey: auctionEntityMongo.IdempotencyKey,
+		WebhookURL:     auctionEntityMongo.WebhookURL,
+		Images:         auctionEntityMongo.Images,
+		WinnerUserId:   auctionEntityMongo.WinnerUserId,
+		WinningAmount:  winningAmount(auctionEntityMongo),
+		NoBids:         auctionEntityMongo.NoBids,
+		Version:        auctionEntityMongo.Version,
+
+		WinnerResolutionPending: auctionEntityMongo.WinnerResolutionPending,
 	}
 }
 
+func (ar *AuctionRepository) storedExpiresAt(auctionEntityMongo AuctionEntityMongo) time.Time {
+	if auctionEntityMongo.ExpiresAt == 0 {
+		return timeFromUnix(auctionEntityMongo.Timestamp).Add(ar.interval())
+	}
+
+	return timeFromUnix(auctionEntityMongo.ExpiresAt)
+}
+
+// storedStartsAt trata leilões gravados sem starts_at como abertos na criação
+func storedStartsAt(auctionEntityMongo AuctionEntityMongo) time.Time {
+	if auctionEntityMongo.StartsAt == 0 {
+		return timeFromUnix(auctionEntityMongo.Timestamp)
+	}
+
+	return timeFromUnix(auctionEntityMongo.StartsAt)
+}
+
+const defaultAuctionInterval = time.Minute * 5
+
 func getAuctionInterval() time.Duration {
 	auctionInterval := os.Getenv("AUCTION_INTERVAL")
+	if auctionInterval == "" {
+		logger.Info("AUCTION_INTERVAL is not set, using default value",
+			zap.Duration("default", defaultAuctionInterval))
+		return defaultAuctionInterval
+	}
+
 	duration, err := time.ParseDuration(auctionInterval)
 	if err != nil {
-		return time.Minute * 5
+		logger.Warn("AUCTION_INTERVAL is invalid, using default value",
+			zap.String("value", auctionInterval),
+			zap.Duration("default", defaultAuctionInterval),
+			zap.Error(err))
+		return defaultAuctionInterval
+	}
+
+	if duration <= 0 {
+		logger.Warn("AUCTION_INTERVAL must be positive, using default value",
+			zap.String("value", auctionInterval),
+			zap.Duration("default", defaultAuctionInterval))
+		return defaultAuctionInterval
+	}
+
+	return duration
+}
+
+const maxDerivedCheckInterval = 30 * time.Second
+
+func getAuctionCheckInterval(auctionInterval time.Duration) time.Duration {
+	// Sem AUCTION_CHECK_INTERVAL, verifica com mais frequência do que o
+	// intervalo de expiração, limitado a 30s para intervalos longos
+	derivedInterval := auctionInterval / 2
+	if derivedInterval < time.Second {
+		derivedInterval = time.Second
+	}
+	if derivedInterval > maxDerivedCheckInterval {
+		derivedInterval = maxDerivedCheckInterval
+	}
+
+	checkInterval := os.Getenv("AUCTION_CHECK_INTERVAL")
+	if checkInterval == "" {
+		return derivedInterval
+	}
+
+	duration, err := time.ParseDuration(checkInterval)
+	if err != nil {
+		logger.Warn("AUCTION_CHECK_INTERVAL is invalid, using derived value",
+			zap.String("value", checkInterval),
+			zap.Duration("default", derivedInterval),
+			zap.Error(err))
+		return derivedInterval
+	}
+
+	if duration <= 0 {
+		logger.Warn("AUCTION_CHECK_INTERVAL must be positive, using derived value",
+			zap.String("value", checkInterval),
+			zap.Duration("default", derivedInterval))
+		return derivedInterval
+	}
+
+	return duration
+}
+
+const defaultAuctionCloseBatchSize = 500
+
+func getAuctionCloseBatchSize() int64 {
+	value, err := strconv.ParseInt(os.Getenv("AUCTION_CLOSE_BATCH_SIZE"), 10, 64)
+	if err != nil || value <= 0 {
+		return defaultAuctionCloseBatchSize
+	}
+
+	return value
+}
+
+// getMaxActiveAuctionsPerUser retorna 0 (sem limite) quando a variável não
+// está definida
+func getMaxActiveAuctionsPerUser() int64 {
+	maxActiveAuctions := os.Getenv("MAX_ACTIVE_AUCTIONS_PER_USER")
+	if maxActiveAuctions == "" {
+		return 0
+	}
+
+	value, err := strconv.ParseInt(maxActiveAuctions, 10, 64)
+	if err != nil || value < 0 {
+		logger.Warn("MAX_ACTIVE_AUCTIONS_PER_USER is invalid, using no limit",
+			zap.String("value", maxActiveAuctions))
+		return 0
+	}
+
+	return value
+}
+
+const defaultMaxAuctionExtension = time.Hour
+
+func getMaxAuctionExtension() time.Duration {
+	maxExtension := os.Getenv("MAX_AUCTION_EXTENSION")
+	if maxExtension == "" {
+		return defaultMaxAuctionExtension
+	}
+
+	duration, err := time.ParseDuration(maxExtension)
+	if err != nil || duration < 0 {
+		logger.Warn("MAX_AUCTION_EXTENSION is invalid, using default value",
+			zap.String("value", maxExtension),
+			zap.Duration("default", defaultMaxAuctionExtension))
+		return defaultMaxAuctionExtension
+	}
+
+	return duration
+}
+
+// getAuctionCloserDryRun liga o modo em que o closer apenas registra os
+// leilões expirados, sem fechá-los
+func getAuctionCloserDryRun() bool {
+	dryRun := os.Getenv("AUCTION_CLOSER_DRY_RUN")
+	if dryRun == "" {
+		return false
+	}
+
+	value, err := strconv.ParseBool(dryRun)
+	if err != nil {
+		logger.Warn("AUCTION_CLOSER_DRY_RUN is invalid, closing auctions normally",
+			zap.String("value", dryRun))
+		return false
+	}
+
+	if value {
+		logger.Warn("AUCTION_CLOSER_DRY_RUN is enabled, expired auctions will not be closed")
+	}
+
+	return value
+}
+
+// getCategoryIntervals lê CATEGORY_INTERVALS, um objeto JSON de categoria
+// para duração, por exemplo {"Eletrônicos": "72h", "Perecíveis": "6h"}.
+// Entradas inválidas são ignoradas e a categoria usa o AUCTION_INTERVAL.
+func getCategoryIntervals() map[string]time.Duration {
+	categoryIntervals := make(map[string]time.Duration)
+
+	value := os.Getenv("CATEGORY_INTERVALS")
+	if value == "" {
+		return categoryIntervals
+	}
+
+	var rawIntervals map[string]string
+	if err := json.Unmarshal([]byte(value), &rawIntervals); err != nil {
+		logger.Warn("CATEGORY_INTERVALS is invalid, using AUCTION_INTERVAL for every category",
+			zap.String("value", value),
+			zap.Error(err))
+		return categoryIntervals
+	}
+
+	for category, rawInterval := range rawIntervals {
+		interval, err := time.ParseDuration(rawInterval)
+		if err != nil || interval <= 0 {
+			logger.Warn("CATEGORY_INTERVALS has an invalid interval, using AUCTION_INTERVAL",
+				zap.String("category", category),
+				zap.String("value", rawInterval))
+			continue
+		}
+
+		categoryIntervals[strings.ToLower(strings.TrimSpace(category))] = interval
+	}
+
+	return categoryIntervals
+}
+
+const defaultAuctionCloserOpTimeout = time.Minute
+
+func getAuctionCloserOpTimeout() time.Duration {
+	closerOpTimeout := os.Getenv("AUCTION_CLOSER_OP_TIMEOUT")
+	if closerOpTimeout == "" {
+		return defaultAuctionCloserOpTimeout
+	}
+
+	duration, err := time.ParseDuration(closerOpTimeout)
+	if err != nil || duration <= 0 {
+		logger.Warn("AUCTION_CLOSER_OP_TIMEOUT is invalid, using default value",
+			zap.String("value", closerOpTimeout),
+			zap.Duration("default", defaultAuctionCloserOpTimeout))
+		return defaultAuctionCloserOpTimeout
+	}
+
+	return duration
+}
+
+// getAuctionCloseGrace lê AUCTION_CLOSE_GRACE, a tolerância depois da
+// expiração antes de o closer fechar o leilão. Enquanto ela não passa o leilão
+// continua Active e aceitando lances.
+func getAuctionCloseGrace() time.Duration {
+	closeGrace := os.Getenv("AUCTION_CLOSE_GRACE")
+	if closeGrace == "" {
+		return 0
+	}
+
+	duration, err := time.ParseDuration(closeGrace)
+	if err != nil || duration < 0 {
+		logger.Warn("AUCTION_CLOSE_GRACE is invalid, closing auctions right after they expire",
+			zap.String("value", closeGrace))
+		return 0
+	}
+
+	return duration
+}
+
+const defaultMongoOpTimeout = 10 * time.Second
+
+func getMongoOpTimeout() time.Duration {
+	opTimeout := os.Getenv("MONGO_OP_TIMEOUT")
+	if opTimeout == "" {
+		return defaultMongoOpTimeout
+	}
+
+	duration, err := time.ParseDuration(opTimeout)
+	if err != nil || duration <= 0 {
+		logger.Warn("MONGO_OP_TIMEOUT is invalid, using default value",
+			zap.String("value", opTimeout),
+			zap.Duration("default", defaultMongoOpTimeout))
+		return defaultMongoOpTimeout
 	}
 
 	return duration
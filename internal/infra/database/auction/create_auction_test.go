@@ -3,17 +3,25 @@ package auction
 import (
 	"context"
 	"fmt"
+	"fullcycle-auction_go/configuration/logger"
 	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/internal_error"
 	"log"
-	"os"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
 	"github.com/testcontainers/testcontainers-go/modules/mongodb"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 func createDatabaseContainer(ctx context.Context, t *testing.T) *mongodb.MongoDBContainer {
@@ -48,36 +56,47 @@ func getTestDatabase(ctx context.Context, t *testing.T) (*mongo.Client, *mongo.D
 	return client, client.Database("auctions_test"), mongoContainer
 }
 
+type fakeClock struct {
+	mutex *sync.Mutex
+	now   time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{mutex: &sync.Mutex{}, now: time.Now()}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.now
+}
+
+func (c *fakeClock) Advance(duration time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.now = c.now.Add(duration)
+}
+
 func TestAutoCloseAuction(t *testing.T) {
-	os.Setenv("AUCTION_INTERVAL", "2s")
-	defer os.Unsetenv("AUCTION_INTERVAL")
+	t.Setenv("AUCTION_INTERVAL", "2s")
+	t.Setenv("AUCTION_CHECK_INTERVAL", "1h")
 
 	ctx := context.Background()
 
-	client, db, container := getTestDatabase(ctx, t)
-	defer func() {
-		if err := container.Terminate(ctx); err != nil {
-			log.Printf("failed to terminate container: %s", err)
-		}
-	}()
-	defer func() {
-		if err := client.Disconnect(ctx); err != nil {
-			log.Fatal(err)
-		}
-	}()
+	db := getSharedTestDatabase(ctx, t)
 
 	collectionName := fmt.Sprintf("auctions_test_%d", time.Now().UnixNano())
 	collection := db.Collection(collectionName)
 	collection.Drop(ctx)
 	defer collection.Drop(ctx)
 
-	closerCtx, closerCancel := context.WithCancel(ctx)
-	defer func() {
-		closerCancel()                     // Cancela o contexto
-		time.Sleep(100 * time.Millisecond) // Dá tempo para a goroutine terminar
-	}()
+	repo := NewAuctionRepositoryWithCollection(ctx, db, collectionName)
+	defer repo.Close()
 
-	repo := NewAuctionRepositoryWithCollection(closerCtx, db, collectionName)
+	clock := newFakeClock()
+	repo.SetClock(clock)
 
 	// Criar leilão expirado
 	expiredAuction := &auction_entity.Auction{
@@ -87,17 +106,13 @@ func TestAutoCloseAuction(t *testing.T) {
 		Description: "Test Description for the auction",
 		Condition:   auction_entity.New,
 		Status:      auction_entity.Active,
-		Timestamp:   time.Now().Add(-3 * time.Second), // 3s no passado
+		Timestamp:   clock.Now().Add(-3 * time.Second), // 3s no passado
 	}
 
-	if err := repo.CreateAuction(ctx, expiredAuction); err != nil {
+	if _, err := repo.CreateAuction(ctx, expiredAuction); err != nil {
 		t.Fatalf("Failed to create expired auction: %v", err)
 	}
 
-	// Aguardar um pouco antes de criar o leilão ativo
-	time.Sleep(500 * time.Millisecond)
-
-	// Criar leilão ativo DEPOIS
 	activeAuction := &auction_entity.Auction{
 		Id:          "test-auction-active",
 		ProductName: "Active Product",
@@ -105,15 +120,15 @@ func TestAutoCloseAuction(t *testing.T) {
 		Description: "This auction should stay open",
 		Condition:   auction_entity.Used,
 		Status:      auction_entity.Active,
-		Timestamp:   time.Now().Add(time.Second * 6),
+		Timestamp:   clock.Now(),
 	}
 
-	if err := repo.CreateAuction(ctx, activeAuction); err != nil {
+	if _, err := repo.CreateAuction(ctx, activeAuction); err != nil {
 		t.Fatalf("Failed to create active auction: %v", err)
 	}
 
-	// Aguardar a verificação
-	time.Sleep(2500 * time.Millisecond) // Um pouco mais que 2s
+	clock.Advance(500 * time.Millisecond)
+	repo.closeExpiredAuctions(ctx)
 
 	var expiredResult AuctionEntityMongo
 	if err := collection.FindOne(ctx, bson.M{"_id": "test-auction-expired"}).Decode(&expiredResult); err != nil {
@@ -121,8 +136,8 @@ func TestAutoCloseAuction(t *testing.T) {
 	}
 
 	if expiredResult.Status != auction_entity.Completed {
-		t.Errorf("Expected expired auction status to be Completed (%d), got %d",
-			auction_entity.Completed, expiredResult.Status)
+		t.Errorf("Expected expired auction status to be Completed, got %s",
+			expiredResult.Status)
 	}
 
 	var activeResult AuctionEntityMongo
@@ -131,14 +146,68 @@ func TestAutoCloseAuction(t *testing.T) {
 	}
 
 	if activeResult.Status != auction_entity.Active {
-		t.Errorf("Expected active auction status to be Active (%d), got %d",
-			auction_entity.Active, activeResult.Status)
+		t.Errorf("Expected active auction status to be Active, got %s",
+			activeResult.Status)
 	}
 }
 
 func TestAutoCloseAuctionAfterExpiration(t *testing.T) {
-	os.Setenv("AUCTION_INTERVAL", "2s")
-	defer os.Unsetenv("AUCTION_INTERVAL")
+	t.Setenv("AUCTION_INTERVAL", "2s")
+	t.Setenv("AUCTION_CHECK_INTERVAL", "1h")
+
+	ctx := context.Background()
+
+	db := getSharedTestDatabase(ctx, t)
+
+	collectionName := fmt.Sprintf("auctions_test_%d", time.Now().UnixNano())
+	collection := db.Collection(collectionName)
+	collection.Drop(ctx)
+	defer collection.Drop(ctx)
+
+	repo := NewAuctionRepositoryWithCollection(ctx, db, collectionName)
+	defer repo.Close()
+
+	clock := newFakeClock()
+	repo.SetClock(clock)
+
+	auctionEntity := &auction_entity.Auction{
+		Id:          "test-auction-transition",
+		ProductName: "Transition Product",
+		Category:    "Electronics",
+		Description: "This auction will expire during the test",
+		Condition:   auction_entity.Refurbished,
+		Status:      auction_entity.Active,
+		Timestamp:   clock.Now(),
+	}
+
+	if _, err := repo.CreateAuction(ctx, auctionEntity); err != nil {
+		t.Fatalf("Failed to create auction: %v", err)
+	}
+
+	repo.closeExpiredAuctions(ctx)
+
+	var result AuctionEntityMongo
+	if err := collection.FindOne(ctx, bson.M{"_id": "test-auction-transition"}).Decode(&result); err != nil {
+		t.Fatalf("Failed to find auction: %v", err)
+	}
+	if result.Status != auction_entity.Active {
+		t.Errorf("Expected auction to be Active right after creation, got %s", result.Status)
+	}
+
+	clock.Advance(4 * time.Second)
+	repo.closeExpiredAuctions(ctx)
+
+	if err := collection.FindOne(ctx, bson.M{"_id": "test-auction-transition"}).Decode(&result); err != nil {
+		t.Fatalf("Failed to find auction after expiration: %v", err)
+	}
+	if result.Status != auction_entity.Completed {
+		t.Errorf("Expected auction to be Completed after interval, got %s", result.Status)
+	}
+}
+
+func TestAutoCloseSetsClosedAtOnce(t *testing.T) {
+	t.Setenv("AUCTION_INTERVAL", "2s")
+	t.Setenv("AUCTION_CHECK_INTERVAL", "1h")
 
 	ctx := context.Background()
 
@@ -159,42 +228,2031 @@ func TestAutoCloseAuctionAfterExpiration(t *testing.T) {
 	collection.Drop(ctx)
 	defer collection.Drop(ctx)
 
-	closerCtx, closerCancel := context.WithCancel(ctx)
-	defer func() {
-		closerCancel()                     // Cancela o contexto
-		time.Sleep(100 * time.Millisecond) // Dá tempo para a goroutine terminar
-	}()
+	repo := NewAuctionRepositoryWithCollection(ctx, db, collectionName)
+	defer repo.Close()
 
-	repo := NewAuctionRepositoryWithCollection(closerCtx, db, collectionName)
+	clock := newFakeClock()
+	repo.SetClock(clock)
 
 	auctionEntity := &auction_entity.Auction{
-		Id:          "test-auction-transition",
-		ProductName: "Transition Product",
+		Id:          "test-auction-closed-at",
+		ProductName: "Closed At Product",
 		Category:    "Electronics",
-		Description: "This auction will expire during the test",
-		Condition:   auction_entity.Refurbished,
+		Description: "This auction records when it was closed",
+		Condition:   auction_entity.New,
 		Status:      auction_entity.Active,
-		Timestamp:   time.Now(),
+		Timestamp:   clock.Now(),
 	}
 
-	if err := repo.CreateAuction(ctx, auctionEntity); err != nil {
+	if _, err := repo.CreateAuction(ctx, auctionEntity); err != nil {
 		t.Fatalf("Failed to create auction: %v", err)
 	}
 
+	clock.Advance(4 * time.Second)
+	closedAt := clock.Now().Unix()
+	repo.closeExpiredAuctions(ctx)
+
 	var result AuctionEntityMongo
-	if err := collection.FindOne(ctx, bson.M{"_id": "test-auction-transition"}).Decode(&result); err != nil {
+	require.NoError(t, collection.FindOne(ctx, bson.M{"_id": "test-auction-closed-at"}).Decode(&result))
+	require.Equal(t, auction_entity.Completed, result.Status)
+	require.NotNil(t, result.ClosedAt)
+	require.Equal(t, closedAt, *result.ClosedAt)
+
+	// Outro processo volta o status para Active sem limpar o closed_at
+	_, err := collection.UpdateOne(ctx,
+		bson.M{"_id": "test-auction-closed-at"},
+		bson.M{"$set": bson.M{"status": auction_entity.Active}})
+	require.NoError(t, err)
+
+	var callbacks atomic.Int32
+	repo.SetOnAuctionClosed(func(ctx context.Context, auctionID string) {
+		callbacks.Add(1)
+	})
+
+	clock.Advance(time.Minute)
+	repo.closeExpiredAuctions(ctx)
+
+	require.NoError(t, collection.FindOne(ctx, bson.M{"_id": "test-auction-closed-at"}).Decode(&result))
+	require.Equal(t, auction_entity.Active, result.Status)
+	require.NotNil(t, result.ClosedAt)
+	require.Equal(t, closedAt, *result.ClosedAt)
+	require.Equal(t, int32(0), callbacks.Load())
+}
+
+func TestAutoCloseDryRun(t *testing.T) {
+	t.Setenv("AUCTION_INTERVAL", "2s")
+	t.Setenv("AUCTION_CHECK_INTERVAL", "1h")
+	t.Setenv("AUCTION_CLOSER_DRY_RUN", "true")
+
+	ctx := context.Background()
+
+	client, db, container := getTestDatabase(ctx, t)
+	defer func() {
+		if err := container.Terminate(ctx); err != nil {
+			log.Printf("failed to terminate container: %s", err)
+		}
+	}()
+	defer func() {
+		if err := client.Disconnect(ctx); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	collectionName := fmt.Sprintf("auctions_test_%d", time.Now().UnixNano())
+	collection := db.Collection(collectionName)
+	collection.Drop(ctx)
+	defer collection.Drop(ctx)
+
+	repo := NewAuctionRepositoryWithCollection(ctx, db, collectionName)
+	defer repo.Close()
+
+	clock := newFakeClock()
+	repo.SetClock(clock)
+
+	for _, id := range []string{"test-auction-dry-run-1", "test-auction-dry-run-2"} {
+		auctionEntity := &auction_entity.Auction{
+			Id:          id,
+			ProductName: "Dry Run Product",
+			Category:    "Electronics",
+			Description: "This auction expires but stays active",
+			Condition:   auction_entity.New,
+			Status:      auction_entity.Active,
+			Timestamp:   clock.Now(),
+		}
+		if _, err := repo.CreateAuction(ctx, auctionEntity); err != nil {
+			t.Fatalf("Failed to create auction: %v", err)
+		}
+	}
+
+	scheduledAuction := &auction_entity.Auction{
+		Id:          "test-auction-dry-run-scheduled",
+		ProductName: "Dry Run Product",
+		Category:    "Electronics",
+		Description: "This auction starts but stays scheduled",
+		Condition:   auction_entity.New,
+		Status:      auction_entity.Scheduled,
+		Timestamp:   clock.Now(),
+		StartsAt:    clock.Now().Add(time.Second),
+	}
+	if _, err := repo.CreateAuction(ctx, scheduledAuction); err != nil {
+		t.Fatalf("Failed to create auction: %v", err)
+	}
+
+	core, logs := observer.New(zap.InfoLevel)
+	restoreLogger := logger.Replace(zap.New(core))
+	defer restoreLogger()
+
+	clock.Advance(4 * time.Second)
+	repo.closeExpiredAuctions(ctx)
+
+	activeAuctions, err := collection.CountDocuments(ctx, bson.M{"status": auction_entity.Active})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), activeAuctions)
+
+	// O dry run também não abre os agendados
+	scheduled, findErr := repo.FindAuctionById(ctx, scheduledAuction.Id)
+	require.Nil(t, findErr)
+	require.Equal(t, auction_entity.Scheduled, scheduled.Status)
+
+	scheduledEntries := logs.FilterMessage("Auction closer dry run, scheduled auctions were not started").All()
+	require.Len(t, scheduledEntries, 1)
+	require.Equal(t, []interface{}{scheduledAuction.Id}, scheduledEntries[0].ContextMap()["auction_ids"])
+
+	entries := logs.FilterMessage("Auction closer dry run, expired auctions were not closed").All()
+	require.Len(t, entries, 1)
+
+	fields := entries[0].ContextMap()
+	require.Equal(t, int64(2), fields["candidates"])
+	require.ElementsMatch(t,
+		[]interface{}{"test-auction-dry-run-1", "test-auction-dry-run-2"}, fields["auction_ids"])
+}
+
+func TestCloseStopsAuctionCloser(t *testing.T) {
+	t.Setenv("AUCTION_INTERVAL", "2s")
+	t.Setenv("AUCTION_CHECK_INTERVAL", "100ms")
+
+	ctx := context.Background()
+
+	client, db, container := getTestDatabase(ctx, t)
+	defer func() {
+		if err := container.Terminate(ctx); err != nil {
+			log.Printf("failed to terminate container: %s", err)
+		}
+	}()
+	defer func() {
+		if err := client.Disconnect(ctx); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	collectionName := fmt.Sprintf("auctions_test_%d", time.Now().UnixNano())
+	collection := db.Collection(collectionName)
+	collection.Drop(ctx)
+	defer collection.Drop(ctx)
+
+	repo := NewAuctionRepositoryWithCollection(ctx, db, collectionName)
+
+	closed := make(chan struct{})
+	go func() {
+		repo.Close()
+		repo.Close()
+		close(closed)
+	}()
+
+	select {
+	case <-closed:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected Close to return after stopping the auction closer")
+	}
+
+	// Depois de fechado, o closer não deve mais alterar leilões expirados
+	expiredAuction := &auction_entity.Auction{
+		Id:          "test-auction-after-close",
+		ProductName: "Test Product",
+		Category:    "Test Category",
+		Description: "Test Description for the auction",
+		Condition:   auction_entity.New,
+		Status:      auction_entity.Active,
+		Timestamp:   time.Now().Add(-3 * time.Second),
+	}
+
+	if _, err := repo.CreateAuction(ctx, expiredAuction); err != nil {
+		t.Fatalf("Failed to create expired auction: %v", err)
+	}
+
+	// Tempo suficiente para vários ticks, caso o closer ainda estivesse rodando
+	time.Sleep(500 * time.Millisecond)
+
+	var result AuctionEntityMongo
+	if err := collection.FindOne(ctx, bson.M{"_id": "test-auction-after-close"}).Decode(&result); err != nil {
 		t.Fatalf("Failed to find auction: %v", err)
 	}
 	if result.Status != auction_entity.Active {
-		t.Errorf("Expected auction to be Active right after creation, got %d", result.Status)
+		t.Errorf("Expected auction to stay Active after Close, got %s", result.Status)
 	}
+}
 
-	time.Sleep(4 * time.Second)
+func TestAutoCloseAuctionWithDifferentExpirations(t *testing.T) {
+	t.Setenv("AUCTION_INTERVAL", "2s")
+	t.Setenv("AUCTION_CHECK_INTERVAL", "1h")
 
-	if err := collection.FindOne(ctx, bson.M{"_id": "test-auction-transition"}).Decode(&result); err != nil {
-		t.Fatalf("Failed to find auction after expiration: %v", err)
+	ctx := context.Background()
+
+	db := getSharedTestDatabase(ctx, t)
+
+	collectionName := fmt.Sprintf("auctions_test_%d", time.Now().UnixNano())
+	collection := db.Collection(collectionName)
+	collection.Drop(ctx)
+	defer collection.Drop(ctx)
+
+	repo := NewAuctionRepositoryWithCollection(ctx, db, collectionName)
+	defer repo.Close()
+
+	clock := newFakeClock()
+	repo.SetClock(clock)
+
+	// Leilão relâmpago: expira em 1s
+	flashAuction := &auction_entity.Auction{
+		Id:          "test-auction-flash",
+		ProductName: "Flash Product",
+		Category:    "Test Category",
+		Description: "This auction expires quickly",
+		Condition:   auction_entity.New,
+		Status:      auction_entity.Active,
+		Timestamp:   clock.Now(),
+		ExpiresAt:   clock.Now().Add(1 * time.Second),
+	}
+
+	// Leilão longo: expira em 5s, mesmo com AUCTION_INTERVAL de 2s
+	longAuction := &auction_entity.Auction{
+		Id:          "test-auction-long",
+		ProductName: "Long Product",
+		Category:    "Test Category",
+		Description: "This auction lasts longer than the interval",
+		Condition:   auction_entity.Used,
+		Status:      auction_entity.Active,
+		Timestamp:   clock.Now(),
+		ExpiresAt:   clock.Now().Add(5 * time.Second),
+	}
+
+	if _, err := repo.CreateAuction(ctx, flashAuction); err != nil {
+		t.Fatalf("Failed to create flash auction: %v", err)
+	}
+	if _, err := repo.CreateAuction(ctx, longAuction); err != nil {
+		t.Fatalf("Failed to create long auction: %v", err)
+	}
+
+	clock.Advance(3 * time.Second)
+	repo.closeExpiredAuctions(ctx)
+
+	var flashResult AuctionEntityMongo
+	if err := collection.FindOne(ctx, bson.M{"_id": "test-auction-flash"}).Decode(&flashResult); err != nil {
+		t.Fatalf("Failed to find flash auction: %v", err)
+	}
+	if flashResult.Status != auction_entity.Completed {
+		t.Errorf("Expected flash auction to be Completed, got %s", flashResult.Status)
+	}
+
+	var longResult AuctionEntityMongo
+	if err := collection.FindOne(ctx, bson.M{"_id": "test-auction-long"}).Decode(&longResult); err != nil {
+		t.Fatalf("Failed to find long auction: %v", err)
+	}
+	if longResult.Status != auction_entity.Active {
+		t.Errorf("Expected long auction to still be Active, got %s", longResult.Status)
+	}
+
+	clock.Advance(4 * time.Second)
+	repo.closeExpiredAuctions(ctx)
+
+	if err := collection.FindOne(ctx, bson.M{"_id": "test-auction-long"}).Decode(&longResult); err != nil {
+		t.Fatalf("Failed to find long auction after expiration: %v", err)
+	}
+	if longResult.Status != auction_entity.Completed {
+		t.Errorf("Expected long auction to be Completed after its expiration, got %s", longResult.Status)
+	}
+}
+
+func TestAutoCloseLegacyAuctionWithoutExpiresAt(t *testing.T) {
+	t.Setenv("AUCTION_INTERVAL", "2s")
+	t.Setenv("AUCTION_CHECK_INTERVAL", "1h")
+
+	ctx := context.Background()
+
+	client, db, container := getTestDatabase(ctx, t)
+	defer func() {
+		if err := container.Terminate(ctx); err != nil {
+			log.Printf("failed to terminate container: %s", err)
+		}
+	}()
+	defer func() {
+		if err := client.Disconnect(ctx); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	collectionName := fmt.Sprintf("auctions_test_%d", time.Now().UnixNano())
+	collection := db.Collection(collectionName)
+	collection.Drop(ctx)
+	defer collection.Drop(ctx)
+
+	repo := NewAuctionRepositoryWithCollection(ctx, db, collectionName)
+	defer repo.Close()
+
+	clock := newFakeClock()
+	repo.SetClock(clock)
+
+	// Documento gravado antes da existência do campo expires_at
+	_, err := collection.InsertOne(ctx, bson.M{
+		"_id":          "test-auction-legacy",
+		"product_name": "Legacy Product",
+		"category":     "Test Category",
+		"description":  "Auction stored without expires_at",
+		"condition":    auction_entity.New,
+		"status":       auction_entity.Active,
+		"timestamp":    clock.Now().Unix(),
+	})
+	require.NoError(t, err)
+
+	clock.Advance(3 * time.Second)
+	repo.closeExpiredAuctions(ctx)
+
+	var result AuctionEntityMongo
+	if err := collection.FindOne(ctx, bson.M{"_id": "test-auction-legacy"}).Decode(&result); err != nil {
+		t.Fatalf("Failed to find legacy auction: %v", err)
 	}
 	if result.Status != auction_entity.Completed {
-		t.Errorf("Expected auction to be Completed after interval, got %d", result.Status)
+		t.Errorf("Expected legacy auction to be Completed, got %s", result.Status)
+	}
+}
+
+func TestAuctionIndexesCreated(t *testing.T) {
+	ctx := context.Background()
+
+	client, db, container := getTestDatabase(ctx, t)
+	defer func() {
+		if err := container.Terminate(ctx); err != nil {
+			log.Printf("failed to terminate container: %s", err)
+		}
+	}()
+	defer func() {
+		if err := client.Disconnect(ctx); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	collectionName := fmt.Sprintf("auctions_test_%d", time.Now().UnixNano())
+	collection := db.Collection(collectionName)
+	collection.Drop(ctx)
+	defer collection.Drop(ctx)
+
+	repo := NewAuctionRepositoryWithCollection(ctx, db, collectionName)
+	defer repo.Close()
+
+	cursor, err := collection.Indexes().List(ctx)
+	require.NoError(t, err)
+
+	var indexes []bson.M
+	require.NoError(t, cursor.All(ctx, &indexes))
+
+	indexNames := make(map[string]bool)
+	for _, index := range indexes {
+		indexNames[index["name"].(string)] = true
+	}
+
+	require.True(t, indexNames["status_1_timestamp_1"], "expected status+timestamp index, got %v", indexNames)
+	require.True(t, indexNames["status_1_expires_at_1"], "expected status+expires_at index, got %v", indexNames)
+	require.True(t, indexNames["user_id_1_status_1"], "expected user_id+status index, got %v", indexNames)
+	require.True(t, indexNames["status_1_closed_at_1"], "expected status+closed_at index, got %v", indexNames)
+	require.True(t, indexNames["user_id_1_idempotency_key_1"], "expected user_id+idempotency_key index, got %v", indexNames)
+}
+
+func TestGetAuctionInterval(t *testing.T) {
+	testCases := []struct {
+		name     string
+		value    string
+		expected time.Duration
+	}{
+		{name: "unset", value: "", expected: defaultAuctionInterval},
+		{name: "valid", value: "30s", expected: 30 * time.Second},
+		{name: "unparseable", value: "5minutes", expected: defaultAuctionInterval},
+		{name: "zero", value: "0s", expected: defaultAuctionInterval},
+		{name: "negative", value: "-1m", expected: defaultAuctionInterval},
 	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("AUCTION_INTERVAL", tc.value)
+
+			require.Equal(t, tc.expected, getAuctionInterval())
+		})
+	}
+}
+
+func TestOnAuctionClosedCallback(t *testing.T) {
+	t.Setenv("AUCTION_INTERVAL", "2s")
+	t.Setenv("AUCTION_CHECK_INTERVAL", "1h")
+
+	ctx := context.Background()
+
+	client, db, container := getTestDatabase(ctx, t)
+	defer func() {
+		if err := container.Terminate(ctx); err != nil {
+			log.Printf("failed to terminate container: %s", err)
+		}
+	}()
+	defer func() {
+		if err := client.Disconnect(ctx); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	collectionName := fmt.Sprintf("auctions_test_%d", time.Now().UnixNano())
+	collection := db.Collection(collectionName)
+	collection.Drop(ctx)
+	defer collection.Drop(ctx)
+
+	repo := NewAuctionRepositoryWithCollection(ctx, db, collectionName)
+	defer repo.Close()
+
+	clock := newFakeClock()
+	repo.SetClock(clock)
+
+	closedIds := make(chan string, 10)
+	repo.SetOnAuctionClosed(func(ctx context.Context, auctionID string) {
+		closedIds <- auctionID
+	})
+
+	for _, auctionId := range []string{"test-auction-callback-1", "test-auction-callback-2"} {
+		expiredAuction := &auction_entity.Auction{
+			Id:          auctionId,
+			ProductName: "Callback Product",
+			Category:    "Test Category",
+			Description: "This auction should trigger the callback",
+			Condition:   auction_entity.New,
+			Status:      auction_entity.Active,
+			Timestamp:   clock.Now(),
+		}
+
+		if _, err := repo.CreateAuction(ctx, expiredAuction); err != nil {
+			t.Fatalf("Failed to create expired auction: %v", err)
+		}
+	}
+
+	clock.Advance(3 * time.Second)
+	repo.closeExpiredAuctions(ctx)
+	close(closedIds)
+
+	received := map[string]bool{}
+	for auctionId := range closedIds {
+		received[auctionId] = true
+	}
+
+	require.Len(t, received, 2)
+	require.True(t, received["test-auction-callback-1"])
+	require.True(t, received["test-auction-callback-2"])
+}
+
+func TestGetAuctionCheckInterval(t *testing.T) {
+	testCases := []struct {
+		name            string
+		value           string
+		auctionInterval time.Duration
+		expected        time.Duration
+	}{
+		{name: "derived from short interval", value: "", auctionInterval: 20 * time.Second, expected: 10 * time.Second},
+		{name: "derived floored at one second", value: "", auctionInterval: time.Second, expected: time.Second},
+		{name: "derived capped for long interval", value: "", auctionInterval: 7 * 24 * time.Hour, expected: maxDerivedCheckInterval},
+		{name: "explicit", value: "5s", auctionInterval: time.Hour, expected: 5 * time.Second},
+		{name: "invalid", value: "often", auctionInterval: 20 * time.Second, expected: 10 * time.Second},
+		{name: "negative", value: "-1s", auctionInterval: 20 * time.Second, expected: 10 * time.Second},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("AUCTION_CHECK_INTERVAL", tc.value)
+
+			require.Equal(t, tc.expected, getAuctionCheckInterval(tc.auctionInterval))
+		})
+	}
+}
+
+func TestAutoCloseWithShortCheckInterval(t *testing.T) {
+	t.Setenv("AUCTION_INTERVAL", "168h")
+	t.Setenv("AUCTION_CHECK_INTERVAL", "100ms")
+
+	ctx := context.Background()
+
+	client, db, container := getTestDatabase(ctx, t)
+	defer func() {
+		if err := container.Terminate(ctx); err != nil {
+			log.Printf("failed to terminate container: %s", err)
+		}
+	}()
+	defer func() {
+		if err := client.Disconnect(ctx); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	collectionName := fmt.Sprintf("auctions_test_%d", time.Now().UnixNano())
+	collection := db.Collection(collectionName)
+	collection.Drop(ctx)
+	defer collection.Drop(ctx)
+
+	repo := NewAuctionRepositoryWithCollection(ctx, db, collectionName)
+	defer repo.Close()
+
+	clock := newFakeClock()
+	repo.SetClock(clock)
+
+	auctionEntity := &auction_entity.Auction{
+		Id:          "test-auction-check-interval",
+		ProductName: "Check Interval Product",
+		Category:    "Test Category",
+		Description: "This auction expires long before the interval",
+		Condition:   auction_entity.New,
+		Status:      auction_entity.Active,
+		Timestamp:   clock.Now(),
+		ExpiresAt:   clock.Now().Add(time.Second),
+	}
+
+	if _, err := repo.CreateAuction(ctx, auctionEntity); err != nil {
+		t.Fatalf("Failed to create auction: %v", err)
+	}
+
+	// A própria goroutine do closer deve fechar o leilão no próximo tick
+	clock.Advance(2 * time.Second)
+
+	require.Eventually(t, func() bool {
+		var result AuctionEntityMongo
+		if err := collection.FindOne(ctx, bson.M{"_id": "test-auction-check-interval"}).Decode(&result); err != nil {
+			return false
+		}
+		return result.Status == auction_entity.Completed
+	}, 2*time.Second, 50*time.Millisecond, "expected auction to be Completed shortly after expiring")
+}
+
+func TestCloseExpiredAuctionsInBatches(t *testing.T) {
+	t.Setenv("AUCTION_INTERVAL", "2s")
+	t.Setenv("AUCTION_CHECK_INTERVAL", "1h")
+	t.Setenv("AUCTION_CLOSE_BATCH_SIZE", "2")
+
+	ctx := context.Background()
+
+	client, db, container := getTestDatabase(ctx, t)
+	defer func() {
+		if err := container.Terminate(ctx); err != nil {
+			log.Printf("failed to terminate container: %s", err)
+		}
+	}()
+	defer func() {
+		if err := client.Disconnect(ctx); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	collectionName := fmt.Sprintf("auctions_test_%d", time.Now().UnixNano())
+	collection := db.Collection(collectionName)
+	collection.Drop(ctx)
+	defer collection.Drop(ctx)
+
+	repo := NewAuctionRepositoryWithCollection(ctx, db, collectionName)
+	defer repo.Close()
+
+	require.Equal(t, int64(2), repo.closeBatchSize)
+
+	for i := 0; i < 5; i++ {
+		expiredAuction := &auction_entity.Auction{
+			Id:          fmt.Sprintf("test-auction-batch-%d", i),
+			ProductName: "Batch Product",
+			Category:    "Test Category",
+			Description: "This auction is closed in batches",
+			Condition:   auction_entity.New,
+			Status:      auction_entity.Active,
+			Timestamp:   time.Now().Add(-3 * time.Second),
+		}
+
+		if _, err := repo.CreateAuction(ctx, expiredAuction); err != nil {
+			t.Fatalf("Failed to create expired auction: %v", err)
+		}
+	}
+
+	repo.closeExpiredAuctions(ctx)
+
+	completed, err := collection.CountDocuments(ctx, bson.M{"status": auction_entity.Completed})
+	require.NoError(t, err)
+	require.Equal(t, int64(5), completed)
+}
+
+func TestCreateAuctionDuplicateId(t *testing.T) {
+	ctx := context.Background()
+
+	client, db, container := getTestDatabase(ctx, t)
+	defer func() {
+		if err := container.Terminate(ctx); err != nil {
+			log.Printf("failed to terminate container: %s", err)
+		}
+	}()
+	defer func() {
+		if err := client.Disconnect(ctx); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	collectionName := fmt.Sprintf("auctions_test_%d", time.Now().UnixNano())
+	collection := db.Collection(collectionName)
+	collection.Drop(ctx)
+	defer collection.Drop(ctx)
+
+	repo := NewAuctionRepositoryWithCollection(ctx, db, collectionName)
+	defer repo.Close()
+
+	auctionEntity := &auction_entity.Auction{
+		Id:          "test-auction-duplicate",
+		ProductName: "Duplicate Product",
+		Category:    "Test Category",
+		Description: "This auction is inserted twice",
+		Condition:   auction_entity.New,
+		Status:      auction_entity.Active,
+		Timestamp:   time.Now(),
+	}
+
+	createdAuction, err := repo.CreateAuction(ctx, auctionEntity)
+	require.Nil(t, err)
+	require.Equal(t, auctionEntity.Id, createdAuction.Id)
+	require.Equal(t, auctionEntity.Timestamp.Unix(), createdAuction.Timestamp.Unix())
+	require.Equal(t, time.UTC, createdAuction.Timestamp.Location())
+
+	createdAuction, err = repo.CreateAuction(ctx, auctionEntity)
+	require.Nil(t, createdAuction)
+	require.NotNil(t, err)
+	require.Equal(t, "conflict", err.Err)
+	require.Equal(t, "auction already exists", err.Message)
+}
+
+func TestCreateAuctionActiveLimitPerUser(t *testing.T) {
+	t.Setenv("AUCTION_INTERVAL", "2s")
+	t.Setenv("AUCTION_CHECK_INTERVAL", "1h")
+	t.Setenv("MAX_ACTIVE_AUCTIONS_PER_USER", "2")
+
+	ctx := context.Background()
+
+	client, db, container := getTestDatabase(ctx, t)
+	defer func() {
+		if err := container.Terminate(ctx); err != nil {
+			log.Printf("failed to terminate container: %s", err)
+		}
+	}()
+	defer func() {
+		if err := client.Disconnect(ctx); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	collectionName := fmt.Sprintf("auctions_test_%d", time.Now().UnixNano())
+	collection := db.Collection(collectionName)
+	collection.Drop(ctx)
+	defer collection.Drop(ctx)
+
+	repo := NewAuctionRepositoryWithCollection(ctx, db, collectionName)
+	defer repo.Close()
+
+	clock := newFakeClock()
+	repo.SetClock(clock)
+
+	userId := uuid.New().String()
+	newUserAuction := func(id, userId string) *auction_entity.Auction {
+		return &auction_entity.Auction{
+			Id:          id,
+			UserId:      userId,
+			ProductName: "Limited Product",
+			Category:    "Electronics",
+			Description: "Auction created to test the per user limit",
+			Condition:   auction_entity.New,
+			Status:      auction_entity.Active,
+			Timestamp:   clock.Now(),
+		}
+	}
+
+	for _, id := range []string{"test-auction-limit-1", "test-auction-limit-2"} {
+		if _, err := repo.CreateAuction(ctx, newUserAuction(id, userId)); err != nil {
+			t.Fatalf("Failed to create auction: %v", err)
+		}
+	}
+
+	_, err := repo.CreateAuction(ctx, newUserAuction("test-auction-limit-3", userId))
+	require.NotNil(t, err)
+	require.Equal(t, "bad_request", err.Err)
+	require.Equal(t, internal_error.CodeActiveAuctionsLimit, err.Code)
+
+	// O limite é por usuário
+	_, err = repo.CreateAuction(ctx, newUserAuction("test-auction-limit-other", uuid.New().String()))
+	require.Nil(t, err)
+
+	// Criações simultâneas do mesmo usuário não passam do limite
+	concurrentUserId := uuid.New().String()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			repo.CreateAuction(ctx, newUserAuction(fmt.Sprintf("test-auction-limit-concurrent-%d", i), concurrentUserId))
+		}(i)
+	}
+	wg.Wait()
+
+	concurrentAuctions, countErr := collection.CountDocuments(ctx, bson.M{"user_id": concurrentUserId})
+	require.NoError(t, countErr)
+	require.LessOrEqual(t, concurrentAuctions, int64(2))
+
+	// Fechar os leilões libera espaço para novos
+	clock.Advance(4 * time.Second)
+	repo.closeExpiredAuctions(ctx)
+
+	_, err = repo.CreateAuction(ctx, newUserAuction("test-auction-limit-3", userId))
+	require.Nil(t, err)
+}
+
+func TestRepositoryLogsCorrelationId(t *testing.T) {
+	t.Setenv("AUCTION_INTERVAL", "2s")
+	t.Setenv("AUCTION_CHECK_INTERVAL", "1h")
+
+	ctx := context.Background()
+
+	client, db, container := getTestDatabase(ctx, t)
+	defer func() {
+		if err := container.Terminate(ctx); err != nil {
+			log.Printf("failed to terminate container: %s", err)
+		}
+	}()
+	defer func() {
+		if err := client.Disconnect(ctx); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	collectionName := fmt.Sprintf("auctions_test_%d", time.Now().UnixNano())
+	collection := db.Collection(collectionName)
+	collection.Drop(ctx)
+	defer collection.Drop(ctx)
+
+	repo := NewAuctionRepositoryWithCollection(ctx, db, collectionName)
+	defer repo.Close()
+
+	clock := newFakeClock()
+	repo.SetClock(clock)
+
+	core, logs := observer.New(zap.InfoLevel)
+	restoreLogger := logger.Replace(zap.New(core))
+	defer restoreLogger()
+
+	auctionEntity := &auction_entity.Auction{
+		Id:          "test-auction-correlation",
+		ProductName: "Logged Product",
+		Category:    "Electronics",
+		Description: "This auction is created inside a request",
+		Condition:   auction_entity.New,
+		Status:      auction_entity.Active,
+		Timestamp:   clock.Now(),
+	}
+
+	requestCtx := logger.ContextWithCorrelationId(ctx, "request-123")
+	if _, err := repo.CreateAuction(requestCtx, auctionEntity); err != nil {
+		t.Fatalf("Failed to create auction: %v", err)
+	}
+
+	created := logs.FilterMessage("Auction created").All()
+	require.Len(t, created, 1)
+	require.Equal(t, "request-123", created[0].ContextMap()["correlation_id"])
+	require.Equal(t, "test-auction-correlation", created[0].ContextMap()["auction_id"])
+
+	clock.Advance(4 * time.Second)
+	repo.closeExpiredAuctions(ctx)
+
+	closed := logs.FilterMessage("Auction closed").All()
+	require.Len(t, closed, 1)
+	require.Equal(t, "test-auction-correlation", closed[0].ContextMap()["auction_id"])
+
+	// Todos os logs da mesma rodada do closer compartilham o id
+	tickCorrelationId := closed[0].ContextMap()["correlation_id"]
+	require.NotEmpty(t, tickCorrelationId)
+	require.Equal(t, tickCorrelationId,
+		logs.FilterMessage("Successfully closed expired auctions").All()[0].ContextMap()["correlation_id"])
+}
+
+func TestCreateAuctionTimeout(t *testing.T) {
+	ctx := context.Background()
+
+	client, db, container := getTestDatabase(ctx, t)
+	defer func() {
+		if err := container.Terminate(ctx); err != nil {
+			log.Printf("failed to terminate container: %s", err)
+		}
+	}()
+	defer func() {
+		if err := client.Disconnect(ctx); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	collectionName := fmt.Sprintf("auctions_test_%d", time.Now().UnixNano())
+	collection := db.Collection(collectionName)
+	collection.Drop(ctx)
+	defer collection.Drop(ctx)
+
+	repo := NewAuctionRepositoryWithCollection(ctx, db, collectionName)
+	defer repo.Close()
+
+	newAuction := func(id string) *auction_entity.Auction {
+		return &auction_entity.Auction{
+			Id:          id,
+			ProductName: "Timeout Product",
+			Category:    "Test Category",
+			Description: "This auction insert should time out",
+			Condition:   auction_entity.New,
+			Status:      auction_entity.Active,
+			Timestamp:   time.Now(),
+		}
+	}
+
+	// Contexto do chamador praticamente expirado
+	deadlineCtx, cancel := context.WithTimeout(ctx, time.Millisecond)
+	defer cancel()
+	time.Sleep(5 * time.Millisecond)
+
+	_, err := repo.CreateAuction(deadlineCtx, newAuction("test-auction-caller-deadline"))
+	require.NotNil(t, err)
+	require.Equal(t, "timeout", err.Err)
+
+	// Sem deadline no chamador, o MONGO_OP_TIMEOUT interno deve ser aplicado
+	t.Setenv("MONGO_OP_TIMEOUT", "1ns")
+	shortTimeoutRepo := NewAuctionRepositoryWithCollection(ctx, db, collectionName)
+	defer shortTimeoutRepo.Close()
+
+	_, err = shortTimeoutRepo.CreateAuction(ctx, newAuction("test-auction-op-timeout"))
+	require.NotNil(t, err)
+	require.Equal(t, "timeout", err.Err)
+
+	_, findErr := shortTimeoutRepo.FindAuctionById(ctx, "test-auction-op-timeout")
+	require.NotNil(t, findErr)
+	require.Equal(t, "timeout", findErr.Err)
+
+	extendErr := shortTimeoutRepo.ExtendAuction(ctx, "test-auction-op-timeout", time.Minute)
+	require.NotNil(t, extendErr)
+	require.Equal(t, "timeout", extendErr.Err)
+}
+
+func TestAuctionCloserMetrics(t *testing.T) {
+	t.Setenv("AUCTION_INTERVAL", "2s")
+	t.Setenv("AUCTION_CHECK_INTERVAL", "1h")
+
+	ctx := context.Background()
+
+	client, db, container := getTestDatabase(ctx, t)
+	defer func() {
+		if err := container.Terminate(ctx); err != nil {
+			log.Printf("failed to terminate container: %s", err)
+		}
+	}()
+	defer func() {
+		if err := client.Disconnect(ctx); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	collectionName := fmt.Sprintf("auctions_test_%d", time.Now().UnixNano())
+	collection := db.Collection(collectionName)
+	collection.Drop(ctx)
+	defer collection.Drop(ctx)
+
+	registry := prometheus.NewRegistry()
+	repo := NewAuctionRepositoryWithCollection(ctx, db, collectionName, registry)
+	defer repo.Close()
+
+	auctions := []*auction_entity.Auction{
+		{Id: "test-auction-metrics-expired", Timestamp: time.Now().Add(-3 * time.Second)},
+		{Id: "test-auction-metrics-active", Timestamp: time.Now(), ExpiresAt: time.Now().Add(time.Hour)},
+	}
+	for _, auctionEntity := range auctions {
+		auctionEntity.ProductName = "Metrics Product"
+		auctionEntity.Category = "Test Category"
+		auctionEntity.Description = "Auction used by the metrics test"
+		auctionEntity.Condition = auction_entity.New
+		auctionEntity.Status = auction_entity.Active
+
+		if _, err := repo.CreateAuction(ctx, auctionEntity); err != nil {
+			t.Fatalf("Failed to create auction: %v", err)
+		}
+	}
+
+	closedCounter := repo.metrics.ClosedAuctions.WithLabelValues("expired")
+	require.Equal(t, 0.0, testutil.ToFloat64(closedCounter))
+
+	repo.closeExpiredAuctions(ctx)
+
+	require.Equal(t, 1.0, testutil.ToFloat64(closedCounter))
+	require.Equal(t, 1.0, testutil.ToFloat64(repo.metrics.ActiveAuctions))
+
+	count, err := testutil.GatherAndCount(registry, "auctions_closed_total", "auctions_active")
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+}
+
+func TestAuctionClosedOnceAcrossInstances(t *testing.T) {
+	t.Setenv("AUCTION_INTERVAL", "2s")
+	t.Setenv("AUCTION_CHECK_INTERVAL", "1h")
+
+	ctx := context.Background()
+
+	client, db, container := getTestDatabase(ctx, t)
+	defer func() {
+		if err := container.Terminate(ctx); err != nil {
+			log.Printf("failed to terminate container: %s", err)
+		}
+	}()
+	defer func() {
+		if err := client.Disconnect(ctx); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	collectionName := fmt.Sprintf("auctions_test_%d", time.Now().UnixNano())
+	collection := db.Collection(collectionName)
+	collection.Drop(ctx)
+	defer collection.Drop(ctx)
+
+	// Duas instâncias do serviço compartilhando a mesma coleção
+	firstRepo := NewAuctionRepositoryWithCollection(ctx, db, collectionName)
+	defer firstRepo.Close()
+	secondRepo := NewAuctionRepositoryWithCollection(ctx, db, collectionName)
+	defer secondRepo.Close()
+
+	var notificationsMutex sync.Mutex
+	notifications := map[string]int{}
+	onAuctionClosed := func(ctx context.Context, auctionID string) {
+		notificationsMutex.Lock()
+		defer notificationsMutex.Unlock()
+		notifications[auctionID]++
+	}
+	firstRepo.SetOnAuctionClosed(onAuctionClosed)
+	secondRepo.SetOnAuctionClosed(onAuctionClosed)
+
+	const totalAuctions = 20
+	for i := 0; i < totalAuctions; i++ {
+		expiredAuction := &auction_entity.Auction{
+			Id:          fmt.Sprintf("test-auction-instances-%d", i),
+			ProductName: "Shared Product",
+			Category:    "Test Category",
+			Description: "This auction is closed by one of two instances",
+			Condition:   auction_entity.New,
+			Status:      auction_entity.Active,
+			Timestamp:   time.Now().Add(-3 * time.Second),
+		}
+
+		if _, err := firstRepo.CreateAuction(ctx, expiredAuction); err != nil {
+			t.Fatalf("Failed to create expired auction: %v", err)
+		}
+	}
+
+	var wg sync.WaitGroup
+	for _, repo := range []*AuctionRepository{firstRepo, secondRepo} {
+		wg.Add(1)
+		go func(repo *AuctionRepository) {
+			defer wg.Done()
+			repo.closeExpiredAuctions(ctx)
+		}(repo)
+	}
+	wg.Wait()
+
+	require.Len(t, notifications, totalAuctions)
+	for auctionId, count := range notifications {
+		require.Equal(t, 1, count, "auction %s notified more than once", auctionId)
+	}
+
+	completed, err := collection.CountDocuments(ctx, bson.M{"status": auction_entity.Completed})
+	require.NoError(t, err)
+	require.Equal(t, int64(totalAuctions), completed)
+}
+
+func TestLastTickAtStopsAfterClose(t *testing.T) {
+	t.Setenv("AUCTION_INTERVAL", "1h")
+	t.Setenv("AUCTION_CHECK_INTERVAL", "100ms")
+
+	ctx := context.Background()
+
+	client, db, container := getTestDatabase(ctx, t)
+	defer func() {
+		if err := container.Terminate(ctx); err != nil {
+			log.Printf("failed to terminate container: %s", err)
+		}
+	}()
+	defer func() {
+		if err := client.Disconnect(ctx); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	collectionName := fmt.Sprintf("auctions_test_%d", time.Now().UnixNano())
+	collection := db.Collection(collectionName)
+	collection.Drop(ctx)
+	defer collection.Drop(ctx)
+
+	repo := NewAuctionRepositoryWithCollection(ctx, db, collectionName)
+	defer repo.Close()
+
+	createdAt := repo.LastTickAt()
+	require.Eventually(t, func() bool {
+		return repo.LastTickAt().After(createdAt)
+	}, 2*time.Second, 20*time.Millisecond)
+
+	// Com a rotina parada o último tick envelhece além de 3x o intervalo
+	repo.Close()
+	stoppedAt := repo.LastTickAt()
+
+	time.Sleep(4 * repo.CheckInterval())
+
+	require.Equal(t, stoppedAt, repo.LastTickAt())
+	require.Greater(t, time.Since(repo.LastTickAt()), 3*repo.CheckInterval())
+}
+
+func TestAutoCloseChecksReservePrice(t *testing.T) {
+	t.Setenv("AUCTION_INTERVAL", "2s")
+	t.Setenv("AUCTION_CHECK_INTERVAL", "1h")
+
+	ctx := context.Background()
+
+	client, db, container := getTestDatabase(ctx, t)
+	defer func() {
+		if err := container.Terminate(ctx); err != nil {
+			log.Printf("failed to terminate container: %s", err)
+		}
+	}()
+	defer func() {
+		if err := client.Disconnect(ctx); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	collectionName := fmt.Sprintf("auctions_test_%d", time.Now().UnixNano())
+	collection := db.Collection(collectionName)
+	collection.Drop(ctx)
+	defer collection.Drop(ctx)
+
+	bidsCollection := db.Collection(testBidsCollectionName)
+	defer bidsCollection.Drop(ctx)
+
+	repo := NewAuctionRepositoryWithCollection(ctx, db, collectionName)
+	defer repo.Close()
+	repo.SetBidStore(newTestBidStore(db))
+
+	clock := newFakeClock()
+	repo.SetClock(clock)
+
+	testCases := []struct {
+		id           string
+		reservePrice float64
+		bids         []float64
+		expected     *bool
+	}{
+		{id: "auction-reserve-met", reservePrice: 500, bids: []float64{300, 500}, expected: boolPtr(true)},
+		{id: "auction-reserve-not-met", reservePrice: 500, bids: []float64{300, 499}, expected: boolPtr(false)},
+		{id: "auction-reserve-without-bids", reservePrice: 500, expected: boolPtr(false)},
+		{id: "auction-without-reserve", bids: []float64{10}, expected: nil},
+	}
+
+	for _, tc := range testCases {
+		auctionEntity := &auction_entity.Auction{
+			Id:           tc.id,
+			ProductName:  "Reserve Product",
+			Category:     "Electronics",
+			Description:  "This auction checks the reserve price",
+			Condition:    auction_entity.New,
+			Status:       auction_entity.Active,
+			Timestamp:    clock.Now(),
+			ReservePrice: tc.reservePrice,
+		}
+		if _, err := repo.CreateAuction(ctx, auctionEntity); err != nil {
+			t.Fatalf("Failed to create auction: %v", err)
+		}
+
+		for _, amount := range tc.bids {
+			_, err := bidsCollection.InsertOne(ctx, bson.M{
+				"_id":        uuid.New().String(),
+				"auction_id": tc.id,
+				"amount":     amount,
+				"timestamp":  clock.Now().Unix(),
+			})
+			require.NoError(t, err)
+		}
+	}
+
+	clock.Advance(4 * time.Second)
+	repo.closeExpiredAuctions(ctx)
+
+	for _, tc := range testCases {
+		t.Run(tc.id, func(t *testing.T) {
+			auctionEntity, err := repo.FindAuctionById(ctx, tc.id)
+			require.Nil(t, err)
+			require.Equal(t, auction_entity.Completed, auctionEntity.Status)
+			require.Equal(t, tc.reservePrice, auctionEntity.ReservePrice)
+			require.Equal(t, tc.expected, auctionEntity.ReserveMet)
+		})
+	}
+}
+
+func TestAutoCloseWithBidsUnavailable(t *testing.T) {
+	t.Setenv("AUCTION_INTERVAL", "2s")
+	t.Setenv("AUCTION_CHECK_INTERVAL", "1h")
+
+	ctx := context.Background()
+
+	client, db, container := getTestDatabase(ctx, t)
+	defer func() {
+		if err := container.Terminate(ctx); err != nil {
+			log.Printf("failed to terminate container: %s", err)
+		}
+	}()
+	defer func() {
+		if err := client.Disconnect(ctx); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	collectionName := fmt.Sprintf("auctions_test_%d", time.Now().UnixNano())
+	collection := db.Collection(collectionName)
+	collection.Drop(ctx)
+	defer collection.Drop(ctx)
+
+	bidsCollection := db.Collection(testBidsCollectionName)
+	defer bidsCollection.Drop(ctx)
+
+	repo := NewAuctionRepositoryWithCollection(ctx, db, collectionName)
+	defer repo.Close()
+
+	clock := newFakeClock()
+	repo.SetClock(clock)
+
+	auctionEntity := &auction_entity.Auction{
+		Id:           "auction-bids-unavailable",
+		ProductName:  "Reserve Product",
+		Category:     "Electronics",
+		Description:  "This auction closes while bids are unavailable",
+		Condition:    auction_entity.New,
+		Status:       auction_entity.Active,
+		Timestamp:    clock.Now(),
+		ReservePrice: 500,
+	}
+	if _, err := repo.CreateAuction(ctx, auctionEntity); err != nil {
+		t.Fatalf("Failed to create auction: %v", err)
+	}
+
+	// Sem BidStore os lances ficam indisponíveis
+	clock.Advance(4 * time.Second)
+	require.NoError(t, repo.closeExpiredAuctions(ctx))
+
+	// O leilão fecha mesmo sem os lances; o vencedor fica pendente
+	closedAuction, err := repo.FindAuctionById(ctx, auctionEntity.Id)
+	require.Nil(t, err)
+	require.Equal(t, auction_entity.Completed, closedAuction.Status)
+	require.True(t, closedAuction.WinnerResolutionPending)
+	require.Nil(t, closedAuction.ReserveMet)
+
+	repo.SetBidStore(newTestBidStore(db))
+	_, insertErr := bidsCollection.InsertOne(ctx, bson.M{
+		"_id":        uuid.New().String(),
+		"user_id":    "user-reserve",
+		"auction_id": auctionEntity.Id,
+		"amount":     500.0,
+		"timestamp":  clock.Now().Unix(),
+	})
+	require.NoError(t, insertErr)
+
+	require.NoError(t, repo.closeExpiredAuctions(ctx))
+
+	resolvedAuction, err := repo.FindAuctionById(ctx, auctionEntity.Id)
+	require.Nil(t, err)
+	require.False(t, resolvedAuction.WinnerResolutionPending)
+	require.Equal(t, boolPtr(true), resolvedAuction.ReserveMet)
+	require.Equal(t, "user-reserve", resolvedAuction.WinnerUserId)
+	require.False(t, resolvedAuction.NoBids)
+}
+
+func boolPtr(value bool) *bool {
+	return &value
+}
+
+func TestCreateAuctionsPartialFailure(t *testing.T) {
+	t.Setenv("AUCTION_INTERVAL", "2s")
+	t.Setenv("AUCTION_CHECK_INTERVAL", "1h")
+
+	ctx := context.Background()
+
+	client, db, container := getTestDatabase(ctx, t)
+	defer func() {
+		if err := container.Terminate(ctx); err != nil {
+			log.Printf("failed to terminate container: %s", err)
+		}
+	}()
+	defer func() {
+		if err := client.Disconnect(ctx); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	collectionName := fmt.Sprintf("auctions_test_%d", time.Now().UnixNano())
+	collection := db.Collection(collectionName)
+	collection.Drop(ctx)
+	defer collection.Drop(ctx)
+
+	repo := NewAuctionRepositoryWithCollection(ctx, db, collectionName)
+	defer repo.Close()
+
+	newAuction := func(id string) *auction_entity.Auction {
+		return &auction_entity.Auction{
+			Id:          id,
+			ProductName: "Catalog Product",
+			Category:    "Electronics",
+			Description: "Auction imported from a catalog",
+			Condition:   auction_entity.New,
+			Status:      auction_entity.Active,
+			Timestamp:   time.Now(),
+		}
+	}
+
+	if _, err := repo.CreateAuction(ctx, newAuction("test-auction-existing")); err != nil {
+		t.Fatalf("Failed to create auction: %v", err)
+	}
+
+	err := repo.CreateAuctions(ctx, []*auction_entity.Auction{
+		newAuction("test-auction-bulk-1"),
+		newAuction("test-auction-existing"),
+		newAuction("test-auction-bulk-2"),
+		newAuction("test-auction-bulk-1"),
+		newAuction("test-auction-bulk-3"),
+	})
+	require.NotNil(t, err)
+	require.Equal(t, "conflict", err.Err)
+	require.Equal(t, internal_error.CodePartialFailure, err.Code)
+	require.Equal(t, "2 of 5 auctions could not be created", err.Message)
+	require.ElementsMatch(t, []string{"test-auction-existing", "test-auction-bulk-1"}, err.FailedIds)
+
+	for _, id := range []string{"test-auction-bulk-1", "test-auction-bulk-2", "test-auction-bulk-3"} {
+		_, findErr := repo.FindAuctionById(ctx, id)
+		require.Nil(t, findErr, id)
+	}
+
+	count, countErr := collection.CountDocuments(ctx, bson.M{})
+	require.NoError(t, countErr)
+	require.Equal(t, int64(4), count)
+
+	require.Nil(t, repo.CreateAuctions(ctx, []*auction_entity.Auction{newAuction("test-auction-bulk-4")}))
+	require.Nil(t, repo.CreateAuctions(ctx, nil))
+
+	// Leilões nil ou inválidos recusam o lote sem gravar nada
+	invalidAuction := newAuction("test-auction-bulk-invalid")
+	invalidAuction.Category = "TV"
+	err = repo.CreateAuctions(ctx, []*auction_entity.Auction{newAuction("test-auction-bulk-5"), invalidAuction})
+	require.NotNil(t, err)
+	require.Equal(t, "bad_request", err.Err)
+	require.Equal(t, "auctions[1]: Category must have more than 2 characters", err.Message)
+	require.Equal(t, []string{"test-auction-bulk-invalid"}, err.FailedIds)
+
+	err = repo.CreateAuctions(ctx, []*auction_entity.Auction{newAuction("test-auction-bulk-5"), nil})
+	require.NotNil(t, err)
+	require.Equal(t, "bad_request", err.Err)
+	require.Equal(t, "auctions[1] is required", err.Message)
+
+	count, countErr = collection.CountDocuments(ctx, bson.M{})
+	require.NoError(t, countErr)
+	require.Equal(t, int64(5), count)
+}
+
+// Rode com -race: criações, ticks e trocas de clock/callback concorrentes não
+// podem disputar memória do repositório nem fechar um leilão duas vezes.
+func TestConcurrentCreatesAndTick(t *testing.T) {
+	t.Setenv("AUCTION_INTERVAL", "2s")
+	t.Setenv("AUCTION_CHECK_INTERVAL", "1h")
+
+	ctx := context.Background()
+
+	client, db, container := getTestDatabase(ctx, t)
+	defer func() {
+		if err := container.Terminate(ctx); err != nil {
+			log.Printf("failed to terminate container: %s", err)
+		}
+	}()
+	defer func() {
+		if err := client.Disconnect(ctx); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	collectionName := fmt.Sprintf("auctions_test_%d", time.Now().UnixNano())
+	collection := db.Collection(collectionName)
+	collection.Drop(ctx)
+	defer collection.Drop(ctx)
+
+	repo := NewAuctionRepositoryWithCollection(ctx, db, collectionName)
+	defer repo.Close()
+
+	clock := newFakeClock()
+	repo.SetClock(clock)
+
+	var closedMutex sync.Mutex
+	closedCount := make(map[string]int)
+	onAuctionClosed := func(ctx context.Context, auctionID string) {
+		closedMutex.Lock()
+		defer closedMutex.Unlock()
+		closedCount[auctionID]++
+	}
+	repo.SetOnAuctionClosed(onAuctionClosed)
+
+	// Metade dos leilões já nasce expirada
+	const auctionsCount = 20
+	var wg sync.WaitGroup
+	for i := 0; i < auctionsCount; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			timestamp := clock.Now()
+			if i%2 == 0 {
+				timestamp = timestamp.Add(-time.Minute)
+			}
+
+			auctionEntity := &auction_entity.Auction{
+				Id:          fmt.Sprintf("test-auction-concurrent-%d", i),
+				ProductName: "Concurrent Product",
+				Category:    "Electronics",
+				Description: "Auction created while the closer runs",
+				Condition:   auction_entity.New,
+				Status:      auction_entity.Active,
+				Timestamp:   timestamp,
+			}
+			if _, err := repo.CreateAuction(ctx, auctionEntity); err != nil {
+				t.Errorf("Failed to create auction: %v", err)
+			}
+		}(i)
+	}
+
+	for i := 0; i < 3; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			repo.closeExpiredAuctions(ctx)
+		}()
+		go func() {
+			defer wg.Done()
+			repo.SetClock(clock)
+			repo.SetOnAuctionClosed(onAuctionClosed)
+		}()
+	}
+	wg.Wait()
+
+	// Um último tick fecha o que foi criado depois dos ticks concorrentes
+	repo.closeExpiredAuctions(ctx)
+
+	count, err := collection.CountDocuments(ctx, bson.M{})
+	require.NoError(t, err)
+	require.Equal(t, int64(auctionsCount), count)
+
+	closedMutex.Lock()
+	defer closedMutex.Unlock()
+	require.Len(t, closedCount, auctionsCount/2)
+	for auctionId, closed := range closedCount {
+		require.Equal(t, 1, closed, auctionId)
+	}
+}
+
+func TestReconcileOnStartupClosesExpiredAuctions(t *testing.T) {
+	t.Setenv("AUCTION_INTERVAL", "1h")
+	t.Setenv("AUCTION_CHECK_INTERVAL", "1h")
+
+	ctx := context.Background()
+
+	client, db, container := getTestDatabase(ctx, t)
+	defer func() {
+		if err := container.Terminate(ctx); err != nil {
+			log.Printf("failed to terminate container: %s", err)
+		}
+	}()
+	defer func() {
+		if err := client.Disconnect(ctx); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	collectionName := fmt.Sprintf("auctions_test_%d", time.Now().UnixNano())
+	collection := db.Collection(collectionName)
+	collection.Drop(ctx)
+	defer collection.Drop(ctx)
+
+	// Leilões que expiraram enquanto o serviço estava fora
+	now := time.Now()
+	for _, auction := range []AuctionEntityMongo{
+		{Id: "test-auction-expired-1", Status: auction_entity.Active,
+			Timestamp: now.Add(-3 * time.Hour).Unix(), ExpiresAt: now.Add(-2 * time.Hour).Unix()},
+		{Id: "test-auction-expired-2", Status: auction_entity.Active,
+			Timestamp: now.Add(-2 * time.Hour).Unix(), ExpiresAt: now.Add(-time.Hour).Unix()},
+		{Id: "test-auction-running", Status: auction_entity.Active,
+			Timestamp: now.Unix(), ExpiresAt: now.Add(time.Hour).Unix()},
+	} {
+		_, err := collection.InsertOne(ctx, auction)
+		require.NoError(t, err)
+	}
+
+	var closedIds []string
+	repo := NewAuctionRepositoryWithOptions(ctx, db,
+		WithCollectionName(collectionName),
+		WithOnClosed(func(ctx context.Context, auctionID string) {
+			closedIds = append(closedIds, auctionID)
+		}))
+	defer repo.Close()
+
+	// Sem esperar o tick de 1h os leilões expirados já estão fechados
+	require.ElementsMatch(t, []string{"test-auction-expired-1", "test-auction-expired-2"}, closedIds)
+
+	for id, expectedStatus := range map[string]auction_entity.AuctionStatus{
+		"test-auction-expired-1": auction_entity.Completed,
+		"test-auction-expired-2": auction_entity.Completed,
+		"test-auction-running":   auction_entity.Active,
+	} {
+		auction, err := repo.FindAuctionById(ctx, id)
+		require.Nil(t, err)
+		require.Equal(t, expectedStatus, auction.Status, id)
+	}
+}
+
+func TestScheduledAuctionBecomesActive(t *testing.T) {
+	t.Setenv("AUCTION_INTERVAL", "2s")
+	t.Setenv("AUCTION_CHECK_INTERVAL", "1h")
+
+	ctx := context.Background()
+
+	client, db, container := getTestDatabase(ctx, t)
+	defer func() {
+		if err := container.Terminate(ctx); err != nil {
+			log.Printf("failed to terminate container: %s", err)
+		}
+	}()
+	defer func() {
+		if err := client.Disconnect(ctx); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	collectionName := fmt.Sprintf("auctions_test_%d", time.Now().UnixNano())
+	collection := db.Collection(collectionName)
+	collection.Drop(ctx)
+	defer collection.Drop(ctx)
+
+	repo := NewAuctionRepositoryWithCollection(ctx, db, collectionName)
+	defer repo.Close()
+
+	clock := newFakeClock()
+	repo.SetClock(clock)
+
+	auctionEntity := &auction_entity.Auction{
+		Id:          "test-auction-scheduled",
+		ProductName: "Scheduled Product",
+		Category:    "Test Category",
+		Description: "Auction that opens for bids later",
+		Condition:   auction_entity.New,
+		Status:      auction_entity.Scheduled,
+		Timestamp:   clock.Now(),
+		StartsAt:    clock.Now().Add(10 * time.Second),
+	}
+	createdAuction, createErr := repo.CreateAuction(ctx, auctionEntity)
+	require.Nil(t, createErr)
+	require.Equal(t, auctionEntity.StartsAt.Unix(), createdAuction.StartsAt.Unix())
+
+	// O intervalo do leilão conta a partir da abertura, não da criação
+	require.Equal(t, auctionEntity.StartsAt.Add(2*time.Second).Unix(), createdAuction.ExpiresAt.Unix())
+
+	status := func() auction_entity.AuctionStatus {
+		result, err := repo.FindAuctionById(ctx, auctionEntity.Id)
+		require.Nil(t, err)
+		return result.Status
+	}
+
+	clock.Advance(5 * time.Second)
+	repo.closeExpiredAuctions(ctx)
+	require.Equal(t, auction_entity.Scheduled, status())
+
+	clock.Advance(5 * time.Second)
+	repo.closeExpiredAuctions(ctx)
+	require.Equal(t, auction_entity.Active, status())
+
+	clock.Advance(3 * time.Second)
+	repo.closeExpiredAuctions(ctx)
+	require.Equal(t, auction_entity.Completed, status())
+}
+
+func TestGetAuctionCloserOpTimeout(t *testing.T) {
+	testCases := []struct {
+		name     string
+		value    string
+		expected time.Duration
+	}{
+		{name: "default", value: "", expected: defaultAuctionCloserOpTimeout},
+		{name: "explicit", value: "15s", expected: 15 * time.Second},
+		{name: "invalid", value: "soon", expected: defaultAuctionCloserOpTimeout},
+		{name: "zero", value: "0s", expected: defaultAuctionCloserOpTimeout},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("AUCTION_CLOSER_OP_TIMEOUT", tc.value)
+
+			require.Equal(t, tc.expected, getAuctionCloserOpTimeout())
+		})
+	}
+}
+
+func TestAutoCloseWaitsForGrace(t *testing.T) {
+	t.Setenv("AUCTION_INTERVAL", "2s")
+	t.Setenv("AUCTION_CHECK_INTERVAL", "1h")
+	t.Setenv("AUCTION_CLOSE_GRACE", "5s")
+
+	ctx := context.Background()
+	db := getSharedTestDatabase(ctx, t)
+
+	collectionName := fmt.Sprintf("auctions_test_%d", time.Now().UnixNano())
+	collection := db.Collection(collectionName)
+	collection.Drop(ctx)
+	defer collection.Drop(ctx)
+
+	repo := NewAuctionRepositoryWithCollection(ctx, db, collectionName)
+	defer repo.Close()
+
+	clock := newFakeClock()
+	repo.SetClock(clock)
+
+	auctionEntity := &auction_entity.Auction{
+		Id:          "test-auction-grace",
+		ProductName: "Grace Product",
+		Category:    "Test Category",
+		Description: "Auction closed only after the grace period",
+		Condition:   auction_entity.New,
+		Status:      auction_entity.Active,
+		Timestamp:   clock.Now(),
+	}
+	_, createErr := repo.CreateAuction(ctx, auctionEntity)
+	require.Nil(t, createErr)
+
+	status := func() auction_entity.AuctionStatus {
+		result, err := repo.FindAuctionById(ctx, auctionEntity.Id)
+		require.Nil(t, err)
+		return result.Status
+	}
+
+	// Expirado há 2s, ainda dentro da tolerância
+	clock.Advance(4 * time.Second)
+	require.NoError(t, repo.closeExpiredAuctions(ctx))
+	require.Equal(t, auction_entity.Active, status())
+
+	stale, err := repo.FindStaleActiveAuctions(ctx)
+	require.Nil(t, err)
+	require.Empty(t, stale)
+
+	clock.Advance(4 * time.Second)
+	require.NoError(t, repo.closeExpiredAuctions(ctx))
+	require.Equal(t, auction_entity.Completed, status())
+}
+
+func TestGetAuctionCloseGrace(t *testing.T) {
+	testCases := []struct {
+		name     string
+		value    string
+		expected time.Duration
+	}{
+		{name: "default", value: "", expected: 0},
+		{name: "explicit", value: "30s", expected: 30 * time.Second},
+		{name: "invalid", value: "later", expected: 0},
+		{name: "negative", value: "-5s", expected: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("AUCTION_CLOSE_GRACE", tc.value)
+
+			require.Equal(t, tc.expected, getAuctionCloseGrace())
+		})
+	}
+}
+
+func TestCloserTickTimesOutAndRecovers(t *testing.T) {
+	t.Setenv("AUCTION_INTERVAL", "2s")
+	t.Setenv("AUCTION_CHECK_INTERVAL", "1h")
+
+	ctx := context.Background()
+
+	client, db, container := getTestDatabase(ctx, t)
+	defer func() {
+		if err := container.Terminate(ctx); err != nil {
+			log.Printf("failed to terminate container: %s", err)
+		}
+	}()
+	defer func() {
+		if err := client.Disconnect(ctx); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	collectionName := fmt.Sprintf("auctions_test_%d", time.Now().UnixNano())
+	collection := db.Collection(collectionName)
+	collection.Drop(ctx)
+	defer collection.Drop(ctx)
+
+	repo := NewAuctionRepositoryWithCollection(ctx, db, collectionName)
+	defer repo.Close()
+
+	clock := newFakeClock()
+	repo.SetClock(clock)
+
+	createAuction := func(id string) {
+		_, err := repo.CreateAuction(ctx, &auction_entity.Auction{
+			Id:          id,
+			ProductName: "Timeout Product",
+			Category:    "Test Category",
+			Description: "Auction used to test the closer timeout",
+			Condition:   auction_entity.New,
+			Status:      auction_entity.Active,
+			Timestamp:   clock.Now(),
+		})
+		require.Nil(t, err)
+	}
+	status := func(id string) auction_entity.AuctionStatus {
+		result, err := repo.FindAuctionById(ctx, id)
+		require.Nil(t, err)
+		return result.Status
+	}
+
+	createAuction("test-auction-timeout-1")
+	clock.Advance(3 * time.Second)
+
+	t.Run("parent cancellation propagates", func(t *testing.T) {
+		cancelledCtx, cancel := context.WithCancel(ctx)
+		cancel()
+
+		err := repo.closeExpiredAuctions(cancelledCtx)
+		require.ErrorIs(t, err, context.Canceled)
+		require.Equal(t, auction_entity.Active, status("test-auction-timeout-1"))
+	})
+
+	t.Run("tick deadline abandons the work", func(t *testing.T) {
+		repo.closerOpTimeout = time.Nanosecond
+
+		err := repo.closeExpiredAuctions(ctx)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.Equal(t, auction_entity.Active, status("test-auction-timeout-1"))
+	})
+
+	t.Run("blocked work is released by the deadline", func(t *testing.T) {
+		repo.closerOpTimeout = 500 * time.Millisecond
+
+		// O callback fica preso até o prazo do tick acabar, como uma
+		// operação travada que respeita o contexto
+		var callbackErr error
+		repo.SetOnAuctionClosed(func(ctx context.Context, auctionID string) {
+			<-ctx.Done()
+			callbackErr = ctx.Err()
+		})
+		defer repo.SetOnAuctionClosed(nil)
+
+		start := time.Now()
+		require.NoError(t, repo.closeExpiredAuctions(ctx))
+		require.Less(t, time.Since(start), 5*time.Second)
+		require.ErrorIs(t, callbackErr, context.DeadlineExceeded)
+		require.Equal(t, auction_entity.Completed, status("test-auction-timeout-1"))
+	})
+
+	t.Run("next tick recovers", func(t *testing.T) {
+		createAuction("test-auction-timeout-2")
+		clock.Advance(3 * time.Second)
+
+		require.NoError(t, repo.closeExpiredAuctions(ctx))
+		require.Equal(t, auction_entity.Completed, status("test-auction-timeout-2"))
+	})
+}
+
+func TestGetCategoryIntervals(t *testing.T) {
+	t.Setenv("CATEGORY_INTERVALS", `{"Eletrônicos": "72h", " Perecíveis ": "6h", "Livros": "soon", "Móveis": "-1h"}`)
+	require.Equal(t, map[string]time.Duration{
+		"eletrônicos": 72 * time.Hour,
+		"perecíveis":  6 * time.Hour,
+	}, getCategoryIntervals())
+
+	t.Setenv("CATEGORY_INTERVALS", "not json")
+	require.Empty(t, getCategoryIntervals())
+
+	t.Setenv("CATEGORY_INTERVALS", "")
+	require.Empty(t, getCategoryIntervals())
+}
+
+func TestCreateAuctionUsesCategoryInterval(t *testing.T) {
+	t.Setenv("AUCTION_INTERVAL", "1h")
+	t.Setenv("AUCTION_CHECK_INTERVAL", "1h")
+	t.Setenv("CATEGORY_INTERVALS", `{"Eletrônicos": "72h", "Perecíveis": "6h"}`)
+
+	ctx := context.Background()
+
+	client, db, container := getTestDatabase(ctx, t)
+	defer func() {
+		if err := container.Terminate(ctx); err != nil {
+			log.Printf("failed to terminate container: %s", err)
+		}
+	}()
+	defer func() {
+		if err := client.Disconnect(ctx); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	collectionName := fmt.Sprintf("auctions_test_%d", time.Now().UnixNano())
+	collection := db.Collection(collectionName)
+	collection.Drop(ctx)
+	defer collection.Drop(ctx)
+
+	repo := NewAuctionRepositoryWithCollection(ctx, db, collectionName)
+	defer repo.Close()
+
+	now := time.Now()
+	testCases := []struct {
+		category string
+		expected time.Duration
+	}{
+		{category: "Eletrônicos", expected: 72 * time.Hour},
+		{category: "perecíveis", expected: 6 * time.Hour},
+		{category: "Livros", expected: time.Hour},
+	}
+
+	for i, tc := range testCases {
+		t.Run(tc.category, func(t *testing.T) {
+			createdAuction, err := repo.CreateAuction(ctx, &auction_entity.Auction{
+				Id:          fmt.Sprintf("test-auction-category-%d", i),
+				ProductName: "Category Product",
+				Category:    tc.category,
+				Description: "Auction used to test category intervals",
+				Condition:   auction_entity.New,
+				Status:      auction_entity.Active,
+				Timestamp:   now,
+			})
+			require.Nil(t, err)
+			require.Equal(t, now.Add(tc.expected).Unix(), createdAuction.ExpiresAt.Unix())
+		})
+	}
+}
+
+func TestCreateAuctionIdempotencyKey(t *testing.T) {
+	t.Setenv("AUCTION_INTERVAL", "1h")
+	t.Setenv("AUCTION_CHECK_INTERVAL", "1h")
+
+	ctx := context.Background()
+
+	client, db, container := getTestDatabase(ctx, t)
+	defer func() {
+		if err := container.Terminate(ctx); err != nil {
+			log.Printf("failed to terminate container: %s", err)
+		}
+	}()
+	defer func() {
+		if err := client.Disconnect(ctx); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	collectionName := fmt.Sprintf("auctions_test_%d", time.Now().UnixNano())
+	collection := db.Collection(collectionName)
+	collection.Drop(ctx)
+	defer collection.Drop(ctx)
+
+	repo := NewAuctionRepositoryWithCollection(ctx, db, collectionName)
+	defer repo.Close()
+
+	userId := uuid.New().String()
+	newAuction := func(idempotencyKey string) *auction_entity.Auction {
+		auctionEntity, err := auction_entity.CreateAuction(auction_entity.AuctionInput{
+			UserId:      userId,
+			ProductName: "Retry Product",
+			Category:    "Test Category",
+			Description: "Auction created by a client that retries",
+			Condition:   auction_entity.New,
+		})
+		require.Nil(t, err)
+		auctionEntity.IdempotencyKey = idempotencyKey
+		return auctionEntity
+	}
+
+	firstAuction, err := repo.CreateAuction(ctx, newAuction("create-1"))
+	require.Nil(t, err)
+
+	// A nova tentativa gera outro id, mas recebe o leilão original
+	replayedAuction, err := repo.CreateAuction(ctx, newAuction("create-1"))
+	require.Nil(t, err)
+	require.Equal(t, firstAuction.Id, replayedAuction.Id)
+
+	count, countErr := collection.CountDocuments(ctx, bson.M{"idempotency_key": "create-1"})
+	require.NoError(t, countErr)
+	require.Equal(t, int64(1), count)
+
+	// Outra chave, ou nenhuma, cria um leilão novo
+	otherAuction, err := repo.CreateAuction(ctx, newAuction("create-2"))
+	require.Nil(t, err)
+	require.NotEqual(t, firstAuction.Id, otherAuction.Id)
+
+	for i := 0; i < 2; i++ {
+		_, err := repo.CreateAuction(ctx, newAuction(""))
+		require.Nil(t, err)
+	}
+
+	count, countErr = collection.CountDocuments(ctx, bson.M{"user_id": userId})
+	require.NoError(t, countErr)
+	require.Equal(t, int64(4), count)
+
+	// O índice único barra a gravação direta de uma chave repetida, que é o
+	// caminho de duas tentativas simultâneas
+	duplicated := repo.toAuctionEntityMongo(newAuction("create-1"))
+	_, insertErr := collection.InsertOne(ctx, duplicated)
+	require.True(t, mongo.IsDuplicateKeyError(insertErr), "expected duplicate key error, got %v", insertErr)
+}
+
+func TestSetAuctionIntervalChangesCadence(t *testing.T) {
+	t.Setenv("AUCTION_INTERVAL", "2s")
+	t.Setenv("AUCTION_CHECK_INTERVAL", "")
+
+	ctx := context.Background()
+
+	client, db, container := getTestDatabase(ctx, t)
+	defer func() {
+		if err := container.Terminate(ctx); err != nil {
+			log.Printf("failed to terminate container: %s", err)
+		}
+	}()
+	defer func() {
+		if err := client.Disconnect(ctx); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	collectionName := fmt.Sprintf("auctions_test_%d", time.Now().UnixNano())
+	collection := db.Collection(collectionName)
+	collection.Drop(ctx)
+	defer collection.Drop(ctx)
+
+	repo := NewAuctionRepositoryWithCollection(ctx, db, collectionName)
+	defer repo.Close()
+
+	require.Equal(t, time.Second, repo.CheckInterval())
+
+	// Espera o closer rodar com o intervalo derivado de 2s
+	createdAt := repo.LastTickAt()
+	require.Eventually(t, func() bool {
+		return repo.LastTickAt().After(createdAt)
+	}, 3*time.Second, 20*time.Millisecond)
+
+	err := repo.SetAuctionInterval(0)
+	require.NotNil(t, err)
+	require.Equal(t, "bad_request", err.Err)
+	require.Equal(t, time.Second, repo.CheckInterval())
+
+	require.Nil(t, repo.SetAuctionInterval(time.Hour))
+	require.Equal(t, maxDerivedCheckInterval, repo.CheckInterval())
+
+	// O ticker antigo dispara no máximo mais uma vez antes de passar a usar o
+	// novo intervalo; depois disso não há ticks no ritmo de 1s
+	time.Sleep(1500 * time.Millisecond)
+	resizedAt := repo.LastTickAt()
+	time.Sleep(2500 * time.Millisecond)
+	require.Equal(t, resizedAt, repo.LastTickAt())
+
+	now := time.Now()
+	createdAuction, createErr := repo.CreateAuction(ctx, &auction_entity.Auction{
+		Id:          "test-auction-new-interval",
+		ProductName: "Interval Product",
+		Category:    "Test Category",
+		Description: "Auction used to test the interval reload",
+		Condition:   auction_entity.New,
+		Status:      auction_entity.Active,
+		Timestamp:   now,
+	})
+	require.Nil(t, createErr)
+	require.Equal(t, now.Add(time.Hour).Unix(), createdAuction.ExpiresAt.Unix())
+}
+
+// panickingClock entra em pânico nas primeiras panics chamadas a Now depois
+// de armado, simulando um bug dentro da rodada do closer
+type panickingClock struct {
+	*fakeClock
+	panics atomic.Int32
+}
+
+func (c *panickingClock) Now() time.Time {
+	if c.panics.Add(-1) >= 0 {
+		panic("clock exploded")
+	}
+	return c.fakeClock.Now()
+}
+
+func TestAuctionCloserSurvivesPanic(t *testing.T) {
+	t.Setenv("AUCTION_INTERVAL", "2s")
+
+	ctx := context.Background()
+	db := getSharedTestDatabase(ctx, t)
+
+	collectionName := fmt.Sprintf("auctions_test_%d", time.Now().UnixNano())
+	collection := db.Collection(collectionName)
+	collection.Drop(ctx)
+	defer collection.Drop(ctx)
+
+	clock := &panickingClock{fakeClock: newFakeClock()}
+	repo := NewAuctionRepositoryWithOptions(ctx, db,
+		WithCollectionName(collectionName),
+		WithClock(clock),
+		WithCheckInterval(50*time.Millisecond))
+	defer repo.Close()
+
+	auctionEntity := &auction_entity.Auction{
+		Id:          "test-auction-closer-panic",
+		ProductName: "Panic Product",
+		Category:    "Electronics",
+		Description: "Auction closed after the closer recovers from a panic",
+		Condition:   auction_entity.New,
+		Status:      auction_entity.Active,
+		Timestamp:   clock.Now(),
+	}
+	_, createErr := repo.CreateAuction(ctx, auctionEntity)
+	require.Nil(t, createErr)
+
+	clock.panics.Store(1)
+	clock.Advance(4 * time.Second)
+
+	require.Eventually(t, func() bool {
+		return testutil.ToFloat64(repo.metrics.CloserPanics) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// O tick seguinte fecha o leilão normalmente
+	require.Eventually(t, func() bool {
+		result, err := repo.FindAuctionById(ctx, auctionEntity.Id)
+		return err == nil && result.Status == auction_entity.Completed
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestSetAuctionIntervalDebouncesTickerReset(t *testing.T) {
+	t.Setenv("AUCTION_INTERVAL", "1h")
+	t.Setenv("AUCTION_CHECK_INTERVAL", "")
+
+	ctx := context.Background()
+	db := getSharedTestDatabase(ctx, t)
+
+	collectionName := fmt.Sprintf("auctions_test_%d", time.Now().UnixNano())
+	collection := db.Collection(collectionName)
+	collection.Drop(ctx)
+	defer collection.Drop(ctx)
+
+	repo := NewAuctionRepositoryWithCollection(ctx, db, collectionName)
+	defer repo.Close()
+
+	tickerResets := func() float64 {
+		return testutil.ToFloat64(repo.metrics.CloserTickerResets)
+	}
+
+	// Uma troca que volta ao intervalo original não refaz o ticker
+	require.Nil(t, repo.SetAuctionInterval(2*time.Second))
+	require.Nil(t, repo.SetAuctionInterval(time.Hour))
+	time.Sleep(2 * intervalChangeQuietPeriod)
+	require.Zero(t, tickerResets())
+
+	for _, auctionInterval := range []time.Duration{
+		2 * time.Second, 4 * time.Second, 10 * time.Second, time.Minute, 6 * time.Second,
+	} {
+		require.Nil(t, repo.SetAuctionInterval(auctionInterval))
+		time.Sleep(intervalChangeQuietPeriod / 10)
+	}
+	require.Equal(t, 3*time.Second, repo.CheckInterval())
+
+	require.Eventually(t, func() bool {
+		return tickerResets() == 1
+	}, 2*time.Second, 20*time.Millisecond)
+
+	time.Sleep(2 * intervalChangeQuietPeriod)
+	require.Equal(t, 1.0, tickerResets())
+}
+
+func TestReloadAuctionInterval(t *testing.T) {
+	t.Setenv("AUCTION_INTERVAL", "2s")
+	t.Setenv("AUCTION_CHECK_INTERVAL", "1h")
+
+	ctx := context.Background()
+
+	client, db, container := getTestDatabase(ctx, t)
+	defer func() {
+		if err := container.Terminate(ctx); err != nil {
+			log.Printf("failed to terminate container: %s", err)
+		}
+	}()
+	defer func() {
+		if err := client.Disconnect(ctx); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	collectionName := fmt.Sprintf("auctions_test_%d", time.Now().UnixNano())
+	collection := db.Collection(collectionName)
+	collection.Drop(ctx)
+	defer collection.Drop(ctx)
+
+	repo := NewAuctionRepositoryWithCollection(ctx, db, collectionName)
+	defer repo.Close()
+
+	t.Setenv("AUCTION_INTERVAL", "10m")
+	require.Nil(t, repo.ReloadAuctionInterval())
+	require.Equal(t, 10*time.Minute, repo.interval())
+
+	// AUCTION_CHECK_INTERVAL explícito não é derivado de novo
+	require.Equal(t, time.Hour, repo.CheckInterval())
+
+	t.Setenv("AUCTION_INTERVAL", "soon")
+	require.NotNil(t, repo.ReloadAuctionInterval())
+	require.Equal(t, 10*time.Minute, repo.interval())
 }
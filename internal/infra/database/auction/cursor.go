@@ -0,0 +1,49 @@
+package auction
+
+import (
+	"context"
+	"fullcycle-auction_go/configuration/logger"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const cursorCloseTimeout = 5 * time.Second
+
+// documentCursor é a parte do *mongo.Cursor usada nas leituras documento a
+// documento
+type documentCursor interface {
+	Next(ctx context.Context) bool
+	Decode(val interface{}) error
+	Err() error
+	Close(ctx context.Context) error
+}
+
+// closeCursor fecha o cursor num contexto próprio. Com o ctx da leitura já
+// cancelado o driver não conseguiria enviar o killCursors, e o cursor ficaria
+// aberto no servidor até expirar.
+func closeCursor(ctx context.Context, cursor documentCursor) {
+	closeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), cursorCloseTimeout)
+	defer cancel()
+
+	if err := cursor.Close(closeCtx); err != nil {
+		logger.Warn("Error trying to close cursor", zap.Error(err))
+	}
+}
+
+// nextDocument avança o cursor, parando assim que ctx é cancelado. O driver só
+// olha o contexto quando busca um novo lote no servidor; sem a verificação os
+// documentos do lote já recebido seriam percorridos até o fim.
+func nextDocument(ctx context.Context, cursor documentCursor) bool {
+	return ctx.Err() == nil && cursor.Next(ctx)
+}
+
+// cursorErr é o erro que encerrou a iteração: o cancelamento de ctx ou a
+// falha do cursor
+func cursorErr(ctx context.Context, cursor documentCursor) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return cursor.Err()
+}
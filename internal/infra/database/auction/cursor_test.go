@@ -0,0 +1,144 @@
+package auction
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeCursor entrega documentos de um lote já em memória, como o driver faz
+// antes de buscar o próximo lote no servidor
+type fakeCursor struct {
+	documents     []AuctionEntityMongo
+	position      int
+	nextCalls     int
+	closed        bool
+	closeCtxError error
+}
+
+func (c *fakeCursor) Next(ctx context.Context) bool {
+	c.nextCalls++
+	if c.position >= len(c.documents) {
+		return false
+	}
+	c.position++
+	return true
+}
+
+func (c *fakeCursor) Decode(val interface{}) error {
+	*val.(*AuctionEntityMongo) = c.documents[c.position-1]
+	return nil
+}
+
+func (c *fakeCursor) Err() error {
+	return nil
+}
+
+func (c *fakeCursor) Close(ctx context.Context) error {
+	c.closed = true
+	c.closeCtxError = ctx.Err()
+	return nil
+}
+
+// cancellingWriter cancela o contexto da exportação depois da primeira
+// escrita, como um cliente que desiste do download
+type cancellingWriter struct {
+	output bytes.Buffer
+	cancel context.CancelFunc
+}
+
+func (w *cancellingWriter) Write(p []byte) (int, error) {
+	w.cancel()
+	return w.output.Write(p)
+}
+
+func TestExportAuctionsStopsWhenContextIsCancelled(t *testing.T) {
+	t.Setenv("AUCTION_INTERVAL", "1h")
+	t.Setenv("AUCTION_CHECK_INTERVAL", "1h")
+
+	ctx := context.Background()
+	db := getSharedTestDatabase(ctx, t)
+
+	collectionName := fmt.Sprintf("auctions_test_%d", time.Now().UnixNano())
+	collection := db.Collection(collectionName)
+	collection.Drop(ctx)
+	defer collection.Drop(ctx)
+
+	repo := NewAuctionRepositoryWithCollection(ctx, db, collectionName)
+	defer repo.Close()
+
+	t.Run("stops the iteration and closes the cursor", func(t *testing.T) {
+		cursor := &fakeCursor{}
+		for i := 0; i < 10; i++ {
+			cursor.documents = append(cursor.documents, AuctionEntityMongo{
+				Id:        fmt.Sprintf("cursor-%d", i),
+				Status:    auction_entity.Active,
+				Condition: auction_entity.New,
+			})
+		}
+
+		exportCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		var written int
+		err := repo.writeAuctions(exportCtx, cursor, func(auctionExport) error {
+			written++
+			cancel()
+			return nil
+		})
+
+		require.NotNil(t, err)
+		require.Equal(t, 1, written)
+		require.Equal(t, 1, cursor.nextCalls)
+		require.True(t, cursor.closed)
+		// O fechamento não usa o contexto cancelado
+		require.NoError(t, cursor.closeCtxError)
+	})
+
+	t.Run("export against mongo", func(t *testing.T) {
+		// Mais documentos que o primeiro lote do driver (101), para que o
+		// cursor continue aberto no servidor quando o contexto é cancelado
+		auctions := make([]*auction_entity.Auction, 0, 250)
+		now := time.Now()
+		for i := 0; i < 250; i++ {
+			auctions = append(auctions, &auction_entity.Auction{
+				Id:          fmt.Sprintf("export-cancel-%03d", i),
+				ProductName: "Cancelled export",
+				Category:    "Electronics",
+				Description: "Auction used by the cancelled export test",
+				Condition:   auction_entity.New,
+				Status:      auction_entity.Active,
+				Timestamp:   now.Add(time.Duration(i) * time.Second),
+			})
+		}
+		require.Nil(t, repo.CreateAuctions(ctx, auctions))
+
+		// Uma exportação completa antes de medir, para que as conexões do pool
+		// já existam
+		var warmup bytes.Buffer
+		require.Nil(t, repo.ExportAuctions(ctx, &warmup, ExportFormatJSON, auction_entity.AuctionFilter{}))
+		require.Equal(t, 250, strings.Count(warmup.String(), "\n"))
+		goroutinesBefore := runtime.NumGoroutine()
+
+		exportCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		writer := &cancellingWriter{cancel: cancel}
+		err := repo.ExportAuctions(exportCtx, writer, ExportFormatJSON, auction_entity.AuctionFilter{})
+		require.NotNil(t, err)
+
+		// O encoder JSON escreve um leilão por chamada
+		require.Equal(t, 1, strings.Count(writer.output.String(), "\n"))
+
+		require.Eventually(t, func() bool {
+			return runtime.NumGoroutine() <= goroutinesBefore
+		}, 5*time.Second, 50*time.Millisecond)
+	})
+}
@@ -0,0 +1,53 @@
+package auction
+
+import (
+	"context"
+	"fmt"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/internal_error"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func (ar *AuctionRepository) DeleteAuction(
+	ctx context.Context, id string) *internal_error.InternalError {
+	result, err := ar.Collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		logger.Error(fmt.Sprintf("Error trying to delete auction = %s", id), err)
+		return internal_error.NewInternalServerError("Error trying to delete auction")
+	}
+
+	if result.DeletedCount == 0 {
+		return internal_error.NewNotFoundError(
+			fmt.Sprintf("Auction not found with this id = %s", id)).
+			WithCode(internal_error.CodeAuctionNotFound)
+	}
+
+	return nil
+}
+
+// SoftDelete marca o leilão com deleted_at, preservando o documento para
+// auditoria. Leilões marcados deixam de aparecer nas buscas e no closer.
+func (ar *AuctionRepository) SoftDelete(
+	ctx context.Context, id string) *internal_error.InternalError {
+	filter := bson.M{"_id": id, "deleted_at": nil}
+	update := bson.M{
+		"$set": bson.M{"deleted_at": time.Now().Unix()},
+		"$inc": bson.M{"version": 1},
+	}
+
+	result, err := ar.Collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Error trying to soft delete auction = %s", id), err)
+		return internal_error.NewInternalServerError("Error trying to soft delete auction")
+	}
+
+	if result.MatchedCount == 0 {
+		return internal_error.NewNotFoundError(
+			fmt.Sprintf("Auction not found with this id = %s", id)).
+			WithCode(internal_error.CodeAuctionNotFound)
+	}
+
+	return nil
+}
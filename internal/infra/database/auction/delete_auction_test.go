@@ -0,0 +1,77 @@
+package auction
+
+import (
+	"context"
+	"fmt"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestSoftDeleteAuction(t *testing.T) {
+	t.Setenv("AUCTION_INTERVAL", "1h")
+	t.Setenv("AUCTION_CHECK_INTERVAL", "1h")
+
+	ctx := context.Background()
+
+	client, db, container := getTestDatabase(ctx, t)
+	defer func() {
+		if err := container.Terminate(ctx); err != nil {
+			log.Printf("failed to terminate container: %s", err)
+		}
+	}()
+	defer func() {
+		if err := client.Disconnect(ctx); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	collectionName := fmt.Sprintf("auctions_test_%d", time.Now().UnixNano())
+	collection := db.Collection(collectionName)
+	collection.Drop(ctx)
+	defer collection.Drop(ctx)
+
+	repo := NewAuctionRepositoryWithCollection(ctx, db, collectionName)
+	defer repo.Close()
+
+	// Leilão ativo e já expirado: seria fechado pelo closer se não fosse removido
+	auctionEntity := &auction_entity.Auction{
+		Id:          "test-auction-soft-delete",
+		ProductName: "Soft Delete Product",
+		Category:    "Test Category",
+		Description: "This auction is soft deleted",
+		Condition:   auction_entity.New,
+		Status:      auction_entity.Active,
+		Timestamp:   time.Now().Add(-time.Hour),
+		ExpiresAt:   time.Now().Add(-time.Minute),
+	}
+
+	if _, err := repo.CreateAuction(ctx, auctionEntity); err != nil {
+		t.Fatalf("Failed to create auction: %v", err)
+	}
+
+	require.Nil(t, repo.SoftDelete(ctx, auctionEntity.Id))
+
+	repo.closeExpiredAuctions(ctx)
+
+	var stored AuctionEntityMongo
+	require.NoError(t, collection.FindOne(ctx, bson.M{"_id": auctionEntity.Id}).Decode(&stored))
+	require.Equal(t, auction_entity.Active, stored.Status)
+	require.NotNil(t, stored.DeletedAt)
+
+	_, findErr := repo.FindAuctionById(ctx, auctionEntity.Id)
+	require.NotNil(t, findErr)
+	require.Equal(t, "not_found", findErr.Err)
+
+	auctions, findErr := repo.FindAuctions(ctx, statusRef(auction_entity.Active), "", "", 1, 0)
+	require.Nil(t, findErr)
+	require.Empty(t, auctions)
+
+	deleteErr := repo.SoftDelete(ctx, auctionEntity.Id)
+	require.NotNil(t, deleteErr)
+	require.Equal(t, "not_found", deleteErr.Err)
+}
@@ -0,0 +1,196 @@
+package auction
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/infra/database/database_error"
+	"fullcycle-auction_go/internal/internal_error"
+	"io"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	ExportFormatCSV  = "csv"
+	ExportFormatJSON = "json"
+)
+
+// auctionExportColumns é o cabeçalho do CSV, na mesma ordem de csvRow
+var auctionExportColumns = []string{
+	"id", "user_id", "product_name", "category", "description", "condition", "status",
+	"currency", "reserve_price", "reserve_met", "buy_now_price", "winning_bid_id",
+	"cancel_reason", "timestamp", "starts_at", "expires_at",
+}
+
+// auctionExport é uma linha da exportação. Os enums saem pelo nome e os
+// horários em RFC 3339; campos internos, como a webhook_url e a chave de
+// idempotência, ficam de fora.
+type auctionExport struct {
+	Id           string  `json:"id"`
+	UserId       string  `json:"user_id"`
+	ProductName  string  `json:"product_name"`
+	Category     string  `json:"category"`
+	Description  string  `json:"description"`
+	Condition    string  `json:"condition"`
+	Status       string  `json:"status"`
+	Currency     string  `json:"currency"`
+	ReservePrice float64 `json:"reserve_price"`
+	ReserveMet   *bool   `json:"reserve_met"`
+	BuyNowPrice  float64 `json:"buy_now_price"`
+	WinningBidId string  `json:"winning_bid_id"`
+	CancelReason string  `json:"cancel_reason"`
+	Timestamp    string  `json:"timestamp"`
+	StartsAt     string  `json:"starts_at"`
+	ExpiresAt    string  `json:"expires_at"`
+}
+
+func newAuctionExport(auctionEntity *auction_entity.Auction) auctionExport {
+	return auctionExport{
+		Id:           auctionEntity.Id,
+		UserId:       auctionEntity.UserId,
+		ProductName:  auctionEntity.ProductName,
+		Category:     auctionEntity.Category,
+		Description:  auctionEntity.Description,
+		Condition:    auctionEntity.Condition.String(),
+		Status:       auctionEntity.Status.String(),
+		Currency:     auctionEntity.Currency,
+		ReservePrice: auctionEntity.ReservePrice,
+		ReserveMet:   auctionEntity.ReserveMet,
+		BuyNowPrice:  auctionEntity.BuyNowPrice,
+		WinningBidId: auctionEntity.WinningBidId,
+		CancelReason: auctionEntity.CancelReason,
+		Timestamp:    formatExportTime(auctionEntity.Timestamp),
+		StartsAt:     formatExportTime(auctionEntity.StartsAt),
+		ExpiresAt:    formatExportTime(auctionEntity.ExpiresAt),
+	}
+}
+
+func (e auctionExport) csvRow() []string {
+	var reserveMet string
+	if e.ReserveMet != nil {
+		reserveMet = strconv.FormatBool(*e.ReserveMet)
+	}
+
+	return []string{
+		e.Id, e.UserId, e.ProductName, e.Category, e.Description, e.Condition, e.Status,
+		e.Currency,
+		strconv.FormatFloat(e.ReservePrice, 'f', -1, 64),
+		reserveMet,
+		strconv.FormatFloat(e.BuyNowPrice, 'f', -1, 64),
+		e.WinningBidId, e.CancelReason, e.Timestamp, e.StartsAt, e.ExpiresAt,
+	}
+}
+
+func formatExportTime(value time.Time) string {
+	if value.IsZero() {
+		return ""
+	}
+
+	return value.UTC().Format(time.RFC3339)
+}
+
+// ExportAuctions escreve em w os leilões do filtro, em CSV com cabeçalho ou em
+// JSON com um leilão por linha. Os documentos são lidos do cursor e escritos
+// um a um, sem carregar a coleção na memória; por isso a duração não é
+// limitada pelo MONGO_OP_TIMEOUT, e sim pelo contexto recebido.
+func (ar *AuctionRepository) ExportAuctions(
+	ctx context.Context,
+	w io.Writer,
+	format string,
+	auctionFilter auction_entity.AuctionFilter) *internal_error.InternalError {
+	ctx, span := ar.startSpan(ctx, "ExportAuctions", attribute.String("export.format", format))
+	err := ar.exportAuctions(ctx, w, format, auctionFilter)
+	endSpan(span, err)
+
+	return err
+}
+
+func (ar *AuctionRepository) exportAuctions(
+	ctx context.Context,
+	w io.Writer,
+	format string,
+	auctionFilter auction_entity.AuctionFilter) *internal_error.InternalError {
+	var writeAuction func(auctionExport) error
+	var flush func() error
+
+	switch format {
+	case ExportFormatCSV:
+		csvWriter := csv.NewWriter(w)
+		if err := csvWriter.Write(auctionExportColumns); err != nil {
+			return internal_error.NewInternalServerError("Error trying to write auctions export")
+		}
+		writeAuction = func(record auctionExport) error {
+			return csvWriter.Write(record.csvRow())
+		}
+		flush = func() error {
+			csvWriter.Flush()
+			return csvWriter.Error()
+		}
+	case ExportFormatJSON:
+		encoder := json.NewEncoder(w)
+		writeAuction = func(record auctionExport) error {
+			return encoder.Encode(record)
+		}
+		flush = func() error { return nil }
+	default:
+		return internal_error.NewBadRequestError(
+			fmt.Sprintf("invalid export format %q, use csv or json", format))
+	}
+
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "timestamp", Value: 1}, {Key: "_id", Value: 1}})
+
+	cursor, err := ar.Collection.Find(ctx, auctionFilterQuery(auctionFilter), findOptions)
+	if err != nil {
+		logger.Error("Error trying to find auctions to export", err)
+		return database_error.NewInternalError(err, "Error trying to export auctions")
+	}
+
+	if err := ar.writeAuctions(ctx, cursor, writeAuction); err != nil {
+		return err
+	}
+
+	if err := flush(); err != nil {
+		logger.Error("Error trying to write auctions export", err)
+		return internal_error.NewInternalServerError("Error trying to write auctions export")
+	}
+
+	return nil
+}
+
+// writeAuctions percorre e fecha o cursor. Um cliente que desiste do download
+// cancela ctx, e a leitura para no documento seguinte.
+func (ar *AuctionRepository) writeAuctions(
+	ctx context.Context,
+	cursor documentCursor,
+	writeAuction func(auctionExport) error) *internal_error.InternalError {
+	defer closeCursor(ctx, cursor)
+
+	for nextDocument(ctx, cursor) {
+		var auctionEntityMongo AuctionEntityMongo
+		if err := cursor.Decode(&auctionEntityMongo); err != nil {
+			logger.Error("Error trying to decode auction to export", err)
+			return database_error.NewInternalError(err, "Error trying to export auctions")
+		}
+
+		if err := writeAuction(newAuctionExport(ar.toAuctionEntity(auctionEntityMongo))); err != nil {
+			logger.Error("Error trying to write auctions export", err)
+			return internal_error.NewInternalServerError("Error trying to write auctions export")
+		}
+	}
+
+	if err := cursorErr(ctx, cursor); err != nil {
+		logger.Error("Error trying to read auctions to export", err)
+		return database_error.NewInternalError(err, "Error trying to export auctions")
+	}
+
+	return nil
+}
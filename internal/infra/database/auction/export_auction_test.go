@@ -0,0 +1,115 @@
+package auction
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportAuctions(t *testing.T) {
+	t.Setenv("AUCTION_INTERVAL", "1h")
+	t.Setenv("AUCTION_CHECK_INTERVAL", "1h")
+
+	ctx := context.Background()
+	db := getSharedTestDatabase(ctx, t)
+
+	collectionName := fmt.Sprintf("auctions_test_%d", time.Now().UnixNano())
+	collection := db.Collection(collectionName)
+	collection.Drop(ctx)
+	defer collection.Drop(ctx)
+
+	repo := NewAuctionRepositoryWithCollection(ctx, db, collectionName)
+	defer repo.Close()
+
+	now := time.Now()
+	auctions := []*auction_entity.Auction{
+		{Id: "export-1", ProductName: "Notebook", Category: "Electronics", Condition: auction_entity.New, Status: auction_entity.Active, ReservePrice: 1500},
+		{Id: "export-2", ProductName: "Chair, \"vintage\"", Category: "Furniture", Condition: auction_entity.Used, Status: auction_entity.Active, Currency: "USD"},
+		{Id: "export-3", ProductName: "Phone", Category: "Electronics", Condition: auction_entity.Refurbished, Status: auction_entity.Cancelled},
+	}
+	for i, auctionEntity := range auctions {
+		auctionEntity.Description = "Auction used by the export test"
+		auctionEntity.Timestamp = now.Add(time.Duration(i) * time.Second)
+		if _, err := repo.CreateAuction(ctx, auctionEntity); err != nil {
+			t.Fatalf("Failed to create auction: %v", err)
+		}
+	}
+
+	t.Run("csv", func(t *testing.T) {
+		var output bytes.Buffer
+		require.Nil(t, repo.ExportAuctions(ctx, &output, ExportFormatCSV, auction_entity.AuctionFilter{}))
+
+		rows, err := csv.NewReader(&output).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, rows, len(auctions)+1)
+		require.Equal(t, auctionExportColumns, rows[0])
+
+		column := func(row []string, name string) string {
+			for i, columnName := range auctionExportColumns {
+				if columnName == name {
+					return row[i]
+				}
+			}
+			t.Fatalf("unknown column %s", name)
+			return ""
+		}
+
+		require.Equal(t, "export-1", column(rows[1], "id"))
+		require.Equal(t, "New", column(rows[1], "condition"))
+		require.Equal(t, "Active", column(rows[1], "status"))
+		require.Equal(t, "BRL", column(rows[1], "currency"))
+		require.Equal(t, "1500", column(rows[1], "reserve_price"))
+		require.Empty(t, column(rows[1], "reserve_met"))
+
+		require.Equal(t, "Chair, \"vintage\"", column(rows[2], "product_name"))
+		require.Equal(t, "Used", column(rows[2], "condition"))
+		require.Equal(t, "USD", column(rows[2], "currency"))
+
+		require.Equal(t, "Refurbished", column(rows[3], "condition"))
+		require.Equal(t, "Cancelled", column(rows[3], "status"))
+
+		timestamp, err := time.Parse(time.RFC3339, column(rows[1], "timestamp"))
+		require.NoError(t, err)
+		require.Equal(t, now.Unix(), timestamp.Unix())
+	})
+
+	t.Run("json filtered by status", func(t *testing.T) {
+		activeStatus := auction_entity.Active
+
+		var output bytes.Buffer
+		require.Nil(t, repo.ExportAuctions(ctx, &output, ExportFormatJSON,
+			auction_entity.AuctionFilter{Status: &activeStatus, Category: "Electronics"}))
+
+		var exported []map[string]interface{}
+		scanner := bufio.NewScanner(&output)
+		for scanner.Scan() {
+			var record map[string]interface{}
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+			exported = append(exported, record)
+		}
+		require.NoError(t, scanner.Err())
+
+		require.Len(t, exported, 1)
+		require.Equal(t, "export-1", exported[0]["id"])
+		require.Equal(t, "New", exported[0]["condition"])
+		require.Equal(t, "Active", exported[0]["status"])
+		require.Equal(t, 1500.0, exported[0]["reserve_price"])
+		require.NotContains(t, exported[0], "webhook_url")
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		var output bytes.Buffer
+		err := repo.ExportAuctions(ctx, &output, "xml", auction_entity.AuctionFilter{})
+		require.NotNil(t, err)
+		require.Equal(t, "bad_request", err.Err)
+		require.Zero(t, output.Len())
+	})
+}
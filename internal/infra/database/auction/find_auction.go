@@ -5,42 +5,77 @@ import (
 	"fmt"
 	"fullcycle-auction_go/configuration/logger"
 	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/entity/bid_entity"
+	"fullcycle-auction_go/internal/infra/database/database_error"
+	"fullcycle-auction_go/internal/infra/database/decimal"
+	"fullcycle-auction_go/internal/infra/database/sanitize"
 	"fullcycle-auction_go/internal/internal_error"
 	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel/attribute"
+	"sort"
 	"time"
 )
 
 func (ar *AuctionRepository) FindAuctionById(
 	ctx context.Context, id string) (*auction_entity.Auction, *internal_error.InternalError) {
-	filter := bson.M{"_id": id}
+	ctx, span := ar.startSpan(ctx, "FindAuctionById", attribute.String("auction.id", id))
+	auction, err := ar.findAuctionById(ctx, id)
+	endSpan(span, err)
+
+	return auction, err
+}
+
+func (ar *AuctionRepository) findAuctionById(
+	ctx context.Context, id string) (*auction_entity.Auction, *internal_error.InternalError) {
+	filter := bson.M{"_id": id, "deleted_at": nil}
+
+	ctx, cancel := ar.withOpTimeout(ctx)
+	defer cancel()
 
 	var auctionEntityMongo AuctionEntityMongo
 	if err := ar.Collection.FindOne(ctx, filter).Decode(&auctionEntityMongo); err != nil {
+		if database_error.IsNotFound(err) {
+			logger.Error(fmt.Sprintf("Auction not found with this id = %s", id), err)
+			return nil, internal_error.NewNotFoundError(
+				fmt.Sprintf("Auction not found with this id = %s", id)).
+				WithCode(internal_error.CodeAuctionNotFound)
+		}
+
 		logger.Error(fmt.Sprintf("Error trying to find auction by id = %s", id), err)
-		return nil, internal_error.NewInternalServerError("Error trying to find auction by id")
+		return nil, database_error.NewInternalError(err, "Error trying to find auction by id")
 	}
 
-	return &auction_entity.Auction{
-		Id:          auctionEntityMongo.Id,
-		ProductName: auctionEntityMongo.ProductName,
-		Category:    auctionEntityMongo.Category,
-		Description: auctionEntityMongo.Description,
-		Condition:   auctionEntityMongo.Condition,
-		Status:      auctionEntityMongo.Status,
-		Timestamp:   time.Unix(auctionEntityMongo.Timestamp, 0),
-	}, nil
+	return ar.toAuctionEntity(auctionEntityMongo), nil
 }
 
+// FindAuctions lista os leilões; status nil não filtra por status, já que o
+// valor zero do enum é Active.
 func (repo *AuctionRepository) FindAuctions(
 	ctx context.Context,
-	status auction_entity.AuctionStatus,
+	status *auction_entity.AuctionStatus,
 	category string,
-	productName string) ([]auction_entity.Auction, *internal_error.InternalError) {
-	filter := bson.M{}
+	productName string,
+	page, limit int64) ([]auction_entity.Auction, *internal_error.InternalError) {
+	ctx, span := repo.startSpan(ctx, "FindAuctions")
+	auctions, err := repo.findAuctions(ctx, status, category, productName, page, limit)
+	span.SetAttributes(attribute.Int("auctions.count", len(auctions)))
+	endSpan(span, err)
 
-	if status != 0 {
-		filter["status"] = status
+	return auctions, err
+}
+
+func (repo *AuctionRepository) findAuctions(
+	ctx context.Context,
+	status *auction_entity.AuctionStatus,
+	category string,
+	productName string,
+	page, limit int64) ([]auction_entity.Auction, *internal_error.InternalError) {
+	filter := bson.M{"deleted_at": nil}
+
+	if status != nil {
+		filter["status"] = *status
 	}
 
 	if category != "" {
@@ -48,34 +83,595 @@ func (repo *AuctionRepository) FindAuctions(
 	}
 
 	if productName != "" {
-		filter["productName"] = primitive.Regex{Pattern: productName, Options: "i"}
+		filter["product_name"] = sanitize.ContainsRegex(productName)
+	}
+
+	findOptions := options.Find().SetSort(bson.D{{Key: "timestamp", Value: 1}, {Key: "_id", Value: 1}})
+	if limit > 0 {
+		if page < 1 {
+			page = 1
+		}
+		findOptions.SetSkip((page - 1) * limit).SetLimit(limit)
 	}
 
-	cursor, err := repo.Collection.Find(ctx, filter)
+	ctx, cancel := repo.withOpTimeout(ctx)
+	defer cancel()
+
+	cursor, err := repo.Collection.Find(ctx, filter, findOptions)
 	if err != nil {
 		logger.Error("Error finding auctions", err)
+		if database_error.IsTimeout(err) {
+			return nil, internal_error.NewTimeoutError("Timeout finding auctions")
+		}
 		return nil, internal_error.NewInternalServerError("Error finding auctions")
 	}
-	defer cursor.Close(ctx)
+	defer closeCursor(ctx, cursor)
 
 	var auctionsMongo []AuctionEntityMongo
 	if err := cursor.All(ctx, &auctionsMongo); err != nil {
 		logger.Error("Error decoding auctions", err)
+		if database_error.IsTimeout(err) {
+			return nil, internal_error.NewTimeoutError("Timeout finding auctions")
+		}
 		return nil, internal_error.NewInternalServerError("Error decoding auctions")
 	}
 
-	var auctionsEntity []auction_entity.Auction
+	auctionsEntity := make([]auction_entity.Auction, 0, len(auctionsMongo))
 	for _, auction := range auctionsMongo {
-		auctionsEntity = append(auctionsEntity, auction_entity.Auction{
-			Id:          auction.Id,
-			ProductName: auction.ProductName,
-			Category:    auction.Category,
-			Status:      auction.Status,
-			Description: auction.Description,
-			Condition:   auction.Condition,
-			Timestamp:   time.Unix(auction.Timestamp, 0),
-		})
+		auctionsEntity = append(auctionsEntity, *repo.toAuctionEntity(auction))
 	}
 
 	return auctionsEntity, nil
 }
+
+const (
+	defaultAuctionPageSize = 20
+	maxAuctionPageSize     = 100
+)
+
+// FindAuctionsAfter pagina por (timestamp, _id) a partir do cursor, o que
+// mantém as páginas estáveis mesmo com leilões criados durante a navegação.
+// Um cursor com Id vazio busca a primeira página. O cursor retornado é nil
+// quando não há mais páginas.
+func (ar *AuctionRepository) FindAuctionsAfter(
+	ctx context.Context,
+	auctionFilter auction_entity.AuctionFilter,
+	afterTimestamp int64,
+	afterId string,
+	limit int64) ([]auction_entity.Auction, *auction_entity.AuctionCursor, *internal_error.InternalError) {
+	ctx, span := ar.startSpan(ctx, "FindAuctionsAfter")
+	auctions, cursor, err := ar.findAuctionsAfter(ctx, auctionFilter, afterTimestamp, afterId, limit)
+	span.SetAttributes(attribute.Int("auctions.count", len(auctions)))
+	endSpan(span, err)
+
+	return auctions, cursor, err
+}
+
+func (ar *AuctionRepository) findAuctionsAfter(
+	ctx context.Context,
+	auctionFilter auction_entity.AuctionFilter,
+	afterTimestamp int64,
+	afterId string,
+	limit int64) ([]auction_entity.Auction, *auction_entity.AuctionCursor, *internal_error.InternalError) {
+	if limit <= 0 {
+		limit = defaultAuctionPageSize
+	} else if limit > maxAuctionPageSize {
+		limit = maxAuctionPageSize
+	}
+
+	filter := auctionFilterQuery(auctionFilter)
+
+	if afterId != "" {
+		filter["$or"] = bson.A{
+			bson.M{"timestamp": bson.M{"$gt": afterTimestamp}},
+			bson.M{"timestamp": afterTimestamp, "_id": bson.M{"$gt": afterId}},
+		}
+	}
+
+	// Um item a mais indica se existe próxima página
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "timestamp", Value: 1}, {Key: "_id", Value: 1}}).
+		SetLimit(limit + 1)
+
+	ctx, cancel := ar.withOpTimeout(ctx)
+	defer cancel()
+
+	cursor, err := ar.Collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		logger.Error("Error finding auctions page", err)
+		return nil, nil, database_error.NewInternalError(err, "Error finding auctions")
+	}
+	defer closeCursor(ctx, cursor)
+
+	var auctionsMongo []AuctionEntityMongo
+	if err := cursor.All(ctx, &auctionsMongo); err != nil {
+		logger.Error("Error decoding auctions page", err)
+		return nil, nil, database_error.NewInternalError(err, "Error decoding auctions")
+	}
+
+	var nextCursor *auction_entity.AuctionCursor
+	if int64(len(auctionsMongo)) > limit {
+		auctionsMongo = auctionsMongo[:limit]
+		last := auctionsMongo[len(auctionsMongo)-1]
+		nextCursor = &auction_entity.AuctionCursor{Timestamp: last.Timestamp, Id: last.Id}
+	}
+
+	auctionsEntity := make([]auction_entity.Auction, 0, len(auctionsMongo))
+	for _, auction := range auctionsMongo {
+		auctionsEntity = append(auctionsEntity, *ar.toAuctionEntity(auction))
+	}
+
+	return auctionsEntity, nextCursor, nil
+}
+
+// FindAuctionsPaged busca uma página numerada junto com os dados para o
+// cliente montar a paginação. O Total vem de um CountDocuments com o mesmo
+// filtro, que pode ser dispensado com withTotal = false em coleções grandes.
+func (ar *AuctionRepository) FindAuctionsPaged(
+	ctx context.Context,
+	auctionFilter auction_entity.AuctionFilter,
+	page, limit int64,
+	withTotal bool) (*auction_entity.AuctionPage, *internal_error.InternalError) {
+	ctx, span := ar.startSpan(ctx, "FindAuctionsPaged", attribute.Bool("auctions.with_total", withTotal))
+	auctionPage, err := ar.findAuctionsPaged(ctx, auctionFilter, page, limit, withTotal)
+	if auctionPage != nil {
+		span.SetAttributes(attribute.Int("auctions.count", len(auctionPage.Items)))
+	}
+	endSpan(span, err)
+
+	return auctionPage, err
+}
+
+func (ar *AuctionRepository) findAuctionsPaged(
+	ctx context.Context,
+	auctionFilter auction_entity.AuctionFilter,
+	page, limit int64,
+	withTotal bool) (*auction_entity.AuctionPage, *internal_error.InternalError) {
+	if limit <= 0 {
+		limit = defaultAuctionPageSize
+	} else if limit > maxAuctionPageSize {
+		limit = maxAuctionPageSize
+	}
+	if page < 1 {
+		page = 1
+	}
+
+	filter := auctionFilterQuery(auctionFilter)
+
+	// Um item a mais indica se existe próxima página, com ou sem o Total
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "timestamp", Value: 1}, {Key: "_id", Value: 1}}).
+		SetSkip((page - 1) * limit).
+		SetLimit(limit + 1)
+
+	ctx, cancel := ar.withOpTimeout(ctx)
+	defer cancel()
+
+	cursor, err := ar.Collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		logger.Error("Error finding auctions page", err)
+		return nil, database_error.NewInternalError(err, "Error finding auctions")
+	}
+	defer closeCursor(ctx, cursor)
+
+	var auctionsMongo []AuctionEntityMongo
+	if err := cursor.All(ctx, &auctionsMongo); err != nil {
+		logger.Error("Error decoding auctions page", err)
+		return nil, database_error.NewInternalError(err, "Error decoding auctions")
+	}
+
+	auctionPage := &auction_entity.AuctionPage{
+		Items: make([]auction_entity.Auction, 0, len(auctionsMongo)),
+		Page:  page,
+		Limit: limit,
+	}
+
+	if int64(len(auctionsMongo)) > limit {
+		auctionsMongo = auctionsMongo[:limit]
+		auctionPage.HasNext = true
+	}
+
+	for _, auction := range auctionsMongo {
+		auctionPage.Items = append(auctionPage.Items, *ar.toAuctionEntity(auction))
+	}
+
+	if withTotal {
+		total, err := ar.Collection.CountDocuments(ctx, filter)
+		if err != nil {
+			logger.Error("Error counting auctions", err)
+			return nil, database_error.NewInternalError(err, "Error counting auctions")
+		}
+		auctionPage.Total = &total
+	}
+
+	return auctionPage, nil
+}
+
+func auctionFilterQuery(auctionFilter auction_entity.AuctionFilter) bson.M {
+	filter := bson.M{"deleted_at": nil}
+
+	if auctionFilter.Status != nil {
+		filter["status"] = *auctionFilter.Status
+	}
+
+	if auctionFilter.Category != "" {
+		filter["category"] = auctionFilter.Category
+	}
+
+	if auctionFilter.ProductName != "" {
+		filter["product_name"] = sanitize.ContainsRegex(auctionFilter.ProductName)
+	}
+
+	return filter
+}
+
+func (ar *AuctionRepository) CountAuctionsByStatus(
+	ctx context.Context) (map[auction_entity.AuctionStatus]int64, *internal_error.InternalError) {
+	ctx, span := ar.startSpan(ctx, "CountAuctionsByStatus")
+	counts, err := ar.countAuctionsByStatus(ctx)
+	endSpan(span, err)
+
+	return counts, err
+}
+
+func (ar *AuctionRepository) countAuctionsByStatus(
+	ctx context.Context) (map[auction_entity.AuctionStatus]int64, *internal_error.InternalError) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"deleted_at": nil}}},
+		{{Key: "$group", Value: bson.M{"_id": "$status", "count": bson.M{"$sum": 1}}}},
+	}
+
+	ctx, cancel := ar.withOpTimeout(ctx)
+	defer cancel()
+
+	cursor, err := ar.Collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		logger.Error("Error trying to count auctions by status", err)
+		if database_error.IsTimeout(err) {
+			return nil, internal_error.NewTimeoutError("Timeout trying to count auctions by status")
+		}
+		return nil, internal_error.NewInternalServerError("Error trying to count auctions by status")
+	}
+	defer closeCursor(ctx, cursor)
+
+	var results []struct {
+		Status auction_entity.AuctionStatus `bson:"_id"`
+		Count  int64                        `bson:"count"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		logger.Error("Error trying to decode auction counts", err)
+		return nil, internal_error.NewInternalServerError("Error trying to count auctions by status")
+	}
+
+	// Status sem nenhum leilão aparecem com zero
+	counts := map[auction_entity.AuctionStatus]int64{
+		auction_entity.Active:    0,
+		auction_entity.Completed: 0,
+		auction_entity.Cancelled: 0,
+		auction_entity.Scheduled: 0,
+	}
+	for _, result := range results {
+		counts[result.Status] = result.Count
+	}
+
+	return counts, nil
+}
+
+// FindStaleActiveAuctions lista os leilões que o closer já deveria ter
+// fechado. Em operação normal a lista fica vazia ou só tem leilões que
+// expiraram desde o último tick; itens antigos indicam que o closer parou ou
+// está mal configurado.
+func (ar *AuctionRepository) FindStaleActiveAuctions(
+	ctx context.Context) ([]auction_entity.Auction, *internal_error.InternalError) {
+	ctx, span := ar.startSpan(ctx, "FindStaleActiveAuctions")
+	auctions, err := ar.findStaleActiveAuctions(ctx)
+	span.SetAttributes(attribute.Int("auctions.count", len(auctions)))
+	endSpan(span, err)
+
+	return auctions, err
+}
+
+func (ar *AuctionRepository) findStaleActiveAuctions(
+	ctx context.Context) ([]auction_entity.Auction, *internal_error.InternalError) {
+	filter := ar.expiredAuctionsFilter(ar.now())
+	findOptions := options.Find().SetSort(bson.D{{Key: "timestamp", Value: 1}, {Key: "_id", Value: 1}})
+
+	ctx, cancel := ar.withOpTimeout(ctx)
+	defer cancel()
+
+	cursor, err := ar.Collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		logger.Error("Error trying to find stale active auctions", err)
+		return nil, database_error.NewInternalError(err, "Error trying to find stale active auctions")
+	}
+	defer closeCursor(ctx, cursor)
+
+	var auctionsMongo []AuctionEntityMongo
+	if err := cursor.All(ctx, &auctionsMongo); err != nil {
+		logger.Error("Error trying to decode stale active auctions", err)
+		return nil, database_error.NewInternalError(err, "Error trying to find stale active auctions")
+	}
+
+	auctionsEntity := make([]auction_entity.Auction, 0, len(auctionsMongo))
+	for _, auction := range auctionsMongo {
+		auctionsEntity = append(auctionsEntity, *ar.toAuctionEntity(auction))
+	}
+
+	return auctionsEntity, nil
+}
+
+// FindAuctionsClosingWithin retorna os leilões ativos que fecham entre agora
+// e agora+window, do que fecha primeiro para o último, para alimentar os
+// avisos de "termina em breve".
+func (ar *AuctionRepository) FindAuctionsClosingWithin(
+	ctx context.Context, window time.Duration) ([]auction_entity.Auction, *internal_error.InternalError) {
+	ctx, span := ar.startSpan(ctx, "FindAuctionsClosingWithin")
+	auctions, err := ar.findAuctionsClosingWithin(ctx, window)
+	span.SetAttributes(attribute.Int("auctions.count", len(auctions)))
+	endSpan(span, err)
+
+	return auctions, err
+}
+
+func (ar *AuctionRepository) findAuctionsClosingWithin(
+	ctx context.Context, window time.Duration) ([]auction_entity.Auction, *internal_error.InternalError) {
+	if window <= 0 {
+		return nil, internal_error.NewBadRequestError("window must be positive")
+	}
+
+	now := ar.now()
+	interval := ar.interval()
+	// Cada ramo do $or usa um índice: status+expires_at para os leilões com
+	// expires_at e status+timestamp para os antigos, que expiram em
+	// timestamp + AUCTION_INTERVAL
+	filter := bson.M{
+		"status":     auction_entity.Active,
+		"deleted_at": nil,
+		"closed_at":  nil,
+		"$or": bson.A{
+			bson.M{"expires_at": bson.M{
+				"$gte": now.Unix(),
+				"$lte": now.Add(window).Unix(),
+			}},
+			bson.M{
+				"expires_at": bson.M{"$exists": false},
+				"timestamp": bson.M{
+					"$gte": now.Add(-interval).Unix(),
+					"$lte": now.Add(window - interval).Unix(),
+				},
+			},
+		},
+	}
+
+	ctx, cancel := ar.withOpTimeout(ctx)
+	defer cancel()
+
+	cursor, err := ar.Collection.Find(ctx, filter)
+	if err != nil {
+		logger.Error("Error trying to find auctions closing soon", err)
+		return nil, database_error.NewInternalError(err, "Error trying to find auctions closing soon")
+	}
+	defer closeCursor(ctx, cursor)
+
+	var auctionsMongo []AuctionEntityMongo
+	if err := cursor.All(ctx, &auctionsMongo); err != nil {
+		logger.Error("Error trying to decode auctions closing soon", err)
+		return nil, database_error.NewInternalError(err, "Error trying to find auctions closing soon")
+	}
+
+	auctionsEntity := make([]auction_entity.Auction, 0, len(auctionsMongo))
+	for _, auction := range auctionsMongo {
+		auctionsEntity = append(auctionsEntity, *ar.toAuctionEntity(auction))
+	}
+
+	// Os leilões antigos não têm expires_at gravado, então a ordem sai da
+	// expiração efetiva
+	sort.SliceStable(auctionsEntity, func(i, j int) bool {
+		return auctionsEntity[i].ExpiresAt.Before(auctionsEntity[j].ExpiresAt)
+	})
+
+	return auctionsEntity, nil
+}
+
+// FindOpenAuctionIds retorna os ids dos leilões ativos na moeda informada
+// cujos lances são públicos, ou seja, fora os Sealed. Leilões sem moeda
+// gravada são da DefaultCurrency. O Distinct roda no Mongo, sem carregar os
+// leilões.
+func (ar *AuctionRepository) FindOpenAuctionIds(
+	ctx context.Context, currency string) ([]string, *internal_error.InternalError) {
+	ctx, span := ar.startSpan(ctx, "FindOpenAuctionIds")
+	ids, err := ar.findOpenAuctionIds(ctx, currency)
+	span.SetAttributes(attribute.Int("auctions.count", len(ids)))
+	endSpan(span, err)
+
+	return ids, err
+}
+
+func (ar *AuctionRepository) findOpenAuctionIds(
+	ctx context.Context, currency string) ([]string, *internal_error.InternalError) {
+	currencyFilter := interface{}(currency)
+	if currency == bid_entity.DefaultCurrency {
+		currencyFilter = bson.M{"$in": bson.A{currency, nil}}
+	}
+
+	ctx, cancel := ar.withOpTimeout(ctx)
+	defer cancel()
+
+	values, err := ar.Collection.Distinct(ctx, "_id", bson.M{
+		"status":     auction_entity.Active,
+		"deleted_at": nil,
+		"closed_at":  nil,
+		"currency":   currencyFilter,
+		"type":       bson.M{"$ne": auction_entity.Sealed},
+	})
+	if err != nil {
+		logger.Error("Error trying to find active auctions", err)
+		return nil, database_error.NewInternalError(err, "Error trying to find active auctions")
+	}
+
+	ids := make([]string, 0, len(values))
+	for _, value := range values {
+		if id, ok := value.(string); ok {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids, nil
+}
+
+// FindAuctionsByOwner retorna todos os leilões de um vendedor agrupados por
+// status, do mais recente para o mais antigo dentro de cada grupo. Todos os
+// status aparecem no mapa, mesmo sem leilões.
+func (ar *AuctionRepository) FindAuctionsByOwner(
+	ctx context.Context,
+	ownerId string) (map[auction_entity.AuctionStatus][]auction_entity.Auction, *internal_error.InternalError) {
+	ctx, span := ar.startSpan(ctx, "FindAuctionsByOwner", attribute.String("owner.id", ownerId))
+	auctionsByStatus, err := ar.findAuctionsByOwner(ctx, ownerId)
+	endSpan(span, err)
+
+	return auctionsByStatus, err
+}
+
+func (ar *AuctionRepository) findAuctionsByOwner(
+	ctx context.Context,
+	ownerId string) (map[auction_entity.AuctionStatus][]auction_entity.Auction, *internal_error.InternalError) {
+	if ownerId == "" {
+		return nil, internal_error.NewBadRequestError("owner id is required")
+	}
+
+	filter := bson.M{"user_id": ownerId, "deleted_at": nil}
+	// O _id desempata leilões criados no mesmo segundo
+	findOptions := options.Find().SetSort(bson.D{{Key: "timestamp", Value: -1}, {Key: "_id", Value: -1}})
+
+	ctx, cancel := ar.withOpTimeout(ctx)
+	defer cancel()
+
+	cursor, err := ar.Collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Error trying to find auctions of owner = %s", ownerId), err)
+		return nil, database_error.NewInternalError(err, "Error trying to find auctions by owner")
+	}
+	defer closeCursor(ctx, cursor)
+
+	var auctionsMongo []AuctionEntityMongo
+	if err := cursor.All(ctx, &auctionsMongo); err != nil {
+		logger.Error(fmt.Sprintf("Error trying to decode auctions of owner = %s", ownerId), err)
+		return nil, database_error.NewInternalError(err, "Error trying to find auctions by owner")
+	}
+
+	auctionsByStatus := map[auction_entity.AuctionStatus][]auction_entity.Auction{
+		auction_entity.Active:    {},
+		auction_entity.Completed: {},
+		auction_entity.Cancelled: {},
+		auction_entity.Scheduled: {},
+	}
+	// A ordem do Find se mantém dentro de cada grupo
+	for _, auction := range auctionsMongo {
+		auctionsByStatus[auction.Status] = append(auctionsByStatus[auction.Status], *ar.toAuctionEntity(auction))
+	}
+
+	return auctionsByStatus, nil
+}
+
+// auctionWithBidsMongo é o resultado do $lookup entre leilões e lances
+type auctionWithBidsMongo struct {
+	AuctionEntityMongo `bson:",inline"`
+	Bids               []auctionBidMongo `bson:"bids"`
+}
+
+type auctionBidMongo struct {
+	Id        string         `bson:"_id"`
+	UserId    string         `bson:"user_id"`
+	AuctionId string         `bson:"auction_id"`
+	Amount    decimal.Amount `bson:"amount"`
+	Timestamp int64          `bson:"timestamp"`
+	Currency  string         `bson:"currency,omitempty"`
+}
+
+// FindAuctionWithBids busca o leilão e seus lances em uma única agregação,
+// com os lances ordenados por (timestamp, _id) como na listagem de lances. A
+// coleção dos lances vem do BidStore. Como na listagem, leilões Sealed voltam
+// sem lances até o fechamento.
+func (ar *AuctionRepository) FindAuctionWithBids(
+	ctx context.Context, id string) (*auction_entity.AuctionWithBids, *internal_error.InternalError) {
+	ctx, span := ar.startSpan(ctx, "FindAuctionWithBids", attribute.String("auction.id", id))
+	auctionWithBids, err := ar.findAuctionWithBids(ctx, id)
+	if auctionWithBids != nil {
+		span.SetAttributes(attribute.Int("bids.count", len(auctionWithBids.Bids)))
+	}
+	endSpan(span, err)
+
+	return auctionWithBids, err
+}
+
+func (ar *AuctionRepository) findAuctionWithBids(
+	ctx context.Context, id string) (*auction_entity.AuctionWithBids, *internal_error.InternalError) {
+	bidStore, internalErr := ar.bidStore()
+	if internalErr != nil {
+		logger.Error(fmt.Sprintf("Error trying to find auction with bids = %s", id), internalErr)
+		return nil, internalErr
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"_id": id, "deleted_at": nil}}},
+		{{Key: "$lookup", Value: bson.M{
+			"from": bidStore.CollectionName(),
+			"let":  bson.M{"auctionId": "$_id"},
+			"pipeline": bson.A{
+				bson.M{"$match": bson.M{"$expr": bson.M{"$eq": bson.A{"$auction_id", "$$auctionId"}}}},
+				bson.M{"$sort": bson.D{{Key: "timestamp", Value: 1}, {Key: "_id", Value: 1}}},
+			},
+			"as": "bids",
+		}}},
+	}
+
+	ctx, cancel := ar.withOpTimeout(ctx)
+	defer cancel()
+
+	cursor, err := ar.Collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Error trying to find auction with bids = %s", id), err)
+		return nil, database_error.NewInternalError(err, "Error trying to find auction with bids")
+	}
+	defer closeCursor(ctx, cursor)
+
+	var results []auctionWithBidsMongo
+	if err := cursor.All(ctx, &results); err != nil {
+		logger.Error(fmt.Sprintf("Error trying to decode auction with bids = %s", id), err)
+		return nil, database_error.NewInternalError(err, "Error trying to find auction with bids")
+	}
+
+	if len(results) == 0 {
+		return nil, internal_error.NewNotFoundError(
+			fmt.Sprintf("Auction not found with this id = %s", id)).
+			WithCode(internal_error.CodeAuctionNotFound)
+	}
+
+	auctionEntity := ar.toAuctionEntity(results[0].AuctionEntityMongo)
+	if auctionEntity.BidsHidden() {
+		return &auction_entity.AuctionWithBids{Auction: *auctionEntity, Bids: []bid_entity.Bid{}}, nil
+	}
+
+	bids := make([]bid_entity.Bid, 0, len(results[0].Bids))
+	for _, bid := range results[0].Bids {
+		bids = append(bids, bid_entity.Bid{
+			Id:        bid.Id,
+			UserId:    bid.UserId,
+			AuctionId: bid.AuctionId,
+			Amount:    bid_entity.Amount(bid.Amount),
+			Timestamp: timeFromUnix(bid.Timestamp),
+			Currency:  bid_entity.CurrencyOrDefault(bid.Currency),
+		})
+	}
+
+	return &auction_entity.AuctionWithBids{
+		Auction: *auctionEntity,
+		Bids:    bids,
+	}, nil
+}
+
+// timeFromUnix reconstrói os segundos unix gravados no Mongo sempre em UTC,
+// independente do fuso do processo que leu o documento.
+func timeFromUnix(timestamp int64) time.Time {
+	return time.Unix(timestamp, 0).UTC()
+}
@@ -0,0 +1,805 @@
+package auction
+
+import (
+	"context"
+	"fmt"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/entity/bid_entity"
+	"fullcycle-auction_go/internal/infra/database/decimal"
+	"fullcycle-auction_go/internal/internal_error"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindAuctionById(t *testing.T) {
+	ctx := context.Background()
+
+	client, db, container := getTestDatabase(ctx, t)
+	defer func() {
+		if err := container.Terminate(ctx); err != nil {
+			log.Printf("failed to terminate container: %s", err)
+		}
+	}()
+	defer func() {
+		if err := client.Disconnect(ctx); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	collectionName := fmt.Sprintf("auctions_test_%d", time.Now().UnixNano())
+	collection := db.Collection(collectionName)
+	collection.Drop(ctx)
+	defer collection.Drop(ctx)
+
+	repo := NewAuctionRepositoryWithCollection(ctx, db, collectionName)
+	defer repo.Close()
+
+	auctionEntity := &auction_entity.Auction{
+		Id:          "test-auction-find",
+		ProductName: "Find Product",
+		Category:    "Test Category",
+		Description: "Auction used to test find by id",
+		Condition:   auction_entity.New,
+		Status:      auction_entity.Active,
+		Timestamp:   time.Now(),
+		ExpiresAt:   time.Now().Add(time.Hour),
+	}
+
+	createdAuction, createErr := repo.CreateAuction(ctx, auctionEntity)
+	if createErr != nil {
+		t.Fatalf("Failed to create auction: %v", createErr)
+	}
+
+	t.Run("found", func(t *testing.T) {
+		result, err := repo.FindAuctionById(ctx, "test-auction-find")
+		require.Nil(t, err)
+		require.Equal(t, createdAuction, result)
+		require.Equal(t, auctionEntity.Id, result.Id)
+		require.Equal(t, auctionEntity.ProductName, result.ProductName)
+		require.Equal(t, auctionEntity.Category, result.Category)
+		require.Equal(t, auctionEntity.Description, result.Description)
+		require.Equal(t, auctionEntity.Condition, result.Condition)
+		require.Equal(t, auction_entity.Active, result.Status)
+		require.Equal(t, auctionEntity.Timestamp.Unix(), result.Timestamp.Unix())
+		require.Equal(t, auctionEntity.ExpiresAt.Unix(), result.ExpiresAt.Unix())
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		result, err := repo.FindAuctionById(ctx, "test-auction-missing")
+		require.Nil(t, result)
+		require.NotNil(t, err)
+		require.Equal(t, "not_found", err.Err)
+		require.Equal(t, internal_error.CodeAuctionNotFound, err.Code)
+	})
+}
+
+func TestFindAuctions(t *testing.T) {
+	ctx := context.Background()
+
+	client, db, container := getTestDatabase(ctx, t)
+	defer func() {
+		if err := container.Terminate(ctx); err != nil {
+			log.Printf("failed to terminate container: %s", err)
+		}
+	}()
+	defer func() {
+		if err := client.Disconnect(ctx); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	collectionName := fmt.Sprintf("auctions_test_%d", time.Now().UnixNano())
+	collection := db.Collection(collectionName)
+	collection.Drop(ctx)
+	defer collection.Drop(ctx)
+
+	repo := NewAuctionRepositoryWithCollection(ctx, db, collectionName)
+	defer repo.Close()
+
+	now := time.Now()
+	auctions := []*auction_entity.Auction{
+		{Id: "auction-1", ProductName: "iPhone 15", Category: "Electronics", Status: auction_entity.Active},
+		{Id: "auction-2", ProductName: "IPHONE 14", Category: "Electronics", Status: auction_entity.Completed},
+		{Id: "auction-3", ProductName: "Galaxy S24", Category: "Electronics", Status: auction_entity.Active},
+		{Id: "auction-4", ProductName: "Office Chair", Category: "Furniture", Status: auction_entity.Completed},
+		{Id: "auction-5", ProductName: "Desk", Category: "Furniture", Status: auction_entity.Active},
+	}
+	for i, auctionEntity := range auctions {
+		auctionEntity.Description = "Auction used to test find auctions"
+		auctionEntity.Condition = auction_entity.New
+		auctionEntity.Timestamp = now.Add(time.Duration(i) * time.Second)
+		auctionEntity.ExpiresAt = now.Add(time.Hour)
+
+		if _, err := repo.CreateAuction(ctx, auctionEntity); err != nil {
+			t.Fatalf("Failed to create auction: %v", err)
+		}
+	}
+
+	ids := func(result []auction_entity.Auction) []string {
+		var auctionIds []string
+		for _, auctionEntity := range result {
+			auctionIds = append(auctionIds, auctionEntity.Id)
+		}
+		return auctionIds
+	}
+
+	t.Run("filter by status", func(t *testing.T) {
+		result, err := repo.FindAuctions(ctx, statusRef(auction_entity.Completed), "", "", 0, 0)
+		require.Nil(t, err)
+		require.Equal(t, []string{"auction-2", "auction-4"}, ids(result))
+	})
+
+	// Active é o valor zero do enum e ainda assim filtra
+	t.Run("filter by active status", func(t *testing.T) {
+		result, err := repo.FindAuctions(ctx, statusRef(auction_entity.Active), "", "", 0, 0)
+		require.Nil(t, err)
+		require.Equal(t, []string{"auction-1", "auction-3", "auction-5"}, ids(result))
+	})
+
+	t.Run("without status filter", func(t *testing.T) {
+		result, err := repo.FindAuctions(ctx, nil, "", "", 0, 0)
+		require.Nil(t, err)
+		require.Len(t, result, len(auctions))
+	})
+
+	t.Run("filter by category", func(t *testing.T) {
+		result, err := repo.FindAuctions(ctx, nil, "Furniture", "", 0, 0)
+		require.Nil(t, err)
+		require.Equal(t, []string{"auction-4", "auction-5"}, ids(result))
+	})
+
+	t.Run("filter by product name ignoring case", func(t *testing.T) {
+		result, err := repo.FindAuctions(ctx, nil, "", "iphone", 0, 0)
+		require.Nil(t, err)
+		require.Equal(t, []string{"auction-1", "auction-2"}, ids(result))
+	})
+
+	t.Run("malicious filters are matched literally", func(t *testing.T) {
+		for _, productName := range []string{".*", "(a+)+$", "iPhone|Desk", `{$gt:""}`} {
+			result, err := repo.FindAuctions(ctx, nil, "", productName, 0, 0)
+			require.Nil(t, err, productName)
+			require.Empty(t, result, productName)
+		}
+
+		result, err := repo.FindAuctions(ctx, nil, `{$gt:""}`, "", 0, 0)
+		require.Nil(t, err)
+		require.Empty(t, result)
+
+		pageResult, _, err := repo.FindAuctionsAfter(ctx,
+			auction_entity.AuctionFilter{Category: "Electronics", ProductName: ".*"}, 0, "", 0)
+		require.Nil(t, err)
+		require.Empty(t, pageResult)
+	})
+
+	t.Run("paginate", func(t *testing.T) {
+		firstPage, err := repo.FindAuctions(ctx, nil, "", "", 1, 2)
+		require.Nil(t, err)
+		require.Equal(t, []string{"auction-1", "auction-2"}, ids(firstPage))
+
+		secondPage, err := repo.FindAuctions(ctx, nil, "", "", 2, 2)
+		require.Nil(t, err)
+		require.Equal(t, []string{"auction-3", "auction-4"}, ids(secondPage))
+
+		lastPage, err := repo.FindAuctions(ctx, nil, "", "", 3, 2)
+		require.Nil(t, err)
+		require.Equal(t, []string{"auction-5"}, ids(lastPage))
+	})
+
+	t.Run("page past the end", func(t *testing.T) {
+		result, err := repo.FindAuctions(ctx, nil, "", "", 10, 2)
+		require.Nil(t, err)
+		require.NotNil(t, result)
+		require.Empty(t, result)
+	})
+}
+
+func TestFindAuctionsAfter(t *testing.T) {
+	ctx := context.Background()
+
+	client, db, container := getTestDatabase(ctx, t)
+	defer func() {
+		if err := container.Terminate(ctx); err != nil {
+			log.Printf("failed to terminate container: %s", err)
+		}
+	}()
+	defer func() {
+		if err := client.Disconnect(ctx); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	collectionName := fmt.Sprintf("auctions_test_%d", time.Now().UnixNano())
+	collection := db.Collection(collectionName)
+	collection.Drop(ctx)
+	defer collection.Drop(ctx)
+
+	repo := NewAuctionRepositoryWithCollection(ctx, db, collectionName)
+	defer repo.Close()
+
+	now := time.Now()
+	createAuction := func(id string, timestamp time.Time) {
+		auctionEntity := &auction_entity.Auction{
+			Id:          id,
+			ProductName: "Paged Product",
+			Category:    "Electronics",
+			Description: "Auction used to test keyset pagination",
+			Condition:   auction_entity.New,
+			Status:      auction_entity.Active,
+			Timestamp:   timestamp,
+			ExpiresAt:   now.Add(time.Hour),
+		}
+		if _, err := repo.CreateAuction(ctx, auctionEntity); err != nil {
+			t.Fatalf("Failed to create auction: %v", err)
+		}
+	}
+
+	// auction-2a e auction-2b empatam no timestamp e são desempatadas pelo id
+	createAuction("auction-1", now.Add(1*time.Second))
+	createAuction("auction-2a", now.Add(2*time.Second))
+	createAuction("auction-2b", now.Add(2*time.Second))
+	createAuction("auction-3", now.Add(3*time.Second))
+	createAuction("auction-4", now.Add(4*time.Second))
+
+	var seen []string
+	var cursor *auction_entity.AuctionCursor
+	for page := 0; ; page++ {
+		var afterTimestamp int64
+		var afterId string
+		if cursor != nil {
+			afterTimestamp, afterId = cursor.Timestamp, cursor.Id
+		}
+
+		result, nextCursor, err := repo.FindAuctionsAfter(
+			ctx, auction_entity.AuctionFilter{}, afterTimestamp, afterId, 2)
+		require.Nil(t, err)
+
+		for _, auctionEntity := range result {
+			seen = append(seen, auctionEntity.Id)
+		}
+
+		// Leilões criados no meio da navegação: um antes do cursor, que não
+		// desloca as páginas seguintes, e um depois, que aparece no fim
+		if page == 0 {
+			createAuction("auction-0", now)
+			createAuction("auction-5", now.Add(5*time.Second))
+		}
+
+		if nextCursor == nil {
+			break
+		}
+		cursor = nextCursor
+	}
+
+	require.Equal(t,
+		[]string{"auction-1", "auction-2a", "auction-2b", "auction-3", "auction-4", "auction-5"}, seen)
+
+	completed := auction_entity.Completed
+	result, nextCursor, err := repo.FindAuctionsAfter(
+		ctx, auction_entity.AuctionFilter{Status: &completed}, 0, "", 2)
+	require.Nil(t, err)
+	require.Empty(t, result)
+	require.Nil(t, nextCursor)
+}
+
+func TestFindAuctionsPaged(t *testing.T) {
+	ctx := context.Background()
+
+	client, db, container := getTestDatabase(ctx, t)
+	defer func() {
+		if err := container.Terminate(ctx); err != nil {
+			log.Printf("failed to terminate container: %s", err)
+		}
+	}()
+	defer func() {
+		if err := client.Disconnect(ctx); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	collectionName := fmt.Sprintf("auctions_test_%d", time.Now().UnixNano())
+	collection := db.Collection(collectionName)
+	collection.Drop(ctx)
+	defer collection.Drop(ctx)
+
+	repo := NewAuctionRepositoryWithCollection(ctx, db, collectionName)
+	defer repo.Close()
+
+	now := time.Now()
+	for i := 1; i <= 5; i++ {
+		auctionEntity := &auction_entity.Auction{
+			Id:          fmt.Sprintf("auction-%d", i),
+			ProductName: "Paged Product",
+			Category:    "Electronics",
+			Description: "Auction used to test numbered pagination",
+			Condition:   auction_entity.New,
+			Status:      auction_entity.Active,
+			Timestamp:   now.Add(time.Duration(i) * time.Second),
+			ExpiresAt:   now.Add(time.Hour),
+		}
+		if _, err := repo.CreateAuction(ctx, auctionEntity); err != nil {
+			t.Fatalf("Failed to create auction: %v", err)
+		}
+	}
+
+	testCases := []struct {
+		page            int64
+		expectedIds     []string
+		expectedHasNext bool
+	}{
+		{page: 1, expectedIds: []string{"auction-1", "auction-2"}, expectedHasNext: true},
+		{page: 2, expectedIds: []string{"auction-3", "auction-4"}, expectedHasNext: true},
+		{page: 3, expectedIds: []string{"auction-5"}, expectedHasNext: false},
+		{page: 4, expectedIds: []string{}, expectedHasNext: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("page %d", tc.page), func(t *testing.T) {
+			auctionPage, err := repo.FindAuctionsPaged(ctx, auction_entity.AuctionFilter{}, tc.page, 2, true)
+			require.Nil(t, err)
+
+			ids := make([]string, 0, len(auctionPage.Items))
+			for _, auctionEntity := range auctionPage.Items {
+				ids = append(ids, auctionEntity.Id)
+			}
+			require.Equal(t, tc.expectedIds, ids)
+			require.Equal(t, tc.expectedHasNext, auctionPage.HasNext)
+			require.Equal(t, tc.page, auctionPage.Page)
+			require.Equal(t, int64(2), auctionPage.Limit)
+			require.NotNil(t, auctionPage.Total)
+			require.Equal(t, int64(5), *auctionPage.Total)
+		})
+	}
+
+	t.Run("without total", func(t *testing.T) {
+		auctionPage, err := repo.FindAuctionsPaged(ctx, auction_entity.AuctionFilter{}, 1, 2, false)
+		require.Nil(t, err)
+		require.Nil(t, auctionPage.Total)
+		require.True(t, auctionPage.HasNext)
+		require.Len(t, auctionPage.Items, 2)
+	})
+
+	t.Run("total follows the filter", func(t *testing.T) {
+		completed := auction_entity.Completed
+		auctionPage, err := repo.FindAuctionsPaged(
+			ctx, auction_entity.AuctionFilter{Status: &completed}, 1, 2, true)
+		require.Nil(t, err)
+		require.Empty(t, auctionPage.Items)
+		require.False(t, auctionPage.HasNext)
+		require.Equal(t, int64(0), *auctionPage.Total)
+	})
+}
+
+func TestFindAuctionsByOwner(t *testing.T) {
+	t.Setenv("AUCTION_INTERVAL", "1h")
+	t.Setenv("AUCTION_CHECK_INTERVAL", "1h")
+
+	ctx := context.Background()
+
+	client, db, container := getTestDatabase(ctx, t)
+	defer func() {
+		if err := container.Terminate(ctx); err != nil {
+			log.Printf("failed to terminate container: %s", err)
+		}
+	}()
+	defer func() {
+		if err := client.Disconnect(ctx); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	collectionName := fmt.Sprintf("auctions_test_%d", time.Now().UnixNano())
+	collection := db.Collection(collectionName)
+	collection.Drop(ctx)
+	defer collection.Drop(ctx)
+
+	repo := NewAuctionRepositoryWithCollection(ctx, db, collectionName)
+	defer repo.Close()
+
+	ownerId := uuid.New().String()
+	otherOwnerId := uuid.New().String()
+	now := time.Now()
+
+	createAuction := func(id, userId string, status auction_entity.AuctionStatus, timestamp time.Time) {
+		auctionEntity := &auction_entity.Auction{
+			Id:          id,
+			UserId:      userId,
+			ProductName: "Seller Product",
+			Category:    "Electronics",
+			Description: "Auction listed on the seller dashboard",
+			Condition:   auction_entity.New,
+			Status:      status,
+			Timestamp:   timestamp,
+			ExpiresAt:   now.Add(time.Hour),
+		}
+		if _, err := repo.CreateAuction(ctx, auctionEntity); err != nil {
+			t.Fatalf("Failed to create auction: %v", err)
+		}
+	}
+
+	createAuction("active-old", ownerId, auction_entity.Active, now.Add(-3*time.Minute))
+	createAuction("active-new", ownerId, auction_entity.Active, now.Add(-1*time.Minute))
+	createAuction("completed", ownerId, auction_entity.Completed, now.Add(-2*time.Minute))
+	createAuction("cancelled", ownerId, auction_entity.Cancelled, now.Add(-4*time.Minute))
+	createAuction("other-owner", otherOwnerId, auction_entity.Active, now)
+
+	auctionsByStatus, err := repo.FindAuctionsByOwner(ctx, ownerId)
+	require.Nil(t, err)
+
+	ids := func(status auction_entity.AuctionStatus) []string {
+		result := make([]string, 0, len(auctionsByStatus[status]))
+		for _, auctionEntity := range auctionsByStatus[status] {
+			result = append(result, auctionEntity.Id)
+		}
+		return result
+	}
+
+	require.Len(t, auctionsByStatus, 4)
+	require.Equal(t, []string{"active-new", "active-old"}, ids(auction_entity.Active))
+	require.Equal(t, []string{"completed"}, ids(auction_entity.Completed))
+	require.Equal(t, []string{"cancelled"}, ids(auction_entity.Cancelled))
+	require.Empty(t, ids(auction_entity.Scheduled))
+
+	_, err = repo.FindAuctionsByOwner(ctx, "")
+	require.NotNil(t, err)
+	require.Equal(t, "bad_request", err.Err)
+}
+
+func TestFindStaleActiveAuctions(t *testing.T) {
+	t.Setenv("AUCTION_INTERVAL", "1h")
+	t.Setenv("AUCTION_CHECK_INTERVAL", "1h")
+
+	ctx := context.Background()
+
+	client, db, container := getTestDatabase(ctx, t)
+	defer func() {
+		if err := container.Terminate(ctx); err != nil {
+			log.Printf("failed to terminate container: %s", err)
+		}
+	}()
+	defer func() {
+		if err := client.Disconnect(ctx); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	collectionName := fmt.Sprintf("auctions_test_%d", time.Now().UnixNano())
+	collection := db.Collection(collectionName)
+	collection.Drop(ctx)
+	defer collection.Drop(ctx)
+
+	repo := NewAuctionRepositoryWithCollection(ctx, db, collectionName)
+	defer repo.Close()
+
+	// Inseridos direto na coleção, sem passar pelo closer
+	now := time.Now()
+	_, err := collection.InsertMany(ctx, []interface{}{
+		AuctionEntityMongo{Id: "auction-stale", Status: auction_entity.Active,
+			Timestamp: now.Add(-2 * time.Hour).Unix(), ExpiresAt: now.Add(-time.Minute).Unix()},
+		AuctionEntityMongo{Id: "auction-stale-legacy", Status: auction_entity.Active,
+			Timestamp: now.Add(-2 * time.Hour).Unix()},
+		AuctionEntityMongo{Id: "auction-open", Status: auction_entity.Active,
+			Timestamp: now.Unix(), ExpiresAt: now.Add(time.Hour).Unix()},
+		AuctionEntityMongo{Id: "auction-completed", Status: auction_entity.Completed,
+			Timestamp: now.Add(-2 * time.Hour).Unix(), ExpiresAt: now.Add(-time.Minute).Unix()},
+	})
+	require.NoError(t, err)
+
+	result, findErr := repo.FindStaleActiveAuctions(ctx)
+	require.Nil(t, findErr)
+
+	var ids []string
+	for _, auctionEntity := range result {
+		ids = append(ids, auctionEntity.Id)
+	}
+	require.ElementsMatch(t, []string{"auction-stale", "auction-stale-legacy"}, ids)
+
+	repo.closeExpiredAuctions(ctx)
+
+	result, findErr = repo.FindStaleActiveAuctions(ctx)
+	require.Nil(t, findErr)
+	require.Empty(t, result)
+}
+
+func TestFindAuctionsClosingWithin(t *testing.T) {
+	t.Setenv("AUCTION_INTERVAL", "1h")
+	t.Setenv("AUCTION_CHECK_INTERVAL", "1h")
+
+	ctx := context.Background()
+
+	client, db, container := getTestDatabase(ctx, t)
+	defer func() {
+		if err := container.Terminate(ctx); err != nil {
+			log.Printf("failed to terminate container: %s", err)
+		}
+	}()
+	defer func() {
+		if err := client.Disconnect(ctx); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	collectionName := fmt.Sprintf("auctions_test_%d", time.Now().UnixNano())
+	collection := db.Collection(collectionName)
+	collection.Drop(ctx)
+	defer collection.Drop(ctx)
+
+	repo := NewAuctionRepositoryWithCollection(ctx, db, collectionName)
+	defer repo.Close()
+
+	clock := newFakeClock()
+	repo.SetClock(clock)
+
+	now := clock.Now()
+	closedAt := now.Unix()
+	_, err := collection.InsertMany(ctx, []interface{}{
+		AuctionEntityMongo{Id: "auction-closing-in-5m", Status: auction_entity.Active,
+			Timestamp: now.Unix(), ExpiresAt: now.Add(5 * time.Minute).Unix()},
+		AuctionEntityMongo{Id: "auction-closing-in-1m", Status: auction_entity.Active,
+			Timestamp: now.Unix(), ExpiresAt: now.Add(time.Minute).Unix()},
+		// Sem expires_at: fecha em timestamp + AUCTION_INTERVAL, daqui a 3m
+		AuctionEntityMongo{Id: "auction-closing-legacy", Status: auction_entity.Active,
+			Timestamp: now.Add(-57 * time.Minute).Unix()},
+		AuctionEntityMongo{Id: "auction-closing-later", Status: auction_entity.Active,
+			Timestamp: now.Unix(), ExpiresAt: now.Add(20 * time.Minute).Unix()},
+		AuctionEntityMongo{Id: "auction-legacy-later", Status: auction_entity.Active,
+			Timestamp: now.Add(-30 * time.Minute).Unix()},
+		AuctionEntityMongo{Id: "auction-already-expired", Status: auction_entity.Active,
+			Timestamp: now.Add(-2 * time.Hour).Unix(), ExpiresAt: now.Add(-time.Minute).Unix()},
+		AuctionEntityMongo{Id: "auction-completed-in-window", Status: auction_entity.Completed,
+			Timestamp: now.Unix(), ExpiresAt: now.Add(2 * time.Minute).Unix(), ClosedAt: &closedAt},
+	})
+	require.NoError(t, err)
+
+	result, findErr := repo.FindAuctionsClosingWithin(ctx, 10*time.Minute)
+	require.Nil(t, findErr)
+
+	var ids []string
+	for _, auctionEntity := range result {
+		ids = append(ids, auctionEntity.Id)
+	}
+	require.Equal(t, []string{"auction-closing-in-1m", "auction-closing-legacy", "auction-closing-in-5m"}, ids)
+
+	result, findErr = repo.FindAuctionsClosingWithin(ctx, 30*time.Second)
+	require.Nil(t, findErr)
+	require.Empty(t, result)
+
+	_, findErr = repo.FindAuctionsClosingWithin(ctx, 0)
+	require.NotNil(t, findErr)
+	require.Equal(t, "bad_request", findErr.Err)
+}
+
+func TestFindAuctionWithBids(t *testing.T) {
+	t.Setenv("AUCTION_INTERVAL", "1h")
+	t.Setenv("AUCTION_CHECK_INTERVAL", "1h")
+
+	ctx := context.Background()
+
+	client, db, container := getTestDatabase(ctx, t)
+	defer func() {
+		if err := container.Terminate(ctx); err != nil {
+			log.Printf("failed to terminate container: %s", err)
+		}
+	}()
+	defer func() {
+		if err := client.Disconnect(ctx); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	collectionName := fmt.Sprintf("auctions_test_%d", time.Now().UnixNano())
+	collection := db.Collection(collectionName)
+	collection.Drop(ctx)
+	defer collection.Drop(ctx)
+
+	bidsCollection := db.Collection(testBidsCollectionName)
+	defer bidsCollection.Drop(ctx)
+
+	repo := NewAuctionRepositoryWithCollection(ctx, db, collectionName)
+	defer repo.Close()
+	repo.SetBidStore(newTestBidStore(db))
+
+	now := time.Now()
+	auctionEntity := &auction_entity.Auction{
+		Id:          "test-auction-with-bids",
+		ProductName: "Joined Product",
+		Category:    "Electronics",
+		Description: "Auction returned together with its bids",
+		Condition:   auction_entity.New,
+		Status:      auction_entity.Active,
+		Timestamp:   now,
+	}
+	if _, err := repo.CreateAuction(ctx, auctionEntity); err != nil {
+		t.Fatalf("Failed to create auction: %v", err)
+	}
+
+	// Fora de ordem de propósito; bid-b e bid-c empatam no timestamp
+	_, err := bidsCollection.InsertMany(ctx, []interface{}{
+		auctionBidMongo{Id: "bid-c", UserId: "user-3", AuctionId: auctionEntity.Id, Amount: decimal.Amount(bid_entity.AmountFromCents(300_00)), Timestamp: now.Add(-time.Minute).Unix()},
+		auctionBidMongo{Id: "bid-a", UserId: "user-1", AuctionId: auctionEntity.Id, Amount: decimal.Amount(bid_entity.AmountFromCents(100_00)), Timestamp: now.Add(-3 * time.Minute).Unix()},
+		auctionBidMongo{Id: "bid-d", UserId: "user-4", AuctionId: auctionEntity.Id, Amount: decimal.Amount(bid_entity.AmountFromCents(400_00)), Timestamp: now.Unix()},
+		auctionBidMongo{Id: "bid-b", UserId: "user-2", AuctionId: auctionEntity.Id, Amount: decimal.Amount(bid_entity.AmountFromCents(200_00)), Timestamp: now.Add(-time.Minute).Unix()},
+		auctionBidMongo{Id: "bid-other", UserId: "user-1", AuctionId: "other-auction", Amount: decimal.Amount(bid_entity.AmountFromCents(999_00)), Timestamp: now.Unix()},
+	})
+	require.NoError(t, err)
+
+	result, findErr := repo.FindAuctionWithBids(ctx, auctionEntity.Id)
+	require.Nil(t, findErr)
+	require.Equal(t, auctionEntity.Id, result.Auction.Id)
+	require.Equal(t, "Joined Product", result.Auction.ProductName)
+	require.Equal(t, now.Add(time.Hour).Unix(), result.Auction.ExpiresAt.Unix())
+
+	var ids []string
+	for _, bid := range result.Bids {
+		require.Equal(t, auctionEntity.Id, bid.AuctionId)
+		ids = append(ids, bid.Id)
+	}
+	require.Equal(t, []string{"bid-a", "bid-b", "bid-c", "bid-d"}, ids)
+	require.Equal(t, bid_entity.AmountFromCents(100_00), result.Bids[0].Amount)
+	require.Equal(t, "user-1", result.Bids[0].UserId)
+	require.Equal(t, now.Add(-3*time.Minute).Unix(), result.Bids[0].Timestamp.Unix())
+
+	withoutBids := &auction_entity.Auction{
+		Id:          "test-auction-without-bids",
+		ProductName: "Lonely Product",
+		Category:    "Electronics",
+		Description: "Auction that has not received bids",
+		Condition:   auction_entity.New,
+		Status:      auction_entity.Active,
+		Timestamp:   now,
+	}
+	if _, err := repo.CreateAuction(ctx, withoutBids); err != nil {
+		t.Fatalf("Failed to create auction: %v", err)
+	}
+
+	result, findErr = repo.FindAuctionWithBids(ctx, withoutBids.Id)
+	require.Nil(t, findErr)
+	require.Empty(t, result.Bids)
+
+	sealed := &auction_entity.Auction{
+		Id:          "test-auction-sealed",
+		ProductName: "Sealed Product",
+		Category:    "Electronics",
+		Description: "Sealed auction that must not reveal its bids",
+		Condition:   auction_entity.New,
+		Status:      auction_entity.Active,
+		Type:        auction_entity.Sealed,
+		Timestamp:   now,
+	}
+	if _, err := repo.CreateAuction(ctx, sealed); err != nil {
+		t.Fatalf("Failed to create auction: %v", err)
+	}
+	_, err = bidsCollection.InsertOne(ctx, auctionBidMongo{Id: "bid-sealed", UserId: "user-1", AuctionId: sealed.Id, Amount: decimal.Amount(bid_entity.AmountFromCents(500_00)), Timestamp: now.Unix()})
+	require.NoError(t, err)
+
+	result, findErr = repo.FindAuctionWithBids(ctx, sealed.Id)
+	require.Nil(t, findErr)
+	require.Equal(t, sealed.Id, result.Auction.Id)
+	require.Empty(t, result.Bids)
+
+	result, findErr = repo.FindAuctionWithBids(ctx, "missing-auction")
+	require.Nil(t, result)
+	require.NotNil(t, findErr)
+	require.Equal(t, "not_found", findErr.Err)
+	require.Equal(t, internal_error.CodeAuctionNotFound, findErr.Code)
+}
+
+func TestAuctionTimestampRoundTripInUTC(t *testing.T) {
+	ctx := context.Background()
+
+	client, db, container := getTestDatabase(ctx, t)
+	defer func() {
+		if err := container.Terminate(ctx); err != nil {
+			log.Printf("failed to terminate container: %s", err)
+		}
+	}()
+	defer func() {
+		if err := client.Disconnect(ctx); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	collectionName := fmt.Sprintf("auctions_test_%d", time.Now().UnixNano())
+	collection := db.Collection(collectionName)
+	collection.Drop(ctx)
+	defer collection.Drop(ctx)
+
+	repo := NewAuctionRepositoryWithCollection(ctx, db, collectionName)
+	defer repo.Close()
+
+	saoPaulo, err := time.LoadLocation("America/Sao_Paulo")
+	require.NoError(t, err)
+
+	timestamp := time.Now().In(saoPaulo)
+	auctionEntity := &auction_entity.Auction{
+		Id:          "test-auction-utc",
+		ProductName: "UTC Product",
+		Category:    "Test Category",
+		Description: "Auction used to test timestamp round trip",
+		Condition:   auction_entity.New,
+		Status:      auction_entity.Active,
+		Timestamp:   timestamp,
+		ExpiresAt:   timestamp.Add(time.Hour),
+	}
+
+	if _, err := repo.CreateAuction(ctx, auctionEntity); err != nil {
+		t.Fatalf("Failed to create auction: %v", err)
+	}
+
+	result, findErr := repo.FindAuctionById(ctx, "test-auction-utc")
+	require.Nil(t, findErr)
+
+	require.Equal(t, time.UTC, result.Timestamp.Location())
+	require.Equal(t, timestamp.Truncate(time.Second).UTC(), result.Timestamp)
+	require.Equal(t, time.UTC, result.ExpiresAt.Location())
+	require.Equal(t, timestamp.Add(time.Hour).Truncate(time.Second).UTC(), result.ExpiresAt)
+}
+
+func TestCountAuctionsByStatus(t *testing.T) {
+	t.Setenv("AUCTION_CHECK_INTERVAL", "1h")
+
+	ctx := context.Background()
+
+	client, db, container := getTestDatabase(ctx, t)
+	defer func() {
+		if err := container.Terminate(ctx); err != nil {
+			log.Printf("failed to terminate container: %s", err)
+		}
+	}()
+	defer func() {
+		if err := client.Disconnect(ctx); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	collectionName := fmt.Sprintf("auctions_test_%d", time.Now().UnixNano())
+	collection := db.Collection(collectionName)
+	collection.Drop(ctx)
+	defer collection.Drop(ctx)
+
+	repo := NewAuctionRepositoryWithCollection(ctx, db, collectionName)
+	defer repo.Close()
+
+	counts, err := repo.CountAuctionsByStatus(ctx)
+	require.Nil(t, err)
+	require.Equal(t, map[auction_entity.AuctionStatus]int64{
+		auction_entity.Active:    0,
+		auction_entity.Completed: 0,
+		auction_entity.Cancelled: 0,
+		auction_entity.Scheduled: 0,
+	}, counts)
+
+	statuses := []auction_entity.AuctionStatus{
+		auction_entity.Active,
+		auction_entity.Active,
+		auction_entity.Active,
+		auction_entity.Completed,
+		auction_entity.Completed,
+	}
+	for i, status := range statuses {
+		auctionEntity := &auction_entity.Auction{
+			Id:          fmt.Sprintf("test-auction-count-%d", i),
+			ProductName: "Count Product",
+			Category:    "Test Category",
+			Description: "Auction used to test counts by status",
+			Condition:   auction_entity.New,
+			Status:      status,
+			Timestamp:   time.Now(),
+		}
+
+		if _, err := repo.CreateAuction(ctx, auctionEntity); err != nil {
+			t.Fatalf("Failed to create auction: %v", err)
+		}
+	}
+
+	// Leilões removidos logicamente não entram na contagem
+	require.Nil(t, repo.SoftDelete(ctx, "test-auction-count-0"))
+
+	counts, err = repo.CountAuctionsByStatus(ctx)
+	require.Nil(t, err)
+	require.Equal(t, int64(2), counts[auction_entity.Active])
+	require.Equal(t, int64(2), counts[auction_entity.Completed])
+}
@@ -0,0 +1,81 @@
+package auction
+
+import (
+	"context"
+	"log"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/testcontainers/testcontainers-go/modules/mongodb"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Container compartilhado pelos testes do pacote. Ele só sobe no primeiro
+// teste que pede o banco, então testes sem Mongo não pagam a inicialização,
+// e o TestMain o encerra depois de todos os testes.
+var sharedMongo struct {
+	once      sync.Once
+	container *mongodb.MongoDBContainer
+	url       string
+	err       error
+}
+
+func TestMain(m *testing.M) {
+	code := m.Run()
+
+	if sharedMongo.container != nil {
+		if err := sharedMongo.container.Terminate(context.Background()); err != nil {
+			log.Printf("failed to terminate shared container: %s", err)
+		}
+	}
+
+	os.Exit(code)
+}
+
+// sharedMongoURL retorna a connection string do container compartilhado,
+// subindo o container na primeira chamada.
+func sharedMongoURL(ctx context.Context) (string, error) {
+	sharedMongo.once.Do(func() {
+		sharedMongo.container, sharedMongo.err = mongodb.Run(ctx, "mongo:latest")
+		if sharedMongo.err != nil {
+			return
+		}
+		sharedMongo.url, sharedMongo.err = sharedMongo.container.ConnectionString(ctx)
+	})
+
+	return sharedMongo.url, sharedMongo.err
+}
+
+// getSharedTestDatabase conecta ao container compartilhado. Cada teste tem o
+// seu client, desconectado no fim do teste; o isolamento entre testes vem do
+// nome único da coleção (auctions_test_<nanos>), como nos demais testes.
+func getSharedTestDatabase(ctx context.Context, t *testing.T) *mongo.Database {
+	t.Helper()
+
+	mongoURL, err := sharedMongoURL(ctx)
+	if err != nil {
+		t.Skipf("Skipping test: could not start MongoDB container: %v", err)
+	}
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(mongoURL))
+	if err != nil {
+		t.Skipf("Skipping test: could not connect to MongoDB: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := client.Disconnect(ctx); err != nil {
+			log.Printf("failed to disconnect client: %s", err)
+		}
+	})
+
+	pingCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	if err := client.Ping(pingCtx, nil); err != nil {
+		t.Skipf("Skipping test: MongoDB not available: %v", err)
+	}
+
+	return client.Database("auctions_test")
+}
@@ -0,0 +1,135 @@
+package auction
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/entity/user_entity"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+)
+
+// Option ajusta a criação do AuctionRepository. Valores não informados vêm
+// das variáveis de ambiente, como nos construtores sem opções.
+type Option func(*repositoryOptions)
+
+type repositoryOptions struct {
+	collectionName  string
+	registerer      prometheus.Registerer
+	clock           Clock
+	checkInterval   time.Duration
+	closeBatchSize  int64
+	dryRun          *bool
+	onAuctionClosed func(ctx context.Context, auctionID string)
+	writeConcern    *writeconcern.WriteConcern
+	readPreference  *readpref.ReadPref
+	userRepository  user_entity.UserRepositoryInterface
+	rules           auction_entity.Rules
+}
+
+func WithCollectionName(collectionName string) Option {
+	return func(options *repositoryOptions) {
+		options.collectionName = collectionName
+	}
+}
+
+// WithRegisterer registra as métricas do closer no registerer informado em
+// vez de um registry isolado.
+func WithRegisterer(registerer prometheus.Registerer) Option {
+	return func(options *repositoryOptions) {
+		options.registerer = registerer
+	}
+}
+
+func WithClock(clock Clock) Option {
+	return func(options *repositoryOptions) {
+		options.clock = clock
+	}
+}
+
+// WithCheckInterval substitui AUCTION_CHECK_INTERVAL. Valores não positivos
+// são ignorados.
+func WithCheckInterval(checkInterval time.Duration) Option {
+	return func(options *repositoryOptions) {
+		options.checkInterval = checkInterval
+	}
+}
+
+// WithCloseBatchSize substitui AUCTION_CLOSE_BATCH_SIZE. Valores não
+// positivos são ignorados.
+func WithCloseBatchSize(closeBatchSize int64) Option {
+	return func(options *repositoryOptions) {
+		options.closeBatchSize = closeBatchSize
+	}
+}
+
+func WithDryRun(dryRun bool) Option {
+	return func(options *repositoryOptions) {
+		options.dryRun = &dryRun
+	}
+}
+
+// WithUserRepository define o repositório usado para validar o novo dono em
+// TransferAuction. Sem a opção o repositório não transfere leilões.
+func WithUserRepository(userRepository user_entity.UserRepositoryInterface) Option {
+	return func(options *repositoryOptions) {
+		options.userRepository = userRepository
+	}
+}
+
+// WithOnClosed registra o callback antes da goroutine de fechamento iniciar,
+// sem a janela que existe ao usar SetOnAuctionClosed depois do construtor.
+func WithOnClosed(onAuctionClosed func(ctx context.Context, auctionID string)) Option {
+	return func(options *repositoryOptions) {
+		options.onAuctionClosed = onAuctionClosed
+	}
+}
+
+// WithWriteConcern define o write concern da coleção de leilões, por exemplo
+// writeconcern.Majority(), e também o da coleção de lances do BidRepository
+// criado sobre este repositório. Sem a opção vale o do banco.
+func WithWriteConcern(writeConcern *writeconcern.WriteConcern) Option {
+	return func(options *repositoryOptions) {
+		options.writeConcern = writeConcern
+	}
+}
+
+// WithReadPreference define de quais membros do replica set as leituras das
+// coleções de leilões e de lances podem vir. Sem a opção vale a do banco.
+func WithReadPreference(readPreference *readpref.ReadPref) Option {
+	return func(options *repositoryOptions) {
+		options.readPreference = readPreference
+	}
+}
+
+// collectionOptions monta as opções da coleção; o que não foi informado é
+// herdado do banco pelo driver.
+func (o *repositoryOptions) collectionOptions() *options.CollectionOptions {
+	collectionOptions := options.Collection()
+	if o.writeConcern != nil {
+		collectionOptions.SetWriteConcern(o.writeConcern)
+	}
+	if o.readPreference != nil {
+		collectionOptions.SetReadPreference(o.readPreference)
+	}
+
+	return collectionOptions
+}
+
+// CollectionOptions retorna o write concern e a read preference configurados
+// para a coleção de leilões, para que o repositório de lances use os mesmos.
+func (ar *AuctionRepository) CollectionOptions() *options.CollectionOptions {
+	return ar.collectionOptions
+}
+
+// WithRules define as regras aplicadas aos campos alterados em UpdateAuction,
+// as mesmas usadas na criação dos leilões. Sem a opção valem as
+// auction_entity.DefaultRules.
+func WithRules(rules auction_entity.Rules) Option {
+	return func(options *repositoryOptions) {
+		options.rules = rules
+	}
+}
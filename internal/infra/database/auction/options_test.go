@@ -0,0 +1,192 @@
+package auction
+
+import (
+	"context"
+	"fmt"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+)
+
+func TestNewAuctionRepositoryWithOptions(t *testing.T) {
+	t.Setenv("AUCTION_INTERVAL", "2s")
+	t.Setenv("AUCTION_CHECK_INTERVAL", "1h")
+
+	ctx := context.Background()
+
+	client, db, container := getTestDatabase(ctx, t)
+	defer func() {
+		if err := container.Terminate(ctx); err != nil {
+			log.Printf("failed to terminate container: %s", err)
+		}
+	}()
+	defer func() {
+		if err := client.Disconnect(ctx); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	collectionName := fmt.Sprintf("auctions_test_%d", time.Now().UnixNano())
+	collection := db.Collection(collectionName)
+	collection.Drop(ctx)
+	defer collection.Drop(ctx)
+
+	clock := newFakeClock()
+	closedAuctions := make(chan string, 1)
+
+	repo := NewAuctionRepositoryWithOptions(ctx, db,
+		WithCollectionName(collectionName),
+		WithClock(clock),
+		WithCheckInterval(50*time.Millisecond),
+		WithOnClosed(func(ctx context.Context, auctionID string) {
+			closedAuctions <- auctionID
+		}))
+	defer repo.Close()
+
+	require.Equal(t, collectionName, repo.Collection.Name())
+	require.Equal(t, 50*time.Millisecond, repo.CheckInterval())
+
+	auctionEntity := &auction_entity.Auction{
+		Id:          "test-auction-options",
+		ProductName: "Options Product",
+		Category:    "Electronics",
+		Description: "This auction is closed by a repository built with options",
+		Condition:   auction_entity.New,
+		Status:      auction_entity.Active,
+		Timestamp:   clock.Now(),
+	}
+
+	if _, err := repo.CreateAuction(ctx, auctionEntity); err != nil {
+		t.Fatalf("Failed to create auction: %v", err)
+	}
+
+	// O relógio falso decide a expiração; o ticker curto só dispara a rotina
+	clock.Advance(4 * time.Second)
+
+	select {
+	case auctionID := <-closedAuctions:
+		require.Equal(t, "test-auction-options", auctionID)
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the closer to call the WithOnClosed callback")
+	}
+
+	closed, err := repo.FindAuctionById(ctx, "test-auction-options")
+	require.Nil(t, err)
+	require.Equal(t, auction_entity.Completed, closed.Status)
+}
+
+func TestNewAuctionRepositoryWithDryRunOption(t *testing.T) {
+	t.Setenv("AUCTION_INTERVAL", "2s")
+	t.Setenv("AUCTION_CHECK_INTERVAL", "1h")
+	t.Setenv("AUCTION_CLOSER_DRY_RUN", "false")
+
+	ctx := context.Background()
+
+	client, db, container := getTestDatabase(ctx, t)
+	defer func() {
+		if err := container.Terminate(ctx); err != nil {
+			log.Printf("failed to terminate container: %s", err)
+		}
+	}()
+	defer func() {
+		if err := client.Disconnect(ctx); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	collectionName := fmt.Sprintf("auctions_test_%d", time.Now().UnixNano())
+	collection := db.Collection(collectionName)
+	collection.Drop(ctx)
+	defer collection.Drop(ctx)
+
+	clock := newFakeClock()
+	repo := NewAuctionRepositoryWithOptions(ctx, db,
+		WithCollectionName(collectionName),
+		WithClock(clock),
+		WithCloseBatchSize(1),
+		WithDryRun(true))
+	defer repo.Close()
+
+	auctionEntity := &auction_entity.Auction{
+		Id:          "test-auction-options-dry-run",
+		ProductName: "Options Product",
+		Category:    "Electronics",
+		Description: "The dry run option overrides the environment",
+		Condition:   auction_entity.New,
+		Status:      auction_entity.Active,
+		Timestamp:   clock.Now(),
+	}
+
+	if _, err := repo.CreateAuction(ctx, auctionEntity); err != nil {
+		t.Fatalf("Failed to create auction: %v", err)
+	}
+
+	clock.Advance(4 * time.Second)
+	repo.closeExpiredAuctions(ctx)
+
+	result, err := repo.FindAuctionById(ctx, "test-auction-options-dry-run")
+	require.Nil(t, err)
+	require.Equal(t, auction_entity.Active, result.Status)
+}
+
+func TestNewAuctionRepositoryWithConcernOptions(t *testing.T) {
+	t.Setenv("AUCTION_INTERVAL", "2s")
+	t.Setenv("AUCTION_CHECK_INTERVAL", "1h")
+
+	ctx := context.Background()
+
+	client, db, container := getTestDatabase(ctx, t)
+	defer func() {
+		if err := container.Terminate(ctx); err != nil {
+			log.Printf("failed to terminate container: %s", err)
+		}
+	}()
+	defer func() {
+		if err := client.Disconnect(ctx); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	collectionName := fmt.Sprintf("auctions_test_%d", time.Now().UnixNano())
+	collection := db.Collection(collectionName)
+	collection.Drop(ctx)
+	defer collection.Drop(ctx)
+
+	defaultOptions := (&repositoryOptions{}).collectionOptions()
+	require.Nil(t, defaultOptions.WriteConcern)
+	require.Nil(t, defaultOptions.ReadPreference)
+
+	repo := NewAuctionRepositoryWithOptions(ctx, db,
+		WithCollectionName(collectionName),
+		WithWriteConcern(writeconcern.Majority()),
+		WithReadPreference(readpref.PrimaryPreferred()))
+	defer repo.Close()
+
+	configuredOptions := (&repositoryOptions{
+		writeConcern:   writeconcern.Majority(),
+		readPreference: readpref.PrimaryPreferred(),
+	}).collectionOptions()
+	require.Equal(t, writeconcern.Majority(), configuredOptions.WriteConcern)
+	require.Equal(t, readpref.PrimaryPreferredMode, configuredOptions.ReadPreference.Mode())
+
+	auctionEntity := &auction_entity.Auction{
+		Id:          "test-auction-write-concern",
+		ProductName: "Concern Product",
+		Category:    "Electronics",
+		Description: "This auction is written with majority write concern",
+		Condition:   auction_entity.New,
+		Status:      auction_entity.Active,
+		Timestamp:   time.Now(),
+	}
+	_, err := repo.CreateAuction(ctx, auctionEntity)
+	require.Nil(t, err)
+
+	found, err := repo.FindAuctionById(ctx, auctionEntity.Id)
+	require.Nil(t, err)
+	require.Equal(t, auctionEntity.Id, found.Id)
+}
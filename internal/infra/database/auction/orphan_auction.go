@@ -0,0 +1,142 @@
+package auction
+
+import (
+	"context"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/infra/database/database_error"
+	"fullcycle-auction_go/internal/internal_error"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+// CloseOrphanedAuctions fecha os leilões ativos cujo dono não existe mais no
+// repositório de usuários e retorna quantos foram fechados. Os donos são
+// lidos em páginas de closeBatchSize, em ordem, e cada página é conferida no
+// repositório de usuários. Leilões sem dono, gravados antes do campo existir,
+// são ignorados.
+func (ar *AuctionRepository) CloseOrphanedAuctions(ctx context.Context) (int64, *internal_error.InternalError) {
+	ctx, span := ar.startSpan(ctx, "CloseOrphanedAuctions")
+	closed, err := ar.closeOrphanedAuctions(ctx)
+	endSpan(span, err)
+
+	return closed, err
+}
+
+func (ar *AuctionRepository) closeOrphanedAuctions(ctx context.Context) (int64, *internal_error.InternalError) {
+	if ar.userRepository == nil {
+		return 0, internal_error.NewInternalServerError("orphaned auctions check is not available")
+	}
+
+	var closed int64
+	lastOwnerId := ""
+	for {
+		ownerIds, err := ar.findActiveOwnersPage(ctx, lastOwnerId)
+		if err != nil {
+			return closed, err
+		}
+		if len(ownerIds) == 0 {
+			return closed, nil
+		}
+		lastOwnerId = ownerIds[len(ownerIds)-1]
+
+		pageClosed, err := ar.closeAuctionsOfRemovedOwners(ctx, ownerIds)
+		closed += pageClosed
+		if err != nil {
+			return closed, err
+		}
+
+		if int64(len(ownerIds)) < ar.closeBatchSize {
+			return closed, nil
+		}
+	}
+}
+
+// activeAuctionsWithOwnerFilter seleciona os leilões ativos com dono
+func activeAuctionsWithOwnerFilter() bson.M {
+	return bson.M{
+		"status":     auction_entity.Active,
+		"deleted_at": nil,
+		"closed_at":  nil,
+		"user_id":    bson.M{"$nin": bson.A{nil, ""}},
+	}
+}
+
+// findActiveOwnersPage retorna até closeBatchSize donos de leilões ativos
+// depois de afterOwnerId, em ordem
+func (ar *AuctionRepository) findActiveOwnersPage(
+	ctx context.Context, afterOwnerId string) ([]string, *internal_error.InternalError) {
+	match := activeAuctionsWithOwnerFilter()
+	if afterOwnerId != "" {
+		match["user_id"] = bson.M{"$gt": afterOwnerId}
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{"_id": "$user_id"}}},
+		{{Key: "$sort", Value: bson.M{"_id": 1}}},
+		{{Key: "$limit", Value: ar.closeBatchSize}},
+	}
+
+	cursor, err := ar.Collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		logger.WithContext(ctx).Error("Error trying to find owners of active auctions", err)
+		return nil, database_error.NewInternalError(err, "Error trying to close orphaned auctions")
+	}
+	defer closeCursor(ctx, cursor)
+
+	var owners []struct {
+		Id string `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &owners); err != nil {
+		logger.WithContext(ctx).Error("Error trying to decode owners of active auctions", err)
+		return nil, database_error.NewInternalError(err, "Error trying to close orphaned auctions")
+	}
+
+	ownerIds := make([]string, 0, len(owners))
+	for _, owner := range owners {
+		ownerIds = append(ownerIds, owner.Id)
+	}
+
+	return ownerIds, nil
+}
+
+func (ar *AuctionRepository) closeAuctionsOfRemovedOwners(
+	ctx context.Context, ownerIds []string) (int64, *internal_error.InternalError) {
+	log := logger.WithContext(ctx)
+
+	existingIds, err := ar.userRepository.FindExistingUserIds(ctx, ownerIds)
+	if err != nil {
+		return 0, err
+	}
+
+	existing := make(map[string]bool, len(existingIds))
+	for _, userId := range existingIds {
+		existing[userId] = true
+	}
+
+	var removedOwners bson.A
+	for _, ownerId := range ownerIds {
+		if !existing[ownerId] {
+			removedOwners = append(removedOwners, ownerId)
+		}
+	}
+
+	if len(removedOwners) == 0 {
+		return 0, nil
+	}
+
+	log.Info("Closing auctions of removed owners", zap.Int("owners", len(removedOwners)))
+
+	filter := activeAuctionsWithOwnerFilter()
+	filter["user_id"] = bson.M{"$in": removedOwners}
+	orphaned, countErr := ar.Collection.CountDocuments(ctx, filter)
+	if countErr != nil {
+		log.Error("Error trying to count orphaned auctions", countErr)
+		return 0, database_error.NewInternalError(countErr, "Error trying to close orphaned auctions")
+	}
+
+	return ar.forceCloseAuctions(ctx, filter, orphaned, "owner removed", "owner_removed")
+}
@@ -0,0 +1,96 @@
+package auction
+
+import (
+	"context"
+	"fmt"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/infra/database/user"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestCloseOrphanedAuctions(t *testing.T) {
+	t.Setenv("AUCTION_INTERVAL", "1h")
+	t.Setenv("AUCTION_CHECK_INTERVAL", "1h")
+
+	ctx := context.Background()
+	db := getSharedTestDatabase(ctx, t)
+
+	collectionName := fmt.Sprintf("auctions_test_%d", time.Now().UnixNano())
+	collection := db.Collection(collectionName)
+	collection.Drop(ctx)
+	defer collection.Drop(ctx)
+
+	// Lotes de um dono para passar por mais de uma página
+	repo := NewAuctionRepositoryWithOptions(ctx, db,
+		WithCollectionName(collectionName),
+		WithCloseBatchSize(1),
+		WithUserRepository(user.NewUserRepository(db)))
+	defer repo.Close()
+
+	var closedIds []string
+	repo.SetOnAuctionClosed(func(ctx context.Context, auctionID string) {
+		closedIds = append(closedIds, auctionID)
+	})
+
+	ownerId := uuid.New().String()
+	removedOwnerId := uuid.New().String()
+	users := db.Collection("users")
+	_, err := users.InsertMany(ctx, []interface{}{
+		bson.M{"_id": ownerId, "name": "Seller"},
+		bson.M{"_id": removedOwnerId, "name": "Removed Seller"},
+	})
+	require.NoError(t, err)
+	defer users.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": bson.A{ownerId, removedOwnerId}}})
+
+	createAuction := func(id, userId string) {
+		auctionEntity := &auction_entity.Auction{
+			Id:          id,
+			UserId:      userId,
+			ProductName: "Orphan Product",
+			Category:    "Electronics",
+			Description: "Auction whose owner may be removed",
+			Condition:   auction_entity.New,
+			Status:      auction_entity.Active,
+			Timestamp:   time.Now(),
+		}
+		if _, err := repo.CreateAuction(ctx, auctionEntity); err != nil {
+			t.Fatalf("Failed to create auction: %v", err)
+		}
+	}
+
+	createAuction("auction-owner", ownerId)
+	createAuction("auction-removed-owner", removedOwnerId)
+	createAuction("auction-legacy", "")
+
+	closed, closeErr := repo.CloseOrphanedAuctions(ctx)
+	require.Nil(t, closeErr)
+	require.Zero(t, closed)
+
+	_, err = users.DeleteOne(ctx, bson.M{"_id": removedOwnerId})
+	require.NoError(t, err)
+
+	closed, closeErr = repo.CloseOrphanedAuctions(ctx)
+	require.Nil(t, closeErr)
+	require.Equal(t, int64(1), closed)
+	require.Equal(t, []string{"auction-removed-owner"}, closedIds)
+
+	expectedStatus := map[string]auction_entity.AuctionStatus{
+		"auction-owner":         auction_entity.Active,
+		"auction-removed-owner": auction_entity.Completed,
+		"auction-legacy":        auction_entity.Active,
+	}
+	for id, status := range expectedStatus {
+		auctionEntity, err := repo.FindAuctionById(ctx, id)
+		require.Nil(t, err)
+		require.Equal(t, status, auctionEntity.Status, id)
+	}
+
+	history, historyErr := repo.FindAuctionHistory(ctx, "auction-removed-owner")
+	require.Nil(t, historyErr)
+	require.Equal(t, "owner removed", history[len(history)-1].Reason)
+}
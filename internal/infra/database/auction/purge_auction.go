@@ -0,0 +1,146 @@
+package auction
+
+import (
+	"context"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"os"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// A limpeza roda bem menos que o closer: leilões fechados há semanas não
+// precisam sair da coleção no minuto exato
+const auctionPurgeInterval = time.Hour
+
+func (ar *AuctionRepository) startAuctionPurger(ctx context.Context) {
+	purgeInterval := auctionPurgeInterval
+	if checkInterval := ar.CheckInterval(); checkInterval > purgeInterval {
+		purgeInterval = checkInterval
+	}
+
+	ticker := time.NewTicker(purgeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ar.done:
+			return
+		case <-ticker.C:
+			if _, err := ar.purgeCompletedAuctions(ctx); err != nil {
+				logger.Error("Error trying to purge completed auctions", err)
+			}
+		}
+	}
+}
+
+// purgeCompletedAuctions apaga os leilões fechados há mais de
+// AUCTION_RETENTION junto com os seus lances. Os lances são apagados antes,
+// então uma falha no meio deixa o leilão para a próxima rodada em vez de
+// lances sem leilão.
+func (ar *AuctionRepository) purgeCompletedAuctions(ctx context.Context) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, ar.closerOpTimeout)
+	defer cancel()
+
+	threshold := ar.now().Add(-ar.retention).Unix()
+	// Leilões fechados antes do closed_at existir usam a expiração
+	filter := bson.M{
+		"status": auction_entity.Completed,
+		"$or": bson.A{
+			bson.M{"closed_at": bson.M{"$lt": threshold}},
+			bson.M{
+				"closed_at":  bson.M{"$exists": false},
+				"expires_at": bson.M{"$lt": threshold},
+			},
+		},
+	}
+
+	bidStore, storeErr := ar.bidStore()
+	if storeErr != nil {
+		return 0, storeErr
+	}
+
+	var purgedAuctions, purgedBids int64
+	for {
+		ids, err := ar.findPurgeCandidates(ctx, filter)
+		if err != nil {
+			return purgedAuctions, err
+		}
+		if len(ids) == 0 {
+			break
+		}
+
+		deletedBids, deleteErr := bidStore.DeleteBidsByAuctionIds(ctx, ids)
+		if deleteErr != nil {
+			return purgedAuctions, deleteErr
+		}
+		purgedBids += deletedBids
+
+		auctionsResult, err := ar.Collection.DeleteMany(ctx,
+			bson.M{"_id": bson.M{"$in": ids}, "status": auction_entity.Completed})
+		if err != nil {
+			return purgedAuctions, err
+		}
+		purgedAuctions += auctionsResult.DeletedCount
+
+		if auctionsResult.DeletedCount == 0 || int64(len(ids)) < ar.closeBatchSize {
+			break
+		}
+	}
+
+	logger.WithContext(ctx).Info("Completed auctions purged",
+		zap.Int64("auctions", purgedAuctions),
+		zap.Int64("bids", purgedBids),
+		zap.Int64("threshold", threshold))
+
+	return purgedAuctions, nil
+}
+
+func (ar *AuctionRepository) findPurgeCandidates(ctx context.Context, filter bson.M) ([]string, error) {
+	opts := options.Find().
+		SetProjection(bson.M{"_id": 1}).
+		SetLimit(ar.closeBatchSize)
+
+	cursor, err := ar.Collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer closeCursor(ctx, cursor)
+
+	var candidates []struct {
+		Id string `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &candidates); err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(candidates))
+	for _, candidate := range candidates {
+		ids = append(ids, candidate.Id)
+	}
+
+	return ids, nil
+}
+
+// getAuctionRetention retorna 0 (limpeza desligada) quando a variável não
+// está definida
+func getAuctionRetention() time.Duration {
+	retention := os.Getenv("AUCTION_RETENTION")
+	if retention == "" {
+		return 0
+	}
+
+	duration, err := time.ParseDuration(retention)
+	if err != nil || duration <= 0 {
+		logger.Warn("AUCTION_RETENTION is invalid, completed auctions will not be purged",
+			zap.String("value", retention))
+		return 0
+	}
+
+	return duration
+}
@@ -0,0 +1,132 @@
+package auction
+
+import (
+	"context"
+	"fmt"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestPurgeCompletedAuctions(t *testing.T) {
+	t.Setenv("AUCTION_INTERVAL", "1h")
+	t.Setenv("AUCTION_CHECK_INTERVAL", "1h")
+	t.Setenv("AUCTION_RETENTION", "720h")
+
+	ctx := context.Background()
+
+	client, db, container := getTestDatabase(ctx, t)
+	defer func() {
+		if err := container.Terminate(ctx); err != nil {
+			log.Printf("failed to terminate container: %s", err)
+		}
+	}()
+	defer func() {
+		if err := client.Disconnect(ctx); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	collectionName := fmt.Sprintf("auctions_test_%d", time.Now().UnixNano())
+	collection := db.Collection(collectionName)
+	collection.Drop(ctx)
+	defer collection.Drop(ctx)
+
+	bidsCollection := db.Collection(testBidsCollectionName)
+	defer bidsCollection.Drop(ctx)
+
+	repo := NewAuctionRepositoryWithCollection(ctx, db, collectionName)
+	defer repo.Close()
+	repo.SetBidStore(newTestBidStore(db))
+
+	require.Equal(t, 720*time.Hour, repo.retention)
+
+	now := time.Now()
+	closedAt := func(age time.Duration) *int64 {
+		value := now.Add(-age).Unix()
+		return &value
+	}
+
+	auctions := []AuctionEntityMongo{
+		{Id: "auction-purge-old", Status: auction_entity.Completed, ClosedAt: closedAt(800 * time.Hour)},
+		{Id: "auction-purge-recent", Status: auction_entity.Completed, ClosedAt: closedAt(time.Hour)},
+		// Fechado antes do closed_at existir; a expiração decide
+		{Id: "auction-purge-legacy", Status: auction_entity.Completed, ExpiresAt: now.Add(-800 * time.Hour).Unix()},
+		// Leilões que não estão Completed nunca são apagados
+		{Id: "auction-purge-cancelled", Status: auction_entity.Cancelled, ExpiresAt: now.Add(-800 * time.Hour).Unix()},
+	}
+	for _, auction := range auctions {
+		auction.ProductName = "Purge Product"
+		auction.Category = "Test Category"
+		auction.Description = "Auction used to test the purge routine"
+		auction.Condition = auction_entity.New
+		auction.Timestamp = now.Add(-900 * time.Hour).Unix()
+
+		_, err := collection.InsertOne(ctx, auction)
+		require.NoError(t, err)
+
+		_, err = bidsCollection.InsertOne(ctx, bson.M{
+			"_id":        "bid-" + auction.Id,
+			"auction_id": auction.Id,
+			"amount":     100.0,
+		})
+		require.NoError(t, err)
+	}
+
+	purged, err := repo.purgeCompletedAuctions(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), purged)
+
+	for _, tc := range []struct {
+		id     string
+		exists bool
+	}{
+		{id: "auction-purge-old", exists: false},
+		{id: "auction-purge-legacy", exists: false},
+		{id: "auction-purge-recent", exists: true},
+		{id: "auction-purge-cancelled", exists: true},
+	} {
+		auctionCount, err := collection.CountDocuments(ctx, bson.M{"_id": tc.id})
+		require.NoError(t, err)
+		bidCount, err := bidsCollection.CountDocuments(ctx, bson.M{"auction_id": tc.id})
+		require.NoError(t, err)
+
+		if tc.exists {
+			require.Equal(t, int64(1), auctionCount, tc.id)
+			require.Equal(t, int64(1), bidCount, tc.id)
+		} else {
+			require.Zero(t, auctionCount, tc.id)
+			require.Zero(t, bidCount, tc.id)
+		}
+	}
+
+	// Sem nada vencido a rodada seguinte não apaga nada
+	purged, err = repo.purgeCompletedAuctions(ctx)
+	require.NoError(t, err)
+	require.Zero(t, purged)
+}
+
+func TestGetAuctionRetention(t *testing.T) {
+	testCases := []struct {
+		name     string
+		value    string
+		expected time.Duration
+	}{
+		{name: "unset disables the purge", value: "", expected: 0},
+		{name: "valid", value: "720h", expected: 720 * time.Hour},
+		{name: "invalid", value: "forever", expected: 0},
+		{name: "negative", value: "-1h", expected: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("AUCTION_RETENTION", tc.value)
+
+			require.Equal(t, tc.expected, getAuctionRetention())
+		})
+	}
+}
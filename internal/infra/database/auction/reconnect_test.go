@@ -0,0 +1,170 @@
+package auction
+
+import (
+	"context"
+	"fmt"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"io"
+	"net"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// flakyProxy repassa conexões TCP para o Mongo e pode derrubá-las, simulando
+// uma queda de rede sem parar o container (o que trocaria a porta exposta).
+type flakyProxy struct {
+	listener net.Listener
+	target   string
+
+	mutex       sync.Mutex
+	down        bool
+	connections []net.Conn
+}
+
+func newFlakyProxy(t *testing.T, target string) *flakyProxy {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	proxy := &flakyProxy{listener: listener, target: target}
+	t.Cleanup(func() {
+		listener.Close()
+		proxy.SetDown(true)
+	})
+
+	go proxy.serve()
+
+	return proxy
+}
+
+func (p *flakyProxy) Addr() string {
+	return p.listener.Addr().String()
+}
+
+func (p *flakyProxy) serve() {
+	for {
+		client, err := p.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		p.mutex.Lock()
+		if p.down {
+			p.mutex.Unlock()
+			client.Close()
+			continue
+		}
+
+		server, err := net.Dial("tcp", p.target)
+		if err != nil {
+			p.mutex.Unlock()
+			client.Close()
+			continue
+		}
+		p.connections = append(p.connections, client, server)
+		p.mutex.Unlock()
+
+		go func() {
+			io.Copy(server, client)
+			server.Close()
+		}()
+		go func() {
+			io.Copy(client, server)
+			client.Close()
+		}()
+	}
+}
+
+// SetDown(true) derruba as conexões abertas e recusa novas até SetDown(false)
+func (p *flakyProxy) SetDown(down bool) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.down = down
+	if down {
+		for _, connection := range p.connections {
+			connection.Close()
+		}
+		p.connections = nil
+	}
+}
+
+func TestCloserRecoversAfterConnectionDrop(t *testing.T) {
+	t.Setenv("AUCTION_INTERVAL", "2s")
+	t.Setenv("AUCTION_CHECK_INTERVAL", "1h")
+	t.Setenv("AUCTION_CLOSER_OP_TIMEOUT", "1s")
+	t.Setenv("MONGO_OP_TIMEOUT", "1s")
+
+	ctx := context.Background()
+
+	mongoURL, err := sharedMongoURL(ctx)
+	if err != nil {
+		t.Skipf("Skipping test: could not start MongoDB container: %v", err)
+	}
+
+	parsedURL, err := url.Parse(mongoURL)
+	require.NoError(t, err)
+
+	proxy := newFlakyProxy(t, parsedURL.Host)
+	parsedURL.Host = proxy.Addr()
+	query := parsedURL.Query()
+	query.Set("directConnection", "true")
+	query.Set("serverSelectionTimeoutMS", "500")
+	parsedURL.RawQuery = query.Encode()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(parsedURL.String()))
+	require.NoError(t, err)
+	defer client.Disconnect(ctx)
+	if err := client.Ping(ctx, nil); err != nil {
+		t.Skipf("Skipping test: MongoDB not available: %v", err)
+	}
+
+	db := client.Database("auctions_test")
+	collectionName := fmt.Sprintf("auctions_test_%d", time.Now().UnixNano())
+	collection := db.Collection(collectionName)
+	defer collection.Drop(ctx)
+
+	repo := NewAuctionRepositoryWithCollection(ctx, db, collectionName)
+	defer repo.Close()
+
+	clock := newFakeClock()
+	repo.SetClock(clock)
+
+	auctionEntity := &auction_entity.Auction{
+		Id:          "test-auction-reconnect",
+		ProductName: "Reconnect Product",
+		Category:    "Electronics",
+		Description: "This auction expires while Mongo is unreachable",
+		Condition:   auction_entity.New,
+		Status:      auction_entity.Active,
+		Timestamp:   clock.Now(),
+	}
+	if _, err := repo.CreateAuction(ctx, auctionEntity); err != nil {
+		t.Fatalf("Failed to create auction: %v", err)
+	}
+
+	clock.Advance(3 * time.Second)
+
+	// Com o Mongo fora do ar o tick falha, sem derrubar o closer
+	proxy.SetDown(true)
+	require.Error(t, repo.closeExpiredAuctions(ctx))
+
+	// Quando o Mongo volta, o mesmo client e a mesma coleção voltam a
+	// funcionar sem reconectar manualmente
+	proxy.SetDown(false)
+	require.Eventually(t, func() bool {
+		return repo.closeExpiredAuctions(ctx) == nil
+	}, 15*time.Second, 200*time.Millisecond)
+
+	var result AuctionEntityMongo
+	require.NoError(t, collection.FindOne(ctx, bson.M{"_id": "test-auction-reconnect"}).Decode(&result))
+	require.Equal(t, auction_entity.Completed, result.Status)
+}
@@ -0,0 +1,64 @@
+package auction
+
+import (
+	"context"
+	"errors"
+	"fullcycle-auction_go/configuration/logger"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+const (
+	closerRetryAttempts  = 3
+	closerRetryBaseDelay = 100 * time.Millisecond
+)
+
+// retryTransient executa operation até attempts vezes, dobrando a espera a
+// cada nova tentativa. Apenas erros transitórios do Mongo são repetidos; os
+// demais, e o cancelamento do contexto, encerram as tentativas na hora.
+func retryTransient(
+	ctx context.Context, attempts int, baseDelay time.Duration, operation func() error) error {
+	delay := baseDelay
+
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		err = operation()
+		if err == nil || !isTransientError(err) || attempt == attempts {
+			return err
+		}
+
+		logger.Warn("Transient Mongo error, retrying",
+			zap.Int("attempt", attempt),
+			zap.Duration("delay", delay),
+			zap.Error(err))
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+
+	return err
+}
+
+func isTransientError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	if mongo.IsNetworkError(err) || mongo.IsTimeout(err) {
+		return true
+	}
+
+	var labeledError mongo.LabeledError
+	if errors.As(err, &labeledError) {
+		return labeledError.HasErrorLabel("RetryableWriteError") ||
+			labeledError.HasErrorLabel("TransientTransactionError")
+	}
+
+	return false
+}
@@ -0,0 +1,95 @@
+package auction
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// flakyOperation simula uma operação na coleção que falha nas primeiras
+// chamadas e depois passa a funcionar
+type flakyOperation struct {
+	failures int
+	err      error
+	calls    int
+}
+
+func (f *flakyOperation) run() error {
+	f.calls++
+	if f.calls <= f.failures {
+		return f.err
+	}
+	return nil
+}
+
+func TestRetryTransient(t *testing.T) {
+	networkError := mongo.CommandError{Message: "connection reset", Labels: []string{"NetworkError"}}
+	retryableError := mongo.CommandError{Message: "not primary", Labels: []string{"RetryableWriteError"}}
+	permanentError := mongo.CommandError{Code: 2, Message: "bad value"}
+
+	testCases := []struct {
+		name          string
+		operation     *flakyOperation
+		expectedCalls int
+		expectedErr   error
+	}{
+		{
+			name:          "fails once with network error then succeeds",
+			operation:     &flakyOperation{failures: 1, err: networkError},
+			expectedCalls: 2,
+		},
+		{
+			name:          "retryable write error",
+			operation:     &flakyOperation{failures: 2, err: retryableError},
+			expectedCalls: 3,
+		},
+		{
+			name:          "gives up after the last attempt",
+			operation:     &flakyOperation{failures: 5, err: networkError},
+			expectedCalls: 3,
+			expectedErr:   networkError,
+		},
+		{
+			name:          "permanent error is not retried",
+			operation:     &flakyOperation{failures: 5, err: permanentError},
+			expectedCalls: 1,
+			expectedErr:   permanentError,
+		},
+		{
+			name:          "no documents is not retried",
+			operation:     &flakyOperation{failures: 5, err: mongo.ErrNoDocuments},
+			expectedCalls: 1,
+			expectedErr:   mongo.ErrNoDocuments,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := retryTransient(context.Background(), 3, time.Millisecond, tc.operation.run)
+
+			require.Equal(t, tc.expectedCalls, tc.operation.calls)
+			if tc.expectedErr == nil {
+				require.NoError(t, err)
+			} else {
+				require.Equal(t, tc.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestRetryTransientStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	operation := &flakyOperation{
+		failures: 5,
+		err:      mongo.CommandError{Message: "connection reset", Labels: []string{"NetworkError"}},
+	}
+
+	err := retryTransient(ctx, 3, time.Hour, operation.run)
+	require.Error(t, err)
+	require.Equal(t, 1, operation.calls)
+}
@@ -0,0 +1,90 @@
+package auction
+
+import (
+	"context"
+	"fmt"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/entity/bid_entity"
+	"fullcycle-auction_go/internal/infra/database/database_error"
+	"fullcycle-auction_go/internal/infra/database/decimal"
+	"fullcycle-auction_go/internal/internal_error"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type sellerStatsMongo struct {
+	Key struct {
+		Currency string `bson:"currency"`
+		Sold     bool   `bson:"sold"`
+	} `bson:"_id"`
+	Count      int64          `bson:"count"`
+	TotalValue decimal.Amount `bson:"total_value"`
+}
+
+// SellerStats agrega no Mongo os leilões concluídos do vendedor, agrupados por
+// moeda. Um leilão conta como vendido quando tem winner_user_id, gravado pelo
+// closer ou pelo compre já; leilões fechados antes desse campo existir contam
+// como não vendidos. Vendedores sem histórico retornam tudo zerado.
+func (ar *AuctionRepository) SellerStats(
+	ctx context.Context, ownerId string) (*auction_entity.SellerStats, *internal_error.InternalError) {
+	ctx, span := ar.startSpan(ctx, "SellerStats", attribute.String("auction.user_id", ownerId))
+	stats, err := ar.sellerStats(ctx, ownerId)
+	endSpan(span, err)
+
+	return stats, err
+}
+
+func (ar *AuctionRepository) sellerStats(
+	ctx context.Context, ownerId string) (*auction_entity.SellerStats, *internal_error.InternalError) {
+	ctx, cancel := ar.withOpTimeout(ctx)
+	defer cancel()
+
+	// Agrupa por moeda e por ter ou não vencedor; winner_user_id ausente
+	// não é maior que null
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"user_id":    ownerId,
+			"status":     auction_entity.Completed,
+			"deleted_at": nil,
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id": bson.M{
+				"currency": "$currency",
+				"sold":     bson.M{"$gt": bson.A{"$winner_user_id", nil}},
+			},
+			"count":       bson.M{"$sum": 1},
+			"total_value": bson.M{"$sum": "$winning_amount"},
+		}}},
+	}
+
+	cursor, err := ar.Collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Error trying to compute stats for seller = %s", ownerId), err)
+		return nil, database_error.NewInternalError(err, "Error trying to compute seller stats")
+	}
+	defer closeCursor(ctx, cursor)
+
+	var results []sellerStatsMongo
+	if err := cursor.All(ctx, &results); err != nil {
+		logger.Error(fmt.Sprintf("Error trying to decode stats for seller = %s", ownerId), err)
+		return nil, database_error.NewInternalError(err, "Error trying to compute seller stats")
+	}
+
+	stats := &auction_entity.SellerStats{TotalValue: map[string]bid_entity.Amount{}}
+	for _, result := range results {
+		if !result.Key.Sold {
+			stats.Unsold += result.Count
+			continue
+		}
+		stats.Sold += result.Count
+
+		// Leilões antigos sem moeda são da DefaultCurrency
+		currency := bid_entity.CurrencyOrDefault(result.Key.Currency)
+		stats.TotalValue[currency] = stats.TotalValue[currency].Add(bid_entity.Amount(result.TotalValue))
+	}
+
+	return stats, nil
+}
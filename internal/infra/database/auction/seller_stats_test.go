@@ -0,0 +1,100 @@
+package auction
+
+import (
+	"context"
+	"fmt"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/entity/bid_entity"
+	"fullcycle-auction_go/internal/infra/database/decimal"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestSellerStats(t *testing.T) {
+	t.Setenv("AUCTION_INTERVAL", "2s")
+	t.Setenv("AUCTION_CHECK_INTERVAL", "1h")
+
+	ctx := context.Background()
+	db := getSharedTestDatabase(ctx, t)
+
+	collectionName := fmt.Sprintf("auctions_test_%d", time.Now().UnixNano())
+	collection := db.Collection(collectionName)
+	collection.Drop(ctx)
+	defer collection.Drop(ctx)
+
+	repo := NewAuctionRepositoryWithCollection(ctx, db, collectionName)
+	defer repo.Close()
+	repo.SetBidStore(newTestBidStore(db))
+
+	clock := newFakeClock()
+	repo.SetClock(clock)
+
+	sellerId := uuid.New().String()
+	createAuction := func(userId string, reservePrice float64, currency string) string {
+		auctionEntity := &auction_entity.Auction{
+			Id:           uuid.New().String(),
+			UserId:       userId,
+			ProductName:  "Seller Product",
+			Category:     "Electronics",
+			Description:  "Auction used by the seller stats test",
+			Condition:    auction_entity.Used,
+			Status:       auction_entity.Active,
+			Timestamp:    clock.Now(),
+			ReservePrice: reservePrice,
+			Currency:     currency,
+		}
+		_, err := repo.CreateAuction(ctx, auctionEntity)
+		require.Nil(t, err)
+		return auctionEntity.Id
+	}
+
+	bidsCollection := db.Collection(testBidsCollectionName)
+	var bidIds []string
+	defer func() { bidsCollection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": bidIds}}) }()
+
+	placeBid := func(auctionId string, cents int64, currency string) {
+		bid := auctionBidMongo{
+			Id:        uuid.New().String(),
+			UserId:    uuid.New().String(),
+			AuctionId: auctionId,
+			Amount:    decimal.Amount(bid_entity.AmountFromCents(cents)),
+			Timestamp: clock.Now().Unix(),
+			Currency:  currency,
+		}
+		_, err := bidsCollection.InsertOne(ctx, bid)
+		require.NoError(t, err)
+		bidIds = append(bidIds, bid.Id)
+	}
+
+	placeBid(createAuction(sellerId, 0, ""), 100_00, "BRL")
+	placeBid(createAuction(sellerId, 0, ""), 250_50, "BRL")
+	createAuction(sellerId, 0, "")
+	placeBid(createAuction(sellerId, 500, ""), 200_00, "BRL")
+	placeBid(createAuction(sellerId, 0, "USD"), 50_00, "USD")
+	placeBid(createAuction(uuid.New().String(), 0, ""), 900_00, "BRL")
+
+	clock.Advance(3 * time.Second)
+	require.NoError(t, repo.closeExpiredAuctions(ctx))
+
+	// Ainda ativo, fica fora das estatísticas
+	placeBid(createAuction(sellerId, 0, ""), 700_00, "BRL")
+
+	stats, err := repo.SellerStats(ctx, sellerId)
+	require.Nil(t, err)
+	require.Equal(t, int64(3), stats.Sold)
+	require.Equal(t, int64(2), stats.Unsold)
+	require.Equal(t, map[string]bid_entity.Amount{
+		"BRL": bid_entity.AmountFromCents(350_50),
+		"USD": bid_entity.AmountFromCents(50_00),
+	}, stats.TotalValue)
+
+	stats, err = repo.SellerStats(ctx, uuid.New().String())
+	require.Nil(t, err)
+	require.Zero(t, stats.Sold)
+	require.Zero(t, stats.Unsold)
+	require.Empty(t, stats.TotalValue)
+}
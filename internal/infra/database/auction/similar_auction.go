@@ -0,0 +1,130 @@
+package auction
+
+import (
+	"context"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/infra/database/database_error"
+	"fullcycle-auction_go/internal/internal_error"
+	"strings"
+	"unicode"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// similarNameCharsPerEdit define a tolerância da comparação: uma edição
+// (inserção, remoção ou troca de caractere) a cada tantos caracteres do nome
+// mais longo. "iphone 13 pro" e "iphone 13 pr0" ficam parecidos, "iphone" e
+// "ipad" não.
+const similarNameCharsPerEdit = 8
+
+// FindSimilarAuctions lista os leilões ativos da categoria cujo nome de
+// produto é quase igual a productName, para avisar o administrador de um
+// possível anúncio repetido. Os nomes são comparados sem diferenciar
+// maiúsculas, pontuação e espaços repetidos. A comparação é feita aqui, e não
+// no Mongo, então todos os leilões ativos da categoria são lidos.
+func (ar *AuctionRepository) FindSimilarAuctions(
+	ctx context.Context,
+	productName, category string) ([]auction_entity.Auction, *internal_error.InternalError) {
+	ctx, span := ar.startSpan(ctx, "FindSimilarAuctions", attribute.String("auction.category", category))
+	auctions, err := ar.findSimilarAuctions(ctx, productName, category)
+	span.SetAttributes(attribute.Int("auctions.count", len(auctions)))
+	endSpan(span, err)
+
+	return auctions, err
+}
+
+func (ar *AuctionRepository) findSimilarAuctions(
+	ctx context.Context,
+	productName, category string) ([]auction_entity.Auction, *internal_error.InternalError) {
+	normalizedName := normalizeProductName(productName)
+	if normalizedName == "" {
+		return []auction_entity.Auction{}, nil
+	}
+
+	ctx, cancel := ar.withOpTimeout(ctx)
+	defer cancel()
+
+	filter := bson.M{
+		"status":     auction_entity.Active,
+		"category":   category,
+		"deleted_at": nil,
+	}
+	findOptions := options.Find().SetSort(bson.D{{Key: "timestamp", Value: 1}, {Key: "_id", Value: 1}})
+
+	cursor, err := ar.Collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		logger.Error("Error trying to find similar auctions", err)
+		return nil, database_error.NewInternalError(err, "Error trying to find similar auctions")
+	}
+	defer closeCursor(ctx, cursor)
+
+	auctions := make([]auction_entity.Auction, 0)
+	for nextDocument(ctx, cursor) {
+		var auctionEntityMongo AuctionEntityMongo
+		if err := cursor.Decode(&auctionEntityMongo); err != nil {
+			logger.Error("Error trying to decode similar auction", err)
+			return nil, database_error.NewInternalError(err, "Error trying to find similar auctions")
+		}
+
+		if similarProductNames(normalizedName, normalizeProductName(auctionEntityMongo.ProductName)) {
+			auctions = append(auctions, *ar.toAuctionEntity(auctionEntityMongo))
+		}
+	}
+
+	if err := cursorErr(ctx, cursor); err != nil {
+		logger.Error("Error trying to read similar auctions", err)
+		return nil, database_error.NewInternalError(err, "Error trying to find similar auctions")
+	}
+
+	return auctions, nil
+}
+
+// normalizeProductName deixa o nome em minúsculas, troca pontuação por espaço
+// e junta os espaços repetidos: "iPhone-13  Pro!" vira "iphone 13 pro"
+func normalizeProductName(productName string) string {
+	normalized := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return ' '
+	}, productName)
+
+	return strings.Join(strings.Fields(normalized), " ")
+}
+
+func similarProductNames(a, b string) bool {
+	if a == b {
+		return true
+	}
+
+	first, second := []rune(a), []rune(b)
+	longest := max(len(first), len(second))
+
+	return editDistance(first, second)*similarNameCharsPerEdit <= longest
+}
+
+// editDistance é a distância de Levenshtein entre a e b
+func editDistance(a, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(b)]
+}
@@ -0,0 +1,71 @@
+package auction
+
+import (
+	"context"
+	"fmt"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindSimilarAuctions(t *testing.T) {
+	t.Setenv("AUCTION_INTERVAL", "1h")
+	t.Setenv("AUCTION_CHECK_INTERVAL", "1h")
+
+	ctx := context.Background()
+	db := getSharedTestDatabase(ctx, t)
+
+	collectionName := fmt.Sprintf("auctions_test_%d", time.Now().UnixNano())
+	collection := db.Collection(collectionName)
+	collection.Drop(ctx)
+	defer collection.Drop(ctx)
+
+	repo := NewAuctionRepositoryWithCollection(ctx, db, collectionName)
+	defer repo.Close()
+
+	now := time.Now()
+	auctions := []*auction_entity.Auction{
+		{Id: "same-name", ProductName: "Apple iPhone 13 Pro", Category: "Electronics", Status: auction_entity.Active},
+		{Id: "case-and-spaces", ProductName: "  apple   IPHONE 13 pro ", Category: "Electronics", Status: auction_entity.Active},
+		{Id: "punctuation", ProductName: "Apple iPhone-13 Pro!", Category: "Electronics", Status: auction_entity.Active},
+		{Id: "typo", ProductName: "Aple iPhone 13 Pro", Category: "Electronics", Status: auction_entity.Active},
+		{Id: "other-product", ProductName: "Samsung Galaxy S21", Category: "Electronics", Status: auction_entity.Active},
+		{Id: "other-model", ProductName: "Apple iPad Air", Category: "Electronics", Status: auction_entity.Active},
+		{Id: "longer-name", ProductName: "Apple iPhone 13 Pro Max 256GB", Category: "Electronics", Status: auction_entity.Active},
+		{Id: "other-category", ProductName: "Apple iPhone 13 Pro", Category: "Collectibles", Status: auction_entity.Active},
+		{Id: "cancelled", ProductName: "Apple iPhone 13 Pro", Category: "Electronics", Status: auction_entity.Cancelled},
+	}
+	for i, auctionEntity := range auctions {
+		auctionEntity.Description = "Auction used by the similar auctions test"
+		auctionEntity.Condition = auction_entity.Used
+		auctionEntity.Timestamp = now.Add(time.Duration(i) * time.Second)
+		if _, err := repo.CreateAuction(ctx, auctionEntity); err != nil {
+			t.Fatalf("Failed to create auction: %v", err)
+		}
+	}
+
+	similar, err := repo.FindSimilarAuctions(ctx, "apple iphone 13 pro", "Electronics")
+	require.Nil(t, err)
+
+	var ids []string
+	for _, auctionEntity := range similar {
+		ids = append(ids, auctionEntity.Id)
+	}
+	require.Equal(t, []string{"same-name", "case-and-spaces", "punctuation", "typo"}, ids)
+
+	similar, err = repo.FindSimilarAuctions(ctx, "Nintendo Switch", "Electronics")
+	require.Nil(t, err)
+	require.Empty(t, similar)
+
+	similar, err = repo.FindSimilarAuctions(ctx, " !? ", "Electronics")
+	require.Nil(t, err)
+	require.Empty(t, similar)
+}
+
+func TestNormalizeProductName(t *testing.T) {
+	require.Equal(t, "iphone 13 pro", normalizeProductName("  iPhone-13\tPRO!! "))
+	require.Equal(t, "relógio ômega", normalizeProductName("Relógio   ÔMEGA"))
+	require.Equal(t, "", normalizeProductName("--- ..."))
+}
@@ -0,0 +1,89 @@
+package auction
+
+import (
+	"context"
+	"encoding/json"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/infra/database/database_error"
+	"fullcycle-auction_go/internal/internal_error"
+	"io"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// auctionSnapshotHeader é a primeira linha do snapshot
+type auctionSnapshotHeader struct {
+	SnapshotAt string `json:"snapshot_at"`
+	Collection string `json:"collection"`
+}
+
+// SnapshotAuctions escreve em w o estado atual de todos os leilões, inclusive
+// os apagados: uma linha de cabeçalho com o horário do snapshot e depois um
+// documento por linha em Extended JSON canônico, exatamente como está no
+// Mongo, para que o estado possa ser restaurado sem perder tipos como o
+// Decimal128. Junto com a auditoria, permite reconstruir o histórico a partir
+// do snapshot em vez do começo.
+//
+// A leitura usa um único cursor em ordem de _id, então cada leilão aparece uma
+// vez. O Mongo não isola o cursor das escritas: um leilão alterado durante a
+// leitura pode sair com o estado novo, e os eventos da auditoria posteriores
+// ao snapshot_at devem ser reaplicados de forma idempotente.
+func (ar *AuctionRepository) SnapshotAuctions(ctx context.Context, w io.Writer) *internal_error.InternalError {
+	ctx, span := ar.startSpan(ctx, "SnapshotAuctions")
+	count, err := ar.snapshotAuctions(ctx, w)
+	span.SetAttributes(attribute.Int("auctions.count", count))
+	endSpan(span, err)
+
+	return err
+}
+
+func (ar *AuctionRepository) snapshotAuctions(ctx context.Context, w io.Writer) (int, *internal_error.InternalError) {
+	header := auctionSnapshotHeader{
+		SnapshotAt: ar.now().UTC().Format(time.RFC3339Nano),
+		Collection: ar.Collection.Name(),
+	}
+	if err := json.NewEncoder(w).Encode(header); err != nil {
+		logger.Error("Error trying to write auctions snapshot", err)
+		return 0, internal_error.NewInternalServerError("Error trying to write auctions snapshot")
+	}
+
+	findOptions := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}})
+
+	cursor, err := ar.Collection.Find(ctx, bson.M{}, findOptions)
+	if err != nil {
+		logger.Error("Error trying to find auctions to snapshot", err)
+		return 0, database_error.NewInternalError(err, "Error trying to snapshot auctions")
+	}
+	defer closeCursor(ctx, cursor)
+
+	count := 0
+	for nextDocument(ctx, cursor) {
+		var document bson.Raw
+		if err := cursor.Decode(&document); err != nil {
+			logger.Error("Error trying to decode auction to snapshot", err)
+			return count, database_error.NewInternalError(err, "Error trying to snapshot auctions")
+		}
+
+		line, err := bson.MarshalExtJSON(document, true, false)
+		if err != nil {
+			logger.Error("Error trying to encode auction snapshot", err)
+			return count, internal_error.NewInternalServerError("Error trying to write auctions snapshot")
+		}
+
+		if _, err := w.Write(append(line, '\n')); err != nil {
+			logger.Error("Error trying to write auctions snapshot", err)
+			return count, internal_error.NewInternalServerError("Error trying to write auctions snapshot")
+		}
+		count++
+	}
+
+	if err := cursorErr(ctx, cursor); err != nil {
+		logger.Error("Error trying to read auctions to snapshot", err)
+		return count, database_error.NewInternalError(err, "Error trying to snapshot auctions")
+	}
+
+	return count, nil
+}
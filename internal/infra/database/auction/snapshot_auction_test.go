@@ -0,0 +1,78 @@
+package auction
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestSnapshotAuctions(t *testing.T) {
+	t.Setenv("AUCTION_INTERVAL", "1h")
+	t.Setenv("AUCTION_CHECK_INTERVAL", "1h")
+
+	ctx := context.Background()
+	db := getSharedTestDatabase(ctx, t)
+
+	collectionName := fmt.Sprintf("auctions_test_%d", time.Now().UnixNano())
+	collection := db.Collection(collectionName)
+	collection.Drop(ctx)
+	defer collection.Drop(ctx)
+
+	repo := NewAuctionRepositoryWithCollection(ctx, db, collectionName)
+	defer repo.Close()
+
+	clock := newFakeClock()
+	repo.SetClock(clock)
+
+	auctions := []*auction_entity.Auction{
+		{Id: "snapshot-c", ProductName: "Snapshot C", ReservePrice: 150.5},
+		{Id: "snapshot-a", ProductName: "Snapshot A"},
+		{Id: "snapshot-b", ProductName: "Snapshot B"},
+	}
+	for _, auctionEntity := range auctions {
+		auctionEntity.Category = "Electronics"
+		auctionEntity.Description = "Auction used by the snapshot test"
+		auctionEntity.Condition = auction_entity.New
+		auctionEntity.Status = auction_entity.Active
+		auctionEntity.Timestamp = clock.Now()
+		_, err := repo.CreateAuction(ctx, auctionEntity)
+		require.Nil(t, err)
+	}
+	// Leilões apagados também fazem parte do estado
+	require.Nil(t, repo.SoftDelete(ctx, "snapshot-b"))
+
+	var output bytes.Buffer
+	require.Nil(t, repo.SnapshotAuctions(ctx, &output))
+
+	scanner := bufio.NewScanner(&output)
+	require.True(t, scanner.Scan())
+
+	var header auctionSnapshotHeader
+	require.NoError(t, json.Unmarshal(scanner.Bytes(), &header))
+	require.Equal(t, clock.Now().UTC().Format(time.RFC3339Nano), header.SnapshotAt)
+	require.Equal(t, collectionName, header.Collection)
+
+	var documents []AuctionEntityMongo
+	for scanner.Scan() {
+		var document AuctionEntityMongo
+		require.NoError(t, bson.UnmarshalExtJSON(scanner.Bytes(), true, &document))
+		documents = append(documents, document)
+	}
+	require.NoError(t, scanner.Err())
+
+	require.Len(t, documents, 3)
+	require.Equal(t, "snapshot-a", documents[0].Id)
+	require.Equal(t, "snapshot-b", documents[1].Id)
+	require.NotNil(t, documents[1].DeletedAt)
+	require.Equal(t, "snapshot-c", documents[2].Id)
+	require.Equal(t, 150.5, documents[2].ReservePrice)
+	require.Equal(t, auction_entity.Active, documents[2].Status)
+}
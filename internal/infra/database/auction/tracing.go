@@ -0,0 +1,54 @@
+package auction
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/internal_error"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "fullcycle-auction_go/internal/infra/database/auction"
+
+// tracerHolder mantém o tipo guardado no atomic.Value constante, já que cada
+// provider devolve uma implementação diferente de trace.Tracer
+type tracerHolder struct {
+	tracer trace.Tracer
+}
+
+// defaultTracer usa o provider global do OpenTelemetry, que é no-op enquanto
+// a aplicação não configura um exporter.
+func defaultTracer() tracerHolder {
+	return tracerHolder{tracer: otel.Tracer(tracerName)}
+}
+
+// SetTracerProvider troca o provider usado para criar os spans do repositório.
+func (ar *AuctionRepository) SetTracerProvider(provider trace.TracerProvider) {
+	ar.tracer.Store(tracerHolder{tracer: provider.Tracer(tracerName)})
+}
+
+func (ar *AuctionRepository) startSpan(
+	ctx context.Context, operation string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	holder := ar.tracer.Load().(tracerHolder)
+	return holder.tracer.Start(ctx, "AuctionRepository."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attributes...))
+}
+
+// endSpan registra o resultado da operação e encerra o span.
+func endSpan(span trace.Span, err *internal_error.InternalError) {
+	defer span.End()
+
+	if err == nil {
+		span.SetAttributes(attribute.String("outcome", "success"))
+		return
+	}
+
+	span.SetAttributes(
+		attribute.String("outcome", "error"),
+		attribute.String("error.code", err.Code))
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Message)
+}
@@ -0,0 +1,77 @@
+package auction
+
+import (
+	"context"
+	"fmt"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestCreateAuctionSpan(t *testing.T) {
+	t.Setenv("AUCTION_CHECK_INTERVAL", "1h")
+
+	ctx := context.Background()
+
+	client, db, container := getTestDatabase(ctx, t)
+	defer func() {
+		if err := container.Terminate(ctx); err != nil {
+			log.Printf("failed to terminate container: %s", err)
+		}
+	}()
+	defer func() {
+		if err := client.Disconnect(ctx); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	collectionName := fmt.Sprintf("auctions_test_%d", time.Now().UnixNano())
+	collection := db.Collection(collectionName)
+	collection.Drop(ctx)
+	defer collection.Drop(ctx)
+
+	repo := NewAuctionRepositoryWithCollection(ctx, db, collectionName)
+	defer repo.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	repo.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	auctionEntity := &auction_entity.Auction{
+		Id:          "test-auction-span",
+		ProductName: "Traced Product",
+		Category:    "Electronics",
+		Description: "This auction is created inside a span",
+		Condition:   auction_entity.New,
+		Status:      auction_entity.Active,
+		Timestamp:   time.Now(),
+	}
+
+	_, err := repo.CreateAuction(ctx, auctionEntity)
+	require.Nil(t, err)
+
+	_, err = repo.CreateAuction(ctx, auctionEntity)
+	require.NotNil(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+
+	for _, span := range spans {
+		require.Equal(t, "AuctionRepository.CreateAuction", span.Name())
+		require.Contains(t, span.Attributes(), attribute.String("auction.id", "test-auction-span"))
+	}
+
+	require.Contains(t, spans[0].Attributes(), attribute.String("outcome", "success"))
+	require.Equal(t, codes.Unset, spans[0].Status().Code)
+
+	require.Contains(t, spans[1].Attributes(), attribute.String("outcome", "error"))
+	require.Equal(t, codes.Error, spans[1].Status().Code)
+	require.Len(t, spans[1].Events(), 1)
+	require.Equal(t, "exception", spans[1].Events()[0].Name)
+}
@@ -0,0 +1,484 @@
+package auction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/infra/database/database_error"
+	"fullcycle-auction_go/internal/internal_error"
+	"maps"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+func (ar *AuctionRepository) ReopenAuction(
+	ctx context.Context, id string, newExpiresAt time.Time) *internal_error.InternalError {
+	if !newExpiresAt.After(ar.now()) {
+		return internal_error.NewBadRequestError("new expiration must be in the future")
+	}
+
+	ctx, cancel := ar.withOpTimeout(ctx)
+	defer cancel()
+
+	auctionEntity, err := ar.FindAuctionById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	switch auctionEntity.Status {
+	case auction_entity.Completed:
+	case auction_entity.Cancelled:
+		return internal_error.NewBadRequestError("cancelled auctions cannot be reopened")
+	case auction_entity.Scheduled:
+		return internal_error.NewBadRequestError("auction has not started yet").
+			WithCode(internal_error.CodeAuctionNotActive)
+	default:
+		return internal_error.NewBadRequestError("auction is already active")
+	}
+
+	filter := bson.M{
+		"status":     auction_entity.Completed,
+		"deleted_at": nil,
+	}
+	// Sem o closed_at o closer volta a fechar o leilão na nova expiração, e o
+	// leilão reaberto ganha de novo todo o limite de extensão. A reserva e o
+	// vencedor são avaliados outra vez no novo fechamento
+	update := bson.M{
+		"$set": bson.M{
+			"status":     auction_entity.Active,
+			"expires_at": newExpiresAt.Unix(),
+		},
+		"$unset": bson.M{
+			"closed_at":                 "",
+			"extended_by":               "",
+			"reserve_met":               "",
+			"winning_bid_id":            "",
+			"winner_user_id":            "",
+			"winning_amount":            "",
+			"no_bids":                   "",
+			"winner_resolution_pending": "",
+		},
+	}
+
+	if err := ar.updateVersioned(ctx, id, auctionEntity.Version, filter, update, "reopen"); err != nil {
+		return err
+	}
+
+	ar.recordTransition(ctx, id, statusRef(auction_entity.Completed), auction_entity.Active,
+		auditActor(auctionEntity.UserId), "reopened")
+
+	logger.Info("Auction reopened",
+		zap.String("auction_id", id),
+		zap.Int64("expires_at", newExpiresAt.Unix()))
+
+	return nil
+}
+
+// ExtendAuction adia a expiração de um leilão ativo em extra. A soma das
+// extensões de um leilão não passa de MAX_AUCTION_EXTENSION.
+func (ar *AuctionRepository) ExtendAuction(
+	ctx context.Context, id string, extra time.Duration) *internal_error.InternalError {
+	if extra < time.Second {
+		return internal_error.NewBadRequestError("extension must be at least one second")
+	}
+
+	ctx, cancel := ar.withOpTimeout(ctx)
+	defer cancel()
+
+	var auctionEntityMongo AuctionEntityMongo
+	err := ar.Collection.FindOne(ctx, bson.M{"_id": id, "deleted_at": nil}).Decode(&auctionEntityMongo)
+	if err != nil {
+		if database_error.IsNotFound(err) {
+			return internal_error.NewNotFoundError(
+				fmt.Sprintf("Auction not found with this id = %s", id)).
+				WithCode(internal_error.CodeAuctionNotFound)
+		}
+
+		logger.Error(fmt.Sprintf("Error trying to find auction to extend = %s", id), err)
+		return database_error.NewInternalError(err, "Error trying to extend auction")
+	}
+
+	expiresAt := ar.storedExpiresAt(auctionEntityMongo)
+	if auctionEntityMongo.Status != auction_entity.Active || !expiresAt.After(ar.now()) {
+		return internal_error.NewBadRequestError("auction is not active").
+			WithCode(internal_error.CodeAuctionNotActive)
+	}
+
+	extraSeconds := int64(extra / time.Second)
+	if time.Duration(auctionEntityMongo.ExtendedBy+extraSeconds)*time.Second > ar.maxAuctionExtension {
+		return internal_error.NewBadRequestError(
+			fmt.Sprintf("auction cannot be extended by more than %s", ar.maxAuctionExtension))
+	}
+
+	// A versão lida entra no filtro para que extensões concorrentes não
+	// passem do limite nem sobrescrevam uma à outra
+	filter := bson.M{
+		"status":     auction_entity.Active,
+		"deleted_at": nil,
+		"closed_at":  nil,
+	}
+
+	newExpiresAt := expiresAt.Add(time.Duration(extraSeconds) * time.Second)
+	update := bson.M{
+		"$set": bson.M{"expires_at": newExpiresAt.Unix()},
+		"$inc": bson.M{"extended_by": extraSeconds},
+	}
+
+	if err := ar.updateVersioned(ctx, id, auctionEntityMongo.Version, filter, update, "extend"); err != nil {
+		return err
+	}
+
+	logger.Info("Auction extended",
+		zap.String("auction_id", id),
+		zap.Int64("expires_at", newExpiresAt.Unix()))
+
+	return nil
+}
+
+// CancelAuction retira do ar um leilão ativo, ou agendado, que ainda não
+// recebeu lances. Leilões com lances exigiriam devolução aos participantes, o
+// que não é tratado aqui, então são recusados. O closer ignora leilões
+// cancelados.
+func (ar *AuctionRepository) CancelAuction(
+	ctx context.Context, id string, reason string) *internal_error.InternalError {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return internal_error.NewBadRequestError("cancel reason is required")
+	}
+
+	ctx, cancel := ar.withOpTimeout(ctx)
+	defer cancel()
+
+	auctionEntity, err := ar.FindAuctionById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if !editableStatus(auctionEntity.Status) {
+		return internal_error.NewBadRequestError("auction is not active").
+			WithCode(internal_error.CodeAuctionNotActive)
+	}
+
+	bidCount, countErr := ar.countBids(ctx, id)
+	if countErr != nil {
+		logger.Error(fmt.Sprintf("Error trying to count bids of auction = %s", id), countErr)
+		return internal_error.NewInternalServerError("Error trying to cancel auction")
+	}
+
+	if bidCount > 0 {
+		return internal_error.NewBadRequestError("auction already has bids")
+	}
+
+	// Um lance gravado depois da contagem é desfeito pelo repositório de
+	// lances ao ver que o leilão deixou de estar ativo
+	filter := bson.M{
+		"status":     auctionEntity.Status,
+		"deleted_at": nil,
+		"closed_at":  nil,
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"status":        auction_entity.Cancelled,
+			"cancel_reason": reason,
+			"cancelled_at":  ar.now().Unix(),
+		},
+	}
+
+	// Se o closer fechou o leilão entre a leitura e a escrita, a versão mudou
+	if err := ar.updateVersioned(ctx, id, auctionEntity.Version, filter, update, "cancel"); err != nil {
+		return err
+	}
+
+	ar.recordTransition(ctx, id, statusRef(auctionEntity.Status), auction_entity.Cancelled,
+		auditActor(auctionEntity.UserId), reason)
+
+	logger.WithContext(ctx).Info("Auction cancelled",
+		zap.String("auction_id", id),
+		zap.String("reason", reason))
+
+	return nil
+}
+
+// CloseAuctions fecha na hora os leilões ativos da lista, por exemplo anúncios
+// fraudulentos, e retorna quantos foram fechados. Usa o mesmo fechamento do
+// closer, um documento por vez, para saber exatamente quais leilões mudaram de
+// status e disparar os mesmos callbacks; ids inexistentes ou de leilões que
+// não estão ativos são ignorados.
+func (ar *AuctionRepository) CloseAuctions(
+	ctx context.Context, ids []string) (int64, *internal_error.InternalError) {
+	uniqueIds := make([]string, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			uniqueIds = append(uniqueIds, id)
+		}
+	}
+
+	if len(uniqueIds) == 0 {
+		return 0, nil
+	}
+
+	filter := bson.M{
+		"_id":        bson.M{"$in": uniqueIds},
+		"status":     auction_entity.Active,
+		"deleted_at": nil,
+		"closed_at":  nil,
+	}
+
+	return ar.forceCloseAuctions(ctx, filter, int64(len(uniqueIds)), "forced", "forced")
+}
+
+// forceCloseAuctions fecha até limit leilões do filtro fora do tick do closer,
+// contando nas métricas com metricLabel e disparando os callbacks como no
+// fechamento por expiração. reason vai para o histórico dos leilões.
+func (ar *AuctionRepository) forceCloseAuctions(
+	ctx context.Context,
+	filter bson.M,
+	limit int64,
+	reason, metricLabel string) (int64, *internal_error.InternalError) {
+	log := logger.WithContext(ctx)
+
+	closedIds, err := ar.claimAuctions(ctx, filter, ar.now().Unix(), limit, reason)
+	ar.metrics.ClosedAuctions.WithLabelValues(metricLabel).Add(float64(len(closedIds)))
+
+	if len(closedIds) > 0 {
+		log.Info("Auctions force-closed",
+			zap.String("reason", reason),
+			zap.Strings("auction_ids", closedIds))
+	}
+
+	// Os leilões já fechados disparam o callback mesmo quando o lote parou
+	// no meio
+	if onAuctionClosed := ar.auctionClosedCallback(); onAuctionClosed != nil {
+		for _, auctionId := range closedIds {
+			onAuctionClosed(ctx, auctionId)
+		}
+	}
+
+	if err != nil {
+		log.Error("Error trying to force-close auctions", err)
+		return int64(len(closedIds)), database_error.NewInternalError(err, "Error trying to close auctions")
+	}
+
+	return int64(len(closedIds)), nil
+}
+
+// TransferAuction passa um leilão ativo para outro dono, por exemplo na
+// fusão de contas. O novo dono precisa existir e respeitar o
+// MAX_ACTIVE_AUCTIONS_PER_USER como se tivesse criado o leilão.
+func (ar *AuctionRepository) TransferAuction(
+	ctx context.Context, auctionId, newOwnerId string) *internal_error.InternalError {
+	if strings.TrimSpace(newOwnerId) == "" {
+		return internal_error.NewBadRequestError("new owner is required")
+	}
+
+	ctx, cancel := ar.withOpTimeout(ctx)
+	defer cancel()
+
+	auctionEntity, err := ar.FindAuctionById(ctx, auctionId)
+	if err != nil {
+		return err
+	}
+
+	if auctionEntity.Status != auction_entity.Active {
+		return internal_error.NewBadRequestError("auction is not active").
+			WithCode(internal_error.CodeAuctionNotActive)
+	}
+
+	if auctionEntity.UserId == newOwnerId {
+		return internal_error.NewBadRequestError("auction already belongs to this user")
+	}
+
+	if ar.userRepository == nil {
+		return internal_error.NewInternalServerError("auction transfer is not available")
+	}
+
+	if _, err := ar.userRepository.FindUserById(ctx, newOwnerId); err != nil {
+		return err
+	}
+
+	if err := ar.checkActiveAuctionsLimit(ctx, newOwnerId, 1); err != nil {
+		return err
+	}
+
+	filter := bson.M{
+		"status":     auction_entity.Active,
+		"deleted_at": nil,
+		"closed_at":  nil,
+	}
+	update := bson.M{
+		"$set": bson.M{"user_id": newOwnerId},
+	}
+
+	if err := ar.updateVersioned(ctx, auctionId, auctionEntity.Version, filter, update, "transfer"); err != nil {
+		return err
+	}
+
+	// Transferências e criações simultâneas para o mesmo dono passam juntas
+	// pela contagem; quem deixa o dono acima do limite devolve o leilão
+	exceeds, err := ar.exceedsActiveAuctionsLimit(ctx, newOwnerId)
+	if err != nil || exceeds {
+		_, revertErr := ar.Collection.UpdateOne(ctx,
+			bson.M{"_id": auctionId, "user_id": newOwnerId},
+			bson.M{
+				"$set": bson.M{"user_id": auctionEntity.UserId},
+				"$inc": bson.M{"version": 1},
+			})
+		if revertErr != nil {
+			logger.WithContext(ctx).Error("Error trying to undo the auction transfer over the active auctions limit", revertErr,
+				zap.String("auction_id", auctionId))
+		}
+		if err != nil {
+			return err
+		}
+		return ar.activeAuctionsLimitError()
+	}
+
+	logger.WithContext(ctx).Info("Auction transferred",
+		zap.String("auction_id", auctionId),
+		zap.String("previous_owner_id", auctionEntity.UserId),
+		zap.String("new_owner_id", newOwnerId))
+
+	return nil
+}
+
+func (ar *AuctionRepository) UpdateAuction(
+	ctx context.Context, id string, patch auction_entity.AuctionPatch) *internal_error.InternalError {
+	ctx, cancel := ar.withOpTimeout(ctx)
+	defer cancel()
+
+	auctionEntity, err := ar.FindAuctionById(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if !editableStatus(auctionEntity.Status) {
+		return internal_error.NewBadRequestError("auction is not active").
+			WithCode(internal_error.CodeAuctionNotActive)
+	}
+
+	bidCount, countErr := ar.countBids(ctx, id)
+	if countErr != nil {
+		logger.Error(fmt.Sprintf("Error trying to count bids of auction = %s", id), countErr)
+		return internal_error.NewInternalServerError("Error trying to update auction")
+	}
+
+	if bidCount > 0 {
+		return internal_error.NewBadRequestError("auction already has bids")
+	}
+
+	patch, err = ar.rules.PreparePatch(patch)
+	if err != nil {
+		return err
+	}
+
+	fields := bson.M{}
+	if patch.ProductName != nil {
+		auctionEntity.ProductName = *patch.ProductName
+		fields["product_name"] = *patch.ProductName
+	}
+	if patch.Category != nil {
+		auctionEntity.Category = *patch.Category
+		fields["category"] = *patch.Category
+	}
+	if patch.Description != nil {
+		auctionEntity.Description = *patch.Description
+		fields["description"] = *patch.Description
+	}
+	if patch.Condition != nil {
+		auctionEntity.Condition = *patch.Condition
+		fields["condition"] = *patch.Condition
+	}
+
+	if len(fields) == 0 {
+		return internal_error.NewBadRequestError("no fields to update")
+	}
+
+	if err := auctionEntity.Validate(); err != nil {
+		return err
+	}
+
+	filter := bson.M{
+		"status":     auctionEntity.Status,
+		"deleted_at": nil,
+	}
+
+	return ar.updateVersioned(ctx, id, auctionEntity.Version, filter, bson.M{"$set": fields}, "update")
+}
+
+// editableStatus indica se o leilão ainda pode ser editado ou cancelado:
+// ativo ou agendado, antes de abrir
+func editableStatus(status auction_entity.AuctionStatus) bool {
+	return status == auction_entity.Active || status == auction_entity.Scheduled
+}
+
+// updateVersioned grava update somente se o leilão ainda estiver na versão
+// lida e incrementa version, evitando que atualizações concorrentes
+// sobrescrevam uma à outra. Quando nada casa com o filtro o leilão é relido
+// para diferenciar um leilão removido, um leilão que deixou de estar ativo
+// (por exemplo, fechado pelo closer no meio da operação) e uma versão
+// desatualizada. filter e update não são alterados.
+func (ar *AuctionRepository) updateVersioned(
+	ctx context.Context,
+	id string,
+	version int,
+	filter bson.M,
+	update bson.M,
+	action string) *internal_error.InternalError {
+	ctx, cancel := ar.withOpTimeout(ctx)
+	defer cancel()
+
+	versionedFilter := maps.Clone(filter)
+	versionedFilter["_id"] = id
+	versionedFilter["version"] = versionFilter(version)
+
+	versionedUpdate := maps.Clone(update)
+	inc, _ := update["$inc"].(bson.M)
+	inc = maps.Clone(inc)
+	if inc == nil {
+		inc = bson.M{}
+	}
+	inc["version"] = 1
+	versionedUpdate["$inc"] = inc
+
+	err := ar.Collection.FindOneAndUpdate(ctx, versionedFilter, versionedUpdate).Err()
+	if err == nil {
+		return nil
+	}
+
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		logger.Error(fmt.Sprintf("Error trying to %s auction = %s", action, id), err)
+		return database_error.NewInternalError(err, fmt.Sprintf("Error trying to %s auction", action))
+	}
+
+	current, findErr := ar.FindAuctionById(ctx, id)
+	if findErr != nil {
+		return findErr
+	}
+
+	expectedStatus, hasStatus := filter["status"].(auction_entity.AuctionStatus)
+	if hasStatus && current.Status != expectedStatus && !editableStatus(current.Status) {
+		return internal_error.NewBadRequestError("auction is not active").
+			WithCode(internal_error.CodeAuctionNotActive)
+	}
+
+	return internal_error.NewConflictError(
+		"auction was modified by another request, reload it and try again")
+}
+
+// versionFilter casa a versão lida. Leilões gravados antes do campo existir
+// não têm version e contam como versão 0.
+func versionFilter(version int) interface{} {
+	if version == 0 {
+		return bson.M{"$in": bson.A{0, nil}}
+	}
+
+	return version
+}
@@ -0,0 +1,793 @@
+package auction
+
+import (
+	"context"
+	"fmt"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/entity/bid_entity"
+	"fullcycle-auction_go/internal/infra/database/decimal"
+	"fullcycle-auction_go/internal/infra/database/user"
+	"fullcycle-auction_go/internal/internal_error"
+	"log"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestReopenAuction(t *testing.T) {
+	t.Setenv("AUCTION_INTERVAL", "2s")
+	t.Setenv("AUCTION_CHECK_INTERVAL", "1h")
+
+	ctx := context.Background()
+
+	client, db, container := getTestDatabase(ctx, t)
+	defer func() {
+		if err := container.Terminate(ctx); err != nil {
+			log.Printf("failed to terminate container: %s", err)
+		}
+	}()
+	defer func() {
+		if err := client.Disconnect(ctx); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	collectionName := fmt.Sprintf("auctions_test_%d", time.Now().UnixNano())
+	collection := db.Collection(collectionName)
+	collection.Drop(ctx)
+	defer collection.Drop(ctx)
+
+	repo := NewAuctionRepositoryWithCollection(ctx, db, collectionName)
+	defer repo.Close()
+
+	auctionEntity := &auction_entity.Auction{
+		Id:          "test-auction-reopen",
+		ProductName: "Reopen Product",
+		Category:    "Test Category",
+		Description: "This auction is closed and then reopened",
+		Condition:   auction_entity.New,
+		Status:      auction_entity.Active,
+		Timestamp:   time.Now().Add(-3 * time.Second),
+	}
+
+	if _, err := repo.CreateAuction(ctx, auctionEntity); err != nil {
+		t.Fatalf("Failed to create auction: %v", err)
+	}
+
+	err := repo.ReopenAuction(ctx, "test-auction-reopen", time.Now().Add(time.Hour))
+	require.NotNil(t, err)
+	require.Equal(t, "bad_request", err.Err)
+
+	repo.closeExpiredAuctions(ctx)
+
+	closed, err := repo.FindAuctionById(ctx, "test-auction-reopen")
+	require.Nil(t, err)
+	require.Equal(t, auction_entity.Completed, closed.Status)
+
+	// Resultado do fechamento que a reabertura precisa descartar
+	winningAmount := decimal.Amount(bid_entity.AmountFromCents(150_00))
+	_, updateErr := collection.UpdateOne(ctx, bson.M{"_id": "test-auction-reopen"}, bson.M{"$set": bson.M{
+		"winning_bid_id":            "test-bid",
+		"winner_user_id":            "test-user",
+		"winning_amount":            winningAmount,
+		"no_bids":                   true,
+		"winner_resolution_pending": true,
+	}})
+	require.NoError(t, updateErr)
+
+	newExpiresAt := time.Now().Add(time.Hour)
+
+	// A nova expiração é comparada com o relógio do repositório
+	clock := newFakeClock()
+	clock.Advance(2 * time.Hour)
+	repo.SetClock(clock)
+	err = repo.ReopenAuction(ctx, "test-auction-reopen", newExpiresAt)
+	require.NotNil(t, err)
+	require.Equal(t, "new expiration must be in the future", err.Message)
+	repo.SetClock(realClock{})
+
+	require.Nil(t, repo.ReopenAuction(ctx, "test-auction-reopen", newExpiresAt))
+
+	repo.closeExpiredAuctions(ctx)
+
+	reopened, err := repo.FindAuctionById(ctx, "test-auction-reopen")
+	require.Nil(t, err)
+	require.Equal(t, auction_entity.Active, reopened.Status)
+	require.Equal(t, newExpiresAt.Unix(), reopened.ExpiresAt.Unix())
+
+	var stored AuctionEntityMongo
+	require.NoError(t, collection.FindOne(ctx, bson.M{"_id": "test-auction-reopen"}).Decode(&stored))
+	require.Nil(t, stored.ClosedAt)
+	require.Empty(t, stored.WinningBidId)
+	require.Empty(t, stored.WinnerUserId)
+	require.Nil(t, stored.WinningAmount)
+	require.False(t, stored.NoBids)
+	require.False(t, stored.WinnerResolutionPending)
+
+	err = repo.ReopenAuction(ctx, "test-auction-missing", newExpiresAt)
+	require.NotNil(t, err)
+	require.Equal(t, "not_found", err.Err)
+}
+
+func TestUpdateAuction(t *testing.T) {
+	t.Setenv("AUCTION_INTERVAL", "1h")
+	t.Setenv("AUCTION_CHECK_INTERVAL", "1h")
+
+	ctx := context.Background()
+
+	client, db, container := getTestDatabase(ctx, t)
+	defer func() {
+		if err := container.Terminate(ctx); err != nil {
+			log.Printf("failed to terminate container: %s", err)
+		}
+	}()
+	defer func() {
+		if err := client.Disconnect(ctx); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	collectionName := fmt.Sprintf("auctions_test_%d", time.Now().UnixNano())
+	collection := db.Collection(collectionName)
+	collection.Drop(ctx)
+	defer collection.Drop(ctx)
+
+	repo := NewAuctionRepositoryWithOptions(ctx, db,
+		WithCollectionName(collectionName),
+		WithRules(auction_entity.Rules{
+			MaxDescriptionLen:   60,
+			TruncateDescription: true,
+			Blocklist:           auction_entity.NewBlocklist([]string{"golpe"}),
+		}))
+	defer repo.Close()
+	repo.SetBidStore(newTestBidStore(db))
+
+	auctionId := fmt.Sprintf("test-auction-update-%d", time.Now().UnixNano())
+	auctionEntity := &auction_entity.Auction{
+		Id:          auctionId,
+		ProductName: "Update Product",
+		Category:    "Test Categroy",
+		Description: "This auction has a typo in its category",
+		Condition:   auction_entity.New,
+		Status:      auction_entity.Active,
+		Timestamp:   time.Now(),
+	}
+
+	if _, err := repo.CreateAuction(ctx, auctionEntity); err != nil {
+		t.Fatalf("Failed to create auction: %v", err)
+	}
+
+	category := "Test Category"
+	description := "This auction had a typo in its category"
+	require.Nil(t, repo.UpdateAuction(ctx, auctionId, auction_entity.AuctionPatch{
+		Category:    &category,
+		Description: &description,
+	}))
+
+	updated, err := repo.FindAuctionById(ctx, auctionId)
+	require.Nil(t, err)
+	require.Equal(t, category, updated.Category)
+	require.Equal(t, description, updated.Description)
+	require.Equal(t, auctionEntity.ProductName, updated.ProductName)
+	require.Equal(t, auction_entity.Active, updated.Status)
+
+	shortDescription := "short"
+	err = repo.UpdateAuction(ctx, auctionId, auction_entity.AuctionPatch{Description: &shortDescription})
+	require.NotNil(t, err)
+	require.Equal(t, "bad_request", err.Err)
+
+	blockedDescription := "This auction is not a golpe"
+	err = repo.UpdateAuction(ctx, auctionId, auction_entity.AuctionPatch{Description: &blockedDescription})
+	require.NotNil(t, err)
+	require.Equal(t, "Description contains a blocked word", err.Message)
+
+	// A descrição editada é cortada como na criação
+	longDescription := strings.Repeat("a", 100)
+	require.Nil(t, repo.UpdateAuction(ctx, auctionId, auction_entity.AuctionPatch{Description: &longDescription}))
+
+	truncated, err := repo.FindAuctionById(ctx, auctionId)
+	require.Nil(t, err)
+	require.Equal(t, strings.Repeat("a", 59)+"…", truncated.Description)
+
+	// Depois do primeiro lance o leilão não pode mais ser editado
+	bidsCollection := db.Collection(testBidsCollectionName)
+	_, insertErr := bidsCollection.InsertOne(ctx, bson.M{
+		"_id":        fmt.Sprintf("test-bid-update-%d", time.Now().UnixNano()),
+		"auction_id": auctionId,
+		"amount":     100.0,
+	})
+	require.NoError(t, insertErr)
+	defer bidsCollection.DeleteMany(ctx, bson.M{"auction_id": auctionId})
+
+	productName := "Another Product"
+	err = repo.UpdateAuction(ctx, auctionId, auction_entity.AuctionPatch{ProductName: &productName})
+	require.NotNil(t, err)
+	require.Equal(t, "bad_request", err.Err)
+	require.Equal(t, "auction already has bids", err.Message)
+
+	unchanged, err := repo.FindAuctionById(ctx, auctionId)
+	require.Nil(t, err)
+	require.Equal(t, auctionEntity.ProductName, unchanged.ProductName)
+}
+
+func TestExtendAuction(t *testing.T) {
+	t.Setenv("AUCTION_INTERVAL", "2s")
+	t.Setenv("AUCTION_CHECK_INTERVAL", "1h")
+	t.Setenv("MAX_AUCTION_EXTENSION", "10s")
+
+	ctx := context.Background()
+
+	client, db, container := getTestDatabase(ctx, t)
+	defer func() {
+		if err := container.Terminate(ctx); err != nil {
+			log.Printf("failed to terminate container: %s", err)
+		}
+	}()
+	defer func() {
+		if err := client.Disconnect(ctx); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	collectionName := fmt.Sprintf("auctions_test_%d", time.Now().UnixNano())
+	collection := db.Collection(collectionName)
+	collection.Drop(ctx)
+	defer collection.Drop(ctx)
+
+	repo := NewAuctionRepositoryWithCollection(ctx, db, collectionName)
+	defer repo.Close()
+
+	clock := newFakeClock()
+	repo.SetClock(clock)
+
+	auctionEntity := &auction_entity.Auction{
+		Id:          "test-auction-extend",
+		ProductName: "Extend Product",
+		Category:    "Test Category",
+		Description: "This auction gets more time before closing",
+		Condition:   auction_entity.New,
+		Status:      auction_entity.Active,
+		Timestamp:   clock.Now(),
+	}
+
+	if _, err := repo.CreateAuction(ctx, auctionEntity); err != nil {
+		t.Fatalf("Failed to create auction: %v", err)
+	}
+
+	err := repo.ExtendAuction(ctx, "test-auction-extend", 500*time.Millisecond)
+	require.NotNil(t, err)
+	require.Equal(t, "bad_request", err.Err)
+
+	// Perto da expiração original, estende por mais 5s
+	clock.Advance(time.Second)
+	require.Nil(t, repo.ExtendAuction(ctx, "test-auction-extend", 5*time.Second))
+
+	extended, err := repo.FindAuctionById(ctx, "test-auction-extend")
+	require.Nil(t, err)
+	require.Equal(t, auctionEntity.Timestamp.Add(7*time.Second).Unix(), extended.ExpiresAt.Unix())
+
+	// Depois da expiração original o closer não fecha o leilão
+	clock.Advance(3 * time.Second)
+	repo.closeExpiredAuctions(ctx)
+
+	stillActive, err := repo.FindAuctionById(ctx, "test-auction-extend")
+	require.Nil(t, err)
+	require.Equal(t, auction_entity.Active, stillActive.Status)
+
+	// 5s + 6s passa do MAX_AUCTION_EXTENSION de 10s
+	err = repo.ExtendAuction(ctx, "test-auction-extend", 6*time.Second)
+	require.NotNil(t, err)
+	require.Equal(t, "bad_request", err.Err)
+
+	clock.Advance(4 * time.Second)
+	repo.closeExpiredAuctions(ctx)
+
+	err = repo.ExtendAuction(ctx, "test-auction-extend", time.Second)
+	require.NotNil(t, err)
+	require.Equal(t, internal_error.CodeAuctionNotActive, err.Code)
+
+	err = repo.ExtendAuction(ctx, "test-auction-missing", time.Second)
+	require.NotNil(t, err)
+	require.Equal(t, "not_found", err.Err)
+}
+
+func TestCancelAuction(t *testing.T) {
+	t.Setenv("AUCTION_INTERVAL", "2s")
+	t.Setenv("AUCTION_CHECK_INTERVAL", "1h")
+
+	ctx := context.Background()
+
+	client, db, container := getTestDatabase(ctx, t)
+	defer func() {
+		if err := container.Terminate(ctx); err != nil {
+			log.Printf("failed to terminate container: %s", err)
+		}
+	}()
+	defer func() {
+		if err := client.Disconnect(ctx); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	collectionName := fmt.Sprintf("auctions_test_%d", time.Now().UnixNano())
+	collection := db.Collection(collectionName)
+	collection.Drop(ctx)
+	defer collection.Drop(ctx)
+
+	repo := NewAuctionRepositoryWithCollection(ctx, db, collectionName)
+	defer repo.Close()
+	repo.SetBidStore(newTestBidStore(db))
+
+	clock := newFakeClock()
+	repo.SetClock(clock)
+
+	createAuction := func(id string) {
+		auctionEntity := &auction_entity.Auction{
+			Id:          id,
+			ProductName: "Cancel Product",
+			Category:    "Electronics",
+			Description: "This auction is going to be cancelled",
+			Condition:   auction_entity.New,
+			Status:      auction_entity.Active,
+			Timestamp:   clock.Now(),
+		}
+		if _, err := repo.CreateAuction(ctx, auctionEntity); err != nil {
+			t.Fatalf("Failed to create auction: %v", err)
+		}
+	}
+
+	t.Run("cancels an active auction without bids", func(t *testing.T) {
+		createAuction("test-auction-cancel")
+
+		err := repo.CancelAuction(ctx, "test-auction-cancel", "  product was sold elsewhere ")
+		require.Nil(t, err)
+
+		cancelled, err := repo.FindAuctionById(ctx, "test-auction-cancel")
+		require.Nil(t, err)
+		require.Equal(t, auction_entity.Cancelled, cancelled.Status)
+		require.Equal(t, "product was sold elsewhere", cancelled.CancelReason)
+
+		var result AuctionEntityMongo
+		require.NoError(t, collection.FindOne(ctx, bson.M{"_id": "test-auction-cancel"}).Decode(&result))
+		require.NotNil(t, result.CancelledAt)
+		require.Equal(t, clock.Now().Unix(), *result.CancelledAt)
+
+		err = repo.CancelAuction(ctx, "test-auction-cancel", "cancelling twice")
+		require.NotNil(t, err)
+		require.Equal(t, internal_error.CodeAuctionNotActive, err.Code)
+	})
+
+	t.Run("rejects an auction with bids", func(t *testing.T) {
+		createAuction("test-auction-cancel-bids")
+
+		bidsCollection := db.Collection(testBidsCollectionName)
+		_, insertErr := bidsCollection.InsertOne(ctx, bson.M{
+			"_id":        fmt.Sprintf("test-bid-cancel-%d", time.Now().UnixNano()),
+			"auction_id": "test-auction-cancel-bids",
+			"amount":     100.0,
+		})
+		require.NoError(t, insertErr)
+		defer bidsCollection.DeleteMany(ctx, bson.M{"auction_id": "test-auction-cancel-bids"})
+
+		err := repo.CancelAuction(ctx, "test-auction-cancel-bids", "changed my mind")
+		require.NotNil(t, err)
+		require.Equal(t, "bad_request", err.Err)
+		require.Equal(t, "auction already has bids", err.Message)
+
+		stillActive, err := repo.FindAuctionById(ctx, "test-auction-cancel-bids")
+		require.Nil(t, err)
+		require.Equal(t, auction_entity.Active, stillActive.Status)
+	})
+
+	t.Run("requires a reason", func(t *testing.T) {
+		createAuction("test-auction-cancel-reason")
+
+		err := repo.CancelAuction(ctx, "test-auction-cancel-reason", "   ")
+		require.NotNil(t, err)
+		require.Equal(t, "cancel reason is required", err.Message)
+	})
+
+	t.Run("unknown auction", func(t *testing.T) {
+		err := repo.CancelAuction(ctx, "missing-auction", "no longer available")
+		require.NotNil(t, err)
+		require.Equal(t, internal_error.CodeAuctionNotFound, err.Code)
+	})
+
+	t.Run("closer ignores cancelled auctions", func(t *testing.T) {
+		var closedIds []string
+		repo.SetOnAuctionClosed(func(ctx context.Context, auctionID string) {
+			closedIds = append(closedIds, auctionID)
+		})
+		defer repo.SetOnAuctionClosed(nil)
+
+		clock.Advance(4 * time.Second)
+		repo.closeExpiredAuctions(ctx)
+
+		require.NotContains(t, closedIds, "test-auction-cancel")
+		require.Contains(t, closedIds, "test-auction-cancel-bids")
+
+		cancelled, err := repo.FindAuctionById(ctx, "test-auction-cancel")
+		require.Nil(t, err)
+		require.Equal(t, auction_entity.Cancelled, cancelled.Status)
+
+		var result AuctionEntityMongo
+		require.NoError(t, collection.FindOne(ctx, bson.M{"_id": "test-auction-cancel"}).Decode(&result))
+		require.Nil(t, result.ClosedAt)
+	})
+}
+
+func TestCloseAuctions(t *testing.T) {
+	t.Setenv("AUCTION_INTERVAL", "1h")
+	t.Setenv("AUCTION_CHECK_INTERVAL", "1h")
+
+	ctx := context.Background()
+
+	client, db, container := getTestDatabase(ctx, t)
+	defer func() {
+		if err := container.Terminate(ctx); err != nil {
+			log.Printf("failed to terminate container: %s", err)
+		}
+	}()
+	defer func() {
+		if err := client.Disconnect(ctx); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	collectionName := fmt.Sprintf("auctions_test_%d", time.Now().UnixNano())
+	collection := db.Collection(collectionName)
+	collection.Drop(ctx)
+	defer collection.Drop(ctx)
+
+	repo := NewAuctionRepositoryWithCollection(ctx, db, collectionName)
+	defer repo.Close()
+
+	clock := newFakeClock()
+	repo.SetClock(clock)
+
+	var mutex sync.Mutex
+	var callbackIds []string
+	repo.SetOnAuctionClosed(func(ctx context.Context, auctionID string) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		callbackIds = append(callbackIds, auctionID)
+	})
+
+	for _, id := range []string{"fraud-1", "fraud-2", "legit-1", "legit-2"} {
+		auctionEntity := &auction_entity.Auction{
+			Id:          id,
+			ProductName: "Force Close Product",
+			Category:    "Electronics",
+			Description: "This auction may be force-closed",
+			Condition:   auction_entity.New,
+			Status:      auction_entity.Active,
+			Timestamp:   clock.Now(),
+		}
+		if _, err := repo.CreateAuction(ctx, auctionEntity); err != nil {
+			t.Fatalf("Failed to create auction: %v", err)
+		}
+	}
+
+	// Ids repetidos ou inexistentes não contam
+	closed, err := repo.CloseAuctions(ctx, []string{"fraud-1", "fraud-2", "fraud-1", "missing"})
+	require.Nil(t, err)
+	require.Equal(t, int64(2), closed)
+	require.ElementsMatch(t, []string{"fraud-1", "fraud-2"}, callbackIds)
+
+	expectedStatus := map[string]auction_entity.AuctionStatus{
+		"fraud-1": auction_entity.Completed,
+		"fraud-2": auction_entity.Completed,
+		"legit-1": auction_entity.Active,
+		"legit-2": auction_entity.Active,
+	}
+	for id, status := range expectedStatus {
+		auctionEntity, err := repo.FindAuctionById(ctx, id)
+		require.Nil(t, err)
+		require.Equal(t, status, auctionEntity.Status, id)
+	}
+
+	history, err := repo.FindAuctionHistory(ctx, "fraud-1")
+	require.Nil(t, err)
+	require.Equal(t, "forced", history[len(history)-1].Reason)
+
+	t.Run("closed auctions are not closed again", func(t *testing.T) {
+		closed, err := repo.CloseAuctions(ctx, []string{"fraud-1", "legit-1"})
+		require.Nil(t, err)
+		require.Equal(t, int64(1), closed)
+		require.Len(t, callbackIds, 3)
+	})
+
+	t.Run("empty list", func(t *testing.T) {
+		closed, err := repo.CloseAuctions(ctx, nil)
+		require.Nil(t, err)
+		require.Zero(t, closed)
+	})
+}
+
+func TestTransferAuction(t *testing.T) {
+	t.Setenv("AUCTION_CHECK_INTERVAL", "1h")
+	t.Setenv("MAX_ACTIVE_AUCTIONS_PER_USER", "1")
+
+	ctx := context.Background()
+
+	client, db, container := getTestDatabase(ctx, t)
+	defer func() {
+		if err := container.Terminate(ctx); err != nil {
+			log.Printf("failed to terminate container: %s", err)
+		}
+	}()
+	defer func() {
+		if err := client.Disconnect(ctx); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	collectionName := fmt.Sprintf("auctions_test_%d", time.Now().UnixNano())
+	collection := db.Collection(collectionName)
+	collection.Drop(ctx)
+	defer collection.Drop(ctx)
+
+	repo := NewAuctionRepositoryWithOptions(ctx, db,
+		WithCollectionName(collectionName),
+		WithUserRepository(user.NewUserRepository(db)))
+	defer repo.Close()
+
+	sellerId := uuid.New().String()
+	newOwnerId := uuid.New().String()
+	_, insertErr := db.Collection("users").InsertMany(ctx, []interface{}{
+		bson.M{"_id": sellerId, "name": "Seller"},
+		bson.M{"_id": newOwnerId, "name": "New Seller"},
+	})
+	require.NoError(t, insertErr)
+
+	newAuction := func(id, userId string) *auction_entity.Auction {
+		return &auction_entity.Auction{
+			Id:          id,
+			UserId:      userId,
+			ProductName: "Transfer Product",
+			Category:    "Electronics",
+			Description: "This auction is going to change owner",
+			Condition:   auction_entity.New,
+			Status:      auction_entity.Active,
+			Timestamp:   time.Now(),
+		}
+	}
+
+	_, err := repo.CreateAuction(ctx, newAuction("test-auction-transfer", sellerId))
+	require.Nil(t, err)
+
+	t.Run("unknown owner is rejected", func(t *testing.T) {
+		err := repo.TransferAuction(ctx, "test-auction-transfer", uuid.New().String())
+		require.NotNil(t, err)
+		require.Equal(t, internal_error.CodeUserNotFound, err.Code)
+	})
+
+	t.Run("transfers to an existing user", func(t *testing.T) {
+		require.Nil(t, repo.TransferAuction(ctx, "test-auction-transfer", newOwnerId))
+
+		transferred, err := repo.FindAuctionById(ctx, "test-auction-transfer")
+		require.Nil(t, err)
+		require.Equal(t, newOwnerId, transferred.UserId)
+
+		// O limite de leilões ativos por usuário passa a contar para o novo dono
+		_, err = repo.CreateAuction(ctx, newAuction("test-auction-new-owner", newOwnerId))
+		require.NotNil(t, err)
+		require.Equal(t, internal_error.CodeActiveAuctionsLimit, err.Code)
+
+		_, err = repo.CreateAuction(ctx, newAuction("test-auction-old-owner", sellerId))
+		require.Nil(t, err)
+	})
+
+	t.Run("only active auctions can be transferred", func(t *testing.T) {
+		_, updateErr := collection.UpdateOne(ctx,
+			bson.M{"_id": "test-auction-old-owner"},
+			bson.M{"$set": bson.M{"status": auction_entity.Completed}})
+		require.NoError(t, updateErr)
+
+		err := repo.TransferAuction(ctx, "test-auction-old-owner", newOwnerId)
+		require.NotNil(t, err)
+		require.Equal(t, internal_error.CodeAuctionNotActive, err.Code)
+	})
+}
+
+func TestConcurrentUpdatesConflict(t *testing.T) {
+	t.Setenv("AUCTION_INTERVAL", "1h")
+	t.Setenv("AUCTION_CHECK_INTERVAL", "1h")
+
+	ctx := context.Background()
+
+	client, db, container := getTestDatabase(ctx, t)
+	defer func() {
+		if err := container.Terminate(ctx); err != nil {
+			log.Printf("failed to terminate container: %s", err)
+		}
+	}()
+	defer func() {
+		if err := client.Disconnect(ctx); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	collectionName := fmt.Sprintf("auctions_test_%d", time.Now().UnixNano())
+	collection := db.Collection(collectionName)
+	collection.Drop(ctx)
+	defer collection.Drop(ctx)
+
+	repo := NewAuctionRepositoryWithCollection(ctx, db, collectionName)
+	defer repo.Close()
+	repo.SetBidStore(newTestBidStore(db))
+
+	auctionEntity := &auction_entity.Auction{
+		Id:          "test-auction-version",
+		ProductName: "Version Product",
+		Category:    "Test Category",
+		Description: "This auction is updated by two admins at once",
+		Condition:   auction_entity.New,
+		Status:      auction_entity.Active,
+		Timestamp:   time.Now(),
+	}
+
+	if _, err := repo.CreateAuction(ctx, auctionEntity); err != nil {
+		t.Fatalf("Failed to create auction: %v", err)
+	}
+
+	// As duas requisições leram o leilão na mesma versão
+	read, err := repo.FindAuctionById(ctx, "test-auction-version")
+	require.Nil(t, err)
+	require.Equal(t, 0, read.Version)
+
+	filter := func() bson.M {
+		return bson.M{"status": auction_entity.Active, "deleted_at": nil}
+	}
+	first := bson.M{"$set": bson.M{"description": "Description from the first admin"}}
+	second := bson.M{"$set": bson.M{"description": "Description from the second admin"}}
+
+	require.Nil(t, repo.updateVersioned(ctx, read.Id, read.Version, filter(), first, "update"))
+
+	err = repo.updateVersioned(ctx, read.Id, read.Version, filter(), second, "update")
+	require.NotNil(t, err)
+	require.Equal(t, "conflict", err.Err)
+	require.Equal(t, internal_error.CodeConflict, err.Code)
+
+	updated, err := repo.FindAuctionById(ctx, "test-auction-version")
+	require.Nil(t, err)
+	require.Equal(t, 1, updated.Version)
+	require.Equal(t, "Description from the first admin", updated.Description)
+
+	// Relendo o leilão a atualização volta a ser aceita
+	require.Nil(t, repo.updateVersioned(ctx, updated.Id, updated.Version, filter(), second, "update"))
+
+	require.Nil(t, repo.ExtendAuction(ctx, "test-auction-version", time.Minute))
+	require.Nil(t, repo.CancelAuction(ctx, "test-auction-version", "Sold elsewhere"))
+
+	cancelled, err := repo.FindAuctionById(ctx, "test-auction-version")
+	require.Nil(t, err)
+	require.Equal(t, 4, cancelled.Version)
+
+	err = repo.updateVersioned(ctx, "test-auction-missing", 0, filter(), first, "update")
+	require.NotNil(t, err)
+	require.Equal(t, "not_found", err.Err)
+
+	// Os mapas do chamador não são alterados
+	callerFilter := filter()
+	callerUpdate := bson.M{"$set": bson.M{"description": "Description after cancel"}}
+	err = repo.updateVersioned(ctx, cancelled.Id, 0, callerFilter, callerUpdate, "update")
+	require.NotNil(t, err)
+	require.Equal(t, filter(), callerFilter)
+	require.Equal(t, bson.M{"$set": bson.M{"description": "Description after cancel"}}, callerUpdate)
+
+	// Um leilão que deixou de estar ativo no meio da operação, como um
+	// fechado pelo closer, responde AUCTION_NOT_ACTIVE em vez de conflito
+	require.Equal(t, "bad_request", err.Err)
+	require.Equal(t, internal_error.CodeAuctionNotActive, err.Code)
+}
+
+func TestUpdateLegacyAuctionWithoutVersion(t *testing.T) {
+	t.Setenv("AUCTION_INTERVAL", "1h")
+	t.Setenv("AUCTION_CHECK_INTERVAL", "1h")
+
+	ctx := context.Background()
+
+	client, db, container := getTestDatabase(ctx, t)
+	defer func() {
+		if err := container.Terminate(ctx); err != nil {
+			log.Printf("failed to terminate container: %s", err)
+		}
+	}()
+	defer func() {
+		if err := client.Disconnect(ctx); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	collectionName := fmt.Sprintf("auctions_test_%d", time.Now().UnixNano())
+	collection := db.Collection(collectionName)
+	collection.Drop(ctx)
+	defer collection.Drop(ctx)
+
+	repo := NewAuctionRepositoryWithCollection(ctx, db, collectionName)
+	defer repo.Close()
+
+	_, insertErr := collection.InsertOne(ctx, bson.M{
+		"_id":          "test-auction-legacy-version",
+		"product_name": "Legacy Product",
+		"category":     "Test Category",
+		"description":  "This auction was stored before the version field",
+		"condition":    auction_entity.New,
+		"status":       auction_entity.Active,
+		"timestamp":    time.Now().Unix(),
+		"expires_at":   time.Now().Add(time.Hour).Unix(),
+	})
+	require.NoError(t, insertErr)
+
+	require.Nil(t, repo.ExtendAuction(ctx, "test-auction-legacy-version", time.Minute))
+
+	extended, err := repo.FindAuctionById(ctx, "test-auction-legacy-version")
+	require.Nil(t, err)
+	require.Equal(t, 1, extended.Version)
+}
+
+func TestScheduledAuctionChanges(t *testing.T) {
+	t.Setenv("AUCTION_INTERVAL", "1h")
+	t.Setenv("AUCTION_CHECK_INTERVAL", "1h")
+
+	ctx := context.Background()
+
+	client, db, container := getTestDatabase(ctx, t)
+	defer func() {
+		if err := container.Terminate(ctx); err != nil {
+			log.Printf("failed to terminate container: %s", err)
+		}
+	}()
+	defer func() {
+		if err := client.Disconnect(ctx); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	collectionName := fmt.Sprintf("auctions_test_%d", time.Now().UnixNano())
+	collection := db.Collection(collectionName)
+	collection.Drop(ctx)
+	defer collection.Drop(ctx)
+
+	repo := NewAuctionRepositoryWithCollection(ctx, db, collectionName)
+	defer repo.Close()
+	repo.SetBidStore(newTestBidStore(db))
+
+	auctionEntity := &auction_entity.Auction{
+		Id:          "test-auction-scheduled-changes",
+		ProductName: "Scheduled Product",
+		Category:    "Test Category",
+		Description: "Auction that opens for bids later",
+		Condition:   auction_entity.New,
+		Status:      auction_entity.Scheduled,
+		Timestamp:   time.Now(),
+		StartsAt:    time.Now().Add(time.Hour),
+	}
+	_, createErr := repo.CreateAuction(ctx, auctionEntity)
+	require.Nil(t, createErr)
+
+	reopenErr := repo.ReopenAuction(ctx, auctionEntity.Id, time.Now().Add(2*time.Hour))
+	require.NotNil(t, reopenErr)
+	require.Equal(t, "auction has not started yet", reopenErr.Message)
+	require.Equal(t, internal_error.CodeAuctionNotActive, reopenErr.Code)
+
+	productName := "Renamed Scheduled Product"
+	require.Nil(t, repo.UpdateAuction(ctx, auctionEntity.Id, auction_entity.AuctionPatch{ProductName: &productName}))
+
+	updated, err := repo.FindAuctionById(ctx, auctionEntity.Id)
+	require.Nil(t, err)
+	require.Equal(t, productName, updated.ProductName)
+	require.Equal(t, auction_entity.Scheduled, updated.Status)
+
+	require.Nil(t, repo.CancelAuction(ctx, auctionEntity.Id, "event was postponed"))
+
+	cancelled, err := repo.FindAuctionById(ctx, auctionEntity.Id)
+	require.Nil(t, err)
+	require.Equal(t, auction_entity.Cancelled, cancelled.Status)
+}
@@ -0,0 +1,152 @@
+package auction
+
+import (
+	"context"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/entity/bid_entity"
+	"fullcycle-auction_go/internal/infra/database/decimal"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+// resolveWinner grava o vencedor do leilão recém fechado. Uma falha na
+// coleção de lances ou na escrita não desfaz o fechamento: o leilão fica
+// Completed com winner_resolution_pending e resolvePendingWinners tenta de
+// novo nos próximos ticks.
+func (ar *AuctionRepository) resolveWinner(ctx context.Context, auctionEntityMongo AuctionEntityMongo) {
+	err := ar.recordWinner(ctx, auctionEntityMongo)
+	if err == nil {
+		return
+	}
+
+	log := logger.WithContext(ctx)
+	log.Warn("Auction closed without resolving the winner, it will be retried on the next ticks",
+		zap.String("auction_id", auctionEntityMongo.Id),
+		zap.Error(err))
+
+	err = retryTransient(ctx, closerRetryAttempts, closerRetryBaseDelay, func() error {
+		_, err := ar.Collection.UpdateOne(ctx,
+			bson.M{"_id": auctionEntityMongo.Id},
+			bson.M{
+				"$set": bson.M{"winner_resolution_pending": true},
+				"$inc": bson.M{"version": 1},
+			})
+		return err
+	})
+	if err != nil {
+		log.Error("Error trying to mark the winner resolution as pending", err,
+			zap.String("auction_id", auctionEntityMongo.Id))
+	}
+}
+
+// resolvePendingWinners refaz a resolução do vencedor dos leilões fechados
+// enquanto a coleção de lances estava indisponível.
+func (ar *AuctionRepository) resolvePendingWinners(ctx context.Context) {
+	log := logger.WithContext(ctx)
+
+	opts := options.Find().SetLimit(ar.closeBatchSize)
+	cursor, err := ar.Collection.Find(ctx, bson.M{
+		"status":                    auction_entity.Completed,
+		"winner_resolution_pending": true,
+	}, opts)
+	if err != nil {
+		log.Error("Error trying to find auctions with pending winner resolution", err)
+		return
+	}
+	defer closeCursor(ctx, cursor)
+
+	var pendingAuctions []AuctionEntityMongo
+	if err := cursor.All(ctx, &pendingAuctions); err != nil {
+		log.Error("Error trying to decode auctions with pending winner resolution", err)
+		return
+	}
+
+	for _, auctionEntityMongo := range pendingAuctions {
+		if err := ar.recordWinner(ctx, auctionEntityMongo); err != nil {
+			log.Warn("Winner resolution is still pending",
+				zap.String("auction_id", auctionEntityMongo.Id),
+				zap.Error(err))
+			continue
+		}
+
+		log.Info("Pending winner resolved", zap.String("auction_id", auctionEntityMongo.Id))
+	}
+}
+
+// recordWinner grava no documento do leilão fechado o dono e o valor do maior
+// lance na moeda do leilão, ou no_bids quando não houve lance, e em
+// reserve_met se a reserva foi atingida. Com a reserva não atingida o leilão
+// fica sem vencedor. Sem vencedor os campos ficam ausentes, o que um filtro
+// {"winner_user_id": null} também encontra.
+func (ar *AuctionRepository) recordWinner(ctx context.Context, auctionEntityMongo AuctionEntityMongo) error {
+	winner, hasBids, err := ar.findWinningBid(ctx,
+		auctionEntityMongo.Id, bid_entity.CurrencyOrDefault(auctionEntityMongo.Currency))
+	if err != nil {
+		return err
+	}
+
+	reserveMet := winner != nil && winner.Amount.Reaches(auctionEntityMongo.ReservePrice)
+
+	set := bson.M{"no_bids": !hasBids}
+	unset := bson.M{"winner_resolution_pending": ""}
+	if auctionEntityMongo.ReservePrice > 0 {
+		set["reserve_met"] = reserveMet
+	}
+	if reserveMet {
+		set["winner_user_id"] = winner.UserId
+		set["winning_amount"] = decimal.Amount(winner.Amount)
+	} else {
+		unset["winner_user_id"] = ""
+		unset["winning_amount"] = ""
+	}
+
+	err = retryTransient(ctx, closerRetryAttempts, closerRetryBaseDelay, func() error {
+		_, err := ar.Collection.UpdateOne(ctx,
+			bson.M{"_id": auctionEntityMongo.Id},
+			bson.M{
+				"$set":   set,
+				"$unset": unset,
+				"$inc":   bson.M{"version": 1},
+			})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if auctionEntityMongo.ReservePrice > 0 && !reserveMet {
+		logger.WithContext(ctx).Info("Auction closed without reaching the reserve price",
+			zap.String("auction_id", auctionEntityMongo.Id),
+			zap.Float64("reserve_price", auctionEntityMongo.ReservePrice))
+	}
+
+	return nil
+}
+
+// findWinningBid busca no BidStore o maior lance do leilão na moeda dele e se
+// o leilão recebeu algum lance.
+func (ar *AuctionRepository) findWinningBid(
+	ctx context.Context, auctionId, currency string) (*bid_entity.Bid, bool, error) {
+	bidStore, err := ar.bidStore()
+	if err != nil {
+		return nil, false, err
+	}
+
+	winner, hasBids, err := bidStore.FindHighestBidInCurrency(ctx, auctionId, currency)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return winner, hasBids, nil
+}
+
+func winningAmount(auctionEntityMongo AuctionEntityMongo) bid_entity.Amount {
+	if auctionEntityMongo.WinningAmount == nil {
+		return bid_entity.Amount{}
+	}
+
+	return bid_entity.Amount(*auctionEntityMongo.WinningAmount)
+}
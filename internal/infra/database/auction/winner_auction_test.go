@@ -0,0 +1,122 @@
+package auction
+
+import (
+	"context"
+	"fmt"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/entity/bid_entity"
+	"fullcycle-auction_go/internal/infra/database/decimal"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestCloserRecordsWinners(t *testing.T) {
+	t.Setenv("AUCTION_INTERVAL", "2s")
+	t.Setenv("AUCTION_CHECK_INTERVAL", "1h")
+
+	ctx := context.Background()
+	db := getSharedTestDatabase(ctx, t)
+
+	collectionName := fmt.Sprintf("auctions_test_%d", time.Now().UnixNano())
+	collection := db.Collection(collectionName)
+	collection.Drop(ctx)
+	defer collection.Drop(ctx)
+
+	repo := NewAuctionRepositoryWithCollection(ctx, db, collectionName)
+	defer repo.Close()
+	repo.SetBidStore(newTestBidStore(db))
+
+	clock := newFakeClock()
+	repo.SetClock(clock)
+
+	createAuction := func(reservePrice float64, currency string) string {
+		auctionEntity := &auction_entity.Auction{
+			Id:           uuid.New().String(),
+			ProductName:  "Winner Product",
+			Category:     "Electronics",
+			Description:  "Auction used by the winner test",
+			Condition:    auction_entity.New,
+			Status:       auction_entity.Active,
+			Timestamp:    clock.Now(),
+			ReservePrice: reservePrice,
+			Currency:     currency,
+		}
+		_, err := repo.CreateAuction(ctx, auctionEntity)
+		require.Nil(t, err)
+		return auctionEntity.Id
+	}
+
+	bidsCollection := db.Collection(testBidsCollectionName)
+	var bidIds []string
+	defer func() { bidsCollection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": bidIds}}) }()
+
+	placeBid := func(auctionId, userId string, cents int64, currency string, secondsAgo int64) {
+		bid := auctionBidMongo{
+			Id:        uuid.New().String(),
+			UserId:    userId,
+			AuctionId: auctionId,
+			Amount:    decimal.Amount(bid_entity.AmountFromCents(cents)),
+			Timestamp: clock.Now().Unix() - secondsAgo,
+			Currency:  currency,
+		}
+		_, err := bidsCollection.InsertOne(ctx, bid)
+		require.NoError(t, err)
+		bidIds = append(bidIds, bid.Id)
+	}
+
+	withBids := createAuction(0, "")
+	placeBid(withBids, "user-low", 100_00, "BRL", 3)
+	placeBid(withBids, "user-first-high", 150_00, "BRL", 2)
+	placeBid(withBids, "user-late-high", 150_00, "BRL", 1)
+
+	withoutBids := createAuction(0, "")
+
+	reserveNotMet := createAuction(500, "")
+	placeBid(reserveNotMet, "user-below-reserve", 200_00, "BRL", 1)
+
+	// Lances antigos sem moeda são BRL e não disputam um leilão em USD
+	inDollars := createAuction(0, "USD")
+	placeBid(inDollars, "user-dollars", 50_00, "USD", 2)
+	placeBid(inDollars, "user-legacy", 900_00, "", 1)
+
+	clock.Advance(3 * time.Second)
+	require.NoError(t, repo.closeExpiredAuctions(ctx))
+
+	findAuction := func(id string) *auction_entity.Auction {
+		auctionEntity, err := repo.FindAuctionById(ctx, id)
+		require.Nil(t, err)
+		require.Equal(t, auction_entity.Completed, auctionEntity.Status)
+		return auctionEntity
+	}
+
+	winner := findAuction(withBids)
+	require.Equal(t, "user-first-high", winner.WinnerUserId)
+	require.Equal(t, bid_entity.AmountFromCents(150_00), winner.WinningAmount)
+	require.False(t, winner.NoBids)
+
+	noBids := findAuction(withoutBids)
+	require.Empty(t, noBids.WinnerUserId)
+	require.True(t, noBids.NoBids)
+
+	var rawNoBids bson.M
+	require.NoError(t, collection.FindOne(ctx, bson.M{"_id": withoutBids}).Decode(&rawNoBids))
+	require.NotContains(t, rawNoBids, "winner_user_id")
+	require.Equal(t, true, rawNoBids["no_bids"])
+
+	// O filtro por vencedor nulo encontra o leilão sem lances
+	count, err := collection.CountDocuments(ctx, bson.M{"_id": withoutBids, "winner_user_id": nil})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+
+	belowReserve := findAuction(reserveNotMet)
+	require.Empty(t, belowReserve.WinnerUserId)
+	require.False(t, belowReserve.NoBids)
+
+	dollars := findAuction(inDollars)
+	require.Equal(t, "user-dollars", dollars.WinnerUserId)
+	require.Equal(t, bid_entity.AmountFromCents(50_00), dollars.WinningAmount)
+}
@@ -0,0 +1,103 @@
+package bid
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/infra/database/auction"
+	"fullcycle-auction_go/internal/usecase/bid_usecase"
+	"log"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+type testClock struct {
+	mutex *sync.Mutex
+	now   time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.now
+}
+
+func (c *testClock) Advance(d time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func TestLastSecondBidExtendsAuction(t *testing.T) {
+	t.Setenv("AUCTION_INTERVAL", "1h")
+	t.Setenv("MAX_BATCH_SIZE", "1")
+	t.Setenv("BATCH_INSERT_INTERVAL", "1h")
+	t.Setenv("ANTI_SNIPE_WINDOW", "10s")
+	t.Setenv("ANTI_SNIPE_EXTENSION", "30s")
+
+	ctx := context.Background()
+
+	client, db, container := getTestDatabase(ctx, t)
+	defer func() {
+		db.Drop(ctx)
+		if err := client.Disconnect(ctx); err != nil {
+			log.Printf("failed to disconnect client: %s", err)
+		}
+		if err := container.Terminate(ctx); err != nil {
+			log.Printf("failed to terminate container: %s", err)
+		}
+	}()
+
+	clock := &testClock{mutex: &sync.Mutex{}, now: time.Now()}
+	auctionRepository := auction.NewAuctionRepositoryWithOptions(ctx, db,
+		auction.WithClock(clock),
+		auction.WithCheckInterval(20*time.Millisecond))
+	defer auctionRepository.Close()
+
+	bidRepository := NewBidRepository(db, auctionRepository)
+	defer bidRepository.Close()
+
+	auctionEntity := &auction_entity.Auction{
+		Id:          uuid.New().String(),
+		ProductName: "Sniped Product",
+		Category:    "Test Category",
+		Description: "Auction that receives a last-second bid",
+		Condition:   auction_entity.New,
+		Status:      auction_entity.Active,
+		Timestamp:   clock.Now(),
+		ExpiresAt:   clock.Now().Add(2 * time.Second),
+	}
+	if _, err := auctionRepository.CreateAuction(ctx, auctionEntity); err != nil {
+		t.Fatalf("Failed to create auction: %v", err)
+	}
+	originalExpiresAt := auctionEntity.ExpiresAt.Unix()
+
+	bidUseCase := bid_usecase.NewBidUseCase(bidRepository, auctionRepository, nil)
+	t.Cleanup(bidUseCase.Close)
+	require.Nil(t, bidUseCase.CreateBid(ctx, bid_usecase.BidInputDTO{
+		UserId:    uuid.New().String(),
+		AuctionId: auctionEntity.Id,
+		Amount:    100,
+	}))
+
+	require.Eventually(t, func() bool {
+		extended, err := auctionRepository.FindAuctionById(ctx, auctionEntity.Id)
+		return err == nil && extended.ExpiresAt.Unix() == originalExpiresAt+30
+	}, 5*time.Second, 20*time.Millisecond)
+
+	// Passa da expiração original e espera duas rodadas completas do closer
+	clock.Advance(5 * time.Second)
+	for i := 0; i < 2; i++ {
+		tickAfter := time.Now()
+		require.Eventually(t, func() bool {
+			return auctionRepository.LastTickAt().After(tickAfter)
+		}, 5*time.Second, 10*time.Millisecond)
+	}
+
+	result, err := auctionRepository.FindAuctionById(ctx, auctionEntity.Id)
+	require.Nil(t, err)
+	require.Equal(t, auction_entity.Active, result.Status)
+}
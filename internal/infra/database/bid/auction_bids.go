@@ -0,0 +1,143 @@
+package bid
+
+import (
+	"context"
+	"fmt"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/entity/bid_entity"
+	"fullcycle-auction_go/internal/infra/database/auction"
+	"fullcycle-auction_go/internal/infra/database/database_error"
+	"fullcycle-auction_go/internal/infra/database/decimal"
+	"fullcycle-auction_go/internal/internal_error"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Métodos usados pelo repositório de leilões, que não lê a coleção de lances
+// diretamente
+var _ auction.BidStore = (*BidRepository)(nil)
+
+func (bd *BidRepository) CountBidsByAuctionId(
+	ctx context.Context, auctionId string) (int64, *internal_error.InternalError) {
+	count, err := bd.Collection.CountDocuments(ctx, bson.M{"auction_id": auctionId})
+	if err != nil {
+		logger.Error(fmt.Sprintf("Error trying to count bids of auction = %s", auctionId), err)
+		return 0, database_error.NewInternalError(err, "Error trying to count bids")
+	}
+
+	return count, nil
+}
+
+// FindHighestBidInCurrency retorna o maior lance do leilão na moeda
+// informada, o mais antigo no empate. Quando não há lance nessa moeda, o
+// segundo retorno diz se o leilão recebeu lances em outra.
+func (bd *BidRepository) FindHighestBidInCurrency(
+	ctx context.Context, auctionId, currency string) (*bid_entity.Bid, bool, *internal_error.InternalError) {
+	opts := options.FindOne().
+		SetSort(bson.D{{Key: "amount", Value: -1}, {Key: "timestamp", Value: 1}, {Key: "_id", Value: 1}})
+
+	var bidEntityMongo BidEntityMongo
+	err := bd.Collection.FindOne(ctx,
+		bson.M{"auction_id": auctionId, "currency": currencyFilter(currency)}, opts).Decode(&bidEntityMongo)
+	if err == nil {
+		bid := toBidEntity(bidEntityMongo)
+		return &bid, true, nil
+	}
+	if !database_error.IsNotFound(err) {
+		logger.Error(fmt.Sprintf("Error trying to find the highest bid of auction = %s", auctionId), err)
+		return nil, false, database_error.NewInternalError(err, "Error trying to find the highest bid")
+	}
+
+	count, err := bd.Collection.CountDocuments(ctx,
+		bson.M{"auction_id": auctionId}, options.Count().SetLimit(1))
+	if err != nil {
+		logger.Error(fmt.Sprintf("Error trying to count bids of auction = %s", auctionId), err)
+		return nil, false, database_error.NewInternalError(err, "Error trying to count bids")
+	}
+
+	return nil, count > 0, nil
+}
+
+// ListBidsByAuctionId retorna todos os lances do leilão, sem o limite nem o
+// sigilo dos leilões Sealed aplicados em FindBidByAuctionId.
+func (bd *BidRepository) ListBidsByAuctionId(
+	ctx context.Context, auctionId string) ([]bid_entity.Bid, *internal_error.InternalError) {
+	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: 1}, {Key: "_id", Value: 1}})
+
+	cursor, err := bd.Collection.Find(ctx, bson.M{"auction_id": auctionId}, opts)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Error trying to find bids by auctionId %s", auctionId), err)
+		return nil, database_error.NewInternalError(err,
+			fmt.Sprintf("Error trying to find bids by auctionId %s", auctionId))
+	}
+	defer closeCursor(ctx, cursor)
+
+	var bidEntitiesMongo []BidEntityMongo
+	if err := cursor.All(ctx, &bidEntitiesMongo); err != nil {
+		logger.Error(fmt.Sprintf("Error trying to find bids by auctionId %s", auctionId), err)
+		return nil, database_error.NewInternalError(err,
+			fmt.Sprintf("Error trying to find bids by auctionId %s", auctionId))
+	}
+
+	bidEntities := make([]bid_entity.Bid, 0, len(bidEntitiesMongo))
+	for _, bidEntityMongo := range bidEntitiesMongo {
+		bidEntities = append(bidEntities, toBidEntity(bidEntityMongo))
+	}
+
+	return bidEntities, nil
+}
+
+// InsertWinningBid grava o lance do compre já depois que o repositório de
+// leilões fechou o leilão, por isso não passa por checkAuctionIsActive.
+func (bd *BidRepository) InsertWinningBid(
+	ctx context.Context, bidValue bid_entity.Bid) *internal_error.InternalError {
+	if err := bidValue.Validate(); err != nil {
+		return err
+	}
+
+	bidEntityMongo := &BidEntityMongo{
+		Id:        bidValue.Id,
+		UserId:    bidValue.UserId,
+		AuctionId: bidValue.AuctionId,
+		Amount:    decimal.Amount(bidValue.Amount),
+		Timestamp: bidValue.Timestamp.Unix(),
+		Currency:  bidValue.Currency,
+	}
+
+	if _, err := bd.Collection.InsertOne(ctx, bidEntityMongo); err != nil {
+		logger.Error("Error trying to insert bid", err)
+		return internal_error.NewInternalServerError("Error trying to insert bid")
+	}
+
+	return nil
+}
+
+func (bd *BidRepository) DeleteBidsByAuctionIds(
+	ctx context.Context, auctionIds []string) (int64, *internal_error.InternalError) {
+	result, err := bd.Collection.DeleteMany(ctx, bson.M{"auction_id": bson.M{"$in": auctionIds}})
+	if err != nil {
+		logger.Error("Error trying to delete bids of purged auctions", err)
+		return 0, database_error.NewInternalError(err, "Error trying to delete bids")
+	}
+
+	return result.DeletedCount, nil
+}
+
+func (bd *BidRepository) CollectionName() string {
+	return bd.Collection.Name()
+}
+
+// toBidEntity lê os segundos unix sempre em UTC, independente do fuso do
+// processo.
+func toBidEntity(bidEntityMongo BidEntityMongo) bid_entity.Bid {
+	return bid_entity.Bid{
+		Id:        bidEntityMongo.Id,
+		UserId:    bidEntityMongo.UserId,
+		AuctionId: bidEntityMongo.AuctionId,
+		Amount:    bid_entity.Amount(bidEntityMongo.Amount),
+		Timestamp: time.Unix(bidEntityMongo.Timestamp, 0).UTC(),
+		Currency:  bid_entity.CurrencyOrDefault(bidEntityMongo.Currency),
+	}
+}
@@ -0,0 +1,124 @@
+package bid
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/entity/bid_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"fullcycle-auction_go/internal/usecase/bid_usecase"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuyNowBidClosesAuction(t *testing.T) {
+	t.Setenv("BATCH_INSERT_INTERVAL", "1h")
+
+	ctx := context.Background()
+	bidRepository, auctionRepository := newTestRepositories(ctx, t)
+
+	auctionEntity := &auction_entity.Auction{
+		Id:          uuid.New().String(),
+		ProductName: "Buy Now Product",
+		Category:    "Test Category",
+		Description: "Auction that can be bought right away",
+		Condition:   auction_entity.New,
+		Status:      auction_entity.Active,
+		BuyNowPrice: 500,
+		Timestamp:   time.Now(),
+		ExpiresAt:   time.Now().Add(time.Hour),
+	}
+	if _, err := auctionRepository.CreateAuction(ctx, auctionEntity); err != nil {
+		t.Fatalf("Failed to create auction: %v", err)
+	}
+
+	bidUseCase := bid_usecase.NewBidUseCase(bidRepository, auctionRepository, nil)
+	t.Cleanup(bidUseCase.Close)
+
+	buyerId := uuid.New().String()
+	require.Nil(t, bidUseCase.CreateBid(ctx, bid_usecase.BidInputDTO{
+		UserId:    buyerId,
+		AuctionId: auctionEntity.Id,
+		Amount:    500,
+	}))
+
+	// O fechamento é síncrono, sem esperar o lote
+	closed, err := auctionRepository.FindAuctionById(ctx, auctionEntity.Id)
+	require.Nil(t, err)
+	require.Equal(t, auction_entity.Completed, closed.Status)
+	require.NotEmpty(t, closed.WinningBidId)
+
+	winningBid, err := bidRepository.FindWinningBidByAuctionId(ctx, auctionEntity.Id)
+	require.Nil(t, err)
+	require.Equal(t, closed.WinningBidId, winningBid.Id)
+	require.Equal(t, buyerId, winningBid.UserId)
+	require.Equal(t, bid_entity.AmountFromCents(500_00), winningBid.Amount)
+
+	t.Run("second buy now bid is rejected", func(t *testing.T) {
+		err := bidUseCase.CreateBid(ctx, bid_usecase.BidInputDTO{
+			UserId:    uuid.New().String(),
+			AuctionId: auctionEntity.Id,
+			Amount:    600,
+		})
+		require.NotNil(t, err)
+		require.Equal(t, internal_error.CodeAuctionNotActive, err.Code)
+	})
+
+	t.Run("losing the race to close is rejected", func(t *testing.T) {
+		// Simula um lance que leu o leilão ainda ativo antes do primeiro fechar
+		lateBid, err := bid_entity.CreateBid(uuid.New().String(), auctionEntity.Id,
+			bid_entity.AmountFromCents(700_00), "")
+		require.Nil(t, err)
+
+		err = auctionRepository.CompleteWithBuyNow(ctx, *lateBid)
+		require.NotNil(t, err)
+		require.Equal(t, internal_error.CodeAuctionNotActive, err.Code)
+
+		winningBid, err := bidRepository.FindWinningBidByAuctionId(ctx, auctionEntity.Id)
+		require.Nil(t, err)
+		require.Equal(t, buyerId, winningBid.UserId)
+	})
+}
+
+func TestBuyNowRejectedOnExpiredAuction(t *testing.T) {
+	// O closer não pode fechar o leilão expirado no meio do teste
+	t.Setenv("AUCTION_CHECK_INTERVAL", "1h")
+
+	ctx := context.Background()
+	bidRepository, auctionRepository := newTestRepositories(ctx, t)
+
+	// Expirado, mas ainda Active até o closer passar
+	auctionEntity := &auction_entity.Auction{
+		Id:          uuid.New().String(),
+		ProductName: "Buy Now Product",
+		Category:    "Test Category",
+		Description: "Expired auction still waiting for the closer",
+		Condition:   auction_entity.New,
+		Status:      auction_entity.Active,
+		BuyNowPrice: 500,
+		Timestamp:   time.Now().Add(-2 * time.Hour),
+		ExpiresAt:   time.Now().Add(-time.Minute),
+	}
+	if _, err := auctionRepository.CreateAuction(ctx, auctionEntity); err != nil {
+		t.Fatalf("Failed to create auction: %v", err)
+	}
+
+	buyNowBid, err := bid_entity.CreateBid(uuid.New().String(), auctionEntity.Id,
+		bid_entity.AmountFromCents(500_00), "")
+	require.Nil(t, err)
+
+	err = auctionRepository.CompleteWithBuyNow(ctx, *buyNowBid)
+	require.NotNil(t, err)
+	require.Equal(t, internal_error.CodeAuctionNotActive, err.Code)
+
+	stored, err := auctionRepository.FindAuctionById(ctx, auctionEntity.Id)
+	require.Nil(t, err)
+	require.Equal(t, auction_entity.Active, stored.Status)
+	require.Empty(t, stored.WinningBidId)
+
+	_, err = bidRepository.FindWinningBidByAuctionId(ctx, auctionEntity.Id)
+	require.NotNil(t, err)
+	require.Equal(t, "not_found", err.Err)
+}
@@ -6,116 +6,174 @@ import (
 	"fullcycle-auction_go/internal/entity/auction_entity"
 	"fullcycle-auction_go/internal/entity/bid_entity"
 	"fullcycle-auction_go/internal/infra/database/auction"
+	"fullcycle-auction_go/internal/infra/database/decimal"
 	"fullcycle-auction_go/internal/internal_error"
-	"os"
 	"sync"
 	"time"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
 type BidEntityMongo struct {
-	Id        string  `bson:"_id"`
-	UserId    string  `bson:"user_id"`
-	AuctionId string  `bson:"auction_id"`
-	Amount    float64 `bson:"amount"`
-	Timestamp int64   `bson:"timestamp"`
+	Id        string         `bson:"_id"`
+	UserId    string         `bson:"user_id"`
+	AuctionId string         `bson:"auction_id"`
+	Amount    decimal.Amount `bson:"amount"`
+	Timestamp int64          `bson:"timestamp"`
+	Currency  string         `bson:"currency,omitempty"`
 }
 
 type BidRepository struct {
-	Collection            *mongo.Collection
-	AuctionRepository     *auction.AuctionRepository
-	auctionInterval       time.Duration
-	auctionStatusMap      map[string]auction_entity.AuctionStatus
-	auctionEndTimeMap     map[string]time.Time
-	auctionStatusMapMutex *sync.Mutex
-	auctionEndTimeMutex   *sync.Mutex
+	Collection        *mongo.Collection
+	AuctionRepository *auction.AuctionRepository
+
+	batchSize     int
+	batchInterval time.Duration
+	pendingBids   chan batchedBid
+	batcherOnce   *sync.Once
+	batcherMu     *sync.RWMutex
+	closeOnce     *sync.Once
+	batcherDone   chan struct{}
+	batcherWg     *sync.WaitGroup
 }
 
 func NewBidRepository(database *mongo.Database, auctionRepository *auction.AuctionRepository) *BidRepository {
-	return &BidRepository{
-		auctionInterval:       getAuctionInterval(),
-		auctionStatusMap:      make(map[string]auction_entity.AuctionStatus),
-		auctionEndTimeMap:     make(map[string]time.Time),
-		auctionStatusMapMutex: &sync.Mutex{},
-		auctionEndTimeMutex:   &sync.Mutex{},
-		Collection:            database.Collection("bids"),
-		AuctionRepository:     auctionRepository,
+	batchSize := getBidInsertBatchSize()
+
+	bidRepository := &BidRepository{
+		// Os lances seguem o write concern e a read preference da coleção de
+		// leilões (auction.WithWriteConcern, auction.WithReadPreference)
+		Collection:        database.Collection("bids", auctionRepository.CollectionOptions()),
+		AuctionRepository: auctionRepository,
+		batchSize:         batchSize,
+		batchInterval:     getBidInsertBatchInterval(),
+		pendingBids:       make(chan batchedBid, batchSize),
+		batcherOnce:       &sync.Once{},
+		batcherMu:         &sync.RWMutex{},
+		closeOnce:         &sync.Once{},
+		batcherDone:       make(chan struct{}),
+		batcherWg:         &sync.WaitGroup{},
 	}
+	// O repositório de leilões lê e grava lances só por este repositório
+	auctionRepository.SetBidStore(bidRepository)
+
+	return bidRepository
 }
 
+// CreateBid grava os lances de forma independente: uma falha não impede a
+// gravação dos demais. O erro retornado é o primeiro encontrado e lista em
+// FailedIds os lances que não foram gravados.
 func (bd *BidRepository) CreateBid(
 	ctx context.Context,
 	bidEntities []bid_entity.Bid) *internal_error.InternalError {
 	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var firstErr *internal_error.InternalError
+	var failedIds []string
+
 	for _, bid := range bidEntities {
 		wg.Add(1)
 		go func(bidValue bid_entity.Bid) {
 			defer wg.Done()
 
-			bd.auctionStatusMapMutex.Lock()
-			auctionStatus, okStatus := bd.auctionStatusMap[bidValue.AuctionId]
-			bd.auctionStatusMapMutex.Unlock()
-
-			bd.auctionEndTimeMutex.Lock()
-			auctionEndTime, okEndTime := bd.auctionEndTimeMap[bidValue.AuctionId]
-			bd.auctionEndTimeMutex.Unlock()
+			if err := bd.createBid(ctx, bidValue); err != nil {
+				mu.Lock()
+				defer mu.Unlock()
 
-			bidEntityMongo := &BidEntityMongo{
-				Id:        bidValue.Id,
-				UserId:    bidValue.UserId,
-				AuctionId: bidValue.AuctionId,
-				Amount:    bidValue.Amount,
-				Timestamp: bidValue.Timestamp.Unix(),
+				if firstErr == nil {
+					firstErr = err
+				}
+				failedIds = append(failedIds, bidValue.Id)
 			}
+		}(bid)
+	}
+	wg.Wait()
 
-			if okEndTime && okStatus {
-				now := time.Now()
-				if auctionStatus == auction_entity.Completed || now.After(auctionEndTime) {
-					return
-				}
+	if firstErr == nil {
+		return nil
+	}
 
-				if _, err := bd.Collection.InsertOne(ctx, bidEntityMongo); err != nil {
-					logger.Error("Error trying to insert bid", err)
-					return
-				}
+	return firstErr.WithFailedIds(failedIds)
+}
 
-				return
-			}
+func (bd *BidRepository) createBid(
+	ctx context.Context,
+	bidValue bid_entity.Bid) *internal_error.InternalError {
+	activeAuction, err := bd.checkAuctionIsActive(ctx, bidValue.AuctionId, bidValue.Timestamp)
+	if err != nil {
+		return err
+	}
+	if err := checkBidCurrency(activeAuction, bidValue); err != nil {
+		return err
+	}
 
-			auctionEntity, err := bd.AuctionRepository.FindAuctionById(ctx, bidValue.AuctionId)
-			if err != nil {
-				logger.Error("Error trying to find auction by id", err)
-				return
-			}
-			if auctionEntity.Status == auction_entity.Completed {
-				return
-			}
+	bidEntityMongo := &BidEntityMongo{
+		Id:        bidValue.Id,
+		UserId:    bidValue.UserId,
+		AuctionId: bidValue.AuctionId,
+		Amount:    decimal.Amount(bidValue.Amount),
+		Timestamp: bidValue.Timestamp.Unix(),
+		Currency:  bidValue.Currency,
+	}
 
-			bd.auctionStatusMapMutex.Lock()
-			bd.auctionStatusMap[bidValue.AuctionId] = auctionEntity.Status
-			bd.auctionStatusMapMutex.Unlock()
+	if _, err := bd.Collection.InsertOne(ctx, bidEntityMongo); err != nil {
+		logger.Error("Error trying to insert bid", err)
+		return internal_error.NewInternalServerError("Error trying to insert bid")
+	}
 
-			bd.auctionEndTimeMutex.Lock()
-			bd.auctionEndTimeMap[bidValue.AuctionId] = auctionEntity.Timestamp.Add(bd.auctionInterval)
-			bd.auctionEndTimeMutex.Unlock()
+	// O closer pode ter fechado o leilão entre a verificação e a inserção;
+	// nesse caso o lance é desfeito para nunca ficar em um leilão encerrado
+	auctionEntity, err := bd.AuctionRepository.FindAuctionById(ctx, bidValue.AuctionId)
+	if err != nil || auctionEntity.Status != auction_entity.Active {
+		if _, err := bd.Collection.DeleteOne(ctx, bson.M{"_id": bidValue.Id}); err != nil {
+			logger.Error("Error trying to remove bid from inactive auction", err)
+		}
 
-			if _, err := bd.Collection.InsertOne(ctx, bidEntityMongo); err != nil {
-				logger.Error("Error trying to insert bid", err)
-				return
-			}
-		}(bid)
+		if err != nil {
+			return err
+		}
+
+		return internal_error.NewBadRequestError("auction is not active").
+			WithCode(internal_error.CodeAuctionNotActive)
 	}
-	wg.Wait()
+
 	return nil
 }
 
-func getAuctionInterval() time.Duration {
-	auctionInterval := os.Getenv("AUCTION_INTERVAL")
-	duration, err := time.ParseDuration(auctionInterval)
+// checkAuctionIsActive recusa lances em leilões que não estão ativos ou que
+// já tinham expirado em placedAt, o horário do lance. O prazo é comparado com
+// o lance, e não com a gravação, que nos lotes pode vir bem depois.
+func (bd *BidRepository) checkAuctionIsActive(ctx context.Context,
+	auctionId string, placedAt time.Time) (*auction_entity.Auction, *internal_error.InternalError) {
+	// O status não é mantido em cache: um leilão fechado pode ser reaberto
+	auctionEntity, err := bd.AuctionRepository.FindAuctionById(ctx, auctionId)
 	if err != nil {
-		return time.Minute * 5
+		logger.Error("Error trying to find auction by id", err)
+		return nil, err
 	}
 
-	return duration
+	if err := bd.checkAuctionAcceptsBid(auctionEntity, placedAt); err != nil {
+		return nil, err
+	}
+
+	return auctionEntity, nil
+}
+
+// checkAuctionAcceptsBid aplica o AUCTION_CLOSE_GRACE: durante ele o leilão
+// expirado ainda aceita lances.
+func (bd *BidRepository) checkAuctionAcceptsBid(
+	auctionEntity *auction_entity.Auction, placedAt time.Time) *internal_error.InternalError {
+	return auctionEntity.CheckAcceptsBid(placedAt, bd.AuctionRepository.CloseGrace())
+}
+
+// checkBidCurrency recusa lances em moeda diferente da do leilão; os valores
+// não seriam comparáveis com os demais lances nem com os preços do leilão.
+func checkBidCurrency(
+	auctionEntity *auction_entity.Auction, bidValue bid_entity.Bid) *internal_error.InternalError {
+	if bid_entity.CurrencyOrDefault(bidValue.Currency) != auctionEntity.Currency {
+		return internal_error.NewBadRequestError("bid currency must match the auction currency")
+	}
+
+	return nil
 }
@@ -0,0 +1,259 @@
+package bid
+
+import (
+	"context"
+	"errors"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/entity/bid_entity"
+	"fullcycle-auction_go/internal/infra/database/decimal"
+	"fullcycle-auction_go/internal/internal_error"
+	"os"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultBidInsertBatchSize     = 100
+	defaultBidInsertBatchInterval = 50 * time.Millisecond
+	batchFlushTimeout             = 10 * time.Second
+)
+
+type batchedBid struct {
+	bid    bid_entity.Bid
+	result chan *internal_error.InternalError
+}
+
+// CreateBidBatched enfileira o lance para ser gravado junto com outros em um
+// único InsertMany, disparado a cada BID_INSERT_BATCH_SIZE lances ou a cada
+// BID_INSERT_BATCH_INTERVAL. O resultado do lance chega pelo canal retornado.
+// Um único worker grava os lotes em ordem, então lances do mesmo leilão são
+// persistidos na ordem em que foram enfileirados.
+func (bd *BidRepository) CreateBidBatched(
+	ctx context.Context, bid bid_entity.Bid) <-chan *internal_error.InternalError {
+	result := make(chan *internal_error.InternalError, 1)
+
+	bd.batcherOnce.Do(func() {
+		bd.batcherWg.Add(1)
+		go func() {
+			defer bd.batcherWg.Done()
+			bd.runBatcher()
+		}()
+	})
+
+	// O lock de leitura impede que o Close feche o batcherDone no meio do
+	// envio: um lance colocado no buffer depois do worker sair nunca teria
+	// resposta
+	bd.batcherMu.RLock()
+	defer bd.batcherMu.RUnlock()
+
+	select {
+	case <-bd.batcherDone:
+		result <- internal_error.NewInternalServerError("bid repository is closed")
+		return result
+	default:
+	}
+
+	select {
+	case bd.pendingBids <- batchedBid{bid: bid, result: result}:
+	case <-ctx.Done():
+		result <- internal_error.NewTimeoutError("Timeout trying to enqueue bid")
+	}
+
+	return result
+}
+
+// Close grava os lances ainda enfileirados e encerra o worker de lotes.
+// Lances enviados depois dele são recusados.
+func (bd *BidRepository) Close() {
+	bd.closeOnce.Do(func() {
+		bd.batcherMu.Lock()
+		defer bd.batcherMu.Unlock()
+
+		close(bd.batcherDone)
+	})
+	bd.batcherWg.Wait()
+}
+
+func (bd *BidRepository) runBatcher() {
+	ticker := time.NewTicker(bd.batchInterval)
+	defer ticker.Stop()
+
+	batch := make([]batchedBid, 0, bd.batchSize)
+	for {
+		select {
+		case pending := <-bd.pendingBids:
+			batch = append(batch, pending)
+			if len(batch) >= bd.batchSize {
+				bd.flushBatch(batch)
+				batch = make([]batchedBid, 0, bd.batchSize)
+			}
+		case <-ticker.C:
+			if len(batch) > 0 {
+				bd.flushBatch(batch)
+				batch = make([]batchedBid, 0, bd.batchSize)
+			}
+		case <-bd.batcherDone:
+			for {
+				select {
+				case pending := <-bd.pendingBids:
+					batch = append(batch, pending)
+				default:
+					if len(batch) > 0 {
+						bd.flushBatch(batch)
+					}
+					return
+				}
+			}
+		}
+	}
+}
+
+func (bd *BidRepository) flushBatch(batch []batchedBid) {
+	ctx, cancel := context.WithTimeout(context.Background(), batchFlushTimeout)
+	defer cancel()
+
+	// Cada leilão é consultado uma única vez por lote; o prazo é verificado
+	// lance a lance, pelo horário de cada um
+	auctions := map[string]*auction_entity.Auction{}
+	auctionErrors := map[string]*internal_error.InternalError{}
+	checkAuction := func(bid bid_entity.Bid) (*auction_entity.Auction, *internal_error.InternalError) {
+		auctionEntity, ok := auctions[bid.AuctionId]
+		if !ok {
+			var err *internal_error.InternalError
+			auctionEntity, err = bd.AuctionRepository.FindAuctionById(ctx, bid.AuctionId)
+			if err != nil {
+				logger.Error("Error trying to find auction by id", err)
+			}
+			auctions[bid.AuctionId] = auctionEntity
+			auctionErrors[bid.AuctionId] = err
+		}
+		if err := auctionErrors[bid.AuctionId]; err != nil {
+			return nil, err
+		}
+
+		if err := bd.checkAuctionAcceptsBid(auctionEntity, bid.Timestamp); err != nil {
+			return nil, err
+		}
+		return auctionEntity, nil
+	}
+
+	var accepted []batchedBid
+	var documents []interface{}
+	for _, pending := range batch {
+		activeAuction, err := checkAuction(pending.bid)
+		if err == nil {
+			err = checkBidCurrency(activeAuction, pending.bid)
+		}
+		if err != nil {
+			pending.result <- err
+			continue
+		}
+
+		accepted = append(accepted, pending)
+		documents = append(documents, BidEntityMongo{
+			Id:        pending.bid.Id,
+			UserId:    pending.bid.UserId,
+			AuctionId: pending.bid.AuctionId,
+			Amount:    decimal.Amount(pending.bid.Amount),
+			Timestamp: pending.bid.Timestamp.Unix(),
+			Currency:  pending.bid.Currency,
+		})
+	}
+
+	if len(documents) == 0 {
+		return
+	}
+
+	accepted = bd.insertBatch(ctx, accepted, documents)
+
+	// Mesma proteção do createBid: lances gravados em um leilão que o closer
+	// fechou durante a inserção são desfeitos
+	closedAuctions := map[string]bool{}
+	for _, pending := range accepted {
+		auctionId := pending.bid.AuctionId
+		if _, checked := closedAuctions[auctionId]; checked {
+			continue
+		}
+
+		auctionEntity, findErr := bd.AuctionRepository.FindAuctionById(ctx, auctionId)
+		closedAuctions[auctionId] = findErr != nil || auctionEntity.Status != auction_entity.Active
+	}
+
+	var removedIds []string
+	for _, pending := range accepted {
+		if closedAuctions[pending.bid.AuctionId] {
+			removedIds = append(removedIds, pending.bid.Id)
+		}
+	}
+	if len(removedIds) > 0 {
+		if _, err := bd.Collection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": removedIds}}); err != nil {
+			logger.Error("Error trying to remove bids from inactive auction", err)
+		}
+	}
+
+	for _, pending := range accepted {
+		if closedAuctions[pending.bid.AuctionId] {
+			pending.result <- internal_error.NewBadRequestError("auction is not active").
+				WithCode(internal_error.CodeAuctionNotActive)
+			continue
+		}
+		pending.result <- nil
+	}
+}
+
+// insertBatch grava os documentos com InsertMany ordenado. Quando um lance
+// falha, os anteriores já foram gravados e a inserção continua a partir do
+// seguinte, mantendo a ordem. Retorna os lances gravados com sucesso.
+func (bd *BidRepository) insertBatch(
+	ctx context.Context, accepted []batchedBid, documents []interface{}) []batchedBid {
+	var inserted []batchedBid
+
+	for offset := 0; offset < len(documents); {
+		_, err := bd.Collection.InsertMany(ctx, documents[offset:], options.InsertMany().SetOrdered(true))
+		if err == nil {
+			inserted = append(inserted, accepted[offset:]...)
+			break
+		}
+
+		logger.Error("Error trying to insert bid batch", err, zap.Int("size", len(documents)-offset))
+
+		var bulkWriteException mongo.BulkWriteException
+		if !errors.As(err, &bulkWriteException) || len(bulkWriteException.WriteErrors) == 0 {
+			for _, pending := range accepted[offset:] {
+				pending.result <- internal_error.NewInternalServerError("Error trying to insert bid")
+			}
+			break
+		}
+
+		failed := offset + bulkWriteException.WriteErrors[0].Index
+		inserted = append(inserted, accepted[offset:failed]...)
+		accepted[failed].result <- internal_error.NewInternalServerError("Error trying to insert bid")
+		offset = failed + 1
+	}
+
+	return inserted
+}
+
+func getBidInsertBatchSize() int {
+	value, err := strconv.Atoi(os.Getenv("BID_INSERT_BATCH_SIZE"))
+	if err != nil || value <= 0 {
+		return defaultBidInsertBatchSize
+	}
+
+	return value
+}
+
+func getBidInsertBatchInterval() time.Duration {
+	duration, err := time.ParseDuration(os.Getenv("BID_INSERT_BATCH_INTERVAL"))
+	if err != nil || duration <= 0 {
+		return defaultBidInsertBatchInterval
+	}
+
+	return duration
+}
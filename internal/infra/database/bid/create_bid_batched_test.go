@@ -0,0 +1,196 @@
+package bid
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/entity/bid_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestCreateBidBatched(t *testing.T) {
+	t.Setenv("BID_INSERT_BATCH_SIZE", "4")
+	t.Setenv("BID_INSERT_BATCH_INTERVAL", "20ms")
+
+	ctx := context.Background()
+	bidRepository, auctionRepository := newTestRepositories(ctx, t)
+
+	activeAuctionId := createTestAuction(ctx, t, auctionRepository, auction_entity.Active)
+	completedAuctionId := createTestAuction(ctx, t, auctionRepository, auction_entity.Completed)
+
+	var activeBids []bid_entity.Bid
+	var results []<-chan *internal_error.InternalError
+	for amount := 1; amount <= 10; amount++ {
+		bidEntity, err := bid_entity.CreateBid(uuid.New().String(), activeAuctionId, bid_entity.AmountFromCents(int64(amount)*100), "")
+		require.Nil(t, err)
+
+		activeBids = append(activeBids, *bidEntity)
+		results = append(results, bidRepository.CreateBidBatched(ctx, *bidEntity))
+	}
+
+	rejectedBid, err := bid_entity.CreateBid(uuid.New().String(), completedAuctionId, bid_entity.AmountFromCents(100_00), "")
+	require.Nil(t, err)
+	rejectedResult := bidRepository.CreateBidBatched(ctx, *rejectedBid)
+
+	for _, result := range results {
+		require.Nil(t, <-result)
+	}
+
+	rejectedErr := <-rejectedResult
+	require.NotNil(t, rejectedErr)
+	require.Equal(t, "auction is not active", rejectedErr.Message)
+
+	// Os lances do mesmo leilão são gravados na ordem em que foram enfileirados
+	// (sem sort, a coleção nova devolve os documentos na ordem de inserção)
+	cursor, findErr := bidRepository.Collection.Find(ctx, bson.M{"auction_id": activeAuctionId})
+	require.NoError(t, findErr)
+
+	var stored []BidEntityMongo
+	require.NoError(t, cursor.All(ctx, &stored))
+	require.Len(t, stored, len(activeBids))
+	for i, bid := range stored {
+		require.Equal(t, activeBids[i].Id, bid.Id)
+	}
+
+	count, countErr := bidRepository.Collection.CountDocuments(ctx, bson.M{"auction_id": completedAuctionId})
+	require.NoError(t, countErr)
+	require.Equal(t, int64(0), count)
+}
+
+func TestCreateBidBatchedChecksExpiryAtBidTime(t *testing.T) {
+	t.Setenv("BID_INSERT_BATCH_INTERVAL", "20ms")
+	// O closer não pode fechar o leilão expirado no meio do teste
+	t.Setenv("AUCTION_CHECK_INTERVAL", "1h")
+
+	ctx := context.Background()
+	bidRepository, auctionRepository := newTestRepositories(ctx, t)
+
+	auctionId := createTestAuction(ctx, t, auctionRepository, auction_entity.Active)
+	expiresAt := time.Now().Add(-time.Second)
+	_, updateErr := auctionRepository.Collection.UpdateOne(ctx,
+		bson.M{"_id": auctionId},
+		bson.M{"$set": bson.M{"expires_at": expiresAt.Unix()}})
+	require.NoError(t, updateErr)
+
+	// O lance dado antes da expiração vale, mesmo gravado depois dela
+	placedInTime, err := bid_entity.CreateBid(uuid.New().String(), auctionId, bid_entity.AmountFromCents(100_00), "")
+	require.Nil(t, err)
+	placedInTime.Timestamp = expiresAt.Add(-time.Minute)
+	require.Nil(t, <-bidRepository.CreateBidBatched(ctx, *placedInTime))
+
+	placedLate, err := bid_entity.CreateBid(uuid.New().String(), auctionId, bid_entity.AmountFromCents(200_00), "")
+	require.Nil(t, err)
+	lateErr := <-bidRepository.CreateBidBatched(ctx, *placedLate)
+	require.NotNil(t, lateErr)
+	require.Equal(t, internal_error.CodeAuctionNotActive, lateErr.Code)
+
+	count, countErr := bidRepository.Collection.CountDocuments(ctx, bson.M{"auction_id": auctionId})
+	require.NoError(t, countErr)
+	require.Equal(t, int64(1), count)
+}
+
+func TestCreateBidBatchedAfterClose(t *testing.T) {
+	ctx := context.Background()
+	bidRepository, auctionRepository := newTestRepositories(ctx, t)
+
+	auctionId := createTestAuction(ctx, t, auctionRepository, auction_entity.Active)
+	bidEntity, err := bid_entity.CreateBid(uuid.New().String(), auctionId, bid_entity.AmountFromCents(100_00), "")
+	require.Nil(t, err)
+
+	// O primeiro lance inicia o worker, que o Close grava e encerra
+	require.Nil(t, <-bidRepository.CreateBidBatched(ctx, *bidEntity))
+	bidRepository.Close()
+
+	// Com espaço no buffer, o envio não pode ser aceito sem worker para gravá-lo
+	for i := 0; i < 10; i++ {
+		select {
+		case createErr := <-bidRepository.CreateBidBatched(ctx, *bidEntity):
+			require.NotNil(t, createErr)
+			require.Equal(t, "bid repository is closed", createErr.Message)
+		case <-time.After(time.Second):
+			t.Fatal("bid enqueued after Close never got a result")
+		}
+	}
+}
+
+func TestCreateBidBatchedWithFailedInsert(t *testing.T) {
+	t.Setenv("BID_INSERT_BATCH_SIZE", "4")
+	t.Setenv("BID_INSERT_BATCH_INTERVAL", "1h")
+
+	ctx := context.Background()
+	bidRepository, auctionRepository := newTestRepositories(ctx, t)
+
+	auctionId := createTestAuction(ctx, t, auctionRepository, auction_entity.Active)
+
+	var bids []bid_entity.Bid
+	for amount := 1; amount <= 4; amount++ {
+		bidEntity, err := bid_entity.CreateBid(uuid.New().String(), auctionId, bid_entity.AmountFromCents(int64(amount)*100), "")
+		require.Nil(t, err)
+		bids = append(bids, *bidEntity)
+	}
+
+	// O terceiro lance já existe, então apenas ele deve falhar no lote
+	_, insertErr := bidRepository.Collection.InsertOne(ctx, BidEntityMongo{Id: bids[2].Id, AuctionId: "another-auction"})
+	require.NoError(t, insertErr)
+
+	var results []<-chan *internal_error.InternalError
+	for _, bid := range bids {
+		results = append(results, bidRepository.CreateBidBatched(ctx, bid))
+	}
+
+	for i, result := range results {
+		err := <-result
+		if i == 2 {
+			require.NotNil(t, err)
+			continue
+		}
+		require.Nil(t, err)
+	}
+
+	count, countErr := bidRepository.Collection.CountDocuments(ctx, bson.M{"auction_id": auctionId})
+	require.NoError(t, countErr)
+	require.Equal(t, int64(3), count)
+}
+
+const benchmarkParallelism = 50
+
+func BenchmarkCreateBid(b *testing.B) {
+	ctx := context.Background()
+	bidRepository, auctionRepository := newTestRepositories(ctx, b)
+	auctionId := createTestAuction(ctx, b, auctionRepository, auction_entity.Active)
+
+	// Muitos lances simultâneos, como em um leilão disputado
+	b.SetParallelism(benchmarkParallelism)
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			bidEntity, _ := bid_entity.CreateBid(uuid.New().String(), auctionId, bid_entity.AmountFromCents(10_00), "")
+			if err := bidRepository.CreateBid(ctx, []bid_entity.Bid{*bidEntity}); err != nil {
+				b.Fatalf("Failed to create bid: %v", err)
+			}
+		}
+	})
+}
+
+func BenchmarkCreateBidBatched(b *testing.B) {
+	ctx := context.Background()
+	bidRepository, auctionRepository := newTestRepositories(ctx, b)
+	auctionId := createTestAuction(ctx, b, auctionRepository, auction_entity.Active)
+
+	// Muitos lances simultâneos, como em um leilão disputado
+	b.SetParallelism(benchmarkParallelism)
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			bidEntity, _ := bid_entity.CreateBid(uuid.New().String(), auctionId, bid_entity.AmountFromCents(10_00), "")
+			if err := <-bidRepository.CreateBidBatched(ctx, *bidEntity); err != nil {
+				b.Fatalf("Failed to create bid: %v", err)
+			}
+		}
+	})
+}
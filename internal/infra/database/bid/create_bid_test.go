@@ -0,0 +1,300 @@
+package bid
+
+import (
+	"context"
+	"fmt"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/entity/bid_entity"
+	"fullcycle-auction_go/internal/infra/database/auction"
+	"fullcycle-auction_go/internal/infra/database/decimal"
+	"fullcycle-auction_go/internal/internal_error"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go/modules/mongodb"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+)
+
+func getTestDatabase(ctx context.Context, t testing.TB) (*mongo.Client, *mongo.Database, *mongodb.MongoDBContainer) {
+	t.Helper()
+
+	mongoContainer, err := mongodb.Run(ctx, "mongo:latest")
+	require.NoError(t, err)
+
+	mongoURL, err := mongoContainer.ConnectionString(ctx)
+	require.NoError(t, err)
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(mongoURL))
+	if err != nil {
+		t.Skipf("Skipping test: could not connect to MongoDB: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	if err := client.Ping(ctx, nil); err != nil {
+		t.Skipf("Skipping test: MongoDB not available: %v", err)
+	}
+
+	return client, client.Database(fmt.Sprintf("bids_test_%d", time.Now().UnixNano())), mongoContainer
+}
+
+func newTestRepositories(ctx context.Context, t testing.TB) (*BidRepository, *auction.AuctionRepository) {
+	t.Helper()
+
+	t.Setenv("AUCTION_INTERVAL", "1h")
+
+	client, db, container := getTestDatabase(ctx, t)
+	t.Cleanup(func() {
+		db.Drop(ctx)
+		if err := client.Disconnect(ctx); err != nil {
+			log.Printf("failed to disconnect client: %s", err)
+		}
+		if err := container.Terminate(ctx); err != nil {
+			log.Printf("failed to terminate container: %s", err)
+		}
+	})
+
+	auctionRepository := auction.NewAuctionRepository(ctx, db)
+	t.Cleanup(auctionRepository.Close)
+
+	bidRepository := NewBidRepository(db, auctionRepository)
+	t.Cleanup(bidRepository.Close)
+
+	return bidRepository, auctionRepository
+}
+
+func createTestAuction(
+	ctx context.Context, t testing.TB,
+	auctionRepository *auction.AuctionRepository,
+	status auction_entity.AuctionStatus) string {
+	t.Helper()
+
+	auctionEntity := &auction_entity.Auction{
+		Id:          uuid.New().String(),
+		ProductName: "Bid Product",
+		Category:    "Test Category",
+		Description: "Auction used by the bid tests",
+		Condition:   auction_entity.New,
+		Status:      status,
+		Timestamp:   time.Now(),
+		ExpiresAt:   time.Now().Add(time.Hour),
+	}
+
+	if _, err := auctionRepository.CreateAuction(ctx, auctionEntity); err != nil {
+		t.Fatalf("Failed to create auction: %v", err)
+	}
+
+	return auctionEntity.Id
+}
+
+func TestCreateBidOnActiveAuction(t *testing.T) {
+	ctx := context.Background()
+	bidRepository, auctionRepository := newTestRepositories(ctx, t)
+
+	auctionId := createTestAuction(ctx, t, auctionRepository, auction_entity.Active)
+
+	bidEntity, err := bid_entity.CreateBid(uuid.New().String(), auctionId, bid_entity.AmountFromCents(100_00), "")
+	require.Nil(t, err)
+
+	require.Nil(t, bidRepository.CreateBid(ctx, []bid_entity.Bid{*bidEntity}))
+
+	count, countErr := bidRepository.Collection.CountDocuments(ctx, bson.M{"auction_id": auctionId})
+	require.NoError(t, countErr)
+	require.Equal(t, int64(1), count)
+}
+
+func TestCreateBidCurrency(t *testing.T) {
+	ctx := context.Background()
+	bidRepository, auctionRepository := newTestRepositories(ctx, t)
+
+	auctionEntity := &auction_entity.Auction{
+		Id:          uuid.New().String(),
+		ProductName: "Imported Product",
+		Category:    "Test Category",
+		Description: "Auction priced in dollars",
+		Condition:   auction_entity.New,
+		Status:      auction_entity.Active,
+		Currency:    "USD",
+		Timestamp:   time.Now(),
+		ExpiresAt:   time.Now().Add(time.Hour),
+	}
+	_, createErr := auctionRepository.CreateAuction(ctx, auctionEntity)
+	require.Nil(t, createErr)
+
+	eurBid, err := bid_entity.CreateBid(uuid.New().String(), auctionEntity.Id, bid_entity.AmountFromCents(100_00), "EUR")
+	require.Nil(t, err)
+
+	createErr = bidRepository.CreateBid(ctx, []bid_entity.Bid{*eurBid})
+	require.NotNil(t, createErr)
+	require.Equal(t, "bad_request", createErr.Err)
+	require.Equal(t, "bid currency must match the auction currency", createErr.Message)
+
+	for _, cents := range []int64{100_00, 250_00} {
+		usdBid, err := bid_entity.CreateBid(uuid.New().String(), auctionEntity.Id, bid_entity.AmountFromCents(cents), "USD")
+		require.Nil(t, err)
+		require.Nil(t, bidRepository.CreateBid(ctx, []bid_entity.Bid{*usdBid}))
+	}
+
+	count, countErr := bidRepository.Collection.CountDocuments(ctx, bson.M{"auction_id": auctionEntity.Id})
+	require.NoError(t, countErr)
+	require.Equal(t, int64(2), count)
+
+	// Um lance maior em outra moeda, gravado por fora, não vence o leilão
+	_, insertErr := bidRepository.Collection.InsertOne(ctx, BidEntityMongo{
+		Id:        uuid.New().String(),
+		UserId:    uuid.New().String(),
+		AuctionId: auctionEntity.Id,
+		Amount:    decimal.Amount(bid_entity.AmountFromCents(900_00)),
+		Timestamp: time.Now().Unix(),
+		Currency:  "EUR",
+	})
+	require.NoError(t, insertErr)
+
+	winningBid, findErr := bidRepository.FindWinningBidByAuctionId(ctx, auctionEntity.Id)
+	require.Nil(t, findErr)
+	require.Equal(t, bid_entity.AmountFromCents(250_00), winningBid.Amount)
+	require.Equal(t, "USD", winningBid.Currency)
+}
+
+func TestBidCollectionFollowsAuctionWriteConcern(t *testing.T) {
+	ctx := context.Background()
+	t.Setenv("AUCTION_INTERVAL", "1h")
+
+	client, db, container := getTestDatabase(ctx, t)
+	t.Cleanup(func() {
+		db.Drop(ctx)
+		if err := client.Disconnect(ctx); err != nil {
+			log.Printf("failed to disconnect client: %s", err)
+		}
+		if err := container.Terminate(ctx); err != nil {
+			log.Printf("failed to terminate container: %s", err)
+		}
+	})
+
+	auctionRepository := auction.NewAuctionRepositoryWithOptions(ctx, db,
+		auction.WithWriteConcern(writeconcern.Majority()),
+		auction.WithReadPreference(readpref.PrimaryPreferred()))
+	t.Cleanup(auctionRepository.Close)
+
+	bidRepository := NewBidRepository(db, auctionRepository)
+	t.Cleanup(bidRepository.Close)
+
+	collectionOptions := auctionRepository.CollectionOptions()
+	require.Equal(t, writeconcern.Majority(), collectionOptions.WriteConcern)
+	require.Equal(t, readpref.PrimaryPreferredMode, collectionOptions.ReadPreference.Mode())
+
+	auctionId := createTestAuction(ctx, t, auctionRepository, auction_entity.Active)
+	bidEntity, err := bid_entity.CreateBid(uuid.New().String(), auctionId, bid_entity.AmountFromCents(100_00), "")
+	require.Nil(t, err)
+	require.Nil(t, bidRepository.CreateBid(ctx, []bid_entity.Bid{*bidEntity}))
+}
+
+func TestCreateBidRejectedOnScheduledAuction(t *testing.T) {
+	ctx := context.Background()
+	bidRepository, auctionRepository := newTestRepositories(ctx, t)
+
+	auctionId := createTestAuction(ctx, t, auctionRepository, auction_entity.Scheduled)
+
+	bidEntity, err := bid_entity.CreateBid(uuid.New().String(), auctionId, bid_entity.AmountFromCents(100_00), "")
+	require.Nil(t, err)
+
+	createErr := bidRepository.CreateBid(ctx, []bid_entity.Bid{*bidEntity})
+	require.NotNil(t, createErr)
+	require.Equal(t, "bad_request", createErr.Err)
+	require.Equal(t, "auction has not started yet", createErr.Message)
+	require.Equal(t, internal_error.CodeAuctionNotActive, createErr.Code)
+
+	count, countErr := bidRepository.Collection.CountDocuments(ctx, bson.M{"auction_id": auctionId})
+	require.NoError(t, countErr)
+	require.Equal(t, int64(0), count)
+}
+
+func TestCreateBidRejectedOnCompletedAuction(t *testing.T) {
+	ctx := context.Background()
+	bidRepository, auctionRepository := newTestRepositories(ctx, t)
+
+	auctionId := createTestAuction(ctx, t, auctionRepository, auction_entity.Active)
+
+	// Fecha o leilão como o closer faria
+	_, updateErr := auctionRepository.Collection.UpdateOne(ctx,
+		bson.M{"_id": auctionId},
+		bson.M{"$set": bson.M{"status": auction_entity.Completed}})
+	require.NoError(t, updateErr)
+
+	bidEntity, err := bid_entity.CreateBid(uuid.New().String(), auctionId, bid_entity.AmountFromCents(100_00), "")
+	require.Nil(t, err)
+
+	createErr := bidRepository.CreateBid(ctx, []bid_entity.Bid{*bidEntity})
+	require.NotNil(t, createErr)
+	require.Equal(t, "bad_request", createErr.Err)
+	require.Equal(t, "auction is not active", createErr.Message)
+	require.Equal(t, internal_error.CodeAuctionNotActive, createErr.Code)
+
+	count, countErr := bidRepository.Collection.CountDocuments(ctx, bson.M{"auction_id": auctionId})
+	require.NoError(t, countErr)
+	require.Equal(t, int64(0), count)
+}
+
+func TestCreateBidDuringCloseGrace(t *testing.T) {
+	t.Setenv("AUCTION_CLOSE_GRACE", "1h")
+
+	ctx := context.Background()
+	bidRepository, auctionRepository := newTestRepositories(ctx, t)
+
+	auctionId := createTestAuction(ctx, t, auctionRepository, auction_entity.Active)
+
+	// Expirado há pouco, ainda dentro da tolerância
+	_, updateErr := auctionRepository.Collection.UpdateOne(ctx,
+		bson.M{"_id": auctionId},
+		bson.M{"$set": bson.M{"expires_at": time.Now().Add(-time.Minute).Unix()}})
+	require.NoError(t, updateErr)
+
+	bidEntity, err := bid_entity.CreateBid(uuid.New().String(), auctionId, bid_entity.AmountFromCents(100_00), "")
+	require.Nil(t, err)
+	require.Nil(t, bidRepository.CreateBid(ctx, []bid_entity.Bid{*bidEntity}))
+
+	// Depois da tolerância o lance é recusado mesmo antes de o closer rodar
+	_, updateErr = auctionRepository.Collection.UpdateOne(ctx,
+		bson.M{"_id": auctionId},
+		bson.M{"$set": bson.M{"expires_at": time.Now().Add(-2 * time.Hour).Unix()}})
+	require.NoError(t, updateErr)
+
+	bidEntity, err = bid_entity.CreateBid(uuid.New().String(), auctionId, bid_entity.AmountFromCents(200_00), "")
+	require.Nil(t, err)
+	createErr := bidRepository.CreateBid(ctx, []bid_entity.Bid{*bidEntity})
+	require.NotNil(t, createErr)
+	require.Equal(t, internal_error.CodeAuctionNotActive, createErr.Code)
+
+	count, countErr := bidRepository.Collection.CountDocuments(ctx, bson.M{"auction_id": auctionId})
+	require.NoError(t, countErr)
+	require.Equal(t, int64(1), count)
+}
+
+func TestCreateBidReportsFailedIds(t *testing.T) {
+	ctx := context.Background()
+	bidRepository, auctionRepository := newTestRepositories(ctx, t)
+
+	activeAuctionId := createTestAuction(ctx, t, auctionRepository, auction_entity.Active)
+	completedAuctionId := createTestAuction(ctx, t, auctionRepository, auction_entity.Completed)
+
+	storedBid, err := bid_entity.CreateBid(uuid.New().String(), activeAuctionId, bid_entity.AmountFromCents(100_00), "")
+	require.Nil(t, err)
+	failedBid, err := bid_entity.CreateBid(uuid.New().String(), completedAuctionId, bid_entity.AmountFromCents(100_00), "")
+	require.Nil(t, err)
+
+	createErr := bidRepository.CreateBid(ctx, []bid_entity.Bid{*storedBid, *failedBid})
+	require.NotNil(t, createErr)
+	require.Equal(t, []string{failedBid.Id}, createErr.FailedIds)
+
+	count, countErr := bidRepository.Collection.CountDocuments(ctx, bson.M{"_id": storedBid.Id})
+	require.NoError(t, countErr)
+	require.Equal(t, int64(1), count)
+}
@@ -0,0 +1,24 @@
+package bid
+
+import (
+	"context"
+	"fullcycle-auction_go/configuration/logger"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.uber.org/zap"
+)
+
+const cursorCloseTimeout = 5 * time.Second
+
+// closeCursor fecha o cursor num contexto próprio, como no repositório de
+// leilões: com o ctx da leitura já cancelado o driver não conseguiria enviar o
+// killCursors.
+func closeCursor(ctx context.Context, cursor *mongo.Cursor) {
+	closeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), cursorCloseTimeout)
+	defer cancel()
+
+	if err := cursor.Close(closeCtx); err != nil {
+		logger.Warn("Error trying to close cursor", zap.Error(err))
+	}
+}
@@ -0,0 +1,22 @@
+package bid
+
+import (
+	"context"
+	"fmt"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/internal_error"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func (bd *BidRepository) DeleteBidsByAuctionId(
+	ctx context.Context, auctionId string) (int64, *internal_error.InternalError) {
+	result, err := bd.Collection.DeleteMany(ctx, bson.M{"auction_id": auctionId})
+	if err != nil {
+		logger.Error(fmt.Sprintf("Error trying to delete bids by auctionId %s", auctionId), err)
+		return 0, internal_error.NewInternalServerError(
+			fmt.Sprintf("Error trying to delete bids by auctionId %s", auctionId))
+	}
+
+	return result.DeletedCount, nil
+}
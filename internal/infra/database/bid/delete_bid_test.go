@@ -0,0 +1,49 @@
+package bid
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/entity/bid_entity"
+	"fullcycle-auction_go/internal/usecase/auction_usecase"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestDeleteAuctionCascadesToBids(t *testing.T) {
+	ctx := context.Background()
+	bidRepository, auctionRepository := newTestRepositories(ctx, t)
+
+	auctionId := createTestAuction(ctx, t, auctionRepository, auction_entity.Active)
+	otherAuctionId := createTestAuction(ctx, t, auctionRepository, auction_entity.Active)
+
+	var bids []bid_entity.Bid
+	for _, id := range []string{auctionId, auctionId, otherAuctionId} {
+		bidEntity, err := bid_entity.CreateBid(uuid.New().String(), id, bid_entity.AmountFromCents(100_00), "")
+		require.Nil(t, err)
+		bids = append(bids, *bidEntity)
+	}
+	require.Nil(t, bidRepository.CreateBid(ctx, bids))
+
+	auctionUseCase := auction_usecase.NewAuctionUseCase(auctionRepository, bidRepository, auction_entity.DefaultRules())
+	require.Nil(t, auctionUseCase.DeleteAuction(ctx, auctionId))
+
+	_, findErr := auctionRepository.FindAuctionById(ctx, auctionId)
+	require.NotNil(t, findErr)
+	require.Equal(t, "not_found", findErr.Err)
+
+	count, countErr := bidRepository.Collection.CountDocuments(ctx, bson.M{"auction_id": auctionId})
+	require.NoError(t, countErr)
+	require.Equal(t, int64(0), count)
+
+	// Lances de outros leilões não podem ser afetados
+	count, countErr = bidRepository.Collection.CountDocuments(ctx, bson.M{"auction_id": otherAuctionId})
+	require.NoError(t, countErr)
+	require.Equal(t, int64(1), count)
+
+	deleteErr := auctionUseCase.DeleteAuction(ctx, auctionId)
+	require.NotNil(t, deleteErr)
+	require.Equal(t, "not_found", deleteErr.Err)
+}
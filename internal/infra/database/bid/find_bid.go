@@ -5,62 +5,215 @@ import (
 	"fmt"
 	"fullcycle-auction_go/configuration/logger"
 	"fullcycle-auction_go/internal/entity/bid_entity"
+	"fullcycle-auction_go/internal/infra/database/database_error"
+	"fullcycle-auction_go/internal/infra/database/sanitize"
 	"fullcycle-auction_go/internal/internal_error"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo/options"
-	"time"
+	"sort"
+	"strings"
 )
 
+const (
+	defaultBidListSort  = "timestamp"
+	defaultBidListLimit = 100
+	maxBidListLimit     = 500
+)
+
+// Campos aceitos na ordenação da listagem; o prefixo "-" inverte a ordem
+var bidListSortFields = map[string]string{
+	"amount":    "amount",
+	"timestamp": "timestamp",
+}
+
 func (bd *BidRepository) FindBidByAuctionId(
-	ctx context.Context, auctionId string) ([]bid_entity.Bid, *internal_error.InternalError) {
-	filter := bson.M{"auctionId": auctionId}
+	ctx context.Context, auctionId string, sort string, limit int64) ([]bid_entity.Bid, *internal_error.InternalError) {
+	sortOption, sortErr := bidListSort(sort)
+	if sortErr != nil {
+		return nil, sortErr
+	}
 
-	cursor, err := bd.Collection.Find(ctx, filter)
+	// Um leilão inexistente retorna not_found; um leilão sem lances, a lista
+	// vazia. Leilões Sealed não revelam os lances antes de fechar.
+	auctionEntity, auctionErr := bd.AuctionRepository.FindAuctionById(ctx, auctionId)
+	if auctionErr != nil {
+		return nil, auctionErr
+	}
+	if auctionEntity.BidsHidden() {
+		return []bid_entity.Bid{}, nil
+	}
+
+	if limit <= 0 {
+		limit = defaultBidListLimit
+	} else if limit > maxBidListLimit {
+		limit = maxBidListLimit
+	}
+
+	filter := bson.M{"auction_id": auctionId}
+	opts := options.Find().SetSort(sortOption).SetLimit(limit)
+
+	cursor, err := bd.Collection.Find(ctx, filter, opts)
 	if err != nil {
 		logger.Error(
 			fmt.Sprintf("Error trying to find bids by auctionId %s", auctionId), err)
-		return nil, internal_error.NewInternalServerError(
+		return nil, database_error.NewInternalError(err,
 			fmt.Sprintf("Error trying to find bids by auctionId %s", auctionId))
 	}
+	defer closeCursor(ctx, cursor)
 
 	var bidEntitiesMongo []BidEntityMongo
 	if err := cursor.All(ctx, &bidEntitiesMongo); err != nil {
 		logger.Error(
 			fmt.Sprintf("Error trying to find bids by auctionId %s", auctionId), err)
-		return nil, internal_error.NewInternalServerError(
+		return nil, database_error.NewInternalError(err,
 			fmt.Sprintf("Error trying to find bids by auctionId %s", auctionId))
 	}
 
-	var bidEntities []bid_entity.Bid
+	bidEntities := make([]bid_entity.Bid, 0, len(bidEntitiesMongo))
 	for _, bidEntityMongo := range bidEntitiesMongo {
-		bidEntities = append(bidEntities, bid_entity.Bid{
-			Id:        bidEntityMongo.Id,
-			UserId:    bidEntityMongo.UserId,
-			AuctionId: bidEntityMongo.AuctionId,
-			Amount:    bidEntityMongo.Amount,
-			Timestamp: time.Unix(bidEntityMongo.Timestamp, 0),
-		})
+		bidEntities = append(bidEntities, toBidEntity(bidEntityMongo))
 	}
 
 	return bidEntities, nil
 }
 
+// bidListSort converte "campo" ou "-campo" na ordenação do Mongo. O _id entra
+// como desempate para que lances iguais venham sempre na mesma ordem.
+func bidListSort(sort string) (bson.D, *internal_error.InternalError) {
+	if sort == "" {
+		sort = defaultBidListSort
+	}
+
+	direction := 1
+	if strings.HasPrefix(sort, "-") {
+		direction = -1
+		sort = strings.TrimPrefix(sort, "-")
+	}
+
+	if _, err := sanitize.Key(sort); err != nil {
+		return nil, err
+	}
+
+	field, ok := bidListSortFields[sort]
+	if !ok {
+		return nil, internal_error.NewBadRequestError(
+			fmt.Sprintf("invalid sort field %q, use amount or timestamp", sort))
+	}
+
+	return bson.D{{Key: field, Value: direction}, {Key: "_id", Value: direction}}, nil
+}
+
 func (bd *BidRepository) FindWinningBidByAuctionId(
 	ctx context.Context, auctionId string) (*bid_entity.Bid, *internal_error.InternalError) {
+	// Leilões fechados abaixo do preço de reserva não têm vencedor
+	auctionEntity, auctionErr := bd.AuctionRepository.FindAuctionById(ctx, auctionId)
+	if auctionErr != nil && auctionErr.Err != "not_found" {
+		return nil, auctionErr
+	}
+	if auctionEntity != nil && auctionEntity.ReserveMet != nil && !*auctionEntity.ReserveMet {
+		return nil, internal_error.NewNotFoundError(
+			fmt.Sprintf("Reserve price not met for auction = %s", auctionId)).
+			WithCode(internal_error.CodeReserveNotMet)
+	}
+	// Em leilões Sealed o vencedor só aparece depois do fechamento
+	if auctionEntity != nil && auctionEntity.BidsHidden() {
+		return nil, internal_error.NewNotFoundError(
+			fmt.Sprintf("Winning bid is not available for sealed auction = %s", auctionId)).
+			WithCode(internal_error.CodeBidsHidden)
+	}
+
 	filter := bson.M{"auction_id": auctionId}
+	// Leilões fechados pelo compre já guardam o lance vencedor
+	if auctionEntity != nil && auctionEntity.WinningBidId != "" {
+		filter = bson.M{"_id": auctionEntity.WinningBidId}
+	}
+	// Só lances na moeda do leilão disputam a vitória
+	if auctionEntity != nil {
+		filter["currency"] = currencyFilter(auctionEntity.Currency)
+	}
 
 	var bidEntityMongo BidEntityMongo
-	opts := options.FindOne().SetSort(bson.D{{"amount", -1}})
+	opts := options.FindOne().SetSort(bson.D{{Key: "amount", Value: -1}, {Key: "timestamp", Value: 1}})
 	if err := bd.Collection.FindOne(ctx, filter, opts).Decode(&bidEntityMongo); err != nil {
+		if database_error.IsNotFound(err) {
+			logger.Error(fmt.Sprintf("No bids found for auction = %s", auctionId), err)
+			return nil, internal_error.NewNotFoundError(
+				fmt.Sprintf("No bids found for auction = %s", auctionId))
+		}
+
 		logger.Error("Error trying to find the auction winner", err)
-		return nil, internal_error.NewInternalServerError("Error trying to find the auction winner")
+		return nil, database_error.NewInternalError(err, "Error trying to find the auction winner")
+	}
+
+	bid := toBidEntity(bidEntityMongo)
+	return &bid, nil
+}
+
+// FindAuctionsUserBidOn retorna, sem repetição e em ordem, os ids dos leilões
+// em que o usuário deu lances. O Distinct roda no Mongo, sem carregar os
+// lances.
+func (bd *BidRepository) FindAuctionsUserBidOn(
+	ctx context.Context, userId string) ([]string, *internal_error.InternalError) {
+	values, err := bd.Collection.Distinct(ctx, "auction_id", bson.M{"user_id": userId})
+	if err != nil {
+		logger.Error(
+			fmt.Sprintf("Error trying to find auctions the user %s bid on", userId), err)
+		return nil, database_error.NewInternalError(err,
+			fmt.Sprintf("Error trying to find auctions the user %s bid on", userId))
+	}
+
+	auctionIds := make([]string, 0, len(values))
+	for _, value := range values {
+		if auctionId, ok := value.(string); ok {
+			auctionIds = append(auctionIds, auctionId)
+		}
+	}
+	sort.Strings(auctionIds)
+
+	return auctionIds, nil
+}
+
+// FindGlobalHighestActiveBid retorna o maior lance entre todos os leilões
+// ativos, para o destaque de "maior lance agora". Os ids dos leilões ativos
+// vêm do repositório de leilões, em vez de um $lookup, e lances de leilões
+// fechados ficam de fora. Em caso de empate vale o lance mais antigo. Valores
+// em moedas diferentes não são comparáveis, então só contam os leilões em
+// DefaultCurrency. Leilões Sealed ficam de fora para não expor os lances antes
+// do fechamento.
+func (bd *BidRepository) FindGlobalHighestActiveBid(
+	ctx context.Context) (*bid_entity.Bid, *internal_error.InternalError) {
+	activeAuctionIds, auctionErr := bd.AuctionRepository.FindOpenAuctionIds(ctx, bid_entity.DefaultCurrency)
+	if auctionErr != nil {
+		return nil, auctionErr
+	}
+
+	if len(activeAuctionIds) == 0 {
+		return nil, internal_error.NewNotFoundError("No bids found on active auctions")
+	}
+
+	var bidEntityMongo BidEntityMongo
+	opts := options.FindOne().SetSort(bson.D{{Key: "amount", Value: -1}, {Key: "timestamp", Value: 1}})
+	err := bd.Collection.FindOne(ctx, bson.M{"auction_id": bson.M{"$in": activeAuctionIds}}, opts).
+		Decode(&bidEntityMongo)
+	if err != nil {
+		if database_error.IsNotFound(err) {
+			return nil, internal_error.NewNotFoundError("No bids found on active auctions")
+		}
+
+		logger.Error("Error trying to find the highest bid on active auctions", err)
+		return nil, database_error.NewInternalError(err, "Error trying to find the highest bid")
+	}
+
+	bid := toBidEntity(bidEntityMongo)
+	return &bid, nil
+}
+
+// currencyFilter casa os documentos na moeda informada. Leilões e lances
+// gravados antes do campo existir não têm moeda e contam como DefaultCurrency.
+func currencyFilter(currency string) interface{} {
+	if currency == bid_entity.DefaultCurrency {
+		return bson.M{"$in": bson.A{currency, nil}}
 	}
 
-	return &bid_entity.Bid{
-		Id:        bidEntityMongo.Id,
-		UserId:    bidEntityMongo.UserId,
-		AuctionId: bidEntityMongo.AuctionId,
-		Amount:    bidEntityMongo.Amount,
-		Timestamp: time.Unix(bidEntityMongo.Timestamp, 0),
-	}, nil
+	return currency
 }
@@ -0,0 +1,354 @@
+package bid
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/entity/bid_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestFindWinningBidByAuctionId(t *testing.T) {
+	ctx := context.Background()
+	bidRepository, auctionRepository := newTestRepositories(ctx, t)
+
+	auctionId := createTestAuction(ctx, t, auctionRepository, auction_entity.Active)
+
+	now := time.Now()
+	earliestTopBidder := uuid.New().String()
+	bids := []bid_entity.Bid{
+		{Id: uuid.New().String(), UserId: uuid.New().String(), AuctionId: auctionId, Amount: bid_entity.AmountFromCents(100_00), Timestamp: now.Add(-5 * time.Second)},
+		{Id: uuid.New().String(), UserId: uuid.New().String(), AuctionId: auctionId, Amount: bid_entity.AmountFromCents(300_00), Timestamp: now.Add(-2 * time.Second)},
+		{Id: uuid.New().String(), UserId: earliestTopBidder, AuctionId: auctionId, Amount: bid_entity.AmountFromCents(300_00), Timestamp: now.Add(-4 * time.Second)},
+		{Id: uuid.New().String(), UserId: uuid.New().String(), AuctionId: auctionId, Amount: bid_entity.AmountFromCents(200_00), Timestamp: now.Add(-3 * time.Second)},
+	}
+	require.Nil(t, bidRepository.CreateBid(ctx, bids))
+
+	winningBid, err := bidRepository.FindWinningBidByAuctionId(ctx, auctionId)
+	require.Nil(t, err)
+	require.Equal(t, bid_entity.AmountFromCents(300_00), winningBid.Amount)
+	require.Equal(t, earliestTopBidder, winningBid.UserId)
+	require.Equal(t, time.UTC, winningBid.Timestamp.Location())
+}
+
+func TestFindBidsWithDecimalAmounts(t *testing.T) {
+	ctx := context.Background()
+	bidRepository, auctionRepository := newTestRepositories(ctx, t)
+
+	auctionId := createTestAuction(ctx, t, auctionRepository, auction_entity.Active)
+
+	tenCents, amountErr := bid_entity.NewAmount(0.1)
+	require.Nil(t, amountErr)
+	twentyCents, amountErr := bid_entity.NewAmount(0.2)
+	require.Nil(t, amountErr)
+
+	now := time.Now()
+	topBidder := uuid.New().String()
+	bids := []bid_entity.Bid{
+		{Id: uuid.New().String(), UserId: uuid.New().String(), AuctionId: auctionId, Amount: bid_entity.AmountFromCents(29), Timestamp: now.Add(-3 * time.Second)},
+		{Id: uuid.New().String(), UserId: topBidder, AuctionId: auctionId, Amount: tenCents.Add(twentyCents), Timestamp: now.Add(-2 * time.Second)},
+	}
+	require.Nil(t, bidRepository.CreateBid(ctx, bids))
+
+	// Um lance gravado como double antes do Decimal128 é ordenado pelo valor
+	_, err := bidRepository.Collection.InsertOne(ctx, bson.M{
+		"_id":        uuid.New().String(),
+		"user_id":    uuid.New().String(),
+		"auction_id": auctionId,
+		"amount":     0.25,
+		"timestamp":  now.Add(-time.Second).Unix(),
+	})
+	require.NoError(t, err)
+
+	winningBid, findErr := bidRepository.FindWinningBidByAuctionId(ctx, auctionId)
+	require.Nil(t, findErr)
+	require.Equal(t, topBidder, winningBid.UserId)
+	require.Equal(t, bid_entity.AmountFromCents(30), winningBid.Amount)
+
+	sortedBids, findErr := bidRepository.FindBidByAuctionId(ctx, auctionId, "amount", 0)
+	require.Nil(t, findErr)
+	require.Len(t, sortedBids, 3)
+	require.Equal(t, "0.25", sortedBids[0].Amount.String())
+	require.Equal(t, "0.29", sortedBids[1].Amount.String())
+	require.Equal(t, "0.30", sortedBids[2].Amount.String())
+	for _, bid := range sortedBids {
+		require.Equal(t, time.UTC, bid.Timestamp.Location())
+	}
+}
+
+func TestFindWinningBidByAuctionIdWithoutBids(t *testing.T) {
+	ctx := context.Background()
+	bidRepository, auctionRepository := newTestRepositories(ctx, t)
+
+	auctionId := createTestAuction(ctx, t, auctionRepository, auction_entity.Active)
+
+	winningBid, err := bidRepository.FindWinningBidByAuctionId(ctx, auctionId)
+	require.Nil(t, winningBid)
+	require.NotNil(t, err)
+	require.Equal(t, "not_found", err.Err)
+}
+
+func TestFindWinningBidByAuctionIdReservePrice(t *testing.T) {
+	ctx := context.Background()
+	bidRepository, auctionRepository := newTestRepositories(ctx, t)
+
+	testCases := []struct {
+		name          string
+		reserveMet    bool
+		expectedError string
+	}{
+		{name: "reserve met", reserveMet: true},
+		{name: "reserve not met", reserveMet: false, expectedError: internal_error.CodeReserveNotMet},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			auctionId := createTestAuction(ctx, t, auctionRepository, auction_entity.Active)
+
+			bidEntity, err := bid_entity.CreateBid(uuid.New().String(), auctionId, bid_entity.AmountFromCents(100_00), "")
+			require.Nil(t, err)
+			require.Nil(t, bidRepository.CreateBid(ctx, []bid_entity.Bid{*bidEntity}))
+
+			// Estado gravado pelo closer ao fechar um leilão com reserva
+			_, updateErr := auctionRepository.Collection.UpdateOne(ctx,
+				bson.M{"_id": auctionId},
+				bson.M{"$set": bson.M{
+					"status":        auction_entity.Completed,
+					"reserve_price": 150.0,
+					"reserve_met":   tc.reserveMet,
+				}})
+			require.NoError(t, updateErr)
+
+			winningBid, err := bidRepository.FindWinningBidByAuctionId(ctx, auctionId)
+			if tc.expectedError != "" {
+				require.Nil(t, winningBid)
+				require.NotNil(t, err)
+				require.Equal(t, "not_found", err.Err)
+				require.Equal(t, tc.expectedError, err.Code)
+				return
+			}
+
+			require.Nil(t, err)
+			require.Equal(t, bidEntity.Id, winningBid.Id)
+		})
+	}
+}
+
+func TestFindBidByAuctionIdSorting(t *testing.T) {
+	ctx := context.Background()
+	bidRepository, auctionRepository := newTestRepositories(ctx, t)
+
+	auctionId := createTestAuction(ctx, t, auctionRepository, auction_entity.Active)
+
+	now := time.Now()
+	first := bid_entity.Bid{Id: uuid.New().String(), UserId: uuid.New().String(), AuctionId: auctionId, Amount: bid_entity.AmountFromCents(200_00), Timestamp: now.Add(-3 * time.Second)}
+	second := bid_entity.Bid{Id: uuid.New().String(), UserId: uuid.New().String(), AuctionId: auctionId, Amount: bid_entity.AmountFromCents(100_00), Timestamp: now.Add(-2 * time.Second)}
+	third := bid_entity.Bid{Id: uuid.New().String(), UserId: uuid.New().String(), AuctionId: auctionId, Amount: bid_entity.AmountFromCents(300_00), Timestamp: now.Add(-1 * time.Second)}
+	require.Nil(t, bidRepository.CreateBid(ctx, []bid_entity.Bid{second, third, first}))
+
+	testCases := []struct {
+		sort     string
+		expected []string
+	}{
+		{sort: "", expected: []string{first.Id, second.Id, third.Id}},
+		{sort: "timestamp", expected: []string{first.Id, second.Id, third.Id}},
+		{sort: "-timestamp", expected: []string{third.Id, second.Id, first.Id}},
+		{sort: "amount", expected: []string{second.Id, first.Id, third.Id}},
+		{sort: "-amount", expected: []string{third.Id, first.Id, second.Id}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.sort, func(t *testing.T) {
+			bids, err := bidRepository.FindBidByAuctionId(ctx, auctionId, tc.sort, 0)
+			require.Nil(t, err)
+
+			var ids []string
+			for _, bid := range bids {
+				ids = append(ids, bid.Id)
+			}
+			require.Equal(t, tc.expected, ids)
+		})
+	}
+
+	bids, err := bidRepository.FindBidByAuctionId(ctx, auctionId, "-amount", 2)
+	require.Nil(t, err)
+	require.Len(t, bids, 2)
+	require.Equal(t, third.Id, bids[0].Id)
+}
+
+func TestFindBidByAuctionIdInvalidSort(t *testing.T) {
+	ctx := context.Background()
+	bidRepository, auctionRepository := newTestRepositories(ctx, t)
+
+	auctionId := createTestAuction(ctx, t, auctionRepository, auction_entity.Active)
+
+	bids, err := bidRepository.FindBidByAuctionId(ctx, auctionId, "user_id", 0)
+	require.Nil(t, bids)
+	require.NotNil(t, err)
+	require.Equal(t, "bad_request", err.Err)
+}
+
+func TestFindBidByAuctionIdWithoutBids(t *testing.T) {
+	ctx := context.Background()
+	bidRepository, auctionRepository := newTestRepositories(ctx, t)
+
+	auctionId := createTestAuction(ctx, t, auctionRepository, auction_entity.Active)
+
+	bids, err := bidRepository.FindBidByAuctionId(ctx, auctionId, "", 0)
+	require.Nil(t, err)
+	require.NotNil(t, bids)
+	require.Empty(t, bids)
+}
+
+func TestFindBidByAuctionIdUnknownAuction(t *testing.T) {
+	ctx := context.Background()
+	bidRepository, auctionRepository := newTestRepositories(ctx, t)
+
+	// Lances órfãos não fazem um leilão inexistente parecer existir
+	auctionId := uuid.New().String()
+	_, insertErr := bidRepository.Collection.InsertOne(ctx, bson.M{
+		"_id":        uuid.New().String(),
+		"user_id":    uuid.New().String(),
+		"auction_id": auctionId,
+		"amount":     100.0,
+		"timestamp":  time.Now().Unix(),
+	})
+	require.NoError(t, insertErr)
+
+	bids, err := bidRepository.FindBidByAuctionId(ctx, auctionId, "", 0)
+	require.Nil(t, bids)
+	require.NotNil(t, err)
+	require.Equal(t, "not_found", err.Err)
+	require.Equal(t, internal_error.CodeAuctionNotFound, err.Code)
+
+	deletedAuctionId := createTestAuction(ctx, t, auctionRepository, auction_entity.Active)
+	require.Nil(t, auctionRepository.DeleteAuction(ctx, deletedAuctionId))
+
+	bids, err = bidRepository.FindBidByAuctionId(ctx, deletedAuctionId, "", 0)
+	require.Nil(t, bids)
+	require.NotNil(t, err)
+	require.Equal(t, "not_found", err.Err)
+}
+
+func TestFindBidByAuctionIdSealedAuction(t *testing.T) {
+	ctx := context.Background()
+	bidRepository, auctionRepository := newTestRepositories(ctx, t)
+
+	auctionId := createTestAuction(ctx, t, auctionRepository, auction_entity.Active)
+	_, updateErr := auctionRepository.Collection.UpdateOne(ctx,
+		bson.M{"_id": auctionId},
+		bson.M{"$set": bson.M{"type": auction_entity.Sealed}})
+	require.NoError(t, updateErr)
+
+	now := time.Now()
+	bids := []bid_entity.Bid{
+		{Id: uuid.New().String(), UserId: uuid.New().String(), AuctionId: auctionId, Amount: bid_entity.AmountFromCents(100_00), Timestamp: now.Add(-2 * time.Second)},
+		{Id: uuid.New().String(), UserId: uuid.New().String(), AuctionId: auctionId, Amount: bid_entity.AmountFromCents(200_00), Timestamp: now.Add(-time.Second)},
+	}
+	require.Nil(t, bidRepository.CreateBid(ctx, bids))
+
+	hiddenBids, err := bidRepository.FindBidByAuctionId(ctx, auctionId, "", 0)
+	require.Nil(t, err)
+	require.NotNil(t, hiddenBids)
+	require.Empty(t, hiddenBids)
+
+	_, err = bidRepository.FindWinningBidByAuctionId(ctx, auctionId)
+	require.NotNil(t, err)
+	require.Equal(t, "not_found", err.Err)
+	require.Equal(t, internal_error.CodeBidsHidden, err.Code)
+
+	_, updateErr = auctionRepository.Collection.UpdateOne(ctx,
+		bson.M{"_id": auctionId},
+		bson.M{"$set": bson.M{"status": auction_entity.Completed}})
+	require.NoError(t, updateErr)
+
+	revealedBids, err := bidRepository.FindBidByAuctionId(ctx, auctionId, "", 0)
+	require.Nil(t, err)
+	require.Len(t, revealedBids, 2)
+	require.Equal(t, bids[0].Id, revealedBids[0].Id)
+	require.Equal(t, bids[1].Id, revealedBids[1].Id)
+
+	winningBid, err := bidRepository.FindWinningBidByAuctionId(ctx, auctionId)
+	require.Nil(t, err)
+	require.Equal(t, bids[1].Id, winningBid.Id)
+}
+
+func TestFindAuctionsUserBidOn(t *testing.T) {
+	ctx := context.Background()
+	bidRepository, auctionRepository := newTestRepositories(ctx, t)
+
+	firstAuctionId := createTestAuction(ctx, t, auctionRepository, auction_entity.Active)
+	secondAuctionId := createTestAuction(ctx, t, auctionRepository, auction_entity.Active)
+	otherAuctionId := createTestAuction(ctx, t, auctionRepository, auction_entity.Active)
+
+	userId := uuid.New().String()
+	otherUserId := uuid.New().String()
+	now := time.Now()
+	bids := []bid_entity.Bid{
+		{Id: uuid.New().String(), UserId: userId, AuctionId: firstAuctionId, Amount: bid_entity.AmountFromCents(100_00), Timestamp: now.Add(-3 * time.Second)},
+		{Id: uuid.New().String(), UserId: userId, AuctionId: firstAuctionId, Amount: bid_entity.AmountFromCents(200_00), Timestamp: now.Add(-2 * time.Second)},
+		{Id: uuid.New().String(), UserId: userId, AuctionId: secondAuctionId, Amount: bid_entity.AmountFromCents(100_00), Timestamp: now.Add(-2 * time.Second)},
+		{Id: uuid.New().String(), UserId: userId, AuctionId: firstAuctionId, Amount: bid_entity.AmountFromCents(300_00), Timestamp: now.Add(-time.Second)},
+		{Id: uuid.New().String(), UserId: otherUserId, AuctionId: otherAuctionId, Amount: bid_entity.AmountFromCents(100_00), Timestamp: now},
+	}
+	require.Nil(t, bidRepository.CreateBid(ctx, bids))
+
+	auctionIds, err := bidRepository.FindAuctionsUserBidOn(ctx, userId)
+	require.Nil(t, err)
+	require.ElementsMatch(t, []string{firstAuctionId, secondAuctionId}, auctionIds)
+	require.Len(t, auctionIds, 2)
+
+	auctionIds, err = bidRepository.FindAuctionsUserBidOn(ctx, uuid.New().String())
+	require.Nil(t, err)
+	require.NotNil(t, auctionIds)
+	require.Empty(t, auctionIds)
+}
+
+func TestFindGlobalHighestActiveBid(t *testing.T) {
+	ctx := context.Background()
+	bidRepository, auctionRepository := newTestRepositories(ctx, t)
+
+	_, err := bidRepository.FindGlobalHighestActiveBid(ctx)
+	require.NotNil(t, err)
+	require.Equal(t, "not_found", err.Err)
+
+	firstAuctionId := createTestAuction(ctx, t, auctionRepository, auction_entity.Active)
+	secondAuctionId := createTestAuction(ctx, t, auctionRepository, auction_entity.Active)
+	completedAuctionId := createTestAuction(ctx, t, auctionRepository, auction_entity.Active)
+	sealedAuctionId := createTestAuction(ctx, t, auctionRepository, auction_entity.Active)
+
+	now := time.Now()
+	highestActiveBidId := uuid.New().String()
+	bids := []bid_entity.Bid{
+		{Id: uuid.New().String(), UserId: uuid.New().String(), AuctionId: firstAuctionId, Amount: bid_entity.AmountFromCents(100_00), Timestamp: now.Add(-3 * time.Second)},
+		{Id: highestActiveBidId, UserId: uuid.New().String(), AuctionId: secondAuctionId, Amount: bid_entity.AmountFromCents(450_50), Timestamp: now.Add(-2 * time.Second)},
+		{Id: uuid.New().String(), UserId: uuid.New().String(), AuctionId: firstAuctionId, Amount: bid_entity.AmountFromCents(300_00), Timestamp: now.Add(-time.Second)},
+		{Id: uuid.New().String(), UserId: uuid.New().String(), AuctionId: completedAuctionId, Amount: bid_entity.AmountFromCents(9_000_00), Timestamp: now},
+		{Id: uuid.New().String(), UserId: uuid.New().String(), AuctionId: sealedAuctionId, Amount: bid_entity.AmountFromCents(5_000_00), Timestamp: now},
+	}
+	require.Nil(t, bidRepository.CreateBid(ctx, bids))
+
+	// Lances de leilões Sealed ficam escondidos até o fechamento
+	_, updateErr := auctionRepository.Collection.UpdateOne(ctx,
+		bson.M{"_id": sealedAuctionId},
+		bson.M{"$set": bson.M{"type": auction_entity.Sealed}})
+	require.NoError(t, updateErr)
+
+	// O maior lance de todos é de um leilão que fechou e não conta
+	_, updateErr = auctionRepository.Collection.UpdateOne(ctx,
+		bson.M{"_id": completedAuctionId},
+		bson.M{"$set": bson.M{"status": auction_entity.Completed, "closed_at": now.Unix()}})
+	require.NoError(t, updateErr)
+
+	highestBid, err := bidRepository.FindGlobalHighestActiveBid(ctx)
+	require.Nil(t, err)
+	require.Equal(t, highestActiveBidId, highestBid.Id)
+	require.Equal(t, secondAuctionId, highestBid.AuctionId)
+	require.Equal(t, bid_entity.AmountFromCents(450_50), highestBid.Amount)
+	require.Equal(t, time.UTC, highestBid.Timestamp.Location())
+}
@@ -0,0 +1,52 @@
+package bid
+
+import (
+	"context"
+	"fmt"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/infra/database/database_error"
+	"fullcycle-auction_go/internal/internal_error"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// IsLeadingBidder diz se o maior lance do leilão, na ordem usada para o
+// vencedor, é do usuário. Só o user_id do lance é lido, então nenhum valor de
+// outros participantes sai do repositório. Um leilão sem lances não tem
+// ninguém na frente; em leilões Sealed a resposta revelaria a disputa e é
+// recusada até o fechamento.
+func (bd *BidRepository) IsLeadingBidder(
+	ctx context.Context, auctionId, userId string) (bool, *internal_error.InternalError) {
+	auctionEntity, auctionErr := bd.AuctionRepository.FindAuctionById(ctx, auctionId)
+	if auctionErr != nil {
+		return false, auctionErr
+	}
+	if auctionEntity.BidsHidden() {
+		return false, internal_error.NewBadRequestError(
+			fmt.Sprintf("Leading bidder is not available for sealed auction = %s", auctionId)).
+			WithCode(internal_error.CodeBidsHidden)
+	}
+
+	filter := bson.M{
+		"auction_id": auctionId,
+		"currency":   currencyFilter(auctionEntity.Currency),
+	}
+	opts := options.FindOne().
+		SetSort(bson.D{{Key: "amount", Value: -1}, {Key: "timestamp", Value: 1}, {Key: "_id", Value: 1}}).
+		SetProjection(bson.M{"user_id": 1})
+
+	var leadingBid struct {
+		UserId string `bson:"user_id"`
+	}
+	if err := bd.Collection.FindOne(ctx, filter, opts).Decode(&leadingBid); err != nil {
+		if database_error.IsNotFound(err) {
+			return false, nil
+		}
+
+		logger.Error(fmt.Sprintf("Error trying to find the leading bid of auction = %s", auctionId), err)
+		return false, database_error.NewInternalError(err, "Error trying to find the leading bidder")
+	}
+
+	return leadingBid.UserId == userId, nil
+}
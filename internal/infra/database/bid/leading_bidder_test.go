@@ -0,0 +1,72 @@
+package bid
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/entity/bid_entity"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestIsLeadingBidder(t *testing.T) {
+	ctx := context.Background()
+	bidRepository, auctionRepository := newTestRepositories(ctx, t)
+
+	auctionId := createTestAuction(ctx, t, auctionRepository, auction_entity.Active)
+
+	now := time.Now()
+	leader := uuid.New().String()
+	tiedLater := uuid.New().String()
+	outbid := uuid.New().String()
+	require.Nil(t, bidRepository.CreateBid(ctx, []bid_entity.Bid{
+		{Id: uuid.New().String(), UserId: outbid, AuctionId: auctionId, Amount: bid_entity.AmountFromCents(100_00), Timestamp: now.Add(-4 * time.Second)},
+		{Id: uuid.New().String(), UserId: leader, AuctionId: auctionId, Amount: bid_entity.AmountFromCents(300_00), Timestamp: now.Add(-3 * time.Second)},
+		{Id: uuid.New().String(), UserId: tiedLater, AuctionId: auctionId, Amount: bid_entity.AmountFromCents(300_00), Timestamp: now.Add(-2 * time.Second)},
+	}))
+
+	t.Run("leading", func(t *testing.T) {
+		leading, err := bidRepository.IsLeadingBidder(ctx, auctionId, leader)
+		require.Nil(t, err)
+		require.True(t, leading)
+	})
+
+	t.Run("not leading", func(t *testing.T) {
+		// Em caso de empate, o lance mais antigo fica na frente
+		for _, userId := range []string{tiedLater, outbid, uuid.New().String()} {
+			leading, err := bidRepository.IsLeadingBidder(ctx, auctionId, userId)
+			require.Nil(t, err)
+			require.False(t, leading)
+		}
+	})
+
+	t.Run("no bids", func(t *testing.T) {
+		emptyAuctionId := createTestAuction(ctx, t, auctionRepository, auction_entity.Active)
+
+		leading, err := bidRepository.IsLeadingBidder(ctx, emptyAuctionId, leader)
+		require.Nil(t, err)
+		require.False(t, leading)
+	})
+
+	t.Run("unknown auction", func(t *testing.T) {
+		leading, err := bidRepository.IsLeadingBidder(ctx, uuid.New().String(), leader)
+		require.False(t, leading)
+		require.NotNil(t, err)
+		require.Equal(t, "not_found", err.Err)
+	})
+
+	t.Run("sealed auction", func(t *testing.T) {
+		_, updateErr := auctionRepository.Collection.UpdateOne(ctx,
+			bson.M{"_id": auctionId},
+			bson.M{"$set": bson.M{"type": auction_entity.Sealed}})
+		require.NoError(t, updateErr)
+
+		leading, err := bidRepository.IsLeadingBidder(ctx, auctionId, leader)
+		require.False(t, leading)
+		require.NotNil(t, err)
+		require.Equal(t, "bad_request", err.Err)
+	})
+}
@@ -0,0 +1,95 @@
+package bid
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/infra/database/auction"
+	"fullcycle-auction_go/internal/infra/messaging"
+	"log"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type recordingPublisher struct {
+	mutex  sync.Mutex
+	events []messaging.AuctionClosedEvent
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, eventName string, payload any) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if event, ok := payload.(messaging.AuctionClosedEvent); ok {
+		p.events = append(p.events, event)
+	}
+	return nil
+}
+
+// Mesma montagem do main: o notifier existe antes do repositório de leilões e
+// recebe os leilões fechados pela reconciliação do construtor.
+func TestAuctionsClosedOnStartupAreNotified(t *testing.T) {
+	t.Setenv("AUCTION_INTERVAL", "1h")
+	t.Setenv("AUCTION_CHECK_INTERVAL", "1h")
+
+	ctx := context.Background()
+
+	client, db, container := getTestDatabase(ctx, t)
+	defer func() {
+		db.Drop(ctx)
+		if err := client.Disconnect(ctx); err != nil {
+			log.Printf("failed to disconnect client: %s", err)
+		}
+		if err := container.Terminate(ctx); err != nil {
+			log.Printf("failed to terminate container: %s", err)
+		}
+	}()
+
+	// Leilão que expirou enquanto o serviço estava fora, com um lance
+	now := time.Now()
+	auctionId := uuid.New().String()
+	bidderId := uuid.New().String()
+	_, err := db.Collection("auctions").InsertOne(ctx, auction.AuctionEntityMongo{
+		Id:          auctionId,
+		ProductName: "Startup Product",
+		Category:    "Test Category",
+		Description: "Auction that expired while the service was down",
+		Condition:   auction_entity.New,
+		Status:      auction_entity.Active,
+		Timestamp:   now.Add(-2 * time.Hour).Unix(),
+		ExpiresAt:   now.Add(-time.Hour).Unix(),
+	})
+	require.NoError(t, err)
+	_, err = db.Collection("bids").InsertOne(ctx, bson.M{
+		"_id":        uuid.New().String(),
+		"user_id":    bidderId,
+		"auction_id": auctionId,
+		"amount":     150.0,
+		"timestamp":  now.Add(-90 * time.Minute).Unix(),
+	})
+	require.NoError(t, err)
+
+	publisher := &recordingPublisher{}
+	notifier := messaging.NewAuctionClosedNotifier(publisher)
+
+	auctionRepository := auction.NewAuctionRepositoryWithOptions(ctx, db,
+		auction.WithOnClosed(notifier.OnAuctionClosed))
+	defer auctionRepository.Close()
+
+	bidRepository := NewBidRepository(db, auctionRepository)
+	defer bidRepository.Close()
+
+	notifier.Start(bidRepository)
+	notifier.Close()
+
+	publisher.mutex.Lock()
+	defer publisher.mutex.Unlock()
+	require.Len(t, publisher.events, 1)
+	require.Equal(t, auctionId, publisher.events[0].AuctionId)
+	require.NotNil(t, publisher.events[0].WinningBid)
+	require.Equal(t, bidderId, publisher.events[0].WinningBid.UserId)
+}
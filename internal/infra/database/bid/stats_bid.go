@@ -0,0 +1,79 @@
+package bid
+
+import (
+	"context"
+	"fmt"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/entity/bid_entity"
+	"fullcycle-auction_go/internal/infra/database/database_error"
+	"fullcycle-auction_go/internal/infra/database/decimal"
+	"fullcycle-auction_go/internal/internal_error"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type auctionStatsMongo struct {
+	BidCount  int64          `bson:"bid_count"`
+	MaxAmount decimal.Amount `bson:"max_amount"`
+	// A média de valores Decimal128 tem mais casas que um lance
+	AverageAmount bson.RawValue `bson:"average_amount"`
+	Bidders       []string      `bson:"bidders"`
+}
+
+// AuctionStats agrega no Mongo os lances do leilão, sem trazê-los para a
+// aplicação. Leilões sem lances retornam as estatísticas zeradas; em leilões
+// Sealed o maior lance e a média ficam indisponíveis até o fechamento.
+func (bd *BidRepository) AuctionStats(
+	ctx context.Context, auctionId string) (*bid_entity.AuctionStatsResult, *internal_error.InternalError) {
+	auctionEntity, auctionErr := bd.AuctionRepository.FindAuctionById(ctx, auctionId)
+	if auctionErr != nil && auctionErr.Err != "not_found" {
+		return nil, auctionErr
+	}
+	if auctionEntity != nil && auctionEntity.BidsHidden() {
+		return nil, internal_error.NewBadRequestError(
+			fmt.Sprintf("Stats are not available for sealed auction = %s", auctionId)).
+			WithCode(internal_error.CodeBidsHidden)
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"auction_id": auctionId}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":            nil,
+			"bid_count":      bson.M{"$sum": 1},
+			"max_amount":     bson.M{"$max": "$amount"},
+			"average_amount": bson.M{"$avg": "$amount"},
+			"bidders":        bson.M{"$addToSet": "$user_id"},
+		}}},
+	}
+
+	cursor, err := bd.Collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Error trying to compute stats for auction = %s", auctionId), err)
+		return nil, database_error.NewInternalError(err, "Error trying to compute auction stats")
+	}
+	defer closeCursor(ctx, cursor)
+
+	var results []auctionStatsMongo
+	if err := cursor.All(ctx, &results); err != nil {
+		logger.Error(fmt.Sprintf("Error trying to decode stats for auction = %s", auctionId), err)
+		return nil, database_error.NewInternalError(err, "Error trying to compute auction stats")
+	}
+
+	if len(results) == 0 {
+		return &bid_entity.AuctionStatsResult{}, nil
+	}
+
+	averageAmount, err := decimal.Float64(results[0].AverageAmount)
+	if err != nil {
+		logger.Error(fmt.Sprintf("Error trying to decode stats for auction = %s", auctionId), err)
+		return nil, database_error.NewInternalError(err, "Error trying to compute auction stats")
+	}
+
+	return &bid_entity.AuctionStatsResult{
+		BidCount:        results[0].BidCount,
+		MaxAmount:       bid_entity.Amount(results[0].MaxAmount),
+		AverageAmount:   averageAmount,
+		DistinctBidders: int64(len(results[0].Bidders)),
+	}, nil
+}
@@ -0,0 +1,75 @@
+package bid
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/entity/bid_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestAuctionStats(t *testing.T) {
+	ctx := context.Background()
+	bidRepository, auctionRepository := newTestRepositories(ctx, t)
+
+	auctionId := createTestAuction(ctx, t, auctionRepository, auction_entity.Active)
+	otherAuctionId := createTestAuction(ctx, t, auctionRepository, auction_entity.Active)
+
+	firstBidder := uuid.New().String()
+	secondBidder := uuid.New().String()
+	now := time.Now()
+	bids := []bid_entity.Bid{
+		{Id: uuid.New().String(), UserId: firstBidder, AuctionId: auctionId, Amount: bid_entity.AmountFromCents(100_00), Timestamp: now.Add(-4 * time.Second)},
+		{Id: uuid.New().String(), UserId: secondBidder, AuctionId: auctionId, Amount: bid_entity.AmountFromCents(150_00), Timestamp: now.Add(-3 * time.Second)},
+		{Id: uuid.New().String(), UserId: firstBidder, AuctionId: auctionId, Amount: bid_entity.AmountFromCents(200_00), Timestamp: now.Add(-2 * time.Second)},
+		{Id: uuid.New().String(), UserId: secondBidder, AuctionId: auctionId, Amount: bid_entity.AmountFromCents(350_00), Timestamp: now.Add(-time.Second)},
+		{Id: uuid.New().String(), UserId: uuid.New().String(), AuctionId: otherAuctionId, Amount: bid_entity.AmountFromCents(1000_00), Timestamp: now},
+	}
+	require.Nil(t, bidRepository.CreateBid(ctx, bids))
+
+	stats, err := bidRepository.AuctionStats(ctx, auctionId)
+	require.Nil(t, err)
+	require.Equal(t, &bid_entity.AuctionStatsResult{
+		BidCount:        4,
+		MaxAmount:       bid_entity.AmountFromCents(350_00),
+		AverageAmount:   200,
+		DistinctBidders: 2,
+	}, stats)
+}
+
+func TestAuctionStatsWithoutBids(t *testing.T) {
+	ctx := context.Background()
+	bidRepository, auctionRepository := newTestRepositories(ctx, t)
+
+	auctionId := createTestAuction(ctx, t, auctionRepository, auction_entity.Active)
+
+	stats, err := bidRepository.AuctionStats(ctx, auctionId)
+	require.Nil(t, err)
+	require.Equal(t, &bid_entity.AuctionStatsResult{}, stats)
+}
+
+func TestStatsHiddenForSealedAuction(t *testing.T) {
+	ctx := context.Background()
+	bidRepository, auctionRepository := newTestRepositories(ctx, t)
+
+	auctionId := createTestAuction(ctx, t, auctionRepository, auction_entity.Active)
+	_, updateErr := auctionRepository.Collection.UpdateOne(ctx,
+		bson.M{"_id": auctionId},
+		bson.M{"$set": bson.M{"type": auction_entity.Sealed}})
+	require.NoError(t, updateErr)
+
+	require.Nil(t, bidRepository.CreateBid(ctx, []bid_entity.Bid{
+		{Id: uuid.New().String(), UserId: uuid.New().String(), AuctionId: auctionId, Amount: bid_entity.AmountFromCents(100_00), Timestamp: time.Now()},
+	}))
+
+	stats, err := bidRepository.AuctionStats(ctx, auctionId)
+	require.Nil(t, stats)
+	require.NotNil(t, err)
+	require.Equal(t, "bad_request", err.Err)
+	require.Equal(t, internal_error.CodeBidsHidden, err.Code)
+}
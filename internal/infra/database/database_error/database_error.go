@@ -0,0 +1,46 @@
+package database_error
+
+import (
+	"context"
+	"errors"
+	"fullcycle-auction_go/internal/internal_error"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Classify indica qual tipo de internal_error (campo Err) corresponde ao erro
+// retornado pelo driver do Mongo.
+func Classify(err error) string {
+	switch {
+	case errors.Is(err, mongo.ErrNoDocuments):
+		return "not_found"
+	case mongo.IsDuplicateKeyError(err):
+		return "bad_request"
+	case IsTimeout(err):
+		return "timeout"
+	default:
+		return "internal_server_error"
+	}
+}
+
+// NewInternalError cria o internal_error do tipo indicado por Classify.
+func NewInternalError(err error, message string) *internal_error.InternalError {
+	switch Classify(err) {
+	case "not_found":
+		return internal_error.NewNotFoundError(message)
+	case "bad_request":
+		return internal_error.NewBadRequestError(message)
+	case "timeout":
+		return internal_error.NewTimeoutError(message)
+	default:
+		return internal_error.NewInternalServerError(message)
+	}
+}
+
+func IsNotFound(err error) bool {
+	return errors.Is(err, mongo.ErrNoDocuments)
+}
+
+func IsTimeout(err error) bool {
+	return errors.Is(err, context.DeadlineExceeded) || mongo.IsTimeout(err)
+}
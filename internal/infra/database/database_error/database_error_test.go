@@ -0,0 +1,42 @@
+package database_error
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestClassify(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{name: "no documents", err: mongo.ErrNoDocuments, expected: "not_found"},
+		{name: "wrapped no documents", err: fmt.Errorf("find: %w", mongo.ErrNoDocuments), expected: "not_found"},
+		{
+			name: "duplicate key",
+			err: mongo.WriteException{WriteErrors: mongo.WriteErrors{
+				{Code: 11000, Message: "E11000 duplicate key error"},
+			}},
+			expected: "bad_request",
+		},
+		{name: "deadline exceeded", err: context.DeadlineExceeded, expected: "timeout"},
+		{name: "canceled", err: context.Canceled, expected: "internal_server_error"},
+		{name: "generic", err: errors.New("connection refused"), expected: "internal_server_error"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, Classify(tc.err))
+
+			internalError := NewInternalError(tc.err, "message")
+			require.Equal(t, tc.expected, internalError.Err)
+			require.Equal(t, "message", internalError.Message)
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package decimal
+
+import (
+	"fmt"
+	"fullcycle-auction_go/internal/entity/bid_entity"
+	"math"
+	"math/big"
+	"strconv"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Amount grava um bid_entity.Amount como Decimal128. Na leitura também aceita
+// os lances gravados como double antes da mudança, arredondados para o
+// centavo mais próximo.
+type Amount bid_entity.Amount
+
+func (a Amount) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	value, err := primitive.ParseDecimal128(bid_entity.Amount(a).String())
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return bson.MarshalValue(value)
+}
+
+func (a *Amount) UnmarshalBSONValue(valueType bsontype.Type, data []byte) error {
+	rawValue := bson.RawValue{Type: valueType, Value: data}
+
+	switch valueType {
+	case bson.TypeDecimal128:
+		amount, err := fromDecimal128(rawValue.Decimal128())
+		if err != nil {
+			return err
+		}
+		*a = Amount(amount)
+	case bson.TypeDouble:
+		*a = Amount(bid_entity.AmountFromCents(int64(math.Round(rawValue.Double() * 100))))
+	case bson.TypeInt32:
+		*a = Amount(bid_entity.AmountFromCents(int64(rawValue.Int32()) * 100))
+	case bson.TypeInt64:
+		*a = Amount(bid_entity.AmountFromCents(rawValue.Int64() * 100))
+	default:
+		return fmt.Errorf("cannot decode %s into an amount", valueType)
+	}
+
+	return nil
+}
+
+// Float64 lê um resultado numérico de agregação, como o $avg dos valores,
+// que vem como Decimal128 quando os lances são Decimal128
+func Float64(rawValue bson.RawValue) (float64, error) {
+	switch rawValue.Type {
+	case bson.TypeDecimal128:
+		return strconv.ParseFloat(rawValue.Decimal128().String(), 64)
+	case bson.TypeDouble:
+		return rawValue.Double(), nil
+	case bson.TypeInt32:
+		return float64(rawValue.Int32()), nil
+	case bson.TypeInt64:
+		return float64(rawValue.Int64()), nil
+	case bson.TypeNull, 0:
+		return 0, nil
+	default:
+		return 0, fmt.Errorf("cannot decode %s into a number", rawValue.Type)
+	}
+}
+
+// fromDecimal128 converte sem passar por float64. Valores com mais casas do
+// que o bid_entity.AmountScale não são lances válidos e geram erro.
+func fromDecimal128(value primitive.Decimal128) (bid_entity.Amount, error) {
+	significand, exponent, err := value.BigInt()
+	if err != nil {
+		return bid_entity.Amount{}, err
+	}
+
+	// cents = significand * 10^(exponent + escala)
+	shift := exponent + bid_entity.AmountScale
+	cents := new(big.Int).Set(significand)
+	if shift >= 0 {
+		cents.Mul(cents, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(shift)), nil))
+	} else {
+		divisor := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(-shift)), nil)
+		var remainder big.Int
+		cents.QuoRem(cents, divisor, &remainder)
+		if remainder.Sign() != 0 {
+			return bid_entity.Amount{}, fmt.Errorf("amount %s has more than %d decimal places",
+				value, bid_entity.AmountScale)
+		}
+	}
+
+	if !cents.IsInt64() {
+		return bid_entity.Amount{}, fmt.Errorf("amount %s is out of range", value)
+	}
+
+	return bid_entity.AmountFromCents(cents.Int64()), nil
+}
@@ -0,0 +1,93 @@
+package decimal
+
+import (
+	"fullcycle-auction_go/internal/entity/bid_entity"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type document struct {
+	Amount Amount `bson:"amount"`
+}
+
+func TestAmountRoundTrip(t *testing.T) {
+	tenCents, err := bid_entity.NewAmount(0.1)
+	require.Nil(t, err)
+	twentyCents, err := bid_entity.NewAmount(0.2)
+	require.Nil(t, err)
+
+	data, marshalErr := bson.Marshal(document{Amount: Amount(tenCents.Add(twentyCents))})
+	require.NoError(t, marshalErr)
+
+	// O valor é gravado como Decimal128 exato, não como double
+	stored := bson.Raw(data).Lookup("amount")
+	require.Equal(t, bson.TypeDecimal128, stored.Type)
+	require.Equal(t, "0.30", stored.Decimal128().String())
+
+	var decoded document
+	require.NoError(t, bson.Unmarshal(data, &decoded))
+	require.Equal(t, bid_entity.AmountFromCents(30), bid_entity.Amount(decoded.Amount))
+}
+
+func TestAmountDecodesLegacyValues(t *testing.T) {
+	scaled, err := primitive.ParseDecimal128("3.0E-1")
+	require.NoError(t, err)
+	tooPrecise, err := primitive.ParseDecimal128("0.305")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name          string
+		value         interface{}
+		expectedCents int64
+		expectError   bool
+	}{
+		{name: "double", value: 150.5, expectedCents: 150_50},
+		{name: "double rounded to the cent", value: 0.30000000000000004, expectedCents: 30},
+		{name: "int32", value: int32(100), expectedCents: 100_00},
+		{name: "int64", value: int64(100), expectedCents: 100_00},
+		{name: "decimal with exponent", value: scaled, expectedCents: 30},
+		{name: "decimal with more decimal places", value: tooPrecise, expectError: true},
+		{name: "string", value: "100", expectError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := bson.Marshal(bson.M{"amount": tc.value})
+			require.NoError(t, err)
+
+			var decoded document
+			err = bson.Unmarshal(data, &decoded)
+			if tc.expectError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedCents, bid_entity.Amount(decoded.Amount).Cents())
+		})
+	}
+}
+
+func TestFloat64(t *testing.T) {
+	average, err := primitive.ParseDecimal128("200.3333333333333333333333333333333")
+	require.NoError(t, err)
+
+	data, err := bson.Marshal(bson.M{"decimal": average, "double": 2.5, "null": nil})
+	require.NoError(t, err)
+	raw := bson.Raw(data)
+
+	value, err := Float64(raw.Lookup("decimal"))
+	require.NoError(t, err)
+	require.InDelta(t, 200.3333, value, 0.0001)
+
+	value, err = Float64(raw.Lookup("double"))
+	require.NoError(t, err)
+	require.Equal(t, 2.5, value)
+
+	value, err = Float64(raw.Lookup("null"))
+	require.NoError(t, err)
+	require.Zero(t, value)
+}
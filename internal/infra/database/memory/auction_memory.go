@@ -0,0 +1,413 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/entity/bid_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Clock é a fonte de hora do repositório. Tem o mesmo método do auction.Clock,
+// então os relógios falsos dos testes servem para os dois repositórios.
+type Clock interface {
+	Now() time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+// AuctionRepository guarda os leilões num mapa, para testar os use cases sem
+// Mongo. Segue as regras de status do repositório Mongo: só leilões ativos são
+// fechados, cancelados ou estendidos, e os horários são guardados em segundos,
+// como no banco. Não há closer em segundo plano: os testes chamam
+// CloseExpiredAuctions quando querem um tick. Reserva, limite de leilões por
+// usuário, idempotência e auditoria ficam de fora.
+type AuctionRepository struct {
+	mutex           *sync.Mutex
+	auctions        map[string]auction_entity.Auction
+	bidCounts       map[string]int
+	auctionInterval time.Duration
+	clock           Clock
+	onAuctionClosed func(ctx context.Context, auctionID string)
+}
+
+var _ auction_entity.AuctionRepositoryInterface = (*AuctionRepository)(nil)
+
+// NewAuctionRepository usa auctionInterval como duração dos leilões criados
+// sem ExpiresAt.
+func NewAuctionRepository(auctionInterval time.Duration) *AuctionRepository {
+	return &AuctionRepository{
+		mutex:           &sync.Mutex{},
+		auctions:        make(map[string]auction_entity.Auction),
+		bidCounts:       make(map[string]int),
+		auctionInterval: auctionInterval,
+		clock:           realClock{},
+	}
+}
+
+// SetClock troca a fonte de hora usada para decidir quais leilões expiraram.
+func (ar *AuctionRepository) SetClock(clock Clock) {
+	ar.mutex.Lock()
+	defer ar.mutex.Unlock()
+
+	ar.clock = clock
+}
+
+// SetOnAuctionClosed registra um callback chamado para cada leilão fechado por
+// CloseExpiredAuctions ou CloseAuctions. Passar nil remove o callback.
+func (ar *AuctionRepository) SetOnAuctionClosed(onAuctionClosed func(ctx context.Context, auctionID string)) {
+	ar.mutex.Lock()
+	defer ar.mutex.Unlock()
+
+	ar.onAuctionClosed = onAuctionClosed
+}
+
+// RecordBid registra um lance no leilão. No Mongo os lances ficam em outra
+// coleção; aqui só a contagem importa, para CancelAuction recusar leilões com
+// lances.
+func (ar *AuctionRepository) RecordBid(auctionId string) {
+	ar.mutex.Lock()
+	defer ar.mutex.Unlock()
+
+	ar.bidCounts[auctionId]++
+}
+
+func (ar *AuctionRepository) CreateAuction(
+	ctx context.Context,
+	auctionEntity *auction_entity.Auction) (*auction_entity.Auction, *internal_error.InternalError) {
+	ar.mutex.Lock()
+	defer ar.mutex.Unlock()
+
+	if _, ok := ar.auctions[auctionEntity.Id]; ok {
+		return nil, internal_error.NewConflictError("auction already exists")
+	}
+
+	stored := *auctionEntity
+	stored.Images = slices.Clone(stored.Images)
+	stored.Timestamp = truncateToSecond(stored.Timestamp)
+	stored.StartsAt = truncateToSecond(stored.StartsAt)
+	stored.ExpiresAt = truncateToSecond(ar.expiresAt(auctionEntity))
+	ar.auctions[stored.Id] = stored
+
+	return ar.toAuctionEntity(stored), nil
+}
+
+func (ar *AuctionRepository) FindAuctions(
+	ctx context.Context,
+	status *auction_entity.AuctionStatus,
+	category, productName string,
+	page, limit int64) ([]auction_entity.Auction, *internal_error.InternalError) {
+	ar.mutex.Lock()
+	defer ar.mutex.Unlock()
+
+	productName = strings.ToLower(productName)
+
+	auctions := make([]auction_entity.Auction, 0, len(ar.auctions))
+	for _, auctionEntity := range ar.auctions {
+		if status != nil && auctionEntity.Status != *status {
+			continue
+		}
+		if category != "" && auctionEntity.Category != category {
+			continue
+		}
+		if productName != "" && !strings.Contains(strings.ToLower(auctionEntity.ProductName), productName) {
+			continue
+		}
+		auctions = append(auctions, *ar.toAuctionEntity(auctionEntity))
+	}
+
+	sortAuctions(auctions)
+
+	if limit > 0 {
+		if page < 1 {
+			page = 1
+		}
+		start := (page - 1) * limit
+		if start >= int64(len(auctions)) {
+			return []auction_entity.Auction{}, nil
+		}
+		end := start + limit
+		if end > int64(len(auctions)) {
+			end = int64(len(auctions))
+		}
+		auctions = auctions[start:end]
+	}
+
+	return auctions, nil
+}
+
+func (ar *AuctionRepository) FindAuctionById(
+	ctx context.Context, id string) (*auction_entity.Auction, *internal_error.InternalError) {
+	ar.mutex.Lock()
+	defer ar.mutex.Unlock()
+
+	auctionEntity, err := ar.find(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return ar.toAuctionEntity(auctionEntity), nil
+}
+
+func (ar *AuctionRepository) DeleteAuction(
+	ctx context.Context, id string) *internal_error.InternalError {
+	ar.mutex.Lock()
+	defer ar.mutex.Unlock()
+
+	if _, err := ar.find(id); err != nil {
+		return err
+	}
+
+	delete(ar.auctions, id)
+	delete(ar.bidCounts, id)
+
+	return nil
+}
+
+// FindStaleActiveAuctions lista os leilões ativos já expirados, que o próximo
+// CloseExpiredAuctions vai fechar.
+func (ar *AuctionRepository) FindStaleActiveAuctions(
+	ctx context.Context) ([]auction_entity.Auction, *internal_error.InternalError) {
+	ar.mutex.Lock()
+	defer ar.mutex.Unlock()
+
+	now := ar.clock.Now()
+
+	auctions := make([]auction_entity.Auction, 0)
+	for _, auctionEntity := range ar.auctions {
+		if isExpired(auctionEntity, now) {
+			auctions = append(auctions, *ar.toAuctionEntity(auctionEntity))
+		}
+	}
+
+	sortAuctions(auctions)
+
+	return auctions, nil
+}
+
+func (ar *AuctionRepository) CancelAuction(
+	ctx context.Context, id string, reason string) *internal_error.InternalError {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return internal_error.NewBadRequestError("cancel reason is required")
+	}
+
+	ar.mutex.Lock()
+	defer ar.mutex.Unlock()
+
+	auctionEntity, err := ar.find(id)
+	if err != nil {
+		return err
+	}
+
+	if auctionEntity.Status != auction_entity.Active && auctionEntity.Status != auction_entity.Scheduled {
+		return internal_error.NewBadRequestError("auction is not active").
+			WithCode(internal_error.CodeAuctionNotActive)
+	}
+
+	if ar.bidCounts[id] > 0 {
+		return internal_error.NewBadRequestError("auction already has bids")
+	}
+
+	auctionEntity.Status = auction_entity.Cancelled
+	auctionEntity.CancelReason = reason
+	auctionEntity.Version++
+	ar.auctions[id] = auctionEntity
+
+	return nil
+}
+
+// ExtendAuction adia a expiração de um leilão ativo em extra. Diferente do
+// Mongo, não aplica o MAX_AUCTION_EXTENSION.
+func (ar *AuctionRepository) ExtendAuction(
+	ctx context.Context, id string, extra time.Duration) *internal_error.InternalError {
+	if extra < time.Second {
+		return internal_error.NewBadRequestError("extension must be at least one second")
+	}
+
+	ar.mutex.Lock()
+	defer ar.mutex.Unlock()
+
+	auctionEntity, err := ar.find(id)
+	if err != nil {
+		return err
+	}
+
+	if auctionEntity.Status != auction_entity.Active || !auctionEntity.ExpiresAt.After(ar.clock.Now()) {
+		return internal_error.NewBadRequestError("auction is not active").
+			WithCode(internal_error.CodeAuctionNotActive)
+	}
+
+	auctionEntity.ExpiresAt = auctionEntity.ExpiresAt.Add(extra.Truncate(time.Second))
+	auctionEntity.Version++
+	ar.auctions[id] = auctionEntity
+
+	return nil
+}
+
+// CompleteWithBuyNow fecha o leilão ativo com compre já, registrando bid como
+// lance vencedor.
+func (ar *AuctionRepository) CompleteWithBuyNow(
+	ctx context.Context, bid bid_entity.Bid) *internal_error.InternalError {
+	ar.mutex.Lock()
+	defer ar.mutex.Unlock()
+
+	auctionEntity, ok := ar.auctions[bid.AuctionId]
+	if !ok || auctionEntity.Status != auction_entity.Active || auctionEntity.BuyNowPrice <= 0 {
+		return internal_error.NewBadRequestError("auction is not active").
+			WithCode(internal_error.CodeAuctionNotActive)
+	}
+
+	auctionEntity.Status = auction_entity.Completed
+	auctionEntity.WinningBidId = bid.Id
+	auctionEntity.WinnerUserId = bid.UserId
+	auctionEntity.WinningAmount = bid.Amount
+	auctionEntity.Version++
+	ar.auctions[bid.AuctionId] = auctionEntity
+	ar.bidCounts[bid.AuctionId]++
+
+	return nil
+}
+
+// CloseAuctions fecha na hora os leilões ativos da lista e retorna quantos
+// foram fechados; ids repetidos, inexistentes ou de leilões que não estão
+// ativos são ignorados.
+func (ar *AuctionRepository) CloseAuctions(
+	ctx context.Context, ids []string) (int64, *internal_error.InternalError) {
+	ar.mutex.Lock()
+	var closedIds []string
+	for _, id := range ids {
+		auctionEntity, ok := ar.auctions[id]
+		if !ok || auctionEntity.Status != auction_entity.Active {
+			continue
+		}
+
+		ar.complete(auctionEntity)
+		closedIds = append(closedIds, id)
+	}
+	onAuctionClosed := ar.onAuctionClosed
+	ar.mutex.Unlock()
+
+	notifyClosed(ctx, onAuctionClosed, closedIds)
+
+	return int64(len(closedIds)), nil
+}
+
+// CloseExpiredAuctions faz o trabalho de um tick do closer: abre os leilões
+// agendados cujo StartsAt chegou e fecha os ativos já expirados. Retorna os
+// ids fechados.
+func (ar *AuctionRepository) CloseExpiredAuctions(ctx context.Context) []string {
+	ar.mutex.Lock()
+	now := ar.clock.Now()
+
+	// Como no Mongo, os agendados abrem antes: um que abriu e expirou fecha
+	// no mesmo tick
+	for id, auctionEntity := range ar.auctions {
+		if auctionEntity.Status == auction_entity.Scheduled && !auctionEntity.StartsAt.After(now) {
+			auctionEntity.Status = auction_entity.Active
+			auctionEntity.Version++
+			ar.auctions[id] = auctionEntity
+		}
+	}
+
+	var closedIds []string
+	for _, auctionEntity := range ar.auctions {
+		if isExpired(auctionEntity, now) {
+			ar.complete(auctionEntity)
+			closedIds = append(closedIds, auctionEntity.Id)
+		}
+	}
+	sort.Strings(closedIds)
+	onAuctionClosed := ar.onAuctionClosed
+	ar.mutex.Unlock()
+
+	notifyClosed(ctx, onAuctionClosed, closedIds)
+
+	return closedIds
+}
+
+// find deve ser chamado com o mutex travado
+func (ar *AuctionRepository) find(id string) (auction_entity.Auction, *internal_error.InternalError) {
+	auctionEntity, ok := ar.auctions[id]
+	if !ok {
+		return auction_entity.Auction{}, internal_error.NewNotFoundError(
+			fmt.Sprintf("Auction not found with this id = %s", id)).
+			WithCode(internal_error.CodeAuctionNotFound)
+	}
+
+	return auctionEntity, nil
+}
+
+// complete deve ser chamado com o mutex travado
+func (ar *AuctionRepository) complete(auctionEntity auction_entity.Auction) {
+	auctionEntity.Status = auction_entity.Completed
+	auctionEntity.Version++
+	ar.auctions[auctionEntity.Id] = auctionEntity
+}
+
+func (ar *AuctionRepository) expiresAt(auctionEntity *auction_entity.Auction) time.Time {
+	if !auctionEntity.ExpiresAt.IsZero() {
+		return auctionEntity.ExpiresAt
+	}
+
+	if auctionEntity.StartsAt.After(auctionEntity.Timestamp) {
+		return auctionEntity.StartsAt.Add(ar.auctionInterval)
+	}
+	return auctionEntity.Timestamp.Add(ar.auctionInterval)
+}
+
+// toAuctionEntity devolve uma cópia, para que quem chamou não altere o mapa
+func (ar *AuctionRepository) toAuctionEntity(auctionEntity auction_entity.Auction) *auction_entity.Auction {
+	auctionEntity.Currency = bid_entity.CurrencyOrDefault(auctionEntity.Currency)
+	auctionEntity.Images = slices.Clone(auctionEntity.Images)
+	if auctionEntity.ReserveMet != nil {
+		reserveMet := *auctionEntity.ReserveMet
+		auctionEntity.ReserveMet = &reserveMet
+	}
+
+	return &auctionEntity
+}
+
+// isExpired usa a mesma comparação do closer do Mongo, expires_at < agora
+// em segundos
+func isExpired(auctionEntity auction_entity.Auction, now time.Time) bool {
+	return auctionEntity.Status == auction_entity.Active &&
+		auctionEntity.ExpiresAt.Unix() < now.Unix()
+}
+
+func notifyClosed(
+	ctx context.Context, onAuctionClosed func(ctx context.Context, auctionID string), closedIds []string) {
+	if onAuctionClosed == nil {
+		return
+	}
+
+	for _, auctionId := range closedIds {
+		onAuctionClosed(ctx, auctionId)
+	}
+}
+
+func sortAuctions(auctions []auction_entity.Auction) {
+	sort.Slice(auctions, func(i, j int) bool {
+		if !auctions[i].Timestamp.Equal(auctions[j].Timestamp) {
+			return auctions[i].Timestamp.Before(auctions[j].Timestamp)
+		}
+		return auctions[i].Id < auctions[j].Id
+	})
+}
+
+func truncateToSecond(value time.Time) time.Time {
+	if value.IsZero() {
+		return value
+	}
+
+	return value.Truncate(time.Second)
+}
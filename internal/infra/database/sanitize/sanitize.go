@@ -0,0 +1,28 @@
+package sanitize
+
+import (
+	"fmt"
+	"fullcycle-auction_go/internal/internal_error"
+	"regexp"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ContainsRegex monta um filtro "contém", sem diferenciar maiúsculas, que
+// trata o texto do usuário literalmente. Sem o escape um valor como ".*" ou
+// "(a+)+$" viraria parte da expressão regular.
+func ContainsRegex(value string) primitive.Regex {
+	return primitive.Regex{Pattern: regexp.QuoteMeta(value), Options: "i"}
+}
+
+// Key valida um nome de campo vindo do usuário antes de usá-lo como chave de
+// um documento bson. Chaves com "$" seriam interpretadas como operadores.
+func Key(key string) (string, *internal_error.InternalError) {
+	if key == "" || strings.Contains(key, "$") {
+		return "", internal_error.NewBadRequestError(
+			fmt.Sprintf("invalid field name %q", key))
+	}
+
+	return key, nil
+}
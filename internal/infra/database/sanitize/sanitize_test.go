@@ -0,0 +1,71 @@
+package sanitize
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestContainsRegex(t *testing.T) {
+	testCases := []struct {
+		name     string
+		value    string
+		matches  []string
+		rejected []string
+	}{
+		{
+			name:     "plain text",
+			value:    "phone",
+			matches:  []string{"Phone", "smartphone X"},
+			rejected: []string{"tablet"},
+		},
+		{
+			name:     "match everything",
+			value:    ".*",
+			matches:  []string{"promo .* edition"},
+			rejected: []string{"Phone", ""},
+		},
+		{
+			name:     "catastrophic backtracking",
+			value:    "(a+)+$",
+			matches:  []string{"x(a+)+$"},
+			rejected: []string{"aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa!"},
+		},
+		{
+			name:     "operator document",
+			value:    `{$gt:""}`,
+			matches:  []string{`filter {$gt:""}`},
+			rejected: []string{"anything"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			regex := ContainsRegex(tc.value)
+			require.Equal(t, "i", regex.Options)
+
+			// O Mongo usa PCRE; para padrões escapados o comportamento é o
+			// mesmo do pacote regexp
+			compiled := regexp.MustCompile("(?i)" + regex.Pattern)
+			for _, value := range tc.matches {
+				require.True(t, compiled.MatchString(value), value)
+			}
+			for _, value := range tc.rejected {
+				require.False(t, compiled.MatchString(value), value)
+			}
+		})
+	}
+}
+
+func TestKey(t *testing.T) {
+	key, err := Key("amount")
+	require.Nil(t, err)
+	require.Equal(t, "amount", key)
+
+	for _, invalid := range []string{"", "$gt", "amount.$where", `{$gt:""}`} {
+		_, err := Key(invalid)
+		require.NotNil(t, err, invalid)
+		require.Equal(t, "bad_request", err.Err)
+	}
+}
@@ -2,18 +2,20 @@ package user
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"fullcycle-auction_go/configuration/logger"
 	"fullcycle-auction_go/internal/entity/user_entity"
+	"fullcycle-auction_go/internal/infra/database/database_error"
 	"fullcycle-auction_go/internal/internal_error"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type UserEntityMongo struct {
-	Id   string `bson:"_id"`
-	Name string `bson:"name"`
+	Id            string `bson:"_id"`
+	Name          string `bson:"name"`
+	WebhookSecret string `bson:"webhook_secret,omitempty"`
 }
 
 type UserRepository struct {
@@ -33,20 +35,53 @@ func (ur *UserRepository) FindUserById(
 	var userEntityMongo UserEntityMongo
 	err := ur.Collection.FindOne(ctx, filter).Decode(&userEntityMongo)
 	if err != nil {
-		if errors.Is(err, mongo.ErrNoDocuments) {
-			logger.Error(fmt.Sprintf("User not found with this id = %d", userId), err)
+		if database_error.IsNotFound(err) {
+			logger.Error(fmt.Sprintf("User not found with this id = %s", userId), err)
 			return nil, internal_error.NewNotFoundError(
-				fmt.Sprintf("User not found with this id = %d", userId))
+				fmt.Sprintf("User not found with this id = %s", userId)).
+				WithCode(internal_error.CodeUserNotFound)
 		}
 
 		logger.Error("Error trying to find user by userId", err)
-		return nil, internal_error.NewInternalServerError("Error trying to find user by userId")
+		return nil, database_error.NewInternalError(err, "Error trying to find user by userId")
 	}
 
 	userEntity := &user_entity.User{
-		Id:   userEntityMongo.Id,
-		Name: userEntityMongo.Name,
+		Id:            userEntityMongo.Id,
+		Name:          userEntityMongo.Name,
+		WebhookSecret: userEntityMongo.WebhookSecret,
 	}
 
 	return userEntity, nil
 }
+
+func (ur *UserRepository) FindExistingUserIds(
+	ctx context.Context, userIds []string) ([]string, *internal_error.InternalError) {
+	if len(userIds) == 0 {
+		return nil, nil
+	}
+
+	cursor, err := ur.Collection.Find(ctx,
+		bson.M{"_id": bson.M{"$in": userIds}},
+		options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		logger.Error("Error trying to find users by ids", err)
+		return nil, database_error.NewInternalError(err, "Error trying to find users by ids")
+	}
+	defer cursor.Close(ctx)
+
+	var existingUsers []struct {
+		Id string `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &existingUsers); err != nil {
+		logger.Error("Error trying to decode users", err)
+		return nil, database_error.NewInternalError(err, "Error trying to find users by ids")
+	}
+
+	existingIds := make([]string, 0, len(existingUsers))
+	for _, user := range existingUsers {
+		existingIds = append(existingIds, user.Id)
+	}
+
+	return existingIds, nil
+}
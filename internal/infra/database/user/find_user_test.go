@@ -0,0 +1,97 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"fullcycle-auction_go/internal/internal_error"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go/modules/mongodb"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func getTestDatabase(ctx context.Context, t *testing.T) (*mongo.Client, *mongo.Database, *mongodb.MongoDBContainer) {
+	t.Helper()
+
+	mongoContainer, err := mongodb.Run(ctx, "mongo:latest")
+	require.NoError(t, err)
+
+	mongoURL, err := mongoContainer.ConnectionString(ctx)
+	require.NoError(t, err)
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(mongoURL))
+	if err != nil {
+		t.Skipf("Skipping test: could not connect to MongoDB: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	if err := client.Ping(ctx, nil); err != nil {
+		t.Skipf("Skipping test: MongoDB not available: %v", err)
+	}
+
+	return client, client.Database(fmt.Sprintf("users_test_%d", time.Now().UnixNano())), mongoContainer
+}
+
+func TestFindUserById(t *testing.T) {
+	ctx := context.Background()
+
+	client, db, container := getTestDatabase(ctx, t)
+	defer func() {
+		if err := container.Terminate(ctx); err != nil {
+			log.Printf("failed to terminate container: %s", err)
+		}
+	}()
+	defer func() {
+		if err := client.Disconnect(ctx); err != nil {
+			log.Fatal(err)
+		}
+	}()
+	defer db.Drop(ctx)
+
+	userRepository := NewUserRepository(db)
+
+	userId := uuid.New().String()
+	_, err := userRepository.Collection.InsertOne(ctx, UserEntityMongo{Id: userId, Name: "Test User"})
+	require.NoError(t, err)
+
+	t.Run("found", func(t *testing.T) {
+		user, err := userRepository.FindUserById(ctx, userId)
+		require.Nil(t, err)
+		require.Equal(t, userId, user.Id)
+		require.Equal(t, "Test User", user.Name)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		missingId := uuid.New().String()
+
+		user, err := userRepository.FindUserById(ctx, missingId)
+		require.Nil(t, user)
+		require.NotNil(t, err)
+		require.Equal(t, "not_found", err.Err)
+		require.Equal(t, internal_error.CodeUserNotFound, err.Code)
+		require.Equal(t, fmt.Sprintf("User not found with this id = %s", missingId), err.Message)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		canceledCtx, cancel := context.WithCancel(ctx)
+		cancel()
+
+		user, err := userRepository.FindUserById(canceledCtx, userId)
+		require.Nil(t, user)
+		require.NotNil(t, err)
+		require.Equal(t, "internal_server_error", err.Err)
+	})
+
+	t.Run("existing ids", func(t *testing.T) {
+		existingIds, err := userRepository.FindExistingUserIds(ctx, []string{userId, uuid.New().String()})
+		require.Nil(t, err)
+		require.Equal(t, []string{userId}, existingIds)
+	})
+}
@@ -0,0 +1,83 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/testcontainers/testcontainers-go/modules/mongodb"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Container compartilhado pelos testes do pacote, como no pacote auction: só
+// sobe no primeiro teste que pede o banco e o TestMain o encerra no fim.
+var sharedMongo struct {
+	once      sync.Once
+	container *mongodb.MongoDBContainer
+	url       string
+	err       error
+}
+
+func TestMain(m *testing.M) {
+	code := m.Run()
+
+	if sharedMongo.container != nil {
+		if err := sharedMongo.container.Terminate(context.Background()); err != nil {
+			log.Printf("failed to terminate shared container: %s", err)
+		}
+	}
+
+	os.Exit(code)
+}
+
+func sharedMongoURL(ctx context.Context) (string, error) {
+	sharedMongo.once.Do(func() {
+		sharedMongo.container, sharedMongo.err = mongodb.Run(ctx, "mongo:latest")
+		if sharedMongo.err != nil {
+			return
+		}
+		sharedMongo.url, sharedMongo.err = sharedMongo.container.ConnectionString(ctx)
+	})
+
+	return sharedMongo.url, sharedMongo.err
+}
+
+// getSharedTestDatabase conecta ao container compartilhado. O repositório usa
+// um nome de coleção fixo, então cada teste recebe o seu banco
+// (webhooks_test_<nanos>), removido junto com o client no fim do teste.
+func getSharedTestDatabase(ctx context.Context, t *testing.T) *mongo.Database {
+	t.Helper()
+
+	mongoURL, err := sharedMongoURL(ctx)
+	if err != nil {
+		t.Skipf("Skipping test: could not start MongoDB container: %v", err)
+	}
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(mongoURL))
+	if err != nil {
+		t.Skipf("Skipping test: could not connect to MongoDB: %v", err)
+	}
+
+	pingCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	if err := client.Ping(pingCtx, nil); err != nil {
+		client.Disconnect(ctx)
+		t.Skipf("Skipping test: MongoDB not available: %v", err)
+	}
+
+	db := client.Database(fmt.Sprintf("webhooks_test_%d", time.Now().UnixNano()))
+	t.Cleanup(func() {
+		db.Drop(ctx)
+		if err := client.Disconnect(ctx); err != nil {
+			log.Printf("failed to disconnect client: %s", err)
+		}
+	})
+
+	return db
+}
@@ -0,0 +1,192 @@
+package webhook
+
+import (
+	"context"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/entity/webhook_entity"
+	"fullcycle-auction_go/internal/infra/database/database_error"
+	"fullcycle-auction_go/internal/internal_error"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+const webhookOutboxCollectionName = "webhook_outbox"
+
+type WebhookEntityMongo struct {
+	Id            string                       `bson:"_id"`
+	AuctionId     string                       `bson:"auction_id"`
+	URL           string                       `bson:"url"`
+	Payload       []byte                       `bson:"payload"`
+	Signature     string                       `bson:"signature,omitempty"`
+	Status        webhook_entity.WebhookStatus `bson:"status"`
+	Attempts      int                          `bson:"attempts"`
+	LastError     string                       `bson:"last_error,omitempty"`
+	NextAttemptAt int64                        `bson:"next_attempt_at"`
+	CreatedAt     int64                        `bson:"created_at"`
+	DeliveredAt   int64                        `bson:"delivered_at,omitempty"`
+}
+
+// WebhookOutboxRepository guarda os webhooks de leilão fechado que não foram
+// entregues, para que sejam reenviados mesmo depois de um restart.
+type WebhookOutboxRepository struct {
+	Collection *mongo.Collection
+}
+
+func NewWebhookOutboxRepository(ctx context.Context, database *mongo.Database) *WebhookOutboxRepository {
+	repository := &WebhookOutboxRepository{
+		Collection: database.Collection(webhookOutboxCollectionName),
+	}
+
+	indexModel := mongo.IndexModel{
+		Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}},
+	}
+	if _, err := repository.Collection.Indexes().CreateOne(ctx, indexModel); err != nil {
+		logger.Error("Error trying to create webhook outbox index", err)
+	}
+
+	return repository
+}
+
+func (wr *WebhookOutboxRepository) SaveWebhook(
+	ctx context.Context, webhook *webhook_entity.Webhook) *internal_error.InternalError {
+	webhookEntityMongo := &WebhookEntityMongo{
+		Id:            webhook.Id,
+		AuctionId:     webhook.AuctionId,
+		URL:           webhook.URL,
+		Payload:       webhook.Payload,
+		Signature:     webhook.Signature,
+		Status:        webhook.Status,
+		Attempts:      webhook.Attempts,
+		LastError:     webhook.LastError,
+		NextAttemptAt: webhook.NextAttemptAt.Unix(),
+		CreatedAt:     webhook.CreatedAt.Unix(),
+	}
+
+	if _, err := wr.Collection.InsertOne(ctx, webhookEntityMongo); err != nil {
+		logger.Error("Error trying to save webhook in the outbox", err,
+			zap.String("auction_id", webhook.AuctionId))
+		return database_error.NewInternalError(err, "Error trying to save webhook in the outbox")
+	}
+
+	return nil
+}
+
+func (wr *WebhookOutboxRepository) ClaimDueWebhook(
+	ctx context.Context, lease time.Duration) (*webhook_entity.Webhook, *internal_error.InternalError) {
+	now := time.Now()
+	filter := bson.M{
+		"status":          webhook_entity.Pending,
+		"next_attempt_at": bson.M{"$lte": now.Unix()},
+	}
+	update := bson.M{"$set": bson.M{"next_attempt_at": now.Add(lease).Unix()}}
+	findOptions := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "next_attempt_at", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var webhookEntityMongo WebhookEntityMongo
+	err := wr.Collection.FindOneAndUpdate(ctx, filter, update, findOptions).Decode(&webhookEntityMongo)
+	if err != nil {
+		if database_error.IsNotFound(err) {
+			return nil, nil
+		}
+		logger.Error("Error trying to claim webhook from the outbox", err)
+		return nil, database_error.NewInternalError(err, "Error trying to claim webhook from the outbox")
+	}
+
+	return toWebhookEntity(webhookEntityMongo), nil
+}
+
+func (wr *WebhookOutboxRepository) MarkWebhookDelivered(
+	ctx context.Context, id string) *internal_error.InternalError {
+	update := bson.M{
+		"$set":   bson.M{"status": webhook_entity.Delivered, "delivered_at": time.Now().Unix()},
+		"$inc":   bson.M{"attempts": 1},
+		"$unset": bson.M{"last_error": ""},
+	}
+
+	return wr.updateWebhook(ctx, id, update, "Error trying to mark webhook as delivered")
+}
+
+func (wr *WebhookOutboxRepository) RetryWebhookLater(
+	ctx context.Context,
+	id, lastError string,
+	nextAttemptAt time.Time) *internal_error.InternalError {
+	update := bson.M{
+		"$set": bson.M{"last_error": lastError, "next_attempt_at": nextAttemptAt.Unix()},
+		"$inc": bson.M{"attempts": 1},
+	}
+
+	return wr.updateWebhook(ctx, id, update, "Error trying to reschedule webhook")
+}
+
+func (wr *WebhookOutboxRepository) DeadLetterWebhook(
+	ctx context.Context, id, lastError string) *internal_error.InternalError {
+	update := bson.M{
+		"$set": bson.M{"status": webhook_entity.DeadLetter, "last_error": lastError},
+		"$inc": bson.M{"attempts": 1},
+	}
+
+	return wr.updateWebhook(ctx, id, update, "Error trying to dead-letter webhook")
+}
+
+func (wr *WebhookOutboxRepository) updateWebhook(
+	ctx context.Context, id string, update bson.M, message string) *internal_error.InternalError {
+	if _, err := wr.Collection.UpdateOne(ctx, bson.M{"_id": id}, update); err != nil {
+		logger.Error(message, err, zap.String("webhook_id", id))
+		return database_error.NewInternalError(err, message)
+	}
+
+	return nil
+}
+
+// FindPendingWebhooks lista as notificações que ainda aguardam entrega, da
+// próxima tentativa para a mais distante. Uma lista que só cresce indica um
+// endpoint de vendedor fora do ar.
+func (wr *WebhookOutboxRepository) FindPendingWebhooks(
+	ctx context.Context) ([]webhook_entity.Webhook, *internal_error.InternalError) {
+	findOptions := options.Find().SetSort(bson.D{{Key: "next_attempt_at", Value: 1}, {Key: "_id", Value: 1}})
+
+	cursor, err := wr.Collection.Find(ctx, bson.M{"status": webhook_entity.Pending}, findOptions)
+	if err != nil {
+		logger.Error("Error trying to find pending webhooks", err)
+		return nil, database_error.NewInternalError(err, "Error trying to find pending webhooks")
+	}
+	defer cursor.Close(ctx)
+
+	var webhooksMongo []WebhookEntityMongo
+	if err := cursor.All(ctx, &webhooksMongo); err != nil {
+		logger.Error("Error trying to decode pending webhooks", err)
+		return nil, database_error.NewInternalError(err, "Error trying to find pending webhooks")
+	}
+
+	webhooks := make([]webhook_entity.Webhook, 0, len(webhooksMongo))
+	for _, webhookEntityMongo := range webhooksMongo {
+		webhooks = append(webhooks, *toWebhookEntity(webhookEntityMongo))
+	}
+
+	return webhooks, nil
+}
+
+func toWebhookEntity(webhookEntityMongo WebhookEntityMongo) *webhook_entity.Webhook {
+	webhook := &webhook_entity.Webhook{
+		Id:            webhookEntityMongo.Id,
+		AuctionId:     webhookEntityMongo.AuctionId,
+		URL:           webhookEntityMongo.URL,
+		Payload:       webhookEntityMongo.Payload,
+		Signature:     webhookEntityMongo.Signature,
+		Status:        webhookEntityMongo.Status,
+		Attempts:      webhookEntityMongo.Attempts,
+		LastError:     webhookEntityMongo.LastError,
+		NextAttemptAt: time.Unix(webhookEntityMongo.NextAttemptAt, 0),
+		CreatedAt:     time.Unix(webhookEntityMongo.CreatedAt, 0),
+	}
+	if webhookEntityMongo.DeliveredAt != 0 {
+		webhook.DeliveredAt = time.Unix(webhookEntityMongo.DeliveredAt, 0)
+	}
+
+	return webhook
+}
@@ -0,0 +1,86 @@
+package webhook
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/entity/webhook_entity"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestWebhookOutboxRepository(t *testing.T) {
+	ctx := context.Background()
+
+	db := getSharedTestDatabase(ctx, t)
+
+	outbox := NewWebhookOutboxRepository(ctx, db)
+
+	due := webhook_entity.CreateWebhook("test-auction-due", "https://example.com/hooks",
+		[]byte(`{"auction_id":"test-auction-due"}`), "sha256=test", 3, "webhook responded with status 502", time.Now().Add(-time.Minute))
+	later := webhook_entity.CreateWebhook("test-auction-later", "https://example.com/hooks",
+		[]byte(`{"auction_id":"test-auction-later"}`), "", 3, "webhook responded with status 503", time.Now().Add(time.Hour))
+	require.Nil(t, outbox.SaveWebhook(ctx, due))
+	require.Nil(t, outbox.SaveWebhook(ctx, later))
+
+	pending, err := outbox.FindPendingWebhooks(ctx)
+	require.Nil(t, err)
+	require.Len(t, pending, 2)
+	require.Equal(t, due.Id, pending[0].Id)
+	require.Equal(t, []byte(`{"auction_id":"test-auction-due"}`), pending[0].Payload)
+	require.Equal(t, 3, pending[0].Attempts)
+
+	t.Run("claims only due webhooks, once per lease", func(t *testing.T) {
+		claimed, err := outbox.ClaimDueWebhook(ctx, time.Minute)
+		require.Nil(t, err)
+		require.NotNil(t, claimed)
+		require.Equal(t, due.Id, claimed.Id)
+		require.True(t, claimed.NextAttemptAt.After(time.Now()))
+
+		claimed, err = outbox.ClaimDueWebhook(ctx, time.Minute)
+		require.Nil(t, err)
+		require.Nil(t, claimed)
+	})
+
+	t.Run("reschedules a failed retry", func(t *testing.T) {
+		require.Nil(t, outbox.RetryWebhookLater(ctx, due.Id, "webhook responded with status 500", time.Now().Add(-time.Second)))
+
+		claimed, err := outbox.ClaimDueWebhook(ctx, time.Minute)
+		require.Nil(t, err)
+		require.NotNil(t, claimed)
+		require.Equal(t, 4, claimed.Attempts)
+		require.Equal(t, "webhook responded with status 500", claimed.LastError)
+	})
+
+	t.Run("delivered and dead-lettered webhooks leave the pending list", func(t *testing.T) {
+		require.Nil(t, outbox.MarkWebhookDelivered(ctx, due.Id))
+		require.Nil(t, outbox.DeadLetterWebhook(ctx, later.Id, "webhook responded with status 410"))
+
+		pending, err := outbox.FindPendingWebhooks(ctx)
+		require.Nil(t, err)
+		require.Empty(t, pending)
+
+		var delivered WebhookEntityMongo
+		require.NoError(t, outbox.Collection.FindOne(ctx, bson.M{"_id": due.Id}).Decode(&delivered))
+		require.Equal(t, webhook_entity.Delivered, delivered.Status)
+		require.Equal(t, 5, delivered.Attempts)
+		require.NotZero(t, delivered.DeliveredAt)
+		require.Empty(t, delivered.LastError)
+
+		var deadLetter WebhookEntityMongo
+		require.NoError(t, outbox.Collection.FindOne(ctx, bson.M{"_id": later.Id}).Decode(&deadLetter))
+		require.Equal(t, webhook_entity.DeadLetter, deadLetter.Status)
+		require.Equal(t, 4, deadLetter.Attempts)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		canceledCtx, cancel := context.WithCancel(ctx)
+		cancel()
+
+		claimed, err := outbox.ClaimDueWebhook(canceledCtx, time.Minute)
+		require.Nil(t, claimed)
+		require.NotNil(t, err)
+		require.Equal(t, "internal_server_error", err.Err)
+	})
+}
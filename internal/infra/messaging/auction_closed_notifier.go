@@ -0,0 +1,138 @@
+package messaging
+
+import (
+	"context"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/entity/bid_entity"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	defaultNotifierBufferSize = 1000
+	publishTimeout            = 5 * time.Second
+)
+
+type auctionClosed struct {
+	auctionId string
+	closedAt  time.Time
+}
+
+// AuctionClosedNotifier publica um evento auction.closed para cada leilão
+// fechado. OnAuctionClosed apenas enfileira o leilão, então pode ser usado
+// como callback do closer sem atrasá-lo; a busca do lance vencedor e a
+// publicação rodam em uma goroutine própria, iniciada por Start.
+//
+// O notifier é criado antes dos repositórios para ser passado ao repositório
+// de leilões com WithOnClosed: os leilões fechados pela reconciliação do
+// construtor ficam na fila até o Start receber o repositório de lances.
+type AuctionClosedNotifier struct {
+	bidRepository  bid_entity.BidEntityRepository
+	eventPublisher EventPublisher
+	closed         chan auctionClosed
+	startOnce      *sync.Once
+	closeOnce      *sync.Once
+	closeMu        *sync.RWMutex
+	isClosed       bool
+	wg             *sync.WaitGroup
+}
+
+func NewAuctionClosedNotifier(eventPublisher EventPublisher) *AuctionClosedNotifier {
+	if eventPublisher == nil {
+		eventPublisher = NoopPublisher{}
+	}
+
+	return &AuctionClosedNotifier{
+		eventPublisher: eventPublisher,
+		closed:         make(chan auctionClosed, defaultNotifierBufferSize),
+		startOnce:      &sync.Once{},
+		closeOnce:      &sync.Once{},
+		closeMu:        &sync.RWMutex{},
+		wg:             &sync.WaitGroup{},
+	}
+}
+
+// Start inicia a publicação dos eventos, inclusive os enfileirados antes
+// dele. Chamadas seguintes são ignoradas.
+func (n *AuctionClosedNotifier) Start(bidRepository bid_entity.BidEntityRepository) {
+	n.startOnce.Do(func() {
+		n.bidRepository = bidRepository
+
+		n.wg.Add(1)
+		go func() {
+			defer n.wg.Done()
+			n.run()
+		}()
+	})
+}
+
+func (n *AuctionClosedNotifier) OnAuctionClosed(ctx context.Context, auctionId string) {
+	// O lock de leitura impede que o Close feche o canal no meio do envio;
+	// leilões fechados depois do Close são descartados
+	n.closeMu.RLock()
+	defer n.closeMu.RUnlock()
+
+	if n.isClosed {
+		logger.Warn("Auction closed notifier is closed, dropping event",
+			zap.String("auction_id", auctionId))
+		return
+	}
+
+	select {
+	case n.closed <- auctionClosed{auctionId: auctionId, closedAt: time.Now().UTC()}:
+	default:
+		logger.Warn("Auction closed event queue is full, dropping event",
+			zap.String("auction_id", auctionId))
+	}
+}
+
+// Close para de aceitar eventos e aguarda a publicação dos que já estão na
+// fila. Sem Start, os eventos enfileirados são descartados.
+func (n *AuctionClosedNotifier) Close() {
+	n.closeOnce.Do(func() {
+		n.closeMu.Lock()
+		defer n.closeMu.Unlock()
+
+		n.isClosed = true
+		close(n.closed)
+	})
+	n.wg.Wait()
+}
+
+func (n *AuctionClosedNotifier) run() {
+	for closed := range n.closed {
+		n.publish(closed)
+	}
+}
+
+func (n *AuctionClosedNotifier) publish(closed auctionClosed) {
+	ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+	defer cancel()
+
+	event := AuctionClosedEvent{
+		AuctionId: closed.auctionId,
+		ClosedAt:  closed.closedAt,
+	}
+
+	winningBid, err := n.bidRepository.FindWinningBidByAuctionId(ctx, closed.auctionId)
+	if err != nil && err.Err != "not_found" {
+		logger.Error("Error trying to find winning bid for auction closed event", err,
+			zap.String("auction_id", closed.auctionId))
+	}
+	if err == nil {
+		event.WinningBid = &WinningBid{
+			Id:        winningBid.Id,
+			UserId:    winningBid.UserId,
+			Amount:    winningBid.Amount.Float64(),
+			Currency:  winningBid.Currency,
+			Timestamp: winningBid.Timestamp,
+		}
+	}
+
+	if err := n.eventPublisher.Publish(ctx, AuctionClosedEventName, event); err != nil {
+		logger.Error("Error trying to publish auction closed event", err,
+			zap.String("auction_id", closed.auctionId))
+	}
+}
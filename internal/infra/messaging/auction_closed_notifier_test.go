@@ -0,0 +1,145 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"fullcycle-auction_go/internal/entity/bid_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type inMemoryPublisher struct {
+	mutex  *sync.Mutex
+	events []AuctionClosedEvent
+	fail   bool
+}
+
+func (p *inMemoryPublisher) Publish(ctx context.Context, eventName string, payload any) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.fail {
+		return errors.New("broker unavailable")
+	}
+
+	if eventName == AuctionClosedEventName {
+		p.events = append(p.events, payload.(AuctionClosedEvent))
+	}
+	return nil
+}
+
+type fakeBidRepository struct {
+	winningBids map[string]*bid_entity.Bid
+}
+
+func (f *fakeBidRepository) CreateBid(
+	ctx context.Context, bidEntities []bid_entity.Bid) *internal_error.InternalError {
+	return nil
+}
+
+func (f *fakeBidRepository) FindBidByAuctionId(
+	ctx context.Context, auctionId string, sort string, limit int64) ([]bid_entity.Bid, *internal_error.InternalError) {
+	return nil, nil
+}
+
+func (f *fakeBidRepository) FindWinningBidByAuctionId(
+	ctx context.Context, auctionId string) (*bid_entity.Bid, *internal_error.InternalError) {
+	if bid, ok := f.winningBids[auctionId]; ok {
+		return bid, nil
+	}
+	return nil, internal_error.NewNotFoundError("no bids found for this auction")
+}
+
+func (f *fakeBidRepository) DeleteBidsByAuctionId(
+	ctx context.Context, auctionId string) (int64, *internal_error.InternalError) {
+	return 0, nil
+}
+
+func (f *fakeBidRepository) IsLeadingBidder(
+	ctx context.Context, auctionId, userId string) (bool, *internal_error.InternalError) {
+	return false, nil
+}
+
+func TestAuctionClosedNotifier(t *testing.T) {
+	winningBid := &bid_entity.Bid{
+		Id:        "test-bid-winner",
+		UserId:    "test-user",
+		AuctionId: "test-auction-with-bids",
+		Amount:    bid_entity.AmountFromCents(250_00),
+		Timestamp: time.Now(),
+	}
+	bidRepository := &fakeBidRepository{
+		winningBids: map[string]*bid_entity.Bid{winningBid.AuctionId: winningBid},
+	}
+	publisher := &inMemoryPublisher{mutex: &sync.Mutex{}}
+
+	notifier := NewAuctionClosedNotifier(publisher)
+	notifier.Start(bidRepository)
+
+	notifier.OnAuctionClosed(context.Background(), "test-auction-with-bids")
+	notifier.OnAuctionClosed(context.Background(), "test-auction-without-bids")
+	notifier.Close()
+
+	require.Len(t, publisher.events, 2)
+
+	events := map[string]AuctionClosedEvent{}
+	for _, event := range publisher.events {
+		events[event.AuctionId] = event
+	}
+
+	withBids := events["test-auction-with-bids"]
+	require.NotNil(t, withBids.WinningBid)
+	require.Equal(t, winningBid.Id, withBids.WinningBid.Id)
+	require.Equal(t, 250.0, withBids.WinningBid.Amount)
+	require.False(t, withBids.ClosedAt.IsZero())
+
+	withoutBids := events["test-auction-without-bids"]
+	require.Equal(t, "test-auction-without-bids", withoutBids.AuctionId)
+	require.Nil(t, withoutBids.WinningBid)
+}
+
+func TestAuctionClosedNotifierPublishFailure(t *testing.T) {
+	publisher := &inMemoryPublisher{mutex: &sync.Mutex{}, fail: true}
+	notifier := NewAuctionClosedNotifier(publisher)
+	notifier.Start(&fakeBidRepository{})
+
+	// Falhas de publicação são apenas logadas e não travam o notifier
+	notifier.OnAuctionClosed(context.Background(), "test-auction-failure")
+	notifier.Close()
+
+	require.Empty(t, publisher.events)
+}
+
+func TestAuctionClosedNotifierAfterClose(t *testing.T) {
+	publisher := &inMemoryPublisher{mutex: &sync.Mutex{}}
+	notifier := NewAuctionClosedNotifier(publisher)
+	notifier.Start(&fakeBidRepository{})
+	notifier.Close()
+
+	// Leilões fechados depois do Close são descartados em vez de enviados
+	// para o canal fechado
+	require.NotPanics(t, func() {
+		notifier.OnAuctionClosed(context.Background(), "test-auction-after-close")
+	})
+	notifier.Close()
+
+	require.Empty(t, publisher.events)
+}
+
+func TestAuctionClosedNotifierKeepsEventsQueuedBeforeStart(t *testing.T) {
+	publisher := &inMemoryPublisher{mutex: &sync.Mutex{}}
+	notifier := NewAuctionClosedNotifier(publisher)
+
+	// Fechamentos da reconciliação chegam antes do repositório de lances
+	notifier.OnAuctionClosed(context.Background(), "test-auction-reconciled")
+
+	notifier.Start(&fakeBidRepository{})
+	notifier.Close()
+
+	require.Len(t, publisher.events, 1)
+	require.Equal(t, "test-auction-reconciled", publisher.events[0].AuctionId)
+}
@@ -0,0 +1,324 @@
+package messaging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/entity/bid_entity"
+	"fullcycle-auction_go/internal/entity/user_entity"
+	"fullcycle-auction_go/internal/entity/webhook_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	webhookWorkers        = 4
+	webhookTimeout        = 5 * time.Second
+	webhookMaxAttempts    = 3
+	webhookRetryBaseDelay = 500 * time.Millisecond
+)
+
+// AuctionFinder é a parte do repositório de leilões usada pelo webhook
+type AuctionFinder interface {
+	FindAuctionById(
+		ctx context.Context, id string) (*auction_entity.Auction, *internal_error.InternalError)
+}
+
+// UserFinder busca o vendedor do leilão, dono do secret que assina o webhook
+type UserFinder interface {
+	FindUserById(
+		ctx context.Context, userId string) (*user_entity.User, *internal_error.InternalError)
+}
+
+// AuctionClosedWebhookPayload é o corpo enviado ao webhook do vendedor.
+// WinnerId e FinalAmount ficam vazios quando o leilão fechou sem vencedor.
+type AuctionClosedWebhookPayload struct {
+	AuctionId   string    `json:"auction_id"`
+	WinnerId    string    `json:"winner_id,omitempty"`
+	FinalAmount float64   `json:"final_amount,omitempty"`
+	Currency    string    `json:"currency,omitempty"`
+	ClosedAt    time.Time `json:"closed_at"`
+}
+
+// AuctionClosedWebhook faz um POST no WebhookURL de cada leilão fechado que
+// tiver um. Assim como o AuctionClosedNotifier, OnAuctionClosed só enfileira
+// o leilão; a entrega e as novas tentativas rodam nos workers iniciados por
+// Start, fora do closer. Entregas que esgotam as tentativas com um erro que
+// vale repetir vão para o outbox, de onde o WebhookOutboxWorker as reenvia.
+// O corpo vai assinado no webhookSignatureHeader com o WebhookSecret do
+// vendedor; vendedores sem secret recebem o webhook sem assinatura.
+type AuctionClosedWebhook struct {
+	auctionFinder  AuctionFinder
+	bidRepository  bid_entity.BidEntityRepository
+	userFinder     UserFinder
+	outbox         webhook_entity.WebhookOutboxRepositoryInterface
+	httpClient     *http.Client
+	retryBaseDelay time.Duration
+	closed         chan auctionClosed
+	done           chan struct{}
+	startOnce      *sync.Once
+	closeOnce      *sync.Once
+	closeMu        *sync.RWMutex
+	isClosed       bool
+	wg             *sync.WaitGroup
+}
+
+// NewAuctionClosedWebhook usa o client de newWebhookHTTPClient, que recusa
+// endereços internos, quando httpClient é nil. Sem outbox as entregas que falham são apenas registradas
+// no log.
+func NewAuctionClosedWebhook(
+	outbox webhook_entity.WebhookOutboxRepositoryInterface,
+	httpClient *http.Client) *AuctionClosedWebhook {
+	if httpClient == nil {
+		httpClient = newWebhookHTTPClient()
+	}
+
+	return &AuctionClosedWebhook{
+		outbox:         outbox,
+		httpClient:     httpClient,
+		retryBaseDelay: webhookRetryBaseDelay,
+		closed:         make(chan auctionClosed, defaultNotifierBufferSize),
+		done:           make(chan struct{}),
+		startOnce:      &sync.Once{},
+		closeOnce:      &sync.Once{},
+		closeMu:        &sync.RWMutex{},
+		wg:             &sync.WaitGroup{},
+	}
+}
+
+// Start inicia os workers de entrega, inclusive dos leilões enfileirados
+// antes dele, como os fechados pela reconciliação na criação do repositório
+// de leilões. Chamadas seguintes são ignoradas.
+func (w *AuctionClosedWebhook) Start(
+	auctionFinder AuctionFinder,
+	bidRepository bid_entity.BidEntityRepository,
+	userFinder UserFinder) {
+	w.startOnce.Do(func() {
+		w.auctionFinder = auctionFinder
+		w.bidRepository = bidRepository
+		w.userFinder = userFinder
+
+		// Vários workers para que um webhook lento não atrase os dos outros leilões
+		for i := 0; i < webhookWorkers; i++ {
+			w.wg.Add(1)
+			go func() {
+				defer w.wg.Done()
+				w.run()
+			}()
+		}
+	})
+}
+
+func (w *AuctionClosedWebhook) OnAuctionClosed(ctx context.Context, auctionId string) {
+	// O lock de leitura impede que o Close feche o canal no meio do envio;
+	// leilões fechados depois do Close são descartados
+	w.closeMu.RLock()
+	defer w.closeMu.RUnlock()
+
+	if w.isClosed {
+		logger.Warn("Auction closed webhook is closed, dropping webhook",
+			zap.String("auction_id", auctionId))
+		return
+	}
+
+	select {
+	case w.closed <- auctionClosed{auctionId: auctionId, closedAt: time.Now().UTC()}:
+	default:
+		logger.Warn("Auction closed webhook queue is full, dropping webhook",
+			zap.String("auction_id", auctionId))
+	}
+}
+
+// Close para de aceitar leilões e aguarda a entrega dos que já estão na fila.
+// As entregas não esperam mais pelas novas tentativas e, quando valeria
+// repetir, vão direto para o outbox. Sem Start, os leilões enfileirados são
+// descartados.
+func (w *AuctionClosedWebhook) Close() {
+	w.closeOnce.Do(func() {
+		w.closeMu.Lock()
+		defer w.closeMu.Unlock()
+
+		w.isClosed = true
+		close(w.closed)
+		close(w.done)
+	})
+	w.wg.Wait()
+}
+
+func (w *AuctionClosedWebhook) run() {
+	for closed := range w.closed {
+		w.notify(closed)
+	}
+}
+
+// notify monta e entrega o webhook do leilão. As leituras e a gravação no
+// outbox têm cada uma o seu publishTimeout, separado da entrega, que com as
+// novas tentativas pode levar bem mais que isso.
+func (w *AuctionClosedWebhook) notify(closed auctionClosed) {
+	auctionEntity, body, signature, ok := w.prepare(closed)
+	if !ok {
+		return
+	}
+
+	attempts, retryable, deliverErr := w.deliver(context.Background(), auctionEntity.WebhookURL, body, signature)
+	if deliverErr == nil {
+		return
+	}
+
+	logger.Error("Error trying to deliver auction closed webhook", deliverErr,
+		zap.String("auction_id", closed.auctionId))
+	if !retryable || w.outbox == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+	defer cancel()
+
+	pendingWebhook := webhook_entity.CreateWebhook(closed.auctionId, auctionEntity.WebhookURL, body, signature,
+		attempts, deliverErr.Error(), time.Now().Add(outboxRetryBaseDelay))
+	if err := w.outbox.SaveWebhook(ctx, pendingWebhook); err != nil {
+		logger.Error("Error trying to store auction closed webhook for retry", err,
+			zap.String("auction_id", closed.auctionId))
+	}
+}
+
+// prepare lê o leilão, o lance vencedor e o vendedor e monta o corpo
+// assinado. ok é falso quando não há webhook a entregar.
+func (w *AuctionClosedWebhook) prepare(closed auctionClosed) (
+	auctionEntity *auction_entity.Auction, body []byte, signature string, ok bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+	defer cancel()
+
+	auctionEntity, err := w.auctionFinder.FindAuctionById(ctx, closed.auctionId)
+	if err != nil {
+		logger.Error("Error trying to find auction for closed webhook", err,
+			zap.String("auction_id", closed.auctionId))
+		return nil, nil, "", false
+	}
+	if auctionEntity.WebhookURL == "" {
+		return nil, nil, "", false
+	}
+
+	payload := AuctionClosedWebhookPayload{
+		AuctionId: closed.auctionId,
+		ClosedAt:  closed.closedAt,
+	}
+
+	winningBid, err := w.bidRepository.FindWinningBidByAuctionId(ctx, closed.auctionId)
+	if err != nil && err.Err != "not_found" {
+		logger.Error("Error trying to find winning bid for closed webhook", err,
+			zap.String("auction_id", closed.auctionId))
+		return nil, nil, "", false
+	}
+	if err == nil {
+		payload.WinnerId = winningBid.UserId
+		payload.FinalAmount = winningBid.Amount.Float64()
+		payload.Currency = winningBid.Currency
+	}
+
+	body, marshalErr := json.Marshal(payload)
+	if marshalErr != nil {
+		logger.Error("Error trying to encode closed webhook payload", marshalErr,
+			zap.String("auction_id", closed.auctionId))
+		return nil, nil, "", false
+	}
+
+	signature, ok = w.sign(ctx, auctionEntity, body)
+	if !ok {
+		return nil, nil, "", false
+	}
+
+	return auctionEntity, body, signature, true
+}
+
+// sign assina o corpo com o secret do vendedor. Leilões sem dono e vendedores
+// sem secret ficam sem assinatura; ok é falso quando o vendedor não pôde ser
+// lido e o webhook não deve sair.
+func (w *AuctionClosedWebhook) sign(
+	ctx context.Context, auctionEntity *auction_entity.Auction, body []byte) (signature string, ok bool) {
+	if auctionEntity.UserId == "" {
+		return "", true
+	}
+
+	seller, err := w.userFinder.FindUserById(ctx, auctionEntity.UserId)
+	if err != nil {
+		if err.Err == "not_found" {
+			return "", true
+		}
+
+		logger.Error("Error trying to find seller for closed webhook", err,
+			zap.String("auction_id", auctionEntity.Id))
+		return "", false
+	}
+
+	return signWebhook(seller.WebhookSecret, body), true
+}
+
+// deliver tenta o POST até webhookMaxAttempts vezes, dobrando a espera entre
+// as tentativas. Respostas 4xx, exceto 429, não são repetidas: o endpoint do
+// vendedor recusou o payload e a próxima tentativa teria o mesmo resultado.
+// retryable indica se o último erro ainda valeria uma nova tentativa. O Close
+// interrompe a espera entre as tentativas e devolve o último erro junto com as
+// tentativas feitas até ali.
+func (w *AuctionClosedWebhook) deliver(ctx context.Context,
+	webhookURL string, body []byte, signature string) (attempts int, retryable bool, err error) {
+	delay := w.retryBaseDelay
+
+	for attempts = 1; attempts <= webhookMaxAttempts; attempts++ {
+		retryable, err = postWebhook(ctx, w.httpClient, webhookURL, body, signature)
+		if err == nil || !retryable || attempts == webhookMaxAttempts {
+			return attempts, retryable, err
+		}
+
+		logger.Warn("Auction closed webhook failed, retrying",
+			zap.Int("attempt", attempts),
+			zap.Duration("delay", delay),
+			zap.Error(err))
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-w.done:
+			timer.Stop()
+			return attempts, retryable, err
+		case <-ctx.Done():
+			timer.Stop()
+			return attempts, retryable, err
+		case <-timer.C:
+		}
+		delay *= 2
+	}
+
+	return webhookMaxAttempts, retryable, err
+}
+
+func postWebhook(ctx context.Context,
+	httpClient *http.Client, webhookURL string, body []byte, signature string) (retryable bool, err error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	if signature != "" {
+		request.Header.Set(webhookSignatureHeader, signature)
+	}
+
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return true, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode >= 200 && response.StatusCode < 300 {
+		return false, nil
+	}
+
+	retryable = response.StatusCode >= 500 || response.StatusCode == http.StatusTooManyRequests
+	return retryable, fmt.Errorf("webhook responded with status %d", response.StatusCode)
+}
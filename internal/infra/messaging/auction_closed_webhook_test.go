@@ -0,0 +1,268 @@
+package messaging
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/entity/bid_entity"
+	"fullcycle-auction_go/internal/entity/user_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeAuctionFinder struct {
+	auctions map[string]*auction_entity.Auction
+}
+
+func (f *fakeAuctionFinder) FindAuctionById(
+	ctx context.Context, id string) (*auction_entity.Auction, *internal_error.InternalError) {
+	if auction, ok := f.auctions[id]; ok {
+		return auction, nil
+	}
+	return nil, internal_error.NewNotFoundError("auction not found")
+}
+
+type fakeUserFinder struct {
+	users map[string]*user_entity.User
+}
+
+func (f *fakeUserFinder) FindUserById(
+	ctx context.Context, userId string) (*user_entity.User, *internal_error.InternalError) {
+	if user, ok := f.users[userId]; ok {
+		return user, nil
+	}
+	return nil, internal_error.NewNotFoundError("user not found")
+}
+
+// webhookServer guarda os payloads recebidos e responde com os status de
+// statuses, um por requisição; depois deles responde 200.
+type webhookServer struct {
+	mutex      sync.Mutex
+	statuses   []int
+	requests   int
+	payloads   []AuctionClosedWebhookPayload
+	signatures []string
+}
+
+func (s *webhookServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.requests++
+	s.signatures = append(s.signatures, r.Header.Get(webhookSignatureHeader))
+	if len(s.statuses) > 0 {
+		status := s.statuses[0]
+		s.statuses = s.statuses[1:]
+		w.WriteHeader(status)
+		return
+	}
+
+	var payload AuctionClosedWebhookPayload
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	s.payloads = append(s.payloads, payload)
+}
+
+func (s *webhookServer) requestCount() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.requests
+}
+
+func TestAuctionClosedWebhook(t *testing.T) {
+	handler := &webhookServer{}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	auctionFinder := &fakeAuctionFinder{auctions: map[string]*auction_entity.Auction{
+		"test-auction-with-bids":    {Id: "test-auction-with-bids", WebhookURL: server.URL + "/hooks"},
+		"test-auction-without-bids": {Id: "test-auction-without-bids", WebhookURL: server.URL + "/hooks"},
+		"test-auction-no-webhook":   {Id: "test-auction-no-webhook"},
+	}}
+	bidRepository := &fakeBidRepository{winningBids: map[string]*bid_entity.Bid{
+		"test-auction-with-bids": {
+			Id:        "test-bid-winner",
+			UserId:    "test-user",
+			AuctionId: "test-auction-with-bids",
+			Amount:    bid_entity.AmountFromCents(250_00),
+			Timestamp: time.Now(),
+			Currency:  "USD",
+		},
+	}}
+
+	webhook := NewAuctionClosedWebhook(nil, server.Client())
+	webhook.Start(auctionFinder, bidRepository, &fakeUserFinder{})
+	webhook.OnAuctionClosed(context.Background(), "test-auction-with-bids")
+	webhook.OnAuctionClosed(context.Background(), "test-auction-without-bids")
+	webhook.OnAuctionClosed(context.Background(), "test-auction-no-webhook")
+	webhook.Close()
+
+	require.Equal(t, 2, handler.requests)
+
+	// Depois do Close os leilões são descartados sem entrega
+	require.NotPanics(t, func() {
+		webhook.OnAuctionClosed(context.Background(), "test-auction-with-bids")
+	})
+	require.Equal(t, 2, handler.requests)
+
+	payloads := map[string]AuctionClosedWebhookPayload{}
+	for _, payload := range handler.payloads {
+		payloads[payload.AuctionId] = payload
+	}
+
+	withBids := payloads["test-auction-with-bids"]
+	require.Equal(t, "test-user", withBids.WinnerId)
+	require.Equal(t, 250.0, withBids.FinalAmount)
+	require.Equal(t, "USD", withBids.Currency)
+	require.False(t, withBids.ClosedAt.IsZero())
+
+	withoutBids := payloads["test-auction-without-bids"]
+	require.Equal(t, "test-auction-without-bids", withoutBids.AuctionId)
+	require.Empty(t, withoutBids.WinnerId)
+	require.Zero(t, withoutBids.FinalAmount)
+}
+
+func TestAuctionClosedWebhookRetries(t *testing.T) {
+	testCases := []struct {
+		name             string
+		statuses         []int
+		expectedRequests int
+		expectedPayloads int
+	}{
+		{
+			name:             "recovers after server errors",
+			statuses:         []int{http.StatusServiceUnavailable, http.StatusInternalServerError},
+			expectedRequests: 3,
+			expectedPayloads: 1,
+		},
+		{
+			name:             "gives up after max attempts",
+			statuses:         []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway},
+			expectedRequests: webhookMaxAttempts,
+			expectedPayloads: 0,
+		},
+		{
+			name:             "does not retry client errors",
+			statuses:         []int{http.StatusGone},
+			expectedRequests: 1,
+			expectedPayloads: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := &webhookServer{statuses: tc.statuses}
+			server := httptest.NewServer(handler)
+			defer server.Close()
+
+			auctionFinder := &fakeAuctionFinder{auctions: map[string]*auction_entity.Auction{
+				"test-auction": {Id: "test-auction", WebhookURL: server.URL},
+			}}
+
+			webhook := NewAuctionClosedWebhook(nil, server.Client())
+			webhook.Start(auctionFinder, &fakeBidRepository{}, &fakeUserFinder{})
+			webhook.retryBaseDelay = time.Millisecond
+			webhook.OnAuctionClosed(context.Background(), "test-auction")
+
+			// O Close interromperia as novas tentativas ainda pendentes
+			require.Eventually(t, func() bool {
+				return handler.requestCount() == tc.expectedRequests
+			}, 5*time.Second, time.Millisecond)
+			webhook.Close()
+
+			require.Equal(t, tc.expectedRequests, handler.requests)
+			require.Len(t, handler.payloads, tc.expectedPayloads)
+		})
+	}
+}
+
+func TestAuctionClosedWebhookCloseInterruptsRetries(t *testing.T) {
+	handler := &webhookServer{statuses: []int{http.StatusServiceUnavailable}}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	auctionFinder := &fakeAuctionFinder{auctions: map[string]*auction_entity.Auction{
+		"test-auction": {Id: "test-auction", WebhookURL: server.URL},
+	}}
+	outbox := newFakeWebhookOutbox()
+
+	webhook := NewAuctionClosedWebhook(outbox, server.Client())
+	webhook.Start(auctionFinder, &fakeBidRepository{}, &fakeUserFinder{})
+	webhook.retryBaseDelay = time.Hour
+	webhook.OnAuctionClosed(context.Background(), "test-auction")
+
+	require.Eventually(t, func() bool {
+		return handler.requestCount() == 1
+	}, 5*time.Second, time.Millisecond)
+
+	closed := make(chan struct{})
+	go func() {
+		webhook.Close()
+		close(closed)
+	}()
+
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close waited for the retry delay")
+	}
+
+	// A entrega interrompida fica no outbox para o WebhookOutboxWorker
+	pending, err := outbox.FindPendingWebhooks(context.Background())
+	require.Nil(t, err)
+	require.Len(t, pending, 1)
+	require.Equal(t, "test-auction", pending[0].AuctionId)
+	require.Equal(t, 1, pending[0].Attempts)
+	require.Equal(t, 1, handler.requestCount())
+}
+
+func TestAuctionClosedWebhookSignature(t *testing.T) {
+	handler := &webhookServer{}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	sellerId := "test-seller"
+	auctionFinder := &fakeAuctionFinder{auctions: map[string]*auction_entity.Auction{
+		"test-auction": {Id: "test-auction", UserId: sellerId, WebhookURL: server.URL},
+	}}
+	userFinder := &fakeUserFinder{users: map[string]*user_entity.User{
+		sellerId: {Id: sellerId, WebhookSecret: "test-secret"},
+	}}
+
+	webhook := NewAuctionClosedWebhook(nil, server.Client())
+	webhook.Start(auctionFinder, &fakeBidRepository{}, userFinder)
+	webhook.OnAuctionClosed(context.Background(), "test-auction")
+	webhook.Close()
+
+	require.Len(t, handler.payloads, 1)
+	body, err := json.Marshal(handler.payloads[0])
+	require.NoError(t, err)
+
+	mac := hmac.New(sha256.New, []byte("test-secret"))
+	mac.Write(body)
+	require.Equal(t, []string{"sha256=" + hex.EncodeToString(mac.Sum(nil))}, handler.signatures)
+}
+
+func TestWebhookHTTPClientRejectsInternalAddresses(t *testing.T) {
+	handler := &webhookServer{}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	// O httptest escuta em 127.0.0.1, como um serviço interno
+	_, err := postWebhook(context.Background(), newWebhookHTTPClient(), server.URL, []byte(`{}`), "")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "is not allowed")
+	require.Zero(t, handler.requests)
+}
@@ -0,0 +1,33 @@
+package messaging
+
+import (
+	"context"
+	"time"
+)
+
+const AuctionClosedEventName = "auction.closed"
+
+type AuctionClosedEvent struct {
+	AuctionId  string      `json:"auction_id"`
+	ClosedAt   time.Time   `json:"closed_at"`
+	WinningBid *WinningBid `json:"winning_bid,omitempty"`
+}
+
+type WinningBid struct {
+	Id        string    `json:"id"`
+	UserId    string    `json:"user_id"`
+	Amount    float64   `json:"amount"`
+	Currency  string    `json:"currency"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+type EventPublisher interface {
+	Publish(ctx context.Context, eventName string, payload any) error
+}
+
+// NoopPublisher descarta os eventos; é o padrão quando nenhum broker está configurado.
+type NoopPublisher struct{}
+
+func (NoopPublisher) Publish(ctx context.Context, eventName string, payload any) error {
+	return nil
+}
@@ -0,0 +1,66 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+type RabbitMQPublisher struct {
+	connection *amqp.Connection
+	channel    *amqp.Channel
+	exchange   string
+	mutex      *sync.Mutex
+}
+
+// NewRabbitMQPublisher conecta ao broker e declara um exchange do tipo topic,
+// usando o nome do evento como routing key.
+func NewRabbitMQPublisher(url, exchange string) (*RabbitMQPublisher, error) {
+	connection, err := amqp.Dial(url)
+	if err != nil {
+		return nil, err
+	}
+
+	channel, err := connection.Channel()
+	if err != nil {
+		connection.Close()
+		return nil, err
+	}
+
+	if err := channel.ExchangeDeclare(exchange, amqp.ExchangeTopic, true, false, false, false, nil); err != nil {
+		connection.Close()
+		return nil, err
+	}
+
+	return &RabbitMQPublisher{
+		connection: connection,
+		channel:    channel,
+		exchange:   exchange,
+		mutex:      &sync.Mutex{},
+	}, nil
+}
+
+func (p *RabbitMQPublisher) Publish(ctx context.Context, eventName string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return p.channel.PublishWithContext(ctx, p.exchange, eventName, false, false, amqp.Publishing{
+		ContentType:  "application/json",
+		DeliveryMode: amqp.Persistent,
+		Timestamp:    time.Now(),
+		Type:         eventName,
+		Body:         body,
+	})
+}
+
+func (p *RabbitMQPublisher) Close() error {
+	return p.connection.Close()
+}
@@ -0,0 +1,154 @@
+package messaging
+
+import (
+	"context"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/entity/webhook_entity"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	outboxCheckInterval  = 30 * time.Second
+	outboxMaxAttempts    = 10
+	outboxRetryBaseDelay = time.Minute
+	outboxMaxRetryDelay  = time.Hour
+	// outboxLease é o tempo que uma notificação fica reservada para a
+	// instância que a buscou; precisa cobrir o webhookTimeout com folga
+	outboxLease = time.Minute
+)
+
+// WebhookOutboxWorker reenvia os webhooks guardados no outbox. A cada
+// checkInterval entrega as notificações com a tentativa vencida; uma falha
+// dobra a espera até a próxima, até maxRetryDelay. Depois de maxAttempts
+// tentativas, contando as feitas pelo AuctionClosedWebhook, ou de uma
+// resposta 4xx que não vale repetir, a notificação vai para dead letter.
+type WebhookOutboxWorker struct {
+	outbox         webhook_entity.WebhookOutboxRepositoryInterface
+	httpClient     *http.Client
+	checkInterval  time.Duration
+	maxAttempts    int
+	retryBaseDelay time.Duration
+	maxRetryDelay  time.Duration
+	done           chan struct{}
+	closeOnce      *sync.Once
+	wg             *sync.WaitGroup
+}
+
+// NewWebhookOutboxWorker usa o client de newWebhookHTTPClient quando
+// httpClient é nil.
+func NewWebhookOutboxWorker(
+	outbox webhook_entity.WebhookOutboxRepositoryInterface,
+	httpClient *http.Client) *WebhookOutboxWorker {
+	if httpClient == nil {
+		httpClient = newWebhookHTTPClient()
+	}
+
+	worker := &WebhookOutboxWorker{
+		outbox:         outbox,
+		httpClient:     httpClient,
+		checkInterval:  outboxCheckInterval,
+		maxAttempts:    outboxMaxAttempts,
+		retryBaseDelay: outboxRetryBaseDelay,
+		maxRetryDelay:  outboxMaxRetryDelay,
+		done:           make(chan struct{}),
+		closeOnce:      &sync.Once{},
+		wg:             &sync.WaitGroup{},
+	}
+
+	worker.wg.Add(1)
+	go func() {
+		defer worker.wg.Done()
+		worker.run()
+	}()
+
+	return worker
+}
+
+// Close para o worker e aguarda a rodada em andamento terminar.
+func (w *WebhookOutboxWorker) Close() {
+	w.closeOnce.Do(func() {
+		close(w.done)
+	})
+	w.wg.Wait()
+}
+
+func (w *WebhookOutboxWorker) run() {
+	ticker := time.NewTicker(w.checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-ticker.C:
+			w.retryDueWebhooks(context.Background())
+		}
+	}
+}
+
+// retryDueWebhooks entrega, uma a uma, as notificações com a tentativa
+// vencida, até o outbox não ter mais nenhuma ou o worker ser fechado.
+func (w *WebhookOutboxWorker) retryDueWebhooks(ctx context.Context) {
+	for {
+		select {
+		case <-w.done:
+			return
+		default:
+		}
+
+		pendingWebhook, err := w.outbox.ClaimDueWebhook(ctx, outboxLease)
+		if err != nil || pendingWebhook == nil {
+			return
+		}
+
+		w.retry(ctx, pendingWebhook)
+	}
+}
+
+func (w *WebhookOutboxWorker) retry(ctx context.Context, pendingWebhook *webhook_entity.Webhook) {
+	log := logger.WithContext(ctx)
+
+	retryable, err := postWebhook(ctx, w.httpClient, pendingWebhook.URL, pendingWebhook.Payload, pendingWebhook.Signature)
+	if err == nil {
+		if markErr := w.outbox.MarkWebhookDelivered(ctx, pendingWebhook.Id); markErr != nil {
+			log.Error("Error trying to mark outbox webhook as delivered", markErr,
+				zap.String("webhook_id", pendingWebhook.Id))
+		}
+		return
+	}
+
+	attempts := pendingWebhook.Attempts + 1
+	if !retryable || attempts >= w.maxAttempts {
+		log.Warn("Giving up on auction closed webhook",
+			zap.String("webhook_id", pendingWebhook.Id),
+			zap.String("auction_id", pendingWebhook.AuctionId),
+			zap.Int("attempts", attempts),
+			zap.Error(err))
+		if deadLetterErr := w.outbox.DeadLetterWebhook(ctx, pendingWebhook.Id, err.Error()); deadLetterErr != nil {
+			log.Error("Error trying to dead-letter outbox webhook", deadLetterErr,
+				zap.String("webhook_id", pendingWebhook.Id))
+		}
+		return
+	}
+
+	nextAttemptAt := time.Now().Add(w.retryDelay(attempts))
+	if retryErr := w.outbox.RetryWebhookLater(ctx, pendingWebhook.Id, err.Error(), nextAttemptAt); retryErr != nil {
+		log.Error("Error trying to reschedule outbox webhook", retryErr,
+			zap.String("webhook_id", pendingWebhook.Id))
+	}
+}
+
+// retryDelay dobra a espera a cada tentativa feita pelo worker; a primeira
+// espera, depois das tentativas do AuctionClosedWebhook, é retryBaseDelay
+func (w *WebhookOutboxWorker) retryDelay(attempts int) time.Duration {
+	delay := w.retryBaseDelay
+	for i := webhookMaxAttempts; i < attempts && delay < w.maxRetryDelay; i++ {
+		delay *= 2
+	}
+
+	return min(delay, w.maxRetryDelay)
+}
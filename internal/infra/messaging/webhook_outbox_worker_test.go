@@ -0,0 +1,244 @@
+package messaging
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/entity/webhook_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeWebhookOutbox struct {
+	mutex    sync.Mutex
+	webhooks map[string]*webhook_entity.Webhook
+}
+
+func newFakeWebhookOutbox() *fakeWebhookOutbox {
+	return &fakeWebhookOutbox{webhooks: map[string]*webhook_entity.Webhook{}}
+}
+
+func (f *fakeWebhookOutbox) SaveWebhook(
+	ctx context.Context, webhook *webhook_entity.Webhook) *internal_error.InternalError {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	saved := *webhook
+	f.webhooks[webhook.Id] = &saved
+	return nil
+}
+
+func (f *fakeWebhookOutbox) ClaimDueWebhook(
+	ctx context.Context, lease time.Duration) (*webhook_entity.Webhook, *internal_error.InternalError) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	now := time.Now()
+	for _, webhook := range f.webhooks {
+		if webhook.Status == webhook_entity.Pending && !webhook.NextAttemptAt.After(now) {
+			webhook.NextAttemptAt = now.Add(lease)
+			claimed := *webhook
+			return &claimed, nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *fakeWebhookOutbox) MarkWebhookDelivered(
+	ctx context.Context, id string) *internal_error.InternalError {
+	return f.update(id, func(webhook *webhook_entity.Webhook) {
+		webhook.Status = webhook_entity.Delivered
+		webhook.DeliveredAt = time.Now()
+	})
+}
+
+func (f *fakeWebhookOutbox) RetryWebhookLater(
+	ctx context.Context,
+	id, lastError string,
+	nextAttemptAt time.Time) *internal_error.InternalError {
+	return f.update(id, func(webhook *webhook_entity.Webhook) {
+		webhook.LastError = lastError
+		webhook.NextAttemptAt = nextAttemptAt
+	})
+}
+
+func (f *fakeWebhookOutbox) DeadLetterWebhook(
+	ctx context.Context, id, lastError string) *internal_error.InternalError {
+	return f.update(id, func(webhook *webhook_entity.Webhook) {
+		webhook.Status = webhook_entity.DeadLetter
+		webhook.LastError = lastError
+	})
+}
+
+func (f *fakeWebhookOutbox) FindPendingWebhooks(
+	ctx context.Context) ([]webhook_entity.Webhook, *internal_error.InternalError) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	var pending []webhook_entity.Webhook
+	for _, webhook := range f.webhooks {
+		if webhook.Status == webhook_entity.Pending {
+			pending = append(pending, *webhook)
+		}
+	}
+	return pending, nil
+}
+
+func (f *fakeWebhookOutbox) update(
+	id string, apply func(webhook *webhook_entity.Webhook)) *internal_error.InternalError {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	webhook, ok := f.webhooks[id]
+	if !ok {
+		return internal_error.NewNotFoundError("webhook not found")
+	}
+	webhook.Attempts++
+	apply(webhook)
+	return nil
+}
+
+// makeDue adianta a próxima tentativa de todas as notificações, como se a
+// espera já tivesse passado
+func (f *fakeWebhookOutbox) makeDue() {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	for _, webhook := range f.webhooks {
+		webhook.NextAttemptAt = time.Now()
+	}
+}
+
+func (f *fakeWebhookOutbox) webhook(t *testing.T) webhook_entity.Webhook {
+	t.Helper()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	require.Len(t, f.webhooks, 1)
+	for _, webhook := range f.webhooks {
+		return *webhook
+	}
+	return webhook_entity.Webhook{}
+}
+
+func TestWebhookOutboxRetriesFailedDelivery(t *testing.T) {
+	// Falha nas tentativas do AuctionClosedWebhook e na primeira do worker
+	handler := &webhookServer{statuses: []int{
+		http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway, http.StatusServiceUnavailable,
+	}}
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	auctionFinder := &fakeAuctionFinder{auctions: map[string]*auction_entity.Auction{
+		"test-auction": {Id: "test-auction", WebhookURL: server.URL},
+	}}
+	outbox := newFakeWebhookOutbox()
+
+	webhook := NewAuctionClosedWebhook(outbox, server.Client())
+	webhook.Start(auctionFinder, &fakeBidRepository{}, &fakeUserFinder{})
+	webhook.retryBaseDelay = time.Millisecond
+	webhook.OnAuctionClosed(context.Background(), "test-auction")
+	require.Eventually(t, func() bool {
+		return handler.requestCount() == webhookMaxAttempts
+	}, 5*time.Second, time.Millisecond)
+	webhook.Close()
+
+	stored := outbox.webhook(t)
+	require.Equal(t, webhook_entity.Pending, stored.Status)
+	require.Equal(t, "test-auction", stored.AuctionId)
+	require.Equal(t, webhookMaxAttempts, stored.Attempts)
+	require.Contains(t, stored.LastError, "502")
+
+	pending, err := outbox.FindPendingWebhooks(context.Background())
+	require.Nil(t, err)
+	require.Len(t, pending, 1)
+
+	worker := NewWebhookOutboxWorker(outbox, server.Client())
+	defer worker.Close()
+
+	// Antes da espera nada é reenviado
+	worker.retryDueWebhooks(context.Background())
+	require.Equal(t, webhookMaxAttempts, handler.requests)
+
+	outbox.makeDue()
+	worker.retryDueWebhooks(context.Background())
+	stored = outbox.webhook(t)
+	require.Equal(t, webhook_entity.Pending, stored.Status)
+	require.Equal(t, webhookMaxAttempts+1, stored.Attempts)
+	require.Contains(t, stored.LastError, "503")
+	require.WithinDuration(t, time.Now().Add(outboxRetryBaseDelay*2), stored.NextAttemptAt, 5*time.Second)
+
+	outbox.makeDue()
+	worker.retryDueWebhooks(context.Background())
+	stored = outbox.webhook(t)
+	require.Equal(t, webhook_entity.Delivered, stored.Status)
+	require.Len(t, handler.payloads, 1)
+	require.Equal(t, "test-auction", handler.payloads[0].AuctionId)
+
+	pending, err = outbox.FindPendingWebhooks(context.Background())
+	require.Nil(t, err)
+	require.Empty(t, pending)
+}
+
+func TestWebhookOutboxDeadLetter(t *testing.T) {
+	testCases := []struct {
+		name             string
+		statuses         []int
+		attempts         int
+		expectedRequests int
+	}{
+		{
+			name:             "after max attempts",
+			statuses:         []int{http.StatusBadGateway, http.StatusBadGateway},
+			attempts:         outboxMaxAttempts - 2,
+			expectedRequests: 2,
+		},
+		{
+			name:             "on client errors",
+			statuses:         []int{http.StatusGone},
+			attempts:         webhookMaxAttempts,
+			expectedRequests: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := &webhookServer{statuses: tc.statuses}
+			server := httptest.NewServer(handler)
+			defer server.Close()
+
+			outbox := newFakeWebhookOutbox()
+			outbox.SaveWebhook(context.Background(), webhook_entity.CreateWebhook(
+				"test-auction", server.URL, []byte(`{"auction_id":"test-auction"}`), "",
+				tc.attempts, "webhook responded with status 502", time.Now()))
+
+			worker := NewWebhookOutboxWorker(outbox, server.Client())
+			defer worker.Close()
+
+			for i := 0; i < tc.expectedRequests; i++ {
+				outbox.makeDue()
+				worker.retryDueWebhooks(context.Background())
+			}
+
+			stored := outbox.webhook(t)
+			require.Equal(t, webhook_entity.DeadLetter, stored.Status)
+			require.Equal(t, tc.expectedRequests, handler.requests)
+			require.Empty(t, handler.payloads)
+		})
+	}
+}
+
+func TestWebhookOutboxRetryDelay(t *testing.T) {
+	worker := &WebhookOutboxWorker{retryBaseDelay: time.Minute, maxRetryDelay: 10 * time.Minute}
+
+	require.Equal(t, time.Minute, worker.retryDelay(webhookMaxAttempts))
+	require.Equal(t, 2*time.Minute, worker.retryDelay(webhookMaxAttempts+1))
+	require.Equal(t, 8*time.Minute, worker.retryDelay(webhookMaxAttempts+3))
+	require.Equal(t, 10*time.Minute, worker.retryDelay(webhookMaxAttempts+4))
+	require.Equal(t, 10*time.Minute, worker.retryDelay(webhookMaxAttempts+20))
+}
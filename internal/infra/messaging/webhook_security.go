@@ -0,0 +1,64 @@
+package messaging
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+)
+
+// webhookSignatureHeader leva o HMAC-SHA256 do corpo, assinado com o
+// WebhookSecret do vendedor, no formato "sha256=<hex>"
+const webhookSignatureHeader = "X-Auction-Signature"
+
+// newWebhookHTTPClient monta o client padrão dos webhooks. A URL é escolhida
+// pelo vendedor, então o IP é conferido na conexão, depois da resolução do
+// nome: a validação do leilão não vê um nome que resolve para a rede interna,
+// nem um DNS que muda de resposta depois dela. Redirecionamentos passam pela
+// mesma verificação.
+func newWebhookHTTPClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: webhookTimeout,
+		Control: rejectInternalAddress,
+	}
+
+	return &http.Client{
+		Timeout: webhookTimeout,
+		Transport: &http.Transport{
+			// Sem proxy, para que a conexão verificada seja a do destino
+			Proxy:               nil,
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: webhookTimeout,
+		},
+	}
+}
+
+func rejectInternalAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsMulticast() {
+		return fmt.Errorf("webhook address %s is not allowed", address)
+	}
+
+	return nil
+}
+
+// signWebhook retorna o valor do webhookSignatureHeader. Sem secret o webhook
+// vai sem assinatura.
+func signWebhook(secret string, body []byte) string {
+	if secret == "" {
+		return ""
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -1,18 +1,55 @@
 package internal_error
 
+// Códigos estáveis enviados aos clientes no campo "error_code" das respostas de erro
+const (
+	CodeBadRequest          = "BAD_REQUEST"
+	CodeNotFound            = "NOT_FOUND"
+	CodeInternalServerError = "INTERNAL_SERVER_ERROR"
+	CodeTimeout             = "TIMEOUT"
+	CodeRateLimited         = "RATE_LIMITED"
+	CodeConflict            = "CONFLICT"
+	CodeUnauthorized        = "UNAUTHORIZED"
+
+	CodeAuctionNotFound  = "AUCTION_NOT_FOUND"
+	CodeAuctionNotActive = "AUCTION_NOT_ACTIVE"
+	CodeUserNotFound     = "USER_NOT_FOUND"
+
+	CodeActiveAuctionsLimit = "ACTIVE_AUCTIONS_LIMIT_REACHED"
+	CodeReserveNotMet       = "RESERVE_NOT_MET"
+	CodeBidsHidden          = "BIDS_HIDDEN"
+	CodePartialFailure      = "PARTIAL_FAILURE"
+)
+
 type InternalError struct {
 	Message string
 	Err     string
+	Code    string
+
+	// FailedIds lista os registros que falharam em operações em lote
+	FailedIds []string
 }
 
 func (ie *InternalError) Error() string {
 	return ie.Message
 }
 
+// WithCode troca o código padrão do construtor por um mais específico
+func (ie *InternalError) WithCode(code string) *InternalError {
+	ie.Code = code
+	return ie
+}
+
+// WithFailedIds anexa os ids que falharam em uma operação em lote
+func (ie *InternalError) WithFailedIds(ids []string) *InternalError {
+	ie.FailedIds = ids
+	return ie
+}
+
 func NewNotFoundError(message string) *InternalError {
 	return &InternalError{
 		Message: message,
 		Err:     "not_found",
+		Code:    CodeNotFound,
 	}
 }
 
@@ -20,6 +57,7 @@ func NewInternalServerError(message string) *InternalError {
 	return &InternalError{
 		Message: message,
 		Err:     "internal_server_error",
+		Code:    CodeInternalServerError,
 	}
 }
 
@@ -27,5 +65,32 @@ func NewBadRequestError(message string) *InternalError {
 	return &InternalError{
 		Message: message,
 		Err:     "bad_request",
+		Code:    CodeBadRequest,
+	}
+}
+
+func NewTimeoutError(message string) *InternalError {
+	return &InternalError{
+		Message: message,
+		Err:     "timeout",
+		Code:    CodeTimeout,
+	}
+}
+
+// NewConflictError indica que o registro mudou desde que foi lido; o cliente
+// deve buscar a versão atual antes de tentar de novo
+func NewConflictError(message string) *InternalError {
+	return &InternalError{
+		Message: message,
+		Err:     "conflict",
+		Code:    CodeConflict,
+	}
+}
+
+func NewTooManyRequestsError(message string) *InternalError {
+	return &InternalError{
+		Message: message,
+		Err:     "too_many_requests",
+		Code:    CodeRateLimited,
 	}
 }
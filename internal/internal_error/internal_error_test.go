@@ -0,0 +1,48 @@
+package internal_error
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConstructorsSetDefaultCode(t *testing.T) {
+	testCases := []struct {
+		name         string
+		err          *InternalError
+		expectedErr  string
+		expectedCode string
+	}{
+		{name: "not found", err: NewNotFoundError("msg"), expectedErr: "not_found", expectedCode: CodeNotFound},
+		{name: "bad request", err: NewBadRequestError("msg"), expectedErr: "bad_request", expectedCode: CodeBadRequest},
+		{name: "conflict", err: NewConflictError("msg"), expectedErr: "conflict", expectedCode: CodeConflict},
+		{name: "too many requests", err: NewTooManyRequestsError("msg"), expectedErr: "too_many_requests", expectedCode: CodeRateLimited},
+		{name: "internal server error", err: NewInternalServerError("msg"), expectedErr: "internal_server_error", expectedCode: CodeInternalServerError},
+		{name: "timeout", err: NewTimeoutError("msg"), expectedErr: "timeout", expectedCode: CodeTimeout},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, "msg", tc.err.Error())
+			require.Equal(t, tc.expectedErr, tc.err.Err)
+			require.Equal(t, tc.expectedCode, tc.err.Code)
+		})
+	}
+}
+
+func TestWithCode(t *testing.T) {
+	err := NewNotFoundError("auction not found").WithCode(CodeAuctionNotFound)
+
+	require.Equal(t, "not_found", err.Err)
+	require.Equal(t, CodeAuctionNotFound, err.Code)
+}
+
+func TestWithFailedIds(t *testing.T) {
+	err := NewBadRequestError("2 auctions could not be created").
+		WithCode(CodePartialFailure).
+		WithFailedIds([]string{"a", "b"})
+
+	require.Equal(t, "bad_request", err.Err)
+	require.Equal(t, CodePartialFailure, err.Code)
+	require.Equal(t, []string{"a", "b"}, err.FailedIds)
+}
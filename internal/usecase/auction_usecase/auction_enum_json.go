@@ -0,0 +1,100 @@
+package auction_usecase
+
+import (
+	"encoding/json"
+	"fmt"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"strconv"
+)
+
+// No JSON da API os enums vão pelo nome. Na entrada os valores numéricos
+// antigos continuam aceitos para não quebrar clientes existentes.
+
+func (pc ProductCondition) MarshalJSON() ([]byte, error) {
+	return json.Marshal(auction_entity.ProductCondition(pc).String())
+}
+
+func (pc *ProductCondition) UnmarshalJSON(data []byte) error {
+	value, err := unmarshalEnum(data, "condition", func(name string) (int64, *internal_error.InternalError) {
+		condition, err := auction_entity.ParseProductCondition(name)
+		return int64(condition), err
+	})
+	if err != nil {
+		return err
+	}
+
+	*pc = ProductCondition(value)
+	return nil
+}
+
+func (as AuctionStatus) MarshalJSON() ([]byte, error) {
+	return json.Marshal(auction_entity.AuctionStatus(as).String())
+}
+
+func (as *AuctionStatus) UnmarshalJSON(data []byte) error {
+	value, err := unmarshalEnum(data, "status", func(name string) (int64, *internal_error.InternalError) {
+		status, err := auction_entity.ParseAuctionStatus(name)
+		return int64(status), err
+	})
+	if err != nil {
+		return err
+	}
+
+	*as = AuctionStatus(value)
+	return nil
+}
+
+func (at AuctionType) MarshalJSON() ([]byte, error) {
+	return json.Marshal(auction_entity.AuctionType(at).String())
+}
+
+func (at *AuctionType) UnmarshalJSON(data []byte) error {
+	value, err := unmarshalEnum(data, "type", func(name string) (int64, *internal_error.InternalError) {
+		auctionType, err := auction_entity.ParseAuctionType(name)
+		return int64(auctionType), err
+	})
+	if err != nil {
+		return err
+	}
+
+	*at = AuctionType(value)
+	return nil
+}
+
+// ParseAuctionStatus interpreta o status recebido em query strings, pelo
+// nome ("Active") ou pelo valor numérico antigo ("0").
+func ParseAuctionStatus(value string) (AuctionStatus, *internal_error.InternalError) {
+	if number, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return AuctionStatus(number), nil
+	}
+
+	status, err := auction_entity.ParseAuctionStatus(value)
+	if err != nil {
+		return 0, err
+	}
+
+	return AuctionStatus(status), nil
+}
+
+func unmarshalEnum(
+	data []byte,
+	field string,
+	parse func(name string) (int64, *internal_error.InternalError)) (int64, error) {
+	var number int64
+	if err := json.Unmarshal(data, &number); err == nil {
+		return number, nil
+	}
+
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return 0, fmt.Errorf("invalid %s: %s", field, data)
+	}
+
+	value, parseErr := parse(name)
+	if parseErr != nil {
+		return 0, parseErr
+	}
+
+	return value, nil
+}
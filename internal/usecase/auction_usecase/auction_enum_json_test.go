@@ -0,0 +1,126 @@
+package auction_usecase
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProductConditionJSON(t *testing.T) {
+	testCases := []struct {
+		condition ProductCondition
+		json      string
+	}{
+		{condition: 1, json: `"New"`},
+		{condition: 2, json: `"Used"`},
+		{condition: 3, json: `"Refurbished"`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.json, func(t *testing.T) {
+			data, err := json.Marshal(tc.condition)
+			require.NoError(t, err)
+			require.JSONEq(t, tc.json, string(data))
+
+			var condition ProductCondition
+			require.NoError(t, json.Unmarshal(data, &condition))
+			require.Equal(t, tc.condition, condition)
+		})
+	}
+}
+
+func TestAuctionStatusJSON(t *testing.T) {
+	testCases := []struct {
+		status AuctionStatus
+		json   string
+	}{
+		{status: 0, json: `"Active"`},
+		{status: 1, json: `"Completed"`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.json, func(t *testing.T) {
+			data, err := json.Marshal(tc.status)
+			require.NoError(t, err)
+			require.JSONEq(t, tc.json, string(data))
+
+			var status AuctionStatus
+			require.NoError(t, json.Unmarshal(data, &status))
+			require.Equal(t, tc.status, status)
+		})
+	}
+}
+
+func TestAuctionTypeJSON(t *testing.T) {
+	testCases := []struct {
+		auctionType AuctionType
+		json        string
+	}{
+		{auctionType: 0, json: `"Open"`},
+		{auctionType: 1, json: `"Sealed"`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.json, func(t *testing.T) {
+			data, err := json.Marshal(tc.auctionType)
+			require.NoError(t, err)
+			require.JSONEq(t, tc.json, string(data))
+
+			var auctionType AuctionType
+			require.NoError(t, json.Unmarshal(data, &auctionType))
+			require.Equal(t, tc.auctionType, auctionType)
+		})
+	}
+}
+
+func TestAuctionInputDTOAcceptsLegacyCondition(t *testing.T) {
+	var input AuctionInputDTO
+	require.NoError(t, json.Unmarshal([]byte(`{"condition": 2}`), &input))
+	require.Equal(t, ProductCondition(2), input.Condition)
+
+	require.NoError(t, json.Unmarshal([]byte(`{"condition": "Refurbished"}`), &input))
+	require.Equal(t, ProductCondition(3), input.Condition)
+}
+
+func TestProductConditionJSONInvalid(t *testing.T) {
+	var condition ProductCondition
+	err := json.Unmarshal([]byte(`"Broken"`), &condition)
+	require.EqualError(t, err, `invalid product condition "Broken"`)
+
+	err = json.Unmarshal([]byte(`true`), &condition)
+	require.EqualError(t, err, `invalid condition: true`)
+}
+
+func TestAuctionOutputDTOJSON(t *testing.T) {
+	data, err := json.Marshal(AuctionOutputDTO{Id: "auction-1", Condition: 1, Status: 1})
+	require.NoError(t, err)
+
+	var output map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &output))
+	require.Equal(t, "New", output["condition"])
+	require.Equal(t, "Completed", output["status"])
+}
+
+func TestParseAuctionStatus(t *testing.T) {
+	testCases := []struct {
+		value    string
+		expected AuctionStatus
+	}{
+		{value: "Active", expected: 0},
+		{value: "completed", expected: 1},
+		{value: "1", expected: 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.value, func(t *testing.T) {
+			status, err := ParseAuctionStatus(tc.value)
+			require.Nil(t, err)
+			require.Equal(t, tc.expected, status)
+		})
+	}
+
+	_, err := ParseAuctionStatus("")
+	require.NotNil(t, err)
+	require.Equal(t, "bad_request", err.Err)
+}
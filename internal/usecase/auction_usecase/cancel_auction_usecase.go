@@ -0,0 +1,15 @@
+package auction_usecase
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/internal_error"
+)
+
+type CancelAuctionInputDTO struct {
+	Reason string `json:"reason" binding:"required,min=3,max=200"`
+}
+
+func (au *AuctionUseCase) CancelAuction(
+	ctx context.Context, id string, cancelInput CancelAuctionInputDTO) *internal_error.InternalError {
+	return au.auctionRepositoryInterface.CancelAuction(ctx, id, cancelInput.Reason)
+}
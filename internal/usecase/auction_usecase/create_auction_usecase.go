@@ -10,20 +10,73 @@ import (
 )
 
 type AuctionInputDTO struct {
-	ProductName string           `json:"product_name" binding:"required,min=1"`
-	Category    string           `json:"category" binding:"required,min=2"`
-	Description string           `json:"description" binding:"required,min=10,max=200"`
-	Condition   ProductCondition `json:"condition" binding:"oneof=0 1 2"`
+	UserId       string           `json:"user_id" binding:"required,uuid"`
+	ProductName  string           `json:"product_name" binding:"required,min=1"`
+	Category     string           `json:"category" binding:"required,min=2"`
+	Description  string           `json:"description" binding:"required,min=10"`
+	Condition    ProductCondition `json:"condition" binding:"oneof=1 2 3"`
+	ReservePrice float64          `json:"reserve_price" binding:"omitempty,min=0"`
+	BuyNowPrice  float64          `json:"buy_now_price" binding:"omitempty,min=0"`
+	Currency     string           `json:"currency"`
+	StartsAt     *time.Time       `json:"starts_at"`
+	WebhookURL   string           `json:"webhook_url" binding:"omitempty,url"`
+	Images       []string         `json:"images"`
+	Type         AuctionType      `json:"type" binding:"omitempty,oneof=0 1"`
+
+	// IdempotencyKey vem do cabeçalho Idempotency-Key, não do corpo
+	IdempotencyKey string `json:"-"`
 }
 
 type AuctionOutputDTO struct {
-	Id          string           `json:"id"`
-	ProductName string           `json:"product_name"`
-	Category    string           `json:"category"`
-	Description string           `json:"description"`
-	Condition   ProductCondition `json:"condition"`
-	Status      AuctionStatus    `json:"status"`
-	Timestamp   time.Time        `json:"timestamp" time_format:"2006-01-02 15:04:05"`
+	Id            string           `json:"id"`
+	UserId        string           `json:"user_id,omitempty"`
+	ProductName   string           `json:"product_name"`
+	Category      string           `json:"category"`
+	Description   string           `json:"description"`
+	Condition     ProductCondition `json:"condition"`
+	Type          AuctionType      `json:"type"`
+	Status        AuctionStatus    `json:"status"`
+	Timestamp     time.Time        `json:"timestamp" time_format:"2006-01-02 15:04:05"`
+	ExpiresAt     time.Time        `json:"expires_at" time_format:"2006-01-02 15:04:05"`
+	StartsAt      time.Time        `json:"starts_at" time_format:"2006-01-02 15:04:05"`
+	ReservePrice  float64          `json:"reserve_price,omitempty"`
+	ReserveMet    *bool            `json:"reserve_met,omitempty"`
+	BuyNowPrice   float64          `json:"buy_now_price,omitempty"`
+	Currency      string           `json:"currency"`
+	Images        []string         `json:"images,omitempty"`
+	WinningBidId  string           `json:"winning_bid_id,omitempty"`
+	WinnerUserId  string           `json:"winner_user_id,omitempty"`
+	WinningAmount float64          `json:"winning_amount,omitempty"`
+	NoBids        bool             `json:"no_bids,omitempty"`
+	CancelReason  string           `json:"cancel_reason,omitempty"`
+	Version       int              `json:"version"`
+}
+
+func toAuctionOutputDTO(auction *auction_entity.Auction) AuctionOutputDTO {
+	return AuctionOutputDTO{
+		Id:            auction.Id,
+		UserId:        auction.UserId,
+		ProductName:   auction.ProductName,
+		Category:      auction.Category,
+		Description:   auction.Description,
+		Condition:     ProductCondition(auction.Condition),
+		Type:          AuctionType(auction.Type),
+		Status:        AuctionStatus(auction.Status),
+		Timestamp:     auction.Timestamp,
+		ExpiresAt:     auction.ExpiresAt,
+		StartsAt:      auction.StartsAt,
+		ReservePrice:  auction.ReservePrice,
+		ReserveMet:    auction.ReserveMet,
+		BuyNowPrice:   auction.BuyNowPrice,
+		Currency:      auction.Currency,
+		Images:        auction.Images,
+		WinningBidId:  auction.WinningBidId,
+		WinnerUserId:  auction.WinnerUserId,
+		WinningAmount: auction.WinningAmount.Float64(),
+		NoBids:        auction.NoBids,
+		CancelReason:  auction.CancelReason,
+		Version:       auction.Version,
+	}
 }
 
 type WinningInfoOutputDTO struct {
@@ -33,55 +86,91 @@ type WinningInfoOutputDTO struct {
 
 func NewAuctionUseCase(
 	auctionRepositoryInterface auction_entity.AuctionRepositoryInterface,
-	bidRepositoryInterface bid_entity.BidEntityRepository) AuctionUseCaseInterface {
+	bidRepositoryInterface bid_entity.BidEntityRepository,
+	rules auction_entity.Rules) AuctionUseCaseInterface {
 	return &AuctionUseCase{
 		auctionRepositoryInterface: auctionRepositoryInterface,
 		bidRepositoryInterface:     bidRepositoryInterface,
+		rules:                      rules,
 	}
 }
 
 type AuctionUseCaseInterface interface {
 	CreateAuction(
 		ctx context.Context,
-		auctionInput AuctionInputDTO) *internal_error.InternalError
+		auctionInput AuctionInputDTO) (*AuctionOutputDTO, *internal_error.InternalError)
 
 	FindAuctionById(
 		ctx context.Context, id string) (*AuctionOutputDTO, *internal_error.InternalError)
 
 	FindAuctions(
 		ctx context.Context,
-		status AuctionStatus,
-		category, productName string) ([]AuctionOutputDTO, *internal_error.InternalError)
+		status *AuctionStatus,
+		category, productName string,
+		page, limit int64) ([]AuctionOutputDTO, *internal_error.InternalError)
 
 	FindWinningBidByAuctionId(
 		ctx context.Context,
 		auctionId string) (*WinningInfoOutputDTO, *internal_error.InternalError)
+
+	DeleteAuction(
+		ctx context.Context, id string) *internal_error.InternalError
+
+	FindStaleActiveAuctions(
+		ctx context.Context) ([]AuctionOutputDTO, *internal_error.InternalError)
+
+	CancelAuction(
+		ctx context.Context, id string, cancelInput CancelAuctionInputDTO) *internal_error.InternalError
+
+	FindAuctionTimeRemaining(
+		ctx context.Context, id string) (*AuctionTimeRemainingOutputDTO, *internal_error.InternalError)
 }
 
 type ProductCondition int64
 type AuctionStatus int64
+type AuctionType int64
 
 type AuctionUseCase struct {
 	auctionRepositoryInterface auction_entity.AuctionRepositoryInterface
 	bidRepositoryInterface     bid_entity.BidEntityRepository
+	rules                      auction_entity.Rules
 }
 
 func (au *AuctionUseCase) CreateAuction(
 	ctx context.Context,
-	auctionInput AuctionInputDTO) *internal_error.InternalError {
-	auction, err := auction_entity.CreateAuction(
-		auctionInput.ProductName,
-		auctionInput.Category,
-		auctionInput.Description,
-		auction_entity.ProductCondition(auctionInput.Condition))
+	auctionInput AuctionInputDTO) (*AuctionOutputDTO, *internal_error.InternalError) {
+	input := auction_entity.AuctionInput{
+		UserId:       auctionInput.UserId,
+		ProductName:  auctionInput.ProductName,
+		Category:     auctionInput.Category,
+		Description:  au.rules.TruncatedDescription(auctionInput.Description),
+		Condition:    auction_entity.ProductCondition(auctionInput.Condition),
+		Type:         auction_entity.AuctionType(auctionInput.Type),
+		ReservePrice: auctionInput.ReservePrice,
+		BuyNowPrice:  auctionInput.BuyNowPrice,
+		Currency:     auctionInput.Currency,
+		WebhookURL:   auctionInput.WebhookURL,
+		Images:       auctionInput.Images,
+	}
+	if auctionInput.StartsAt != nil {
+		input.StartsAt = *auctionInput.StartsAt
+	}
+
+	auction, err := auction_entity.CreateAuction(input)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if err := au.auctionRepositoryInterface.CreateAuction(
-		ctx, auction); err != nil {
-		return err
+	if err := au.rules.Check(auction); err != nil {
+		return nil, err
+	}
+	auction.IdempotencyKey = auctionInput.IdempotencyKey
+
+	createdAuction, err := au.auctionRepositoryInterface.CreateAuction(ctx, auction)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	dto := toAuctionOutputDTO(createdAuction)
+	return &dto, nil
 }
@@ -0,0 +1,22 @@
+package auction_usecase
+
+import (
+	"context"
+	"fmt"
+	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/internal_error"
+)
+
+func (au *AuctionUseCase) DeleteAuction(
+	ctx context.Context, id string) *internal_error.InternalError {
+	if err := au.auctionRepositoryInterface.DeleteAuction(ctx, id); err != nil {
+		return err
+	}
+
+	// O leilão já foi removido; falhas ao remover os lances ficam apenas no log
+	if _, err := au.bidRepositoryInterface.DeleteBidsByAuctionId(ctx, id); err != nil {
+		logger.Error(fmt.Sprintf("Error trying to delete bids of deleted auction = %s", id), err)
+	}
+
+	return nil
+}
@@ -15,38 +15,46 @@ func (au *AuctionUseCase) FindAuctionById(
 		return nil, err
 	}
 
-	return &AuctionOutputDTO{
-		Id:          auctionEntity.Id,
-		ProductName: auctionEntity.ProductName,
-		Category:    auctionEntity.Category,
-		Description: auctionEntity.Description,
-		Condition:   ProductCondition(auctionEntity.Condition),
-		Status:      AuctionStatus(auctionEntity.Status),
-		Timestamp:   auctionEntity.Timestamp,
-	}, nil
+	dto := toAuctionOutputDTO(auctionEntity)
+	return &dto, nil
 }
 
+// FindAuctions lista os leilões; status nil não filtra por status.
 func (au *AuctionUseCase) FindAuctions(
 	ctx context.Context,
-	status AuctionStatus,
-	category, productName string) ([]AuctionOutputDTO, *internal_error.InternalError) {
+	status *AuctionStatus,
+	category, productName string,
+	page, limit int64) ([]AuctionOutputDTO, *internal_error.InternalError) {
+	var statusFilter *auction_entity.AuctionStatus
+	if status != nil {
+		entityStatus := auction_entity.AuctionStatus(*status)
+		statusFilter = &entityStatus
+	}
+
 	auctionEntities, err := au.auctionRepositoryInterface.FindAuctions(
-		ctx, auction_entity.AuctionStatus(status), category, productName)
+		ctx, statusFilter, category, productName, page, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	auctionOutputs := make([]AuctionOutputDTO, 0, len(auctionEntities))
+	for _, value := range auctionEntities {
+		auctionOutputs = append(auctionOutputs, toAuctionOutputDTO(&value))
+	}
+
+	return auctionOutputs, nil
+}
+
+func (au *AuctionUseCase) FindStaleActiveAuctions(
+	ctx context.Context) ([]AuctionOutputDTO, *internal_error.InternalError) {
+	auctionEntities, err := au.auctionRepositoryInterface.FindStaleActiveAuctions(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	var auctionOutputs []AuctionOutputDTO
+	auctionOutputs := make([]AuctionOutputDTO, 0, len(auctionEntities))
 	for _, value := range auctionEntities {
-		auctionOutputs = append(auctionOutputs, AuctionOutputDTO{
-			Id:          value.Id,
-			ProductName: value.ProductName,
-			Category:    value.Category,
-			Description: value.Description,
-			Condition:   ProductCondition(value.Condition),
-			Status:      AuctionStatus(value.Status),
-			Timestamp:   value.Timestamp,
-		})
+		auctionOutputs = append(auctionOutputs, toAuctionOutputDTO(&value))
 	}
 
 	return auctionOutputs, nil
@@ -60,15 +68,7 @@ func (au *AuctionUseCase) FindWinningBidByAuctionId(
 		return nil, err
 	}
 
-	auctionOutputDTO := AuctionOutputDTO{
-		Id:          auction.Id,
-		ProductName: auction.ProductName,
-		Category:    auction.Category,
-		Description: auction.Description,
-		Condition:   ProductCondition(auction.Condition),
-		Status:      AuctionStatus(auction.Status),
-		Timestamp:   auction.Timestamp,
-	}
+	auctionOutputDTO := toAuctionOutputDTO(auction)
 
 	bidWinning, err := au.bidRepositoryInterface.FindWinningBidByAuctionId(ctx, auction.Id)
 	if err != nil {
@@ -83,7 +83,8 @@ func (au *AuctionUseCase) FindWinningBidByAuctionId(
 		Id:        bidWinning.Id,
 		UserId:    bidWinning.UserId,
 		AuctionId: bidWinning.AuctionId,
-		Amount:    bidWinning.Amount,
+		Amount:    bidWinning.Amount.Float64(),
+		Currency:  bidWinning.Currency,
 		Timestamp: bidWinning.Timestamp,
 	}
 
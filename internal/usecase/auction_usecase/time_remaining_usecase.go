@@ -0,0 +1,29 @@
+package auction_usecase
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/internal_error"
+	"time"
+)
+
+type AuctionTimeRemainingOutputDTO struct {
+	Status           AuctionStatus `json:"status"`
+	ExpiresAt        time.Time     `json:"expires_at" time_format:"2006-01-02 15:04:05"`
+	SecondsRemaining int64         `json:"seconds_remaining"`
+}
+
+// FindAuctionTimeRemaining alimenta a contagem regressiva dos clientes. Os
+// segundos restantes são arredondados para baixo.
+func (au *AuctionUseCase) FindAuctionTimeRemaining(
+	ctx context.Context, id string) (*AuctionTimeRemainingOutputDTO, *internal_error.InternalError) {
+	auctionEntity, err := au.auctionRepositoryInterface.FindAuctionById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AuctionTimeRemainingOutputDTO{
+		Status:           AuctionStatus(auctionEntity.Status),
+		ExpiresAt:        auctionEntity.ExpiresAt,
+		SecondsRemaining: int64(auctionEntity.TimeRemaining(time.Now()) / time.Second),
+	}, nil
+}
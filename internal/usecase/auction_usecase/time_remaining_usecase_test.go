@@ -0,0 +1,50 @@
+package auction_usecase
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/infra/database/memory"
+	"fullcycle-auction_go/internal/internal_error"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindAuctionTimeRemaining(t *testing.T) {
+	// O repositório guarda os horários em segundos, como o Mongo
+	now := time.Now().Truncate(time.Second)
+	auctionRepository := memory.NewAuctionRepository(time.Hour)
+	for _, auctionEntity := range []*auction_entity.Auction{
+		{Id: "active", Status: auction_entity.Active, Timestamp: now, ExpiresAt: now.Add(time.Hour)},
+		{Id: "completed", Status: auction_entity.Completed, Timestamp: now, ExpiresAt: now.Add(-time.Minute)},
+	} {
+		_, err := auctionRepository.CreateAuction(context.Background(), auctionEntity)
+		require.Nil(t, err)
+	}
+	auctionUseCase := NewAuctionUseCase(auctionRepository, nil, auction_entity.DefaultRules())
+
+	t.Run("active auction", func(t *testing.T) {
+		result, err := auctionUseCase.FindAuctionTimeRemaining(context.Background(), "active")
+		require.Nil(t, err)
+		require.Equal(t, AuctionStatus(auction_entity.Active), result.Status)
+		require.Equal(t, now.Add(time.Hour), result.ExpiresAt)
+		require.Greater(t, result.SecondsRemaining, int64(3590))
+		require.LessOrEqual(t, result.SecondsRemaining, int64(3600))
+	})
+
+	t.Run("completed auction", func(t *testing.T) {
+		result, err := auctionUseCase.FindAuctionTimeRemaining(context.Background(), "completed")
+		require.Nil(t, err)
+		require.Equal(t, AuctionStatus(auction_entity.Completed), result.Status)
+		require.Equal(t, int64(0), result.SecondsRemaining)
+	})
+
+	t.Run("unknown auction", func(t *testing.T) {
+		result, err := auctionUseCase.FindAuctionTimeRemaining(context.Background(), "missing")
+		require.Nil(t, result)
+		require.NotNil(t, err)
+		require.Equal(t, "not_found", err.Err)
+		require.Equal(t, internal_error.CodeAuctionNotFound, err.Code)
+	})
+}
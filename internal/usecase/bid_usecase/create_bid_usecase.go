@@ -3,17 +3,23 @@ package bid_usecase
 import (
 	"context"
 	"fullcycle-auction_go/configuration/logger"
+	"fullcycle-auction_go/internal/entity/auction_entity"
 	"fullcycle-auction_go/internal/entity/bid_entity"
 	"fullcycle-auction_go/internal/internal_error"
+	"hash/fnv"
 	"os"
 	"strconv"
+	"sync"
 	"time"
+
+	"go.uber.org/zap"
 )
 
 type BidInputDTO struct {
 	UserId    string  `json:"user_id"`
 	AuctionId string  `json:"auction_id"`
 	Amount    float64 `json:"amount"`
+	Currency  string  `json:"currency"`
 }
 
 type BidOutputDTO struct {
@@ -21,28 +27,80 @@ type BidOutputDTO struct {
 	UserId    string    `json:"user_id"`
 	AuctionId string    `json:"auction_id"`
 	Amount    float64   `json:"amount"`
+	Currency  string    `json:"currency"`
 	Timestamp time.Time `json:"timestamp" time_format:"2006-01-02 15:04:05"`
 }
 
+type BidNotifier interface {
+	Publish(bid bid_entity.Bid)
+}
+
+// AuctionExtender dá acesso ao prazo dos leilões para a extensão anti-sniping
+// e fecha os leilões arrematados pelo compre já
+type AuctionExtender interface {
+	FindAuctionById(
+		ctx context.Context, id string) (*auction_entity.Auction, *internal_error.InternalError)
+
+	ExtendAuction(
+		ctx context.Context, id string, extra time.Duration) *internal_error.InternalError
+
+	CompleteWithBuyNow(
+		ctx context.Context, bid bid_entity.Bid) *internal_error.InternalError
+
+	// CloseGrace é a tolerância depois da expiração em que o leilão ainda
+	// aceita lances
+	CloseGrace() time.Duration
+}
+
+// auctionLockStripes é o número de locks que serializam a verificação de
+// incremento; lances do mesmo leilão caem sempre no mesmo lock
+const auctionLockStripes = 64
+
 type BidUseCase struct {
-	BidRepository bid_entity.BidEntityRepository
+	BidRepository   bid_entity.BidEntityRepository
+	AuctionExtender AuctionExtender
+	BidNotifier     BidNotifier
 
 	timer               *time.Timer
 	maxBatchSize        int
 	batchInsertInterval time.Duration
+	minBidIncrement     bid_entity.Amount
+	antiSnipeWindow     time.Duration
+	antiSnipeExtension  time.Duration
 	bidChannel          chan bid_entity.Bid
+
+	// pendingHighest guarda, por leilão, o maior lance aceito que ainda está
+	// no lote e não aparece no repositório
+	auctionLocks   [auctionLockStripes]sync.Mutex
+	pendingMu      sync.Mutex
+	pendingHighest map[string]bid_entity.Bid
+
+	done      chan struct{}
+	closeMu   sync.RWMutex
+	closeOnce sync.Once
+	wg        sync.WaitGroup
 }
 
-func NewBidUseCase(bidRepository bid_entity.BidEntityRepository) BidUseCaseInterface {
+func NewBidUseCase(
+	bidRepository bid_entity.BidEntityRepository,
+	auctionExtender AuctionExtender,
+	bidNotifier BidNotifier) BidUseCaseInterface {
 	maxSizeInterval := getMaxBatchSizeInterval()
 	maxBatchSize := getMaxBatchSize()
 
 	bidUseCase := &BidUseCase{
 		BidRepository:       bidRepository,
+		AuctionExtender:     auctionExtender,
+		BidNotifier:         bidNotifier,
 		maxBatchSize:        maxBatchSize,
 		batchInsertInterval: maxSizeInterval,
+		minBidIncrement:     getMinBidIncrement(),
+		antiSnipeWindow:     getAntiSnipeWindow(),
+		antiSnipeExtension:  getAntiSnipeExtension(),
 		timer:               time.NewTimer(maxSizeInterval),
 		bidChannel:          make(chan bid_entity.Bid, maxBatchSize),
+		pendingHighest:      make(map[string]bid_entity.Bid),
+		done:                make(chan struct{}),
 	}
 
 	bidUseCase.triggerCreateRoutine(context.Background())
@@ -50,8 +108,6 @@ func NewBidUseCase(bidRepository bid_entity.BidEntityRepository) BidUseCaseInter
 	return bidUseCase
 }
 
-var bidBatch []bid_entity.Bid
-
 type BidUseCaseInterface interface {
 	CreateBid(
 		ctx context.Context,
@@ -61,39 +117,59 @@ type BidUseCaseInterface interface {
 		ctx context.Context, auctionId string) (*BidOutputDTO, *internal_error.InternalError)
 
 	FindBidByAuctionId(
-		ctx context.Context, auctionId string) ([]BidOutputDTO, *internal_error.InternalError)
+		ctx context.Context, auctionId string, sort string, limit int64) ([]BidOutputDTO, *internal_error.InternalError)
+
+	IsLeadingBidder(
+		ctx context.Context, auctionId, userId string) (*LeadingBidderOutputDTO, *internal_error.InternalError)
+
+	// Close grava o lote em andamento e encerra a rotina de lotes
+	Close()
+}
+
+// Close grava os lances ainda no lote e encerra a rotina de lotes. Lances
+// enviados depois dele são recusados.
+func (bu *BidUseCase) Close() {
+	bu.closeOnce.Do(func() {
+		bu.closeMu.Lock()
+		defer bu.closeMu.Unlock()
+
+		close(bu.done)
+	})
+	bu.wg.Wait()
 }
 
 func (bu *BidUseCase) triggerCreateRoutine(ctx context.Context) {
+	bu.wg.Add(1)
 	go func() {
-		defer close(bu.bidChannel)
+		defer bu.wg.Done()
+		defer bu.timer.Stop()
 
+		var bidBatch []bid_entity.Bid
 		for {
 			select {
-			case bidEntity, ok := <-bu.bidChannel:
-				if !ok {
-					if len(bidBatch) > 0 {
-						if err := bu.BidRepository.CreateBid(ctx, bidBatch); err != nil {
-							logger.Error("error trying to process bid batch list", err)
+			case <-bu.done:
+				for {
+					select {
+					case bidEntity := <-bu.bidChannel:
+						bidBatch = append(bidBatch, bidEntity)
+					default:
+						if len(bidBatch) > 0 {
+							bu.processBidBatch(ctx, bidBatch)
 						}
+						return
 					}
-					return
 				}
-
+			case bidEntity := <-bu.bidChannel:
 				bidBatch = append(bidBatch, bidEntity)
 
 				if len(bidBatch) >= bu.maxBatchSize {
-					if err := bu.BidRepository.CreateBid(ctx, bidBatch); err != nil {
-						logger.Error("error trying to process bid batch list", err)
-					}
+					bu.processBidBatch(ctx, bidBatch)
 
 					bidBatch = nil
 					bu.timer.Reset(bu.batchInsertInterval)
 				}
 			case <-bu.timer.C:
-				if err := bu.BidRepository.CreateBid(ctx, bidBatch); err != nil {
-					logger.Error("error trying to process bid batch list", err)
-				}
+				bu.processBidBatch(ctx, bidBatch)
 				bidBatch = nil
 				bu.timer.Reset(bu.batchInsertInterval)
 			}
@@ -101,20 +177,346 @@ func (bu *BidUseCase) triggerCreateRoutine(ctx context.Context) {
 	}()
 }
 
+func (bu *BidUseCase) processBidBatch(ctx context.Context, bids []bid_entity.Bid) {
+	err := bu.BidRepository.CreateBid(ctx, bids)
+	// Gravados ou não, os lances deixam de contar como pendentes
+	bu.releasePendingBids(bids)
+
+	if err != nil {
+		logger.Error("error trying to process bid batch list", err,
+			zap.Strings("failed_bid_ids", err.FailedIds))
+
+		// Os lances gravados ainda precisam do anti-sniping e do stream
+		bids = storedBids(bids, err.FailedIds)
+		if len(bids) == 0 {
+			return
+		}
+	}
+
+	bu.afterBidsStored(ctx, bids)
+}
+
+// afterBidsStored estende os leilões disputados no último instante e publica
+// os lances gravados no stream.
+func (bu *BidUseCase) afterBidsStored(ctx context.Context, bids []bid_entity.Bid) {
+	auctions := bu.findBatchAuctions(ctx, bids)
+	bu.extendSnipedAuctions(ctx, bids, auctions)
+
+	if bu.BidNotifier == nil {
+		return
+	}
+
+	for _, bid := range bids {
+		// Lances de leilões Sealed não vão para o stream até o fechamento
+		if auctionEntity, ok := auctions[bid.AuctionId]; ok && auctionEntity.BidsHidden() {
+			continue
+		}
+		bu.BidNotifier.Publish(bid)
+	}
+}
+
+// storedBids retorna os lances do lote que não estão em failedIds. Sem
+// FailedIds não há como saber o que foi gravado, e o lote todo conta como
+// falho.
+func storedBids(bids []bid_entity.Bid, failedIds []string) []bid_entity.Bid {
+	if len(failedIds) == 0 {
+		return nil
+	}
+
+	failed := make(map[string]struct{}, len(failedIds))
+	for _, id := range failedIds {
+		failed[id] = struct{}{}
+	}
+
+	var stored []bid_entity.Bid
+	for _, bid := range bids {
+		if _, ok := failed[bid.Id]; !ok {
+			stored = append(stored, bid)
+		}
+	}
+
+	return stored
+}
+
+// findBatchAuctions busca uma vez cada leilão do lote. Leilões que não puderam
+// ser lidos ficam fora do mapa.
+func (bu *BidUseCase) findBatchAuctions(
+	ctx context.Context, bids []bid_entity.Bid) map[string]*auction_entity.Auction {
+	auctions := make(map[string]*auction_entity.Auction)
+	if bu.AuctionExtender == nil {
+		return auctions
+	}
+
+	for _, bid := range bids {
+		if _, ok := auctions[bid.AuctionId]; ok {
+			continue
+		}
+
+		auctionEntity, err := bu.AuctionExtender.FindAuctionById(ctx, bid.AuctionId)
+		if err != nil {
+			logger.Error("error trying to find auction of the bid batch", err,
+				zap.String("auction_id", bid.AuctionId))
+			continue
+		}
+		auctions[bid.AuctionId] = auctionEntity
+	}
+
+	return auctions
+}
+
 func (bu *BidUseCase) CreateBid(
 	ctx context.Context,
 	bidInputDTO BidInputDTO) *internal_error.InternalError {
 
-	bidEntity, err := bid_entity.CreateBid(bidInputDTO.UserId, bidInputDTO.AuctionId, bidInputDTO.Amount)
+	amount, err := bid_entity.NewAmount(bidInputDTO.Amount)
+	if err != nil {
+		return err
+	}
+
+	bidEntity, err := bid_entity.CreateBid(bidInputDTO.UserId, bidInputDTO.AuctionId, amount, bidInputDTO.Currency)
+	if err != nil {
+		return err
+	}
+
+	auctionEntity, err := bu.findBiddableAuction(ctx, bidEntity)
 	if err != nil {
 		return err
 	}
 
+	if err := bu.checkBidIncrement(ctx, bidEntity, auctionEntity); err != nil {
+		return err
+	}
+
+	closed, err := bu.tryBuyNow(ctx, bidEntity, auctionEntity)
+	if err != nil {
+		return err
+	}
+	if closed {
+		return nil
+	}
+
+	// O lock de leitura impede que o Close encerre a rotina de lotes com o
+	// lance no canal
+	bu.closeMu.RLock()
+	defer bu.closeMu.RUnlock()
+
+	select {
+	case <-bu.done:
+		bu.releasePendingBids([]bid_entity.Bid{*bidEntity})
+		return internal_error.NewInternalServerError("bid use case is closed")
+	default:
+	}
+
+	// Um lance que só sairia no lote depois da expiração é gravado na hora:
+	// o closer poderia fechar o leilão antes do lote, e o lance já aceito
+	// seria descartado sem acionar o anti-sniping
+	if auctionEntity != nil && time.Until(auctionEntity.ExpiresAt) <= bu.batchInsertInterval {
+		return bu.createBidNow(ctx, *bidEntity)
+	}
+
 	bu.bidChannel <- *bidEntity
 
 	return nil
 }
 
+// createBidNow grava o lance fora do lote e devolve a recusa do repositório
+// ao cliente.
+func (bu *BidUseCase) createBidNow(ctx context.Context, bidEntity bid_entity.Bid) *internal_error.InternalError {
+	bids := []bid_entity.Bid{bidEntity}
+
+	err := bu.BidRepository.CreateBid(ctx, bids)
+	bu.releasePendingBids(bids)
+	if err != nil {
+		return err
+	}
+
+	bu.afterBidsStored(ctx, bids)
+
+	return nil
+}
+
+// checkBidIncrement compara o lance com o maior lance do leilão, gravado ou
+// ainda no lote, e registra o lance aceito como pendente. Lances do mesmo
+// leilão são verificados um de cada vez, então dois lances do mesmo valor no
+// mesmo lote não passam juntos. A verificação vale para esta instância; entre
+// instâncias o lote de uma não vê o da outra. O primeiro lance de um leilão é
+// sempre aceito. Em leilões Sealed a verificação não roda: a recusa serviria
+// para descobrir o maior lance.
+func (bu *BidUseCase) checkBidIncrement(
+	ctx context.Context,
+	bidEntity *bid_entity.Bid,
+	auctionEntity *auction_entity.Auction) *internal_error.InternalError {
+	if auctionEntity != nil && auctionEntity.BidsHidden() {
+		return nil
+	}
+
+	lock := bu.auctionLock(bidEntity.AuctionId)
+	lock.Lock()
+	defer lock.Unlock()
+
+	highestBid, err := bu.BidRepository.FindWinningBidByAuctionId(ctx, bidEntity.AuctionId)
+	if err != nil {
+		if err.Err != "not_found" {
+			return err
+		}
+		highestBid = nil
+	}
+
+	bu.pendingMu.Lock()
+	pendingBid, ok := bu.pendingHighest[bidEntity.AuctionId]
+	bu.pendingMu.Unlock()
+	if ok && (highestBid == nil || pendingBid.Amount.Cmp(highestBid.Amount) > 0) {
+		highestBid = &pendingBid
+	}
+
+	if highestBid != nil {
+		if err := bu.compareWithHighestBid(bidEntity, highestBid); err != nil {
+			return err
+		}
+	}
+
+	bu.pendingMu.Lock()
+	bu.pendingHighest[bidEntity.AuctionId] = *bidEntity
+	bu.pendingMu.Unlock()
+
+	return nil
+}
+
+func (bu *BidUseCase) compareWithHighestBid(
+	bidEntity, highestBid *bid_entity.Bid) *internal_error.InternalError {
+	// Valores em moedas diferentes não são comparáveis
+	if highestBid.Currency != bidEntity.Currency {
+		return currencyMismatchError()
+	}
+
+	// Comparação exata: com float64, 0.1 + 0.2 não alcançaria um lance de 0.3
+	if bidEntity.Amount.Cmp(highestBid.Amount) <= 0 ||
+		bidEntity.Amount.Cmp(highestBid.Amount.Add(bu.minBidIncrement)) < 0 {
+		return internal_error.NewBadRequestError("bid must be higher than current highest bid")
+	}
+
+	return nil
+}
+
+// releasePendingBids tira dos pendentes os lances que já saíram do lote. Um
+// leilão só deixa de ter pendente quando o maior lance registrado está entre
+// eles; um lance aceito depois continua valendo.
+func (bu *BidUseCase) releasePendingBids(bids []bid_entity.Bid) {
+	for _, bid := range bids {
+		lock := bu.auctionLock(bid.AuctionId)
+		lock.Lock()
+
+		bu.pendingMu.Lock()
+		if pendingBid, ok := bu.pendingHighest[bid.AuctionId]; ok && pendingBid.Id == bid.Id {
+			delete(bu.pendingHighest, bid.AuctionId)
+		}
+		bu.pendingMu.Unlock()
+
+		lock.Unlock()
+	}
+}
+
+func (bu *BidUseCase) auctionLock(auctionId string) *sync.Mutex {
+	hash := fnv.New32a()
+	hash.Write([]byte(auctionId))
+	return &bu.auctionLocks[hash.Sum32()%auctionLockStripes]
+}
+
+// findBiddableAuction busca o leilão do lance e recusa, antes do lote e do
+// compre já, lances em leilões que não estão ativos, que passaram do prazo ou
+// em outra moeda. Sem AuctionExtender retorna nil e as verificações ficam
+// para o repositório.
+func (bu *BidUseCase) findBiddableAuction(
+	ctx context.Context, bidEntity *bid_entity.Bid) (*auction_entity.Auction, *internal_error.InternalError) {
+	if bu.AuctionExtender == nil {
+		return nil, nil
+	}
+
+	auctionEntity, err := bu.AuctionExtender.FindAuctionById(ctx, bidEntity.AuctionId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := auctionEntity.CheckAcceptsBid(bidEntity.Timestamp, bu.AuctionExtender.CloseGrace()); err != nil {
+		return nil, err
+	}
+
+	if bidEntity.Currency != auctionEntity.Currency {
+		return nil, currencyMismatchError()
+	}
+
+	return auctionEntity, nil
+}
+
+func currencyMismatchError() *internal_error.InternalError {
+	return internal_error.NewBadRequestError("bid currency must match the auction currency")
+}
+
+// tryBuyNow fecha o leilão na hora quando o lance alcança o BuyNowPrice. O
+// lance vencedor é gravado pelo próprio fechamento, fora do lote; quando dois
+// lances de compre já chegam juntos o segundo recebe AUCTION_NOT_ACTIVE.
+func (bu *BidUseCase) tryBuyNow(
+	ctx context.Context,
+	bidEntity *bid_entity.Bid,
+	auctionEntity *auction_entity.Auction) (bool, *internal_error.InternalError) {
+	if auctionEntity == nil {
+		return false, nil
+	}
+
+	if auctionEntity.BuyNowPrice <= 0 || !bidEntity.Amount.Reaches(auctionEntity.BuyNowPrice) {
+		return false, nil
+	}
+
+	err := bu.AuctionExtender.CompleteWithBuyNow(ctx, *bidEntity)
+	// O lance do compre já é gravado pelo fechamento, fora do lote
+	bu.releasePendingBids([]bid_entity.Bid{*bidEntity})
+	if err != nil {
+		return false, err
+	}
+
+	if bu.BidNotifier != nil {
+		bu.BidNotifier.Publish(*bidEntity)
+	}
+
+	return true, nil
+}
+
+// extendSnipedAuctions adia a expiração dos leilões que receberam lances nos
+// últimos ANTI_SNIPE_WINDOW antes de fechar. A janela é medida a partir do
+// horário do lance, já que o lote pode ser gravado algum tempo depois.
+func (bu *BidUseCase) extendSnipedAuctions(
+	ctx context.Context, bids []bid_entity.Bid, auctions map[string]*auction_entity.Auction) {
+	if bu.AuctionExtender == nil || bu.antiSnipeWindow <= 0 {
+		return
+	}
+
+	// Lances do último minuto de um leilão vêm juntos no lote; basta o mais
+	// recente de cada leilão
+	latestBids := make(map[string]time.Time)
+	for _, bid := range bids {
+		if bid.Timestamp.After(latestBids[bid.AuctionId]) {
+			latestBids[bid.AuctionId] = bid.Timestamp
+		}
+	}
+
+	for auctionId, bidTimestamp := range latestBids {
+		auctionEntity, ok := auctions[auctionId]
+		if !ok {
+			continue
+		}
+
+		if auctionEntity.ExpiresAt.Sub(bidTimestamp) >= bu.antiSnipeWindow {
+			continue
+		}
+
+		if err := bu.AuctionExtender.ExtendAuction(ctx, auctionId, bu.antiSnipeExtension); err != nil {
+			logger.Warn("Could not extend auction after a last-second bid",
+				zap.String("auction_id", auctionId),
+				zap.String("reason", err.Message))
+		}
+	}
+}
+
 func getMaxBatchSizeInterval() time.Duration {
 	batchInsertInterval := os.Getenv("BATCH_INSERT_INTERVAL")
 	duration, err := time.ParseDuration(batchInsertInterval)
@@ -133,3 +535,49 @@ func getMaxBatchSize() int {
 
 	return value
 }
+
+// getAntiSnipeWindow retorna 0 (anti-sniping desligado) quando a variável não
+// está definida
+func getAntiSnipeWindow() time.Duration {
+	antiSnipeWindow := os.Getenv("ANTI_SNIPE_WINDOW")
+	if antiSnipeWindow == "" {
+		return 0
+	}
+
+	duration, err := time.ParseDuration(antiSnipeWindow)
+	if err != nil || duration < 0 {
+		logger.Warn("ANTI_SNIPE_WINDOW is invalid, anti-sniping is disabled",
+			zap.String("value", antiSnipeWindow))
+		return 0
+	}
+
+	return duration
+}
+
+const defaultAntiSnipeExtension = 30 * time.Second
+
+func getAntiSnipeExtension() time.Duration {
+	antiSnipeExtension := os.Getenv("ANTI_SNIPE_EXTENSION")
+	if antiSnipeExtension == "" {
+		return defaultAntiSnipeExtension
+	}
+
+	duration, err := time.ParseDuration(antiSnipeExtension)
+	if err != nil || duration < time.Second {
+		logger.Warn("ANTI_SNIPE_EXTENSION is invalid, using default value",
+			zap.String("value", antiSnipeExtension),
+			zap.Duration("default", defaultAntiSnipeExtension))
+		return defaultAntiSnipeExtension
+	}
+
+	return duration
+}
+
+func getMinBidIncrement() bid_entity.Amount {
+	value, err := bid_entity.ParseAmount(os.Getenv("BID_MIN_INCREMENT"))
+	if err != nil || value.Cmp(bid_entity.Amount{}) < 0 {
+		return bid_entity.Amount{}
+	}
+
+	return value
+}
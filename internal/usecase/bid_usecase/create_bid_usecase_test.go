@@ -0,0 +1,515 @@
+package bid_usecase
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/entity/auction_entity"
+	"fullcycle-auction_go/internal/entity/bid_entity"
+	"fullcycle-auction_go/internal/internal_error"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeBidRepository struct {
+	highestBid *bid_entity.Bid
+	createErr  *internal_error.InternalError
+
+	mutex   sync.Mutex
+	created []bid_entity.Bid
+}
+
+func (f *fakeBidRepository) CreateBid(
+	ctx context.Context, bidEntities []bid_entity.Bid) *internal_error.InternalError {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.createErr == nil {
+		f.created = append(f.created, bidEntities...)
+	}
+	return f.createErr
+}
+
+func (f *fakeBidRepository) createdBids() []bid_entity.Bid {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	return f.created
+}
+
+func (f *fakeBidRepository) FindBidByAuctionId(
+	ctx context.Context, auctionId string, sort string, limit int64) ([]bid_entity.Bid, *internal_error.InternalError) {
+	return nil, nil
+}
+
+func (f *fakeBidRepository) FindWinningBidByAuctionId(
+	ctx context.Context, auctionId string) (*bid_entity.Bid, *internal_error.InternalError) {
+	if f.highestBid == nil {
+		return nil, internal_error.NewNotFoundError("no bids found for this auction")
+	}
+	return f.highestBid, nil
+}
+
+func (f *fakeBidRepository) DeleteBidsByAuctionId(
+	ctx context.Context, auctionId string) (int64, *internal_error.InternalError) {
+	return 0, nil
+}
+
+func (f *fakeBidRepository) IsLeadingBidder(
+	ctx context.Context, auctionId, userId string) (bool, *internal_error.InternalError) {
+	return false, nil
+}
+
+func TestCreateBidIncrement(t *testing.T) {
+	t.Setenv("BATCH_INSERT_INTERVAL", "1h")
+
+	auctionId := uuid.New().String()
+	highestBid := &bid_entity.Bid{
+		Id:        uuid.New().String(),
+		UserId:    uuid.New().String(),
+		AuctionId: auctionId,
+		Amount:    bid_entity.AmountFromCents(100_00),
+		Timestamp: time.Now(),
+		Currency:  bid_entity.DefaultCurrency,
+	}
+	tenCentBid := &bid_entity.Bid{
+		Id:        uuid.New().String(),
+		UserId:    uuid.New().String(),
+		AuctionId: auctionId,
+		Amount:    bid_entity.AmountFromCents(10),
+		Timestamp: time.Now(),
+		Currency:  bid_entity.DefaultCurrency,
+	}
+
+	testCases := []struct {
+		name         string
+		minIncrement string
+		highestBid   *bid_entity.Bid
+		amount       float64
+		expectError  bool
+	}{
+		{name: "first bid", highestBid: nil, amount: 10},
+		{name: "equal bid", highestBid: highestBid, amount: 100, expectError: true},
+		{name: "lower bid", highestBid: highestBid, amount: 90, expectError: true},
+		{name: "higher bid", highestBid: highestBid, amount: 101},
+		{name: "below min increment", minIncrement: "5", highestBid: highestBid, amount: 104, expectError: true},
+		{name: "meets min increment", minIncrement: "5", highestBid: highestBid, amount: 105},
+		// Em float64, 0.1 + 0.2 passa de 0.3 e o lance seria recusado
+		{name: "meets decimal increment", minIncrement: "0.2", highestBid: tenCentBid, amount: 0.3},
+		{name: "below decimal increment", minIncrement: "0.2", highestBid: tenCentBid, amount: 0.29, expectError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("BID_MIN_INCREMENT", tc.minIncrement)
+
+			bidUseCase := NewBidUseCase(&fakeBidRepository{highestBid: tc.highestBid}, nil, nil)
+			t.Cleanup(bidUseCase.Close)
+
+			err := bidUseCase.CreateBid(context.Background(), BidInputDTO{
+				UserId:    uuid.New().String(),
+				AuctionId: auctionId,
+				Amount:    tc.amount,
+			})
+
+			if !tc.expectError {
+				require.Nil(t, err)
+				return
+			}
+
+			require.NotNil(t, err)
+			require.Equal(t, "bad_request", err.Err)
+			require.Equal(t, "bid must be higher than current highest bid", err.Message)
+		})
+	}
+}
+
+func TestCreateBidCurrency(t *testing.T) {
+	t.Setenv("BATCH_INSERT_INTERVAL", "1h")
+
+	auctionId := uuid.New().String()
+	highestBid := &bid_entity.Bid{
+		Id:        uuid.New().String(),
+		UserId:    uuid.New().String(),
+		AuctionId: auctionId,
+		Amount:    bid_entity.AmountFromCents(100_00),
+		Timestamp: time.Now(),
+		Currency:  "USD",
+	}
+
+	testCases := []struct {
+		name            string
+		highestBid      *bid_entity.Bid
+		currency        string
+		amount          float64
+		expectedMessage string
+	}{
+		{name: "first usd bid", currency: "USD", amount: 50},
+		{name: "lowercase usd bid", currency: "usd", amount: 50},
+		{name: "higher usd bid", highestBid: highestBid, currency: "USD", amount: 101},
+		{name: "eur bid", currency: "EUR", amount: 50, expectedMessage: "bid currency must match the auction currency"},
+		// 150 EUR não é comparado com os 100 USD do maior lance
+		{name: "higher eur bid", highestBid: highestBid, currency: "EUR", amount: 150, expectedMessage: "bid currency must match the auction currency"},
+		{name: "bid without currency", currency: "", amount: 50, expectedMessage: "bid currency must match the auction currency"},
+		{name: "invalid currency", currency: "US", amount: 50, expectedMessage: "Currency must be an ISO 4217 code"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			extender := &fakeAuctionExtender{expiresAt: time.Now().Add(time.Hour), currency: "USD"}
+			bidUseCase := NewBidUseCase(&fakeBidRepository{highestBid: tc.highestBid}, extender, nil)
+			t.Cleanup(bidUseCase.Close)
+
+			err := bidUseCase.CreateBid(context.Background(), BidInputDTO{
+				UserId:    uuid.New().String(),
+				AuctionId: auctionId,
+				Amount:    tc.amount,
+				Currency:  tc.currency,
+			})
+
+			if tc.expectedMessage == "" {
+				require.Nil(t, err)
+				return
+			}
+
+			require.NotNil(t, err)
+			require.Equal(t, "bad_request", err.Err)
+			require.Equal(t, tc.expectedMessage, err.Message)
+		})
+	}
+}
+
+type fakeAuctionExtender struct {
+	expiresAt   time.Time
+	currency    string
+	auctionType auction_entity.AuctionType
+	status      auction_entity.AuctionStatus
+	buyNowPrice float64
+	extendedIds []string
+	buyNowBids  []bid_entity.Bid
+}
+
+func (f *fakeAuctionExtender) FindAuctionById(
+	ctx context.Context, id string) (*auction_entity.Auction, *internal_error.InternalError) {
+	return &auction_entity.Auction{
+		Id:          id,
+		Status:      f.status,
+		ExpiresAt:   f.expiresAt,
+		Currency:    bid_entity.CurrencyOrDefault(f.currency),
+		Type:        f.auctionType,
+		BuyNowPrice: f.buyNowPrice,
+	}, nil
+}
+
+func (f *fakeAuctionExtender) ExtendAuction(
+	ctx context.Context, id string, extra time.Duration) *internal_error.InternalError {
+	f.extendedIds = append(f.extendedIds, id)
+	return nil
+}
+
+func (f *fakeAuctionExtender) CompleteWithBuyNow(
+	ctx context.Context, bid bid_entity.Bid) *internal_error.InternalError {
+	f.buyNowBids = append(f.buyNowBids, bid)
+	return nil
+}
+
+func (f *fakeAuctionExtender) CloseGrace() time.Duration {
+	return 0
+}
+
+func TestCreateBidRejectedOnAuctionNotAcceptingBids(t *testing.T) {
+	t.Setenv("BATCH_INSERT_INTERVAL", "1h")
+
+	testCases := []struct {
+		name            string
+		status          auction_entity.AuctionStatus
+		expiresIn       time.Duration
+		amount          float64
+		expectedMessage string
+	}{
+		{name: "scheduled auction", status: auction_entity.Scheduled, expiresIn: 2 * time.Hour, amount: 100, expectedMessage: "auction has not started yet"},
+		// O compre já também não fecha um leilão que ainda não começou
+		{name: "buy now on scheduled auction", status: auction_entity.Scheduled, expiresIn: 2 * time.Hour, amount: 500, expectedMessage: "auction has not started yet"},
+		{name: "completed auction", status: auction_entity.Completed, expiresIn: 2 * time.Hour, amount: 100, expectedMessage: "auction is not active"},
+		{name: "cancelled auction", status: auction_entity.Cancelled, expiresIn: 2 * time.Hour, amount: 100, expectedMessage: "auction is not active"},
+		{name: "expired auction waiting for the closer", status: auction_entity.Active, expiresIn: -time.Minute, amount: 100, expectedMessage: "auction is not active"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			bidRepository := &fakeBidRepository{}
+			extender := &fakeAuctionExtender{
+				expiresAt:   time.Now().Add(tc.expiresIn),
+				status:      tc.status,
+				buyNowPrice: 500,
+			}
+			bidUseCase := NewBidUseCase(bidRepository, extender, nil)
+			t.Cleanup(bidUseCase.Close)
+
+			err := bidUseCase.CreateBid(context.Background(), BidInputDTO{
+				UserId:    uuid.New().String(),
+				AuctionId: uuid.New().String(),
+				Amount:    tc.amount,
+			})
+
+			require.NotNil(t, err)
+			require.Equal(t, tc.expectedMessage, err.Message)
+			require.Equal(t, internal_error.CodeAuctionNotActive, err.Code)
+			require.Empty(t, extender.buyNowBids)
+			require.Empty(t, bidRepository.createdBids())
+		})
+	}
+}
+
+func TestExtendSnipedAuctions(t *testing.T) {
+	t.Setenv("BATCH_INSERT_INTERVAL", "1h")
+	t.Setenv("ANTI_SNIPE_WINDOW", "10s")
+
+	now := time.Now()
+	auctionId := uuid.New().String()
+
+	testCases := []struct {
+		name           string
+		bidTimestamps  []time.Time
+		expectedExtend int
+	}{
+		{name: "bid outside the window", bidTimestamps: []time.Time{now.Add(-time.Minute)}},
+		{name: "bid inside the window", bidTimestamps: []time.Time{now.Add(-5 * time.Second)}, expectedExtend: 1},
+		{
+			name:           "several bids inside the window extend once",
+			bidTimestamps:  []time.Time{now.Add(-5 * time.Second), now.Add(-2 * time.Second)},
+			expectedExtend: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			extender := &fakeAuctionExtender{expiresAt: now}
+			bidUseCase := NewBidUseCase(&fakeBidRepository{}, extender, nil).(*BidUseCase)
+			t.Cleanup(bidUseCase.Close)
+
+			var bids []bid_entity.Bid
+			for _, timestamp := range tc.bidTimestamps {
+				bids = append(bids, bid_entity.Bid{AuctionId: auctionId, Amount: bid_entity.AmountFromCents(100_00), Timestamp: timestamp})
+			}
+
+			bidUseCase.extendSnipedAuctions(context.Background(), bids, bidUseCase.findBatchAuctions(context.Background(), bids))
+			require.Len(t, extender.extendedIds, tc.expectedExtend)
+		})
+	}
+}
+
+func TestCreateBidSealedAuctionSkipsIncrement(t *testing.T) {
+	t.Setenv("BATCH_INSERT_INTERVAL", "1h")
+
+	auctionId := uuid.New().String()
+	highestBid := &bid_entity.Bid{
+		Id:        uuid.New().String(),
+		AuctionId: auctionId,
+		Amount:    bid_entity.AmountFromCents(100_00),
+		Currency:  bid_entity.DefaultCurrency,
+	}
+
+	testCases := []struct {
+		name        string
+		auctionType auction_entity.AuctionType
+		expectError bool
+	}{
+		{name: "open auction", auctionType: auction_entity.Open, expectError: true},
+		// A recusa revelaria o maior lance de um leilão Sealed
+		{name: "sealed auction", auctionType: auction_entity.Sealed},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			extender := &fakeAuctionExtender{expiresAt: time.Now().Add(time.Hour), auctionType: tc.auctionType}
+			bidUseCase := NewBidUseCase(&fakeBidRepository{highestBid: highestBid}, extender, nil)
+			t.Cleanup(bidUseCase.Close)
+
+			err := bidUseCase.CreateBid(context.Background(), BidInputDTO{
+				UserId:    uuid.New().String(),
+				AuctionId: auctionId,
+				Amount:    50,
+			})
+
+			if tc.expectError {
+				require.NotNil(t, err)
+				return
+			}
+			require.Nil(t, err)
+		})
+	}
+}
+
+type recordingBidNotifier struct {
+	published []bid_entity.Bid
+}
+
+func (r *recordingBidNotifier) Publish(bid bid_entity.Bid) {
+	r.published = append(r.published, bid)
+}
+
+func TestProcessBidBatchHidesSealedBidsFromStream(t *testing.T) {
+	t.Setenv("BATCH_INSERT_INTERVAL", "1h")
+
+	testCases := []struct {
+		name            string
+		auctionType     auction_entity.AuctionType
+		expectPublished int
+	}{
+		{name: "open auction", auctionType: auction_entity.Open, expectPublished: 1},
+		{name: "sealed auction", auctionType: auction_entity.Sealed},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			extender := &fakeAuctionExtender{expiresAt: time.Now().Add(time.Hour), auctionType: tc.auctionType}
+			notifier := &recordingBidNotifier{}
+			bidUseCase := NewBidUseCase(&fakeBidRepository{}, extender, notifier).(*BidUseCase)
+			t.Cleanup(bidUseCase.Close)
+
+			bidUseCase.processBidBatch(context.Background(), []bid_entity.Bid{{
+				Id:        uuid.New().String(),
+				AuctionId: uuid.New().String(),
+				Amount:    bid_entity.AmountFromCents(100_00),
+				Timestamp: time.Now(),
+			}})
+
+			require.Len(t, notifier.published, tc.expectPublished)
+		})
+	}
+}
+
+func TestProcessBidBatchNotifiesStoredBidsOnPartialFailure(t *testing.T) {
+	t.Setenv("BATCH_INSERT_INTERVAL", "1h")
+	t.Setenv("ANTI_SNIPE_WINDOW", "10s")
+
+	now := time.Now()
+	storedBid := bid_entity.Bid{Id: uuid.New().String(), AuctionId: uuid.New().String(), Amount: bid_entity.AmountFromCents(100_00), Timestamp: now}
+	failedBid := bid_entity.Bid{Id: uuid.New().String(), AuctionId: uuid.New().String(), Amount: bid_entity.AmountFromCents(100_00), Timestamp: now}
+
+	testCases := []struct {
+		name            string
+		createErr       *internal_error.InternalError
+		expectPublished []bid_entity.Bid
+		expectExtended  []string
+	}{
+		{
+			name: "stored bids are still notified and extended",
+			createErr: internal_error.NewBadRequestError("auction is not active").
+				WithFailedIds([]string{failedBid.Id}),
+			expectPublished: []bid_entity.Bid{storedBid},
+			expectExtended:  []string{storedBid.AuctionId},
+		},
+		{
+			name:      "error without failed ids drops the whole batch",
+			createErr: internal_error.NewInternalServerError("Error trying to insert bid"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			extender := &fakeAuctionExtender{expiresAt: now}
+			notifier := &recordingBidNotifier{}
+			bidUseCase := NewBidUseCase(&fakeBidRepository{createErr: tc.createErr}, extender, notifier).(*BidUseCase)
+			t.Cleanup(bidUseCase.Close)
+
+			bidUseCase.processBidBatch(context.Background(), []bid_entity.Bid{storedBid, failedBid})
+
+			require.Equal(t, tc.expectPublished, notifier.published)
+			require.Equal(t, tc.expectExtended, extender.extendedIds)
+		})
+	}
+}
+
+func TestCreateBidIncrementAgainstQueuedBids(t *testing.T) {
+	t.Setenv("BATCH_INSERT_INTERVAL", "1h")
+	t.Setenv("MAX_BATCH_SIZE", "10")
+
+	auctionId := uuid.New().String()
+	bidUseCase := NewBidUseCase(&fakeBidRepository{}, nil, nil).(*BidUseCase)
+	t.Cleanup(bidUseCase.Close)
+
+	createBid := func(amount float64) *internal_error.InternalError {
+		return bidUseCase.CreateBid(context.Background(), BidInputDTO{
+			UserId:    uuid.New().String(),
+			AuctionId: auctionId,
+			Amount:    amount,
+		})
+	}
+
+	// Nenhum dos lances foi gravado ainda, mas o primeiro já está no lote
+	require.Nil(t, createBid(100))
+	err := createBid(100)
+	require.NotNil(t, err)
+	require.Equal(t, "bid must be higher than current highest bid", err.Message)
+	require.Nil(t, createBid(150))
+
+	// O Close grava o lote, que deixa de contar como pendente
+	bidUseCase.Close()
+	require.Empty(t, bidUseCase.pendingHighest)
+}
+
+func TestCreateBidNearExpiryIsStoredRightAway(t *testing.T) {
+	t.Setenv("BATCH_INSERT_INTERVAL", "1h")
+	t.Setenv("ANTI_SNIPE_WINDOW", "10s")
+
+	testCases := []struct {
+		name           string
+		expiresIn      time.Duration
+		createErr      *internal_error.InternalError
+		expectQueued   int
+		expectStored   int
+		expectExtended int
+	}{
+		{name: "auction expiring after the batch", expiresIn: 2 * time.Hour, expectQueued: 1},
+		{name: "auction expiring before the batch", expiresIn: 5 * time.Second, expectStored: 1, expectExtended: 1},
+		{
+			name:      "rejection reaches the client",
+			expiresIn: 5 * time.Second,
+			createErr: internal_error.NewBadRequestError("auction is not active").
+				WithCode(internal_error.CodeAuctionNotActive),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			bidRepository := &fakeBidRepository{createErr: tc.createErr}
+			extender := &fakeAuctionExtender{expiresAt: time.Now().Add(tc.expiresIn)}
+			bidUseCase := NewBidUseCase(bidRepository, extender, nil).(*BidUseCase)
+			t.Cleanup(bidUseCase.Close)
+
+			err := bidUseCase.CreateBid(context.Background(), BidInputDTO{
+				UserId:    uuid.New().String(),
+				AuctionId: uuid.New().String(),
+				Amount:    100,
+			})
+
+			if tc.createErr != nil {
+				require.Equal(t, tc.createErr, err)
+			} else {
+				require.Nil(t, err)
+			}
+			require.Len(t, bidRepository.createdBids(), tc.expectStored)
+			require.Len(t, extender.extendedIds, tc.expectExtended)
+			require.Len(t, bidUseCase.pendingHighest, tc.expectQueued)
+		})
+	}
+}
+
+func TestCreateBidAfterClose(t *testing.T) {
+	t.Setenv("BATCH_INSERT_INTERVAL", "1h")
+
+	bidUseCase := NewBidUseCase(&fakeBidRepository{}, nil, nil)
+	bidUseCase.Close()
+
+	err := bidUseCase.CreateBid(context.Background(), BidInputDTO{
+		UserId:    uuid.New().String(),
+		AuctionId: uuid.New().String(),
+		Amount:    100,
+	})
+	require.NotNil(t, err)
+	require.Equal(t, "bid use case is closed", err.Message)
+}
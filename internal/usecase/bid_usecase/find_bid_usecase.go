@@ -6,19 +6,20 @@ import (
 )
 
 func (bu *BidUseCase) FindBidByAuctionId(
-	ctx context.Context, auctionId string) ([]BidOutputDTO, *internal_error.InternalError) {
-	bidList, err := bu.BidRepository.FindBidByAuctionId(ctx, auctionId)
+	ctx context.Context, auctionId string, sort string, limit int64) ([]BidOutputDTO, *internal_error.InternalError) {
+	bidList, err := bu.BidRepository.FindBidByAuctionId(ctx, auctionId, sort, limit)
 	if err != nil {
 		return nil, err
 	}
 
-	var bidOutputList []BidOutputDTO
+	bidOutputList := make([]BidOutputDTO, 0, len(bidList))
 	for _, bid := range bidList {
 		bidOutputList = append(bidOutputList, BidOutputDTO{
 			Id:        bid.Id,
 			UserId:    bid.UserId,
 			AuctionId: bid.AuctionId,
-			Amount:    bid.Amount,
+			Amount:    bid.Amount.Float64(),
+			Currency:  bid.Currency,
 			Timestamp: bid.Timestamp,
 		})
 	}
@@ -37,7 +38,8 @@ func (bu *BidUseCase) FindWinningBidByAuctionId(
 		Id:        bidEntity.Id,
 		UserId:    bidEntity.UserId,
 		AuctionId: bidEntity.AuctionId,
-		Amount:    bidEntity.Amount,
+		Amount:    bidEntity.Amount.Float64(),
+		Currency:  bidEntity.Currency,
 		Timestamp: bidEntity.Timestamp,
 	}
 
@@ -0,0 +1,28 @@
+package bid_usecase
+
+import (
+	"context"
+	"fullcycle-auction_go/internal/internal_error"
+)
+
+// LeadingBidderOutputDTO responde só se o usuário está na frente, sem os
+// valores dos lances.
+type LeadingBidderOutputDTO struct {
+	AuctionId string `json:"auction_id"`
+	UserId    string `json:"user_id"`
+	Leading   bool   `json:"leading"`
+}
+
+func (bu *BidUseCase) IsLeadingBidder(
+	ctx context.Context, auctionId, userId string) (*LeadingBidderOutputDTO, *internal_error.InternalError) {
+	leading, err := bu.BidRepository.IsLeadingBidder(ctx, auctionId, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LeadingBidderOutputDTO{
+		AuctionId: auctionId,
+		UserId:    userId,
+		Leading:   leading,
+	}, nil
+}